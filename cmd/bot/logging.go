@@ -0,0 +1,53 @@
+package main
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/alert"
+	"github.com/user/missav-bot-go/internal/config"
+	"github.com/user/missav-bot-go/internal/logfile"
+)
+
+// setupLogging applies the log configuration to the global logger
+// Until it runs, logs are JSON at the default level so configuration errors are still reported.
+// Returns the log file to close on exit, or nil when LOG_FILE is not set, and the
+// alerter to run once Telegram is connected, or nil when LOG_ALERT_CHAT_ID is not set.
+func setupLogging(cfg *config.LogConfig) (*logfile.Writer, *alert.Alerter, error) {
+	level, err := config.ParseLogLevel(cfg.Level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var output io.Writer = os.Stdout
+	if cfg.Format == "console" {
+		output = zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.DateTime}
+	}
+
+	var file *logfile.Writer
+	if cfg.File != "" {
+		file, err = logfile.Open(cfg.File, logfile.Options{
+			MaxSize:    int64(cfg.FileMaxSize) << 20,
+			MaxAge:     cfg.FileMaxAge,
+			MaxBackups: cfg.FileMaxBackups,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		// The file always gets JSON, whatever stdout shows, so it can be searched and parsed
+		output = zerolog.MultiLevelWriter(output, file)
+	}
+
+	var alerts *alert.Alerter
+	if cfg.AlertChatID != 0 {
+		alerts = alert.New(cfg.AlertCooldown)
+		output = zerolog.MultiLevelWriter(output, alerts)
+	}
+
+	log.Logger = zerolog.New(output).With().Timestamp().Caller().Logger()
+	zerolog.SetGlobalLevel(level)
+	return file, alerts, nil
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/user/missav-bot-go/internal/bot"
 	"github.com/user/missav-bot-go/internal/config"
 	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/model"
 	"github.com/user/missav-bot-go/internal/push"
 	"github.com/user/missav-bot-go/internal/scheduler"
 	"github.com/user/missav-bot-go/internal/server"
@@ -29,6 +30,11 @@ func main() {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	log.Logger = zerolog.New(os.Stdout).With().Timestamp().Caller().Logger()
 
+	// Schema migrations run without the rest of the bot
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(os.Args[2:]))
+	}
+
 	// Load configuration (Requirement 7.1, 7.2, 7.3)
 	cfg, err := config.Load()
 	if err != nil {
@@ -39,6 +45,10 @@ func main() {
 	if err := cfg.Validate(); err != nil {
 		log.Fatal().Err(err).Msg("Invalid configuration")
 	}
+	logFile, alerts, err := setupLogging(&cfg.Log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up logging")
+	}
 
 	log.Info().Msg("Configuration loaded successfully")
 
@@ -53,7 +63,18 @@ func main() {
 	}
 	log.Info().Msg("Database connection established")
 
-	// Initialize HTTP crawler
+	// Wrap the store with a Redis cache for hot read paths if configured
+	var dataStore store.Store = mysqlStore
+	if cfg.Redis.URL != "" {
+		redisCache, err := store.NewRedisCache(ctx, cfg.Redis.URL)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to connect to Redis")
+		}
+		dataStore = store.NewCachedStore(mysqlStore, redisCache, cfg.Redis.CacheTTL)
+		log.Info().Dur("ttl", cfg.Redis.CacheTTL).Msg("Redis cache enabled")
+	}
+
+	// Initialize crawler over the configured sources
 	crawlerCfg := &crawler.CrawlerConfig{
 		Enabled:      cfg.Crawler.Enabled,
 		RateLimit:    cfg.Crawler.RateLimit,
@@ -63,33 +84,104 @@ func main() {
 		UserAgent:    cfg.Crawler.UserAgent,
 		ProxyURL:     cfg.Crawler.ProxyURL,
 		InitialPages: cfg.Crawler.InitialPages,
+
+		ImpersonateTLS: cfg.Crawler.ImpersonateTLS,
+		DetailCacheTTL: cfg.Crawler.DetailCacheTTL,
+		DetailCacheDir: cfg.Crawler.DetailCacheDir,
+		ProxyUsername:  cfg.Crawler.ProxyUsername,
+		ProxyPassword:  cfg.Crawler.ProxyPassword,
+		DailyBudget:    cfg.Crawler.DailyBudget,
+
+		BrowserMaxPages: cfg.Crawler.BrowserMaxPages,
+		BrowserMaxAge:   cfg.Crawler.BrowserMaxAge,
+
+		BrowserNavigationDeadline: cfg.Crawler.BrowserNavigationDeadline,
+		BrowserRemoteURL:          cfg.Crawler.BrowserRemoteURL,
+		BrowserBinPath:            cfg.Crawler.BrowserBinPath,
+		BrowserHeadful:            cfg.Crawler.BrowserHeadful,
+		BrowserDevtools:           cfg.Crawler.BrowserDevtools,
+		SnapshotDir:               cfg.Crawler.SnapshotDir,
+		SnapshotKeep:              cfg.Crawler.SnapshotKeep,
+	}
+	crawlerCfg.MergePriority, err = crawler.ParseMergePriority(cfg.Crawler.MergePriority)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid CRAWLER_MERGE_PRIORITY")
+	}
+	crawlerCfg.BrowserExtraFlags, err = crawler.ParseBrowserFlags(cfg.Crawler.BrowserExtraFlags)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid BROWSER_EXTRA_FLAGS")
 	}
-	httpCrawler, err := crawler.NewHTTPCrawler(crawlerCfg)
+	siteCrawler, err := crawler.NewCrawler(crawlerCfg, cfg.Crawler.Sources)
 	if err != nil {
 		log.Fatal().Err(err).Msg("Failed to create crawler")
 	}
-	log.Info().Msg("Crawler initialized")
+	log.Info().Strs("sources", cfg.Crawler.Sources).Msg("Crawler initialized")
 
-	// Initialize Telegram client (Requirement 3.1)
-	telegramClient, err := bot.NewClient(cfg.Bot.Token)
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to create Telegram client")
+	// Initialize Telegram clients, the primary bot first (Requirement 3.1)
+	var telegramClients []*bot.Client
+	for _, token := range cfg.Bot.Tokens() {
+		client, err := bot.NewClient(token, cfg.Bot.APIEndpoint)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to authenticate Telegram bot, check BOT_TOKEN and BOT_TOKENS")
+		}
+		telegramClients = append(telegramClients, client)
+		log.Info().Int64("botID", client.BotID()).Str("username", client.BotUsername()).Msg("Telegram bot authenticated")
+	}
+
+	// Forward error logs to the admin chat through the primary bot
+	if alerts != nil {
+		go alerts.Run(ctx, func(text string) error {
+			return telegramClients[0].SendMessage(cfg.Log.AlertChatID, text)
+		})
+		log.Info().Int64("chatID", cfg.Log.AlertChatID).Msg("Error alerts enabled")
 	}
-	log.Info().Msg("Telegram client initialized")
 
 	// Initialize push service (Requirement 5.1)
-	pushService := push.NewService(mysqlStore, telegramClient)
+	pushService := push.NewServiceWithConfig(dataStore, telegramClients[0], &push.ServiceConfig{
+		Workers:        cfg.Push.Workers,
+		WebhookURLs:    cfg.Push.WebhookURLs,
+		WebhookSecret:  cfg.Push.WebhookSecret,
+		WebhookTimeout: cfg.Push.WebhookTimeout,
+		DefaultChatID:  cfg.Bot.DefaultChatID,
+		DailyCap:       cfg.Push.DailyCap,
+		BatchSize:      cfg.Push.BatchSize,
+		BatchThreshold: cfg.Push.BatchThreshold,
+		ParseMode:      model.ParseMode(cfg.Push.ParseMode),
+		ChatDelay:      cfg.Push.ChatDelay,
+	})
+	for _, client := range telegramClients {
+		pushService.RegisterTelegramBot(client.BotID(), client)
+	}
 	log.Info().Msg("Push service initialized")
 
-	// Initialize bot handler (Requirement 3.1)
-	botHandler := bot.NewHandler(mysqlStore, httpCrawler, pushService, telegramClient)
-	log.Info().Msg("Bot handler initialized")
+	// Initialize one bot handler per bot (Requirement 3.1)
+	botHandlers := make([]*bot.Handler, len(telegramClients))
+	for i, client := range telegramClients {
+		botHandlers[i] = bot.NewHandler(dataStore, siteCrawler, pushService, client, &cfg.Bot)
+	}
+	log.Info().Int("bots", len(botHandlers)).Msg("Bot handlers initialized")
 
 	// Initialize scheduler (Requirement 6.1, 6.2)
-	sched := scheduler.NewScheduler(httpCrawler, mysqlStore, pushService, &cfg.Crawler)
+	sched := scheduler.NewScheduler(siteCrawler, dataStore, pushService, &cfg.Crawler)
+	for _, handler := range botHandlers {
+		handler.SetScheduler(sched)
+	}
 
 	// Initialize HTTP server (Requirement 8.1)
-	httpServer := server.NewServer(mysqlStore)
+	httpServer := server.NewServer(dataStore)
+	if reporter, ok := siteCrawler.(crawler.BrowserHealthReporter); ok {
+		httpServer.SetBrowserHealth(reporter)
+	}
+	httpServer.SetRevoker(pushService, cfg.Server.APIToken)
+	httpServer.SetScheduler(sched)
+	bots := make([]server.BotIdentity, 0, len(telegramClients))
+	for _, client := range telegramClients {
+		bots = append(bots, server.BotIdentity{ID: client.BotID(), Username: client.BotUsername()})
+	}
+	httpServer.SetBots(bots)
+	if err := httpServer.RegisterStoreMetrics(); err != nil {
+		log.Error().Err(err).Msg("Failed to register store metrics")
+	}
 
 	// Setup signal handling for graceful shutdown (Requirement 9.1)
 	sigCh := make(chan os.Signal, 1)
@@ -103,18 +195,24 @@ func main() {
 		}
 	}()
 
+	// Keep the cached video count and the videos_total metric reconciled with the database
+	go httpServer.RefreshVideoCount(ctx)
+
 	// Start scheduler (Requirement 6.1)
 	sched.Start(ctx)
 	log.Info().Msg("Scheduler started")
 
-	// Start Telegram bot polling in goroutine
-	go func() {
-		log.Info().Msg("Starting Telegram bot polling")
-		updates := telegramClient.GetUpdates()
-		for update := range updates {
-			botHandler.HandleUpdate(ctx, update)
-		}
-	}()
+	// Start Telegram bot polling, one goroutine per bot
+	for i, client := range telegramClients {
+		go func(client *bot.Client, handler *bot.Handler) {
+			log.Info().Int64("botID", client.BotID()).Msg("Starting Telegram bot polling")
+			updates := client.GetUpdates(handler.StartOffset(ctx))
+			for update := range updates {
+				handler.HandleUpdate(ctx, update)
+				handler.MarkProcessed(ctx, update.UpdateID)
+			}
+		}(client, botHandlers[i])
+	}
 
 	log.Info().Msg("MissAV Bot started successfully")
 
@@ -129,38 +227,54 @@ func main() {
 	// Graceful shutdown sequence
 	log.Info().Msg("Starting graceful shutdown...")
 
-	// 1. Stop scheduler from triggering new tasks (Requirement 9.1)
-	sched.Stop()
-	log.Info().Msg("Scheduler stopped")
-
-	// 2. Stop Telegram bot polling (Requirement 9.3)
-	telegramClient.StopReceivingUpdates()
+	// 1. Stop Telegram bot polling (Requirement 9.3)
+	for _, client := range telegramClients {
+		client.StopReceivingUpdates()
+	}
 	log.Info().Msg("Telegram bot polling stopped")
 
-	// 3. Stop HTTP server
+	// 2. Drain pushes: no new deliveries start and in-flight ones finish. Undelivered
+	// pushes stay pending in the outbox and are delivered after the next start.
+	if err := pushService.Drain(shutdownCtx); err != nil {
+		log.Warn().Err(err).Msg("Timed out draining in-flight pushes, leaving them pending")
+	} else {
+		log.Info().Msg("Push service drained")
+	}
+
+	// 3. Stop scheduler from triggering new tasks and wait for the running one (Requirement 9.1)
+	if err := sched.Shutdown(shutdownCtx); err != nil {
+		log.Warn().Err(err).Msg("Timed out waiting for running crawl, cancelling it")
+	}
+
+	// 4. Stop HTTP server
 	if err := httpServer.Stop(shutdownCtx); err != nil {
 		log.Error().Err(err).Msg("Error stopping HTTP server")
 	} else {
 		log.Info().Msg("HTTP server stopped")
 	}
 
-	// 4. Close crawler (closes headless browser instances) (Requirement 9.5)
-	if err := httpCrawler.Close(); err != nil {
+	// Save polling offsets so the next start resumes after the last processed update
+	for _, handler := range botHandlers {
+		handler.SaveOffset(shutdownCtx)
+	}
+
+	// Cancel root context so work outliving the drain stops before its resources close
+	cancel()
+
+	// 5. Close crawler (closes headless browser instances) (Requirement 9.5)
+	if err := siteCrawler.Close(); err != nil {
 		log.Error().Err(err).Msg("Error closing crawler")
 	} else {
 		log.Info().Msg("Crawler closed")
 	}
 
-	// 5. Close database connection pool (Requirement 9.4)
-	if err := mysqlStore.Close(); err != nil {
+	// 6. Close database connection pool (Requirement 9.4)
+	if err := dataStore.Close(); err != nil {
 		log.Error().Err(err).Msg("Error closing database connection")
 	} else {
 		log.Info().Msg("Database connection closed")
 	}
 
-	// Cancel root context
-	cancel()
-
 	// Check if shutdown completed within timeout (Requirement 9.6)
 	select {
 	case <-shutdownCtx.Done():
@@ -170,4 +284,8 @@ func main() {
 	default:
 		log.Info().Msg("Graceful shutdown completed")
 	}
+
+	if logFile != nil {
+		logFile.Close()
+	}
 }
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/config"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+const migrateUsage = `usage: bot migrate <command>
+
+commands:
+  up                 apply all pending migrations
+  down               roll back the last applied migration
+  to <id>            apply pending migrations up to and including <id>
+  rollback-to <id>   roll back migrations applied after <id>
+  status             list applied migrations`
+
+// runMigrate handles the "migrate" subcommand and returns the process exit code
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	cmd := args[0]
+	var id string
+	switch cmd {
+	case "up", "down", "status":
+	case "to", "rollback-to":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		id = args[1]
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	dbCfg, err := config.LoadDB()
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to load database configuration")
+		return 1
+	}
+
+	migrator, err := store.NewMigrator(dbCfg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to connect to database")
+		return 1
+	}
+	defer migrator.Close()
+
+	switch cmd {
+	case "up":
+		err = migrator.Up()
+	case "down":
+		err = migrator.Down()
+	case "to":
+		err = migrator.To(id)
+	case "rollback-to":
+		err = migrator.RollbackTo(id)
+	case "status":
+		var applied []string
+		applied, err = migrator.Applied()
+		for _, appliedID := range applied {
+			fmt.Println(appliedID)
+		}
+	}
+	if err != nil {
+		log.Error().Err(err).Str("command", cmd).Msg("Migration failed")
+		return 1
+	}
+
+	log.Info().Str("command", cmd).Msg("Migration completed")
+	return 0
+}
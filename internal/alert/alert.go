@@ -0,0 +1,152 @@
+// Package alert forwards error logs to an admin chat
+// It is a zerolog writer: every Error-level or worse entry becomes a message, with
+// repeats of the same error suppressed for a cooldown so a flapping failure is
+// reported a few times rather than hundreds.
+package alert
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// queueSize is the number of alerts waiting to be sent before new ones are dropped
+	queueSize = 32
+	// alertsPerMinute and alertBurst cap the alerts of all errors together, so many
+	// distinct errors at once still produce a bounded number of messages
+	alertsPerMinute = 2
+	alertBurst      = 5
+)
+
+// Sender delivers an alert text to the admin chat
+type Sender func(text string) error
+
+// errorState tracks when an error was last alerted and how often it was suppressed since
+type errorState struct {
+	sentAt     time.Time
+	suppressed int
+}
+
+// Alerter turns error log entries into rate-limited alerts
+// Entries are queued until Run delivers them, so it can be installed as a log
+// writer before the Telegram client exists.
+type Alerter struct {
+	mu       sync.Mutex
+	cooldown time.Duration
+	limiter  *rate.Limiter
+	errors   map[string]*errorState
+	queue    chan string
+	now      func() time.Time
+}
+
+// New creates an Alerter that alerts each distinct error at most once per cooldown
+func New(cooldown time.Duration) *Alerter {
+	return &Alerter{
+		cooldown: cooldown,
+		limiter:  rate.NewLimiter(rate.Every(time.Minute/alertsPerMinute), alertBurst),
+		errors:   make(map[string]*errorState),
+		queue:    make(chan string, queueSize),
+		now:      time.Now,
+	}
+}
+
+// Write implements io.Writer; entries without a level are not alerted
+func (a *Alerter) Write(p []byte) (int, error) {
+	return a.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter, queueing an alert for Error-level entries
+// It never fails, so a broken alert path cannot break logging.
+func (a *Alerter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < zerolog.ErrorLevel || level == zerolog.NoLevel {
+		return len(p), nil
+	}
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(p, &entry); err != nil {
+		return len(p), nil
+	}
+	message, _ := entry[zerolog.MessageFieldName].(string)
+	errText, _ := entry[zerolog.ErrorFieldName].(string)
+	caller, _ := entry[zerolog.CallerFieldName].(string)
+
+	// Errors are told apart by where they are logged and their message, not by the
+	// error text, which often varies per occurrence
+	key := caller + "|" + message
+	suppressed, ok := a.admit(key)
+	if !ok {
+		return len(p), nil
+	}
+
+	select {
+	case a.queue <- formatAlert(level, message, errText, caller, suppressed):
+	default:
+		// The admin chat is not keeping up; the entry is still in the logs
+	}
+	return len(p), nil
+}
+
+// admit decides whether an occurrence of the error key is alerted
+// It returns the number of occurrences suppressed since the key was last alerted.
+func (a *Alerter) admit(key string) (int, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	state, ok := a.errors[key]
+	if !ok {
+		state = &errorState{}
+		a.errors[key] = state
+	}
+	if !state.sentAt.IsZero() && now.Sub(state.sentAt) < a.cooldown {
+		state.suppressed++
+		return 0, false
+	}
+	if !a.limiter.AllowN(now, 1) {
+		state.suppressed++
+		return 0, false
+	}
+
+	suppressed := state.suppressed
+	state.sentAt = now
+	state.suppressed = 0
+	return suppressed, true
+}
+
+// Run sends queued alerts until ctx is done
+// Failures are logged at Warn level, which is not alerted, so they cannot loop.
+func (a *Alerter) Run(ctx context.Context, send Sender) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case text := <-a.queue:
+			if err := send(text); err != nil {
+				log.Warn().Err(err).Msg("Failed to send error alert")
+			}
+		}
+	}
+}
+
+// formatAlert renders an error log entry as a plain text alert
+func formatAlert(level zerolog.Level, message, errText, caller string, suppressed int) string {
+	lines := []string{fmt.Sprintf("🚨 %s: %s", strings.ToUpper(level.String()), message)}
+	if errText != "" {
+		lines = append(lines, "错误: "+errText)
+	}
+	if caller != "" {
+		lines = append(lines, "位置: "+caller)
+	}
+	if suppressed > 0 {
+		lines = append(lines, fmt.Sprintf("上次告警后又出现 %d 次", suppressed))
+	}
+	return strings.Join(lines, "\n")
+}
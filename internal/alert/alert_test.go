@@ -0,0 +1,99 @@
+package alert
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// newTestAlerter returns an Alerter on a controllable clock and a logger writing to it
+func newTestAlerter(cooldown time.Duration) (*Alerter, zerolog.Logger, *time.Time) {
+	a := New(cooldown)
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	a.now = func() time.Time { return clock }
+	return a, zerolog.New(a), &clock
+}
+
+// drain returns the alerts queued so far
+func drain(a *Alerter) []string {
+	var alerts []string
+	for {
+		select {
+		case text := <-a.queue:
+			alerts = append(alerts, text)
+		default:
+			return alerts
+		}
+	}
+}
+
+func TestAlerter_OnlyErrors(t *testing.T) {
+	a, logger, _ := newTestAlerter(time.Minute)
+	logger.Info().Msg("Crawled videos")
+	logger.Warn().Msg("Browser crawl failed")
+	logger.Error().Str("error", "connection refused").Msg("Failed to save videos")
+
+	alerts := drain(a)
+	if len(alerts) != 1 {
+		t.Fatalf("alerts = %q, want only the error", alerts)
+	}
+	if !strings.Contains(alerts[0], "Failed to save videos") || !strings.Contains(alerts[0], "connection refused") {
+		t.Errorf("alert = %q, want the message and error", alerts[0])
+	}
+}
+
+func TestAlerter_SuppressesRepeats(t *testing.T) {
+	a, logger, clock := newTestAlerter(15 * time.Minute)
+
+	for i := 0; i < 100; i++ {
+		logger.Error().Msg("Failed to connect to database")
+		*clock = clock.Add(time.Second)
+	}
+	logger.Error().Msg("Scheduled crawl failed")
+	if alerts := drain(a); len(alerts) != 2 {
+		t.Fatalf("got %d alerts, want one per distinct error: %q", len(alerts), alerts)
+	}
+
+	*clock = clock.Add(15 * time.Minute)
+	logger.Error().Msg("Failed to connect to database")
+	alerts := drain(a)
+	if len(alerts) != 1 || !strings.Contains(alerts[0], "又出现 99 次") {
+		t.Errorf("alerts = %q, want one reporting the 99 suppressed repeats", alerts)
+	}
+}
+
+func TestAlerter_LimitsDistinctErrors(t *testing.T) {
+	a, logger, _ := newTestAlerter(time.Hour)
+	for i := 0; i < 20; i++ {
+		logger.Error().Int("i", i).Msg("error " + strings.Repeat("x", i))
+	}
+	if alerts := drain(a); len(alerts) != alertBurst {
+		t.Errorf("got %d alerts, want the burst of %d", len(alerts), alertBurst)
+	}
+}
+
+func TestAlerter_Run(t *testing.T) {
+	a, logger, _ := newTestAlerter(time.Minute)
+	logger.Error().Msg("Failed to push videos")
+
+	var sent bytes.Buffer
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		a.Run(ctx, func(text string) error {
+			sent.WriteString(text)
+			cancel()
+			return nil
+		})
+		close(done)
+	}()
+	<-done
+
+	if !strings.Contains(sent.String(), "Failed to push videos") {
+		t.Errorf("sent %q, want the queued alert", sent.String())
+	}
+}
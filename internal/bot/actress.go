@@ -0,0 +1,131 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/push"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+// actressProfileVideos is the number of latest releases listed by /actress
+const actressProfileVideos = 5
+
+// actressProfileAliases is the number of aliases listed by /actress
+const actressProfileAliases = 5
+
+// handleActress handles /actress command
+// Shows an actress's stored releases with the cover of the latest one and a subscribe button.
+// Names are resolved against the catalog; unknown names are looked up through aliases.
+func (h *Handler) handleActress(ctx context.Context, chatID int64, args string) {
+	if args == "" {
+		h.sendError(ctx, chatID, "请提供演员名。例如: /actress 三上悠亜")
+		return
+	}
+
+	name := args
+	catalog, err := h.store.GetActressNames(ctx)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get actress catalog")
+		h.sendError(ctx, chatID, "获取演员信息失败，请重试。")
+		return
+	}
+	exact, suggestions := suggestNames(args, catalog, maxSuggestions)
+	if exact != "" {
+		name = exact
+	}
+
+	filter := &store.VideoFilter{Actress: name, Limit: actressProfileVideos}
+	videos, err := h.store.FindVideos(ctx, filter)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("actress", name).Msg("Failed to get actress videos")
+		h.sendError(ctx, chatID, "获取演员信息失败，请重试。")
+		return
+	}
+	if len(videos) == 0 {
+		message := fmt.Sprintf("📭 片库中没有演员: %s", args)
+		if len(suggestions) > 0 {
+			message += "\n你是不是要找: " + strings.Join(suggestions, "、")
+		}
+		if err := h.sendReply(ctx, chatID, message); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send actress not found message")
+		}
+		return
+	}
+
+	total, err := h.store.CountVideosMatching(ctx, filter)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("actress", name).Msg("Failed to count actress videos")
+		h.sendError(ctx, chatID, "获取演员信息失败，请重试。")
+		return
+	}
+	aliases, err := h.store.GetActressAliases(ctx, name)
+	if err != nil {
+		// The profile is still useful without aliases
+		log.Warn().Err(err).Str("actress", name).Msg("Failed to get actress aliases")
+	}
+
+	text := formatActressProfile(name, aliases, total, videos)
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	if data, ok := quickSubscribeCallbackData(model.SubTypeActress, name); ok {
+		keyboard = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔔 订阅 "+name, data)))
+	}
+
+	// The latest cover stands in for a portrait, which the catalog does not store
+	if cover := videos[0].CoverURL; cover != "" {
+		id, err := h.telegram.SendPhotoWithButtons(chatID, cover, text, tgbotapi.ModeMarkdownV2, keyboard)
+		if err == nil {
+			h.autoDelete(ctx, chatID, id)
+			return
+		}
+		log.Warn().Err(err).Int64("chatID", chatID).Str("actress", name).Msg("Failed to send actress cover, falling back to text")
+	}
+	id, err := h.telegram.SendTextWithButtons(chatID, text, tgbotapi.ModeMarkdownV2, keyboard)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send actress profile")
+		return
+	}
+	h.autoDelete(ctx, chatID, id)
+}
+
+// formatActressProfile builds the MarkdownV2 /actress reply, short enough for a photo caption
+func formatActressProfile(name string, aliases []*model.ActressAlias, total int64, videos []*model.Video) string {
+	lines := []string{"👩 *" + push.EscapeMarkdown(name) + "*"}
+
+	var names []string
+	for _, alias := range aliases {
+		if len(names) == actressProfileAliases {
+			break
+		}
+		names = append(names, alias.Alias)
+	}
+	if len(names) > 0 {
+		lines = append(lines, "别名: "+push.EscapeMarkdown(strings.Join(names, ", ")))
+	}
+	lines = append(lines, fmt.Sprintf("📼 收录作品: %d 部", total), "", "*最新作品:*")
+
+	for i, video := range videos {
+		line := fmt.Sprintf("%d\\. %s", i+1, push.EscapeMarkdown(video.Code))
+		if video.DetailURL != "" {
+			line = fmt.Sprintf("%d\\. [%s](%s)", i+1, push.EscapeMarkdown(video.Code), push.EscapeMarkdownURL(video.DetailURL))
+		}
+		if video.Title != "" {
+			title := []rune(video.Title)
+			if len(title) > 30 {
+				title = append(title[:27], []rune("...")...)
+			}
+			line += " " + push.EscapeMarkdown(string(title))
+		}
+		lines = append(lines, line)
+	}
+
+	if total > int64(len(videos)) {
+		lines = append(lines, "", fmt.Sprintf("_使用 /latest %s 2 查看更多_", push.EscapeMarkdown(name)))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestFormatActressProfile(t *testing.T) {
+	aliases := []*model.ActressAlias{{Name: "三上悠亜", Alias: "Yua Mikami"}}
+	videos := []*model.Video{
+		{Code: "SSIS-001", Title: "タイトル", DetailURL: "https://missav.ws/ssis-001"},
+		{Code: "SSIS-002"},
+	}
+
+	text := formatActressProfile("三上悠亜", aliases, 12, videos)
+	for _, want := range []string{
+		"*三上悠亜*",
+		"别名: Yua Mikami",
+		"收录作品: 12 部",
+		"1\\. [SSIS\\-001](https://missav.ws/ssis-001) タイトル",
+		"2\\. SSIS\\-002",
+		"/latest 三上悠亜 2",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("profile missing %q:\n%s", want, text)
+		}
+	}
+
+	// No pagination hint when every stored release is listed
+	if text := formatActressProfile("三上悠亜", nil, 2, videos); strings.Contains(text, "/latest") {
+		t.Errorf("profile has pagination hint for a complete list:\n%s", text)
+	}
+}
@@ -0,0 +1,109 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// Bounds of the autodelete setting
+// Telegram only lets bots delete messages younger than 48 hours.
+const (
+	autoDeleteMin = 10 * time.Second
+	autoDeleteMax = 47 * time.Hour
+)
+
+// parseAutoDelete parses an autodelete setting value: a duration such as 5m, or off
+// Returns false as the second value when the input is not recognized or out of range.
+func parseAutoDelete(value string) (time.Duration, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if enabled, ok := parseToggle(value); ok && !enabled {
+		return 0, true
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl < autoDeleteMin || ttl > autoDeleteMax {
+		return 0, false
+	}
+	return ttl.Truncate(time.Second), true
+}
+
+// autoDeleteLabel describes an autodelete setting in Chinese
+func autoDeleteLabel(seconds int) string {
+	if seconds <= 0 {
+		return "关闭"
+	}
+	return (time.Duration(seconds) * time.Second).String() + " 后删除"
+}
+
+// setAutoDelete handles /settings autodelete <duration>|off, which only applies to groups
+func (h *Handler) setAutoDelete(ctx context.Context, msg *tgbotapi.Message, settings *model.ChatSettings, value string) {
+	chatID := msg.Chat.ID
+	ttl, ok := parseAutoDelete(value)
+	if !ok {
+		h.sendError(ctx, chatID, fmt.Sprintf("用法: /settings %s 5m|off（%s 到 %s）", settingAutoDelete, autoDeleteMin, autoDeleteMax))
+		return
+	}
+	if !isGroupChat(msg.Chat) {
+		h.sendError(ctx, chatID, "该设置仅适用于群组。")
+		return
+	}
+
+	settings.AutoDeleteSeconds = int(ttl / time.Second)
+	if err := h.store.SaveChatSettings(ctx, settings); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to save chat settings")
+		h.sendError(ctx, chatID, "保存设置失败，请重试。")
+		return
+	}
+
+	if err := h.telegram.SendMessage(chatID, "✅ 自动删除回复: "+autoDeleteLabel(settings.AutoDeleteSeconds)); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send settings confirmation")
+	}
+}
+
+// autoDelete schedules the deletion of a bot reply when the chat set an autodelete TTL
+// Scheduled deletions live in memory, so replies pending deletion at shutdown are kept.
+func (h *Handler) autoDelete(ctx context.Context, chatID int64, messageID int) {
+	if chatID >= 0 || messageID == 0 {
+		// Only groups, whose chat IDs are negative, can enable autodelete
+		return
+	}
+	settings, err := h.store.GetChatSettings(ctx, chatID)
+	if err != nil {
+		log.Warn().Err(err).Int64("chatID", chatID).Msg("Failed to get chat settings, keeping reply")
+		return
+	}
+	if settings.AutoDeleteSeconds <= 0 {
+		return
+	}
+
+	time.AfterFunc(time.Duration(settings.AutoDeleteSeconds)*time.Second, func() {
+		if err := h.telegram.DeleteMessage(chatID, messageID); err != nil {
+			log.Warn().Err(err).Int64("chatID", chatID).Int("messageID", messageID).Msg("Failed to auto-delete reply")
+		}
+	})
+}
+
+// sendReply sends a plain text reply that is auto-deleted in groups that ask for it
+func (h *Handler) sendReply(ctx context.Context, chatID int64, text string) error {
+	id, err := h.telegram.SendText(chatID, text, "")
+	if err != nil {
+		return err
+	}
+	h.autoDelete(ctx, chatID, id)
+	return nil
+}
+
+// sendMarkdownReply sends a MarkdownV2 reply that is auto-deleted in groups that ask for it
+func (h *Handler) sendMarkdownReply(ctx context.Context, chatID int64, text string) error {
+	id, err := h.telegram.SendMarkdown(chatID, text)
+	if err != nil {
+		return err
+	}
+	h.autoDelete(ctx, chatID, id)
+	return nil
+}
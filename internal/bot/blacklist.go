@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// blacklistUsage explains the /blacklist command
+const blacklistUsage = "用法:\n/blacklist tag:标签 - 屏蔽标签\n/blacklist actress:演员 - 屏蔽演员\n/blacklist remove tag:标签 - 移除屏蔽\n/blacklist list - 查看黑名单"
+
+// blacklistPrefixes maps /blacklist argument prefixes to entry types
+var blacklistPrefixes = map[string]model.BlacklistType{
+	"tag":     model.BlacklistTag,
+	"actress": model.BlacklistActress,
+	"actor":   model.BlacklistActress,
+}
+
+// parseBlacklistEntry parses a tag:x or actress:y blacklist argument
+// Returns false as the third value when the prefix is unknown or the keyword empty.
+func parseBlacklistEntry(arg string) (model.BlacklistType, string, bool) {
+	prefix, keyword, found := strings.Cut(strings.TrimSpace(arg), ":")
+	if !found {
+		return "", "", false
+	}
+	entryType, ok := blacklistPrefixes[strings.ToLower(strings.TrimSpace(prefix))]
+	keyword = strings.TrimSpace(keyword)
+	if !ok || keyword == "" {
+		return "", "", false
+	}
+	return entryType, keyword, true
+}
+
+// blacklistLabel returns the Chinese name of a blacklist entry type
+func blacklistLabel(entryType model.BlacklistType) string {
+	if entryType == model.BlacklistActress {
+		return "演员"
+	}
+	return "标签"
+}
+
+// handleBlacklist handles /blacklist command
+// Blacklisted tags and actresses are never pushed to the chat, whatever it subscribes to.
+func (h *Handler) handleBlacklist(ctx context.Context, chatID int64, args string) {
+	command, rest, _ := strings.Cut(strings.TrimSpace(args), " ")
+	switch strings.ToLower(command) {
+	case "", "list":
+		h.listBlacklist(ctx, chatID)
+		return
+	case "remove", "rm", "delete", "del":
+		h.removeBlacklistEntry(ctx, chatID, rest)
+		return
+	}
+
+	entryType, keyword, ok := parseBlacklistEntry(args)
+	if !ok {
+		h.sendError(ctx, chatID, blacklistUsage)
+		return
+	}
+
+	entry := &model.BlacklistEntry{ChatID: chatID, Type: entryType, Keyword: keyword}
+	if err := h.store.AddBlacklistEntry(ctx, entry); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("keyword", keyword).Msg("Failed to add blacklist entry")
+		h.sendError(ctx, chatID, "添加黑名单失败，请重试。")
+		return
+	}
+
+	text := fmt.Sprintf("🚫 已屏蔽%s: %s\n本聊天将不再收到相关视频的推送。", blacklistLabel(entryType), keyword)
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send blacklist confirmation")
+	}
+}
+
+// removeBlacklistEntry removes a tag:x or actress:y entry from a chat's blacklist
+func (h *Handler) removeBlacklistEntry(ctx context.Context, chatID int64, arg string) {
+	entryType, keyword, ok := parseBlacklistEntry(arg)
+	if !ok {
+		h.sendError(ctx, chatID, blacklistUsage)
+		return
+	}
+
+	removed, err := h.store.DeleteBlacklistEntry(ctx, chatID, entryType, keyword)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("keyword", keyword).Msg("Failed to delete blacklist entry")
+		h.sendError(ctx, chatID, "移除黑名单失败，请重试。")
+		return
+	}
+
+	text := fmt.Sprintf("✅ 已移除屏蔽%s: %s", blacklistLabel(entryType), keyword)
+	if !removed {
+		text = fmt.Sprintf("ℹ️ %s %s 不在黑名单中。", blacklistLabel(entryType), keyword)
+	}
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send blacklist confirmation")
+	}
+}
+
+// listBlacklist sends a chat's blacklist
+func (h *Handler) listBlacklist(ctx context.Context, chatID int64) {
+	entries, err := h.store.GetBlacklist(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get blacklist")
+		h.sendError(ctx, chatID, "获取黑名单失败，请重试。")
+		return
+	}
+
+	text := "📭 本聊天的黑名单为空。\n\n" + blacklistUsage
+	if len(entries) > 0 {
+		lines := []string{fmt.Sprintf("🚫 黑名单 (%d):", len(entries))}
+		for _, entry := range entries {
+			lines = append(lines, fmt.Sprintf("%s: %s", blacklistLabel(entry.Type), entry.Keyword))
+		}
+		text = strings.Join(lines, "\n")
+	}
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send blacklist")
+	}
+}
@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestParseBlacklistEntry(t *testing.T) {
+	tests := []struct {
+		input     string
+		entryType model.BlacklistType
+		keyword   string
+		ok        bool
+	}{
+		{"tag:VR", model.BlacklistTag, "VR", true},
+		{" Tag : 中出し ", model.BlacklistTag, "中出し", true},
+		{"actress:三上悠亜", model.BlacklistActress, "三上悠亜", true},
+		{"actor:Yua Mikami", model.BlacklistActress, "Yua Mikami", true},
+		{"tag:", "", "", false},
+		{"studio:S1", "", "", false},
+		{"VR", "", "", false},
+	}
+
+	for _, tt := range tests {
+		entryType, keyword, ok := parseBlacklistEntry(tt.input)
+		if entryType != tt.entryType || keyword != tt.keyword || ok != tt.ok {
+			t.Errorf("parseBlacklistEntry(%q) = %q, %q, %v; want %q, %q, %v",
+				tt.input, entryType, keyword, ok, tt.entryType, tt.keyword, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// Inline button callback data
+// Telegram limits callback data to 64 bytes, so keywords are embedded directly
+// and buttons whose data would not fit are left out.
+const (
+	callbackDataLimit       = 64
+	callbackSubscribePrefix = "sub:"
+	callbackSubscribeCancel = "sub:cancel"
+
+	// Quick subscribe buttons sit on listings, so they confirm with a notification
+	// and keep the message instead of replacing it
+	callbackQuickSubscribePrefix = "q" + callbackSubscribePrefix
+
+	callbackUnsubscribeAll    = "unsub:all"
+	callbackUnsubscribeCancel = "unsub:cancel"
+)
+
+// unsubscribeConfirmArg confirms /unsubscribe (all) without the inline buttons
+const unsubscribeConfirmArg = "confirm"
+
+// subscribeCallbackTypes maps the subscription types offered via buttons to their callback codes
+var subscribeCallbackTypes = map[model.SubscriptionType]string{
+	model.SubTypeActress: "a",
+	model.SubTypeTag:     "t",
+}
+
+// subscribeCallbackData encodes a subscription confirmation button
+// Returns false when the encoded data exceeds Telegram's limit
+func subscribeCallbackData(subType model.SubscriptionType, keyword string) (string, bool) {
+	code, ok := subscribeCallbackTypes[subType]
+	if !ok {
+		return "", false
+	}
+	data := callbackSubscribePrefix + code + ":" + keyword
+	if len(data) > callbackDataLimit {
+		return "", false
+	}
+	return data, true
+}
+
+// quickSubscribeCallbackData encodes a quick subscribe button
+// Returns false when the encoded data exceeds Telegram's limit
+func quickSubscribeCallbackData(subType model.SubscriptionType, keyword string) (string, bool) {
+	data, ok := subscribeCallbackData(subType, keyword)
+	data = "q" + data
+	if !ok || len(data) > callbackDataLimit {
+		return "", false
+	}
+	return data, true
+}
+
+// parseSubscribeCallbackData decodes a subscription confirmation button
+func parseSubscribeCallbackData(data string) (model.SubscriptionType, string, bool) {
+	rest, ok := strings.CutPrefix(data, callbackSubscribePrefix)
+	if !ok {
+		return "", "", false
+	}
+	code, keyword, ok := strings.Cut(rest, ":")
+	if !ok || keyword == "" {
+		return "", "", false
+	}
+	for subType, c := range subscribeCallbackTypes {
+		if c == code {
+			return subType, keyword, true
+		}
+	}
+	return "", "", false
+}
+
+// handleCallback processes inline keyboard button presses
+// Panics are recovered and reported like those of commands.
+func (h *Handler) handleCallback(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	if query.Message == nil {
+		return
+	}
+	chatID := query.Message.Chat.ID
+	messageID := query.Message.MessageID
+	defer h.recoverUpdate(ctx, chatID, "callback", query.Data)
+
+	log.Info().
+		Int64("chatID", chatID).
+		Str("data", query.Data).
+		Msg("Received callback")
+
+	// Buttons that change subscriptions follow the same rules as the commands that sent them
+	if query.Data != callbackSubscribeCancel && query.Data != callbackUnsubscribeCancel &&
+		(strings.HasPrefix(query.Data, callbackSubscribePrefix) || strings.HasPrefix(query.Data, callbackQuickSubscribePrefix) ||
+			query.Data == callbackUnsubscribeAll) {
+		allowed, err := h.canManageChat(ctx, query.Message.Chat, query.From, nil)
+		if err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to check chat permissions")
+			h.answerCallback(query, "检查权限失败，请重试。")
+			return
+		}
+		if !allowed {
+			h.answerCallback(query, "仅群管理员可管理订阅。")
+			return
+		}
+	}
+
+	switch {
+	case query.Data == callbackUnsubscribeAll:
+		message, err := h.unsubscribeAll(ctx, chatID)
+		if err != nil {
+			h.answerCallback(query, "取消订阅失败，请重试。")
+			return
+		}
+		h.answerCallback(query, "")
+		h.editMessage(chatID, messageID, message)
+	case query.Data == callbackUnsubscribeCancel:
+		h.answerCallback(query, "")
+		h.editMessage(chatID, messageID, "已保留所有订阅。")
+	case query.Data == callbackSubscribeCancel:
+		h.answerCallback(query, "")
+		h.editMessage(chatID, messageID, "已取消订阅。")
+	case strings.HasPrefix(query.Data, callbackQuickSubscribePrefix):
+		subType, keyword, ok := parseSubscribeCallbackData(strings.TrimPrefix(query.Data, "q"))
+		if !ok {
+			h.answerCallback(query, "无效的操作。")
+			return
+		}
+		sub := &model.Subscription{ChatID: chatID, ChatType: query.Message.Chat.Type, Type: subType, Keyword: keyword}
+		message, err := h.subscribe(ctx, sub)
+		if err != nil {
+			h.answerCallback(query, "创建订阅失败，请重试。")
+			return
+		}
+		h.answerCallback(query, message)
+		h.previewSubscription(ctx, sub)
+	case strings.HasPrefix(query.Data, callbackTagLatestPrefix):
+		h.answerCallback(query, "")
+		// The explicit page keeps numeric tags from being read as a page number
+		h.handleLatest(ctx, chatID, "#"+strings.TrimPrefix(query.Data, callbackTagLatestPrefix)+" 1")
+	case strings.HasPrefix(query.Data, callbackSubscribePrefix):
+		subType, keyword, ok := parseSubscribeCallbackData(query.Data)
+		if !ok {
+			h.answerCallback(query, "无效的操作。")
+			return
+		}
+		sub := &model.Subscription{ChatID: chatID, ChatType: query.Message.Chat.Type, Type: subType, Keyword: keyword}
+		message, err := h.subscribe(ctx, sub)
+		if err != nil {
+			h.answerCallback(query, "创建订阅失败，请重试。")
+			return
+		}
+		h.answerCallback(query, "")
+		h.editMessage(chatID, messageID, message)
+		h.previewSubscription(ctx, sub)
+	default:
+		h.answerCallback(query, "")
+	}
+}
+
+// answerCallback acknowledges a callback query so the client stops its loading indicator
+func (h *Handler) answerCallback(query *tgbotapi.CallbackQuery, text string) {
+	if err := h.telegram.AnswerCallback(query.ID, text); err != nil {
+		log.Error().Err(err).Str("callbackID", query.ID).Msg("Failed to answer callback")
+	}
+}
+
+// editMessage replaces the text of an earlier bot message
+func (h *Handler) editMessage(chatID int64, messageID int, text string) {
+	if err := h.telegram.EditMessageText(chatID, messageID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to edit message")
+	}
+}
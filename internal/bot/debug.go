@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/push"
+)
+
+// debugReport is the runtime state shown by /debug
+type debugReport struct {
+	goroutines int
+	mem        runtime.MemStats
+	db         sql.DBStats
+	// browser is nil when the crawler has no headless browser
+	browser *crawler.BrowserHealth
+	// hasLimiter is false when the crawler does not report its rate limiter
+	hasLimiter  bool
+	limit       float64
+	limiterWait time.Duration
+	// unpushed and pending are -1 when they could not be counted
+	unpushed int64
+	pending  int64
+}
+
+// handleDebug handles /debug command, reporting the process internals for troubleshooting
+func (h *Handler) handleDebug(ctx context.Context, chatID int64) {
+	report := debugReport{
+		goroutines: runtime.NumGoroutine(),
+		db:         h.store.DBStats(),
+		unpushed:   -1,
+		pending:    -1,
+	}
+	runtime.ReadMemStats(&report.mem)
+
+	if reporter, ok := h.crawler.(crawler.BrowserHealthReporter); ok {
+		health := reporter.BrowserHealth()
+		report.browser = &health
+	}
+	if reporter, ok := h.crawler.(crawler.LimiterReporter); ok {
+		report.hasLimiter = true
+		report.limit, report.limiterWait = reporter.LimiterStatus()
+	}
+	if count, err := h.store.CountUnpushedVideos(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to count unpushed videos")
+	} else {
+		report.unpushed = count
+	}
+	if count, err := h.store.CountPendingPushes(ctx, push.MaxPushAttempts); err != nil {
+		log.Error().Err(err).Msg("Failed to count pending pushes")
+	} else {
+		report.pending = count
+	}
+
+	if _, err := h.telegram.SendMarkdown(chatID, formatDebugReport(&report, time.Now())); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send debug report")
+	}
+}
+
+// formatDebugReport renders a debug report as MarkdownV2
+func formatDebugReport(report *debugReport, now time.Time) string {
+	lines := []string{
+		"🛠 *运行时诊断*\n",
+		fmt.Sprintf("🧵 Goroutine: %d", report.goroutines),
+		push.EscapeMarkdown(fmt.Sprintf("💾 堆内存: 使用中 %s, 对象 %d 个, 系统分配 %s",
+			formatMiB(report.mem.HeapInuse), report.mem.HeapObjects, formatMiB(report.mem.Sys))),
+	}
+
+	gc := fmt.Sprintf("♻️ GC: %d 次, 累计暂停 %s", report.mem.NumGC, time.Duration(report.mem.PauseTotalNs).Round(time.Microsecond))
+	if report.mem.NumGC > 0 {
+		last := now.Sub(time.Unix(0, int64(report.mem.LastGC)))
+		gc += fmt.Sprintf(", 上次 %s 前", last.Round(time.Second))
+	}
+	lines = append(lines, push.EscapeMarkdown(gc))
+
+	maxOpen := "不限"
+	if report.db.MaxOpenConnections > 0 {
+		maxOpen = fmt.Sprint(report.db.MaxOpenConnections)
+	}
+	lines = append(lines, push.EscapeMarkdown(fmt.Sprintf("🗄 数据库连接: 打开 %d/%s, 使用中 %d, 空闲 %d, 等待 %d 次",
+		report.db.OpenConnections, maxOpen, report.db.InUse, report.db.Idle, report.db.WaitCount)))
+
+	if report.browser != nil {
+		lines = append(lines, push.EscapeMarkdown("🌐 浏览器: "+formatBrowserHealth(report.browser)))
+	}
+	if report.hasLimiter {
+		token := "令牌可用"
+		if report.limiterWait > 0 {
+			token = fmt.Sprintf("下个令牌 %s 后", report.limiterWait.Round(time.Millisecond))
+		}
+		lines = append(lines, push.EscapeMarkdown(fmt.Sprintf("🚦 爬虫限速: %.2f 次/秒, %s", report.limit, token)))
+	}
+
+	lines = append(lines, fmt.Sprintf("📬 队列: 待推送视频 %s, 推送发件箱 %s",
+		formatDebugCount(report.unpushed), formatDebugCount(report.pending)))
+	return strings.Join(lines, "\n")
+}
+
+// formatBrowserHealth summarizes the headless browser's state
+func formatBrowserHealth(health *crawler.BrowserHealth) string {
+	if !health.Running {
+		return "未启动"
+	}
+
+	state := "正常"
+	if !health.Healthy {
+		state = "异常"
+		if health.Error != "" {
+			state += " (" + health.Error + ")"
+		}
+	}
+	if health.Remote {
+		state += ", 远程"
+	}
+	state += fmt.Sprintf(", 已渲染 %d 页, 运行 %s", health.PagesServed, health.Age.Round(time.Second))
+	if health.Navigating != "" {
+		state += ", 正在加载 " + health.Navigating
+	}
+	return state
+}
+
+// formatMiB formats a byte count in mebibytes
+func formatMiB(bytes uint64) string {
+	return fmt.Sprintf("%.1f MiB", float64(bytes)/(1<<20))
+}
+
+// formatDebugCount formats a queue depth, or a question mark when it could not be counted
+func formatDebugCount(count int64) string {
+	if count < 0 {
+		return "?"
+	}
+	return fmt.Sprint(count)
+}
@@ -0,0 +1,43 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/crawler"
+)
+
+func TestFormatDebugReport(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	report := &debugReport{
+		goroutines:  42,
+		browser:     &crawler.BrowserHealth{Running: true, Healthy: true, PagesServed: 7, Age: time.Hour},
+		hasLimiter:  true,
+		limit:       0.5,
+		limiterWait: 1500 * time.Millisecond,
+		unpushed:    3,
+		pending:     -1,
+	}
+	report.mem.HeapInuse = 3 << 20
+	report.mem.NumGC = 2
+	report.mem.LastGC = uint64(now.Add(-time.Minute).UnixNano())
+	report.db.OpenConnections = 2
+	report.db.MaxOpenConnections = 10
+
+	text := formatDebugReport(report, now)
+	for _, want := range []string{
+		"Goroutine: 42",
+		"使用中 3\\.0 MiB",
+		"GC: 2 次",
+		"上次 1m0s 前",
+		"打开 2/10",
+		"浏览器: 正常, 已渲染 7 页, 运行 1h0m0s",
+		"0\\.50 次/秒, 下个令牌 1\\.5s 后",
+		"待推送视频 3, 推送发件箱 ?",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+}
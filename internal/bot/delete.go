@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// deletePurgeFlag asks /delete to also delete the messages the video was pushed as
+const deletePurgeFlag = "purge"
+
+// parseDeleteArgs parses /delete arguments: a code followed by an optional purge flag
+// The code is returned in canonical form, empty when none was given.
+func parseDeleteArgs(args string) (code string, purge bool) {
+	var rest []string
+	for _, field := range strings.Fields(args) {
+		switch strings.ToLower(field) {
+		case deletePurgeFlag, "清除":
+			purge = true
+		default:
+			rest = append(rest, field)
+		}
+	}
+	return model.CanonicalCode(crawler.ExtractCode(strings.Join(rest, " "))), purge
+}
+
+// handleDelete handles /delete command, for takedown requests and mis-parsed videos
+// It soft-deletes every video of a code and bans the code so later crawls do not save
+// it again. With purge, the messages it was pushed as are deleted too.
+func (h *Handler) handleDelete(ctx context.Context, chatID int64, userID int64, args string) {
+	code, purge := parseDeleteArgs(args)
+	if code == "" {
+		h.sendError(ctx, chatID, fmt.Sprintf("请提供番号。例如: /delete ABC-123 或 /delete ABC-123 %s（同时删除已推送的消息）", deletePurgeFlag))
+		return
+	}
+
+	deleted, err := h.store.DeleteVideos(ctx, code, userID)
+	if err != nil {
+		log.Error().Err(err).Str("code", code).Msg("Failed to delete video")
+		h.sendError(ctx, chatID, "删除失败，请重试。")
+		return
+	}
+	log.Info().Str("code", code).Int64("deleted", deleted).Int64("userID", userID).Msg("Deleted and banned video")
+
+	text := fmt.Sprintf("🗑 已删除 %s 的 %d 条视频记录，之后的爬取不会再保存该番号。", code, deleted)
+	if purge {
+		removed, failed, err := h.pushService.DeletePushedMessages(ctx, code)
+		if err != nil {
+			log.Error().Err(err).Str("code", code).Msg("Failed to delete pushed messages")
+			text += "\n⚠️ 删除已推送的消息失败，可稍后使用 /revoke 重试。"
+		} else {
+			text += fmt.Sprintf("\n已删除 %d 条推送消息。", removed)
+			if failed > 0 {
+				text += fmt.Sprintf("\n⚠️ %d 条推送删除失败（机器人无删除权限或消息已超过 48 小时）。", failed)
+			}
+		}
+	}
+
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send delete result")
+	}
+}
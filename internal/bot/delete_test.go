@@ -0,0 +1,25 @@
+package bot
+
+import "testing"
+
+func TestParseDeleteArgs(t *testing.T) {
+	tests := []struct {
+		args      string
+		wantCode  string
+		wantPurge bool
+	}{
+		{"", "", false},
+		{"abc-123", "ABC-123", false},
+		{"ABC-123 purge", "ABC-123", true},
+		{"PURGE ssis-001-uncensored-leak", "SSIS-001", true},
+		{"ABC-123 清除", "ABC-123", true},
+		{"purge", "", true},
+	}
+
+	for _, tt := range tests {
+		code, purge := parseDeleteArgs(tt.args)
+		if code != tt.wantCode || purge != tt.wantPurge {
+			t.Errorf("parseDeleteArgs(%q) = (%q, %v), want (%q, %v)", tt.args, code, purge, tt.wantCode, tt.wantPurge)
+		}
+	}
+}
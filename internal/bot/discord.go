@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/push"
+)
+
+// discordUsage describes the /discord command
+const discordUsage = "用法:\n/discord Webhook地址 [演员名|#标签] - 订阅到 Discord 频道\n/discord remove Webhook地址 - 删除该频道的所有订阅\n/discord list - 查看 Discord 订阅"
+
+// handleDiscord handles /discord command (admin only)
+// Discord channels are subscribed through their webhook URL, whose numeric ID
+// becomes the subscription's chat ID.
+func (h *Handler) handleDiscord(ctx context.Context, chatID int64, args string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		h.sendError(ctx, chatID, discordUsage)
+		return
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case "list":
+		h.listDiscordSubscriptions(ctx, chatID)
+	case "remove":
+		if len(fields) != 2 {
+			h.sendError(ctx, chatID, discordUsage)
+			return
+		}
+		webhookID, err := push.DiscordWebhookID(fields[1])
+		if err != nil {
+			h.sendError(ctx, chatID, "无效的 Discord Webhook 地址。")
+			return
+		}
+		removed, err := h.store.DeleteAllSubscriptions(ctx, webhookID)
+		if err != nil {
+			log.Error().Err(err).Int64("webhookID", webhookID).Msg("Failed to remove Discord subscriptions")
+			h.sendError(ctx, chatID, "删除订阅失败，请重试。")
+			return
+		}
+		if err := h.telegram.SendMessage(chatID, fmt.Sprintf("✅ 已删除 Discord 频道 %d 的 %d 个订阅。", webhookID, removed)); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send Discord removal confirmation")
+		}
+	default:
+		webhookURL := fields[0]
+		webhookID, err := push.DiscordWebhookID(webhookURL)
+		if err != nil {
+			h.sendError(ctx, chatID, "无效的 Discord Webhook 地址。\n\n"+discordUsage)
+			return
+		}
+
+		subType, keyword := DetermineSubscriptionType(strings.TrimSpace(strings.TrimPrefix(args, webhookURL)))
+		sub := &model.Subscription{
+			ChatID:   webhookID,
+			ChatType: model.PlatformDiscord,
+			Type:     subType,
+			Keyword:  keyword,
+			Enabled:  true,
+			Platform: model.PlatformDiscord,
+			Target:   webhookURL,
+		}
+		if err := h.store.CreateSubscription(ctx, sub); err != nil {
+			log.Error().Err(err).Int64("webhookID", webhookID).Msg("Failed to create Discord subscription")
+			h.sendError(ctx, chatID, "创建订阅失败，请重试。")
+			return
+		}
+
+		target := "所有新视频"
+		switch subType {
+		case model.SubTypeActress:
+			target = "演员: " + keyword
+		case model.SubTypeTag:
+			target = "标签: #" + keyword
+		}
+		if err := h.telegram.SendMessage(chatID, fmt.Sprintf("✅ Discord 频道 %d 已订阅%s\n\n⚠️ Webhook 地址包含密钥，建议删除上面的命令消息。", webhookID, target)); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send Discord subscription confirmation")
+		}
+	}
+}
+
+// listDiscordSubscriptions lists subscriptions delivered to Discord
+// Webhook URLs contain a secret token, so only their IDs are shown.
+func (h *Handler) listDiscordSubscriptions(ctx context.Context, chatID int64) {
+	subs, err := h.store.GetAllSubscriptions(ctx)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get subscriptions")
+		h.sendError(ctx, chatID, "获取订阅失败，请重试。")
+		return
+	}
+
+	lines := []string{"📋 Discord 订阅:"}
+	for _, sub := range subs {
+		if sub.NotifyPlatform() != model.PlatformDiscord {
+			continue
+		}
+		switch sub.Type {
+		case model.SubTypeAll:
+			lines = append(lines, fmt.Sprintf("• %d: 所有新视频", sub.ChatID))
+		case model.SubTypeActress:
+			lines = append(lines, fmt.Sprintf("• %d: 演员 %s", sub.ChatID, sub.Keyword))
+		case model.SubTypeTag:
+			lines = append(lines, fmt.Sprintf("• %d: 标签 #%s", sub.ChatID, sub.Keyword))
+		}
+	}
+	if len(lines) == 1 {
+		lines = []string{"📭 暂无 Discord 订阅。"}
+	}
+	if err := h.telegram.SendMessage(chatID, strings.Join(lines, "\n")); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send Discord subscription list")
+	}
+}
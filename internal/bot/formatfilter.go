@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// minDurationMax bounds the minduration setting, in minutes
+const minDurationMax = 300
+
+// parseMinDuration parses a minduration setting value: minutes, clip for
+// model.ClipMaxDuration, or off
+// Returns false as the second value when the input is not recognized or out of range.
+func parseMinDuration(value string) (int, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if enabled, ok := parseToggle(value); ok && !enabled {
+		return 0, true
+	}
+	if value == "clip" || value == "clips" || value == "片段" {
+		return model.ClipMaxDuration, true
+	}
+	minutes, err := strconv.Atoi(strings.TrimSuffix(value, "m"))
+	if err != nil || minutes < 1 || minutes > minDurationMax {
+		return 0, false
+	}
+	return minutes, true
+}
+
+// minDurationLabel describes a minduration setting in Chinese
+func minDurationLabel(minutes int) string {
+	if minutes <= 0 {
+		return "关闭"
+	}
+	return fmt.Sprintf("跳过短于 %d 分钟的视频", minutes)
+}
+
+// setSkipVR handles /settings skipvr on|off
+func (h *Handler) setSkipVR(ctx context.Context, chatID int64, settings *model.ChatSettings, value string) {
+	enabled, ok := parseToggle(value)
+	if !ok {
+		h.sendError(ctx, chatID, fmt.Sprintf("用法: /settings %s on|off", settingSkipVR))
+		return
+	}
+
+	settings.SkipVR = enabled
+	if err := h.store.SaveChatSettings(ctx, settings); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to save chat settings")
+		h.sendError(ctx, chatID, "保存设置失败，请重试。")
+		return
+	}
+
+	message := "✅ VR 作品将正常推送。"
+	if enabled {
+		message = "✅ 已开启：不再推送 VR 作品。"
+	}
+	if err := h.telegram.SendMessage(chatID, message); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send settings confirmation")
+	}
+}
+
+// setMinDuration handles /settings minduration <minutes>|clip|off
+func (h *Handler) setMinDuration(ctx context.Context, chatID int64, settings *model.ChatSettings, value string) {
+	minutes, ok := parseMinDuration(value)
+	if !ok {
+		h.sendError(ctx, chatID, fmt.Sprintf("用法: /settings %s 1-%d|clip|off（分钟，clip 为 %d 分钟）", settingMinDuration, minDurationMax, model.ClipMaxDuration))
+		return
+	}
+
+	settings.MinDuration = minutes
+	if err := h.store.SaveChatSettings(ctx, settings); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to save chat settings")
+		h.sendError(ctx, chatID, "保存设置失败，请重试。")
+		return
+	}
+
+	if err := h.telegram.SendMessage(chatID, "✅ 最短时长: "+minDurationLabel(minutes)); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send settings confirmation")
+	}
+}
@@ -2,6 +2,7 @@ package bot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/rs/zerolog/log"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/user/missav-bot-go/internal/config"
 	"github.com/user/missav-bot-go/internal/crawler"
 	"github.com/user/missav-bot-go/internal/model"
 	"github.com/user/missav-bot-go/internal/push"
@@ -21,22 +23,78 @@ type Handler struct {
 	crawler     crawler.Crawler
 	pushService *push.Service
 	telegram    *Client
+	botID       int64 // Telegram user ID of the bot, stored on the subscriptions it serves
+	config      *config.BotConfig
+	throttle    *commandThrottle
+	users       *userActivity
+	offset      updateOffset
+	schedule    SchedulerControl // optional
 	startTime   time.Time
+	jobs        jobRegistry // queued and running /crawl, /import and /refresh jobs
+}
+
+// SchedulerControl reports on and controls the crawl scheduler
+type SchedulerControl interface {
+	NextRun() time.Time
+	IsRunning() bool
+	IsPaused() bool
+	Pause() bool
+	Resume() bool
+	RunNow(ctx context.Context) bool
+}
+
+// SetScheduler makes /status report the next scheduled crawl and enables /scheduler
+func (h *Handler) SetScheduler(scheduler SchedulerControl) {
+	h.schedule = scheduler
 }
 
 // NewHandler creates a new command handler
-func NewHandler(store store.Store, crawler crawler.Crawler, pushService *push.Service, telegram *Client) *Handler {
-	return &Handler{
+func NewHandler(store store.Store, crawler crawler.Crawler, pushService *push.Service, telegram *Client, cfg *config.BotConfig) *Handler {
+	var throttle *commandThrottle
+	if cfg != nil {
+		throttle = newCommandThrottle(cfg.CommandRateLimit)
+	}
+	var botID int64
+	if telegram != nil {
+		botID = telegram.BotID()
+	}
+	h := &Handler{
 		store:       store,
 		crawler:     crawler,
 		pushService: pushService,
 		telegram:    telegram,
+		botID:       botID,
+		config:      cfg,
+		throttle:    throttle,
+		users:       newUserActivity(userTouchInterval),
 		startTime:   time.Now(),
 	}
+	h.jobs.panicked = func(job *crawlJob) {
+		h.sendError(context.Background(), job.chatID, fmt.Sprintf("爬取任务 #%d 执行出错，已终止。", job.id))
+	}
+	return h
 }
 
 // HandleUpdate processes an incoming Telegram update
+// Each update runs under BOT_UPDATE_TIMEOUT, so a stuck store or crawler call cannot hold up the update loop.
 func (h *Handler) HandleUpdate(ctx context.Context, update tgbotapi.Update) {
+	ctx = context.WithValue(ctx, updateRootKey{}, ctx)
+	if h.config != nil && h.config.UpdateTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.config.UpdateTimeout)
+		defer cancel()
+		defer func() {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				log.Warn().Int("updateID", update.UpdateID).Dur("timeout", h.config.UpdateTimeout).Msg("Update handling timed out")
+			}
+		}()
+	}
+
+	if update.CallbackQuery != nil {
+		h.handleCallback(ctx, update.CallbackQuery)
+		return
+	}
+
 	if update.Message == nil {
 		return
 	}
@@ -45,6 +103,8 @@ func (h *Handler) HandleUpdate(ctx context.Context, update tgbotapi.Update) {
 	chatID := msg.Chat.ID
 	chatType := msg.Chat.Type
 
+	h.touchUser(ctx, msg.From)
+
 	// Handle commands
 	if msg.IsCommand() {
 		h.handleCommand(ctx, msg)
@@ -57,42 +117,136 @@ func (h *Handler) HandleUpdate(ctx context.Context, update tgbotapi.Update) {
 	}
 }
 
+type updateRootKey struct{}
+
+// detachUpdate returns a context for work that outlives the update being handled, such as /crawl
+// It keeps ctx's values but not the per-update deadline, and is still canceled when the update loop's context is.
+func detachUpdate(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	root, ok := ctx.Value(updateRootKey{}).(context.Context)
+	if !ok {
+		return detached, cancel
+	}
+	stop := context.AfterFunc(root, cancel)
+	return detached, func() {
+		stop()
+		cancel()
+	}
+}
 
-// handleCommand routes commands to their respective handlers
+// handleCommand runs a command message through the command middleware chain
 func (h *Handler) handleCommand(ctx context.Context, msg *tgbotapi.Message) {
-	chatID := msg.Chat.ID
-	chatType := msg.Chat.Type
-	command := msg.Command()
-	args := strings.TrimSpace(msg.CommandArguments())
+	h.commandChain()(ctx, newCommandRequest(msg))
+}
 
-	log.Info().
-		Int64("chatID", chatID).
-		Str("command", command).
-		Str("args", args).
-		Msg("Received command")
+// routeCommand dispatches a command to its handler
+// Access checks have already run in authorizeCommands.
+func (h *Handler) routeCommand(ctx context.Context, req *commandRequest) {
+	chatID, args := req.chatID, req.args
 
-	switch command {
+	switch req.command {
 	case "start", "help":
 		h.handleStart(ctx, chatID)
 	case "subscribe":
-		h.handleSubscribe(ctx, chatID, chatType, args)
+		h.handleSubscribe(ctx, chatID, req.chatType, req.userID, args)
 	case "unsubscribe":
-		h.handleUnsubscribe(ctx, chatID, args)
+		h.handleUnsubscribe(ctx, chatID, req.chatType, req.userID, args)
 	case "list":
 		h.handleList(ctx, chatID)
+	case "testsub":
+		h.handleTestSub(ctx, chatID, args)
 	case "search":
-		h.handleSearch(ctx, chatID, args)
+		h.replyWithResults(ctx, req, func(chatID int64) { h.handleSearch(ctx, chatID, args) })
 	case "latest":
-		h.handleLatest(ctx, chatID, args)
+		h.replyWithResults(ctx, req, func(chatID int64) { h.handleLatest(ctx, chatID, args) })
+	case "detail":
+		h.handleDetail(ctx, chatID, args)
+	case "similar":
+		h.handleSimilar(ctx, chatID, args)
+	case "actress":
+		h.handleActress(ctx, chatID, args)
+	case "tags":
+		h.handleTags(ctx, chatID, args)
+	case "history":
+		h.handleHistory(ctx, chatID, args)
+	case "mute":
+		h.handleMute(ctx, chatID, args)
+	case "unmute":
+		h.handleUnmute(ctx, chatID, args)
+	case "watch":
+		h.handleWatch(ctx, chatID, req.userID, args)
+	case "unwatch":
+		h.handleUnwatch(ctx, chatID, args)
+	case "blacklist":
+		h.handleBlacklist(ctx, chatID, args)
 	case "crawl":
-		h.handleCrawl(ctx, chatID, chatType, args)
+		h.handleCrawl(ctx, chatID, req.chatType, args, h.isAdmin(req.msg))
+	case "import":
+		h.handleImport(ctx, req.msg, args)
 	case "status":
 		h.handleStatus(ctx, chatID)
+	case "me":
+		h.handleMe(ctx, req.msg)
+	case "settings":
+		h.handleSettings(ctx, req.msg, args)
+	case "crawllog":
+		h.handleCrawlLog(ctx, chatID, args)
+	case "alias":
+		h.handleAlias(ctx, chatID, args)
+	case "duplicates":
+		h.handleDuplicates(ctx, chatID, args)
+	case "discord":
+		h.handleDiscord(ctx, chatID, args)
+	case "revoke":
+		h.handleRevoke(ctx, chatID, args)
+	case "delete":
+		h.handleDelete(ctx, chatID, req.userID, args)
+	case "refresh":
+		h.handleRefresh(ctx, chatID, args)
+	case "scheduler":
+		h.handleScheduler(ctx, chatID, args)
+	case "debug":
+		h.handleDebug(ctx, chatID)
+	case "loglevel":
+		h.handleLogLevel(ctx, chatID, args)
+	case "selftest":
+		h.handleSelftest(ctx, chatID)
 	default:
-		h.sendError(chatID, "未知命令。使用 /help 查看可用命令。")
+		req.label = unknownCommandLabel
+		h.sendError(ctx, chatID, "未知命令。使用 /help 查看可用命令。")
 	}
 }
 
+// allowCommand applies the per-user command throttle
+// Admins are exempt. The first rejected command gets a warning; later ones are dropped silently.
+func (h *Handler) allowCommand(msg *tgbotapi.Message) bool {
+	if h.isAdmin(msg) {
+		return true
+	}
+
+	var userID int64
+	if msg.From != nil {
+		userID = msg.From.ID
+	}
+	allowed, warn := h.throttle.Allow(msg.Chat.ID, userID, time.Now())
+	if allowed {
+		return true
+	}
+
+	commandsTotal.WithLabelValues(throttledCommandLabel, string(model.CommandOutcomeThrottled)).Inc()
+	log.Warn().
+		Int64("chatID", msg.Chat.ID).
+		Int64("userID", userID).
+		Str("command", msg.Command()).
+		Msg("Command throttled")
+	if warn {
+		if err := h.telegram.SendMessage(msg.Chat.ID, "⏳ 命令发送过于频繁，请稍后再试。"); err != nil {
+			log.Error().Err(err).Int64("chatID", msg.Chat.ID).Msg("Failed to send throttle warning")
+		}
+	}
+	return false
+}
+
 // handleStart handles /start and /help commands (Requirement 3.1)
 func (h *Handler) handleStart(ctx context.Context, chatID int64) {
 	helpText := `🤖 *MissAV 机器人帮助*
@@ -101,21 +255,62 @@ func (h *Handler) handleStart(ctx context.Context, chatID int64) {
 /subscribe \- 订阅所有新视频
 /subscribe 演员名 \- 订阅特定演员
 /subscribe \#标签 \- 订阅特定标签
-/unsubscribe \- 取消所有订阅
+/unsubscribe \- 取消所有订阅（需确认）
 /unsubscribe 关键词 \- 取消特定订阅
+/subscribe personal \[dm\] 关键词 \- 群组中仅为自己订阅，推送时@你或私聊发送
+/unsubscribe personal 关键词 \- 取消自己的个人订阅
 /list \- 查看我的订阅
+/testsub 演员名 或 \#标签 \- 测试订阅会匹配最近哪些视频，不会创建订阅
+/me \- 查看我的账户、私聊订阅和推送统计
+/settings \- 查看聊天设置
+/settings adminonly on\|off \- 群组中仅管理员可管理订阅
+/settings pushmode auto\|single\|batch \- 推送方式：自动、逐条或合并为列表
+/settings parsemode default\|markdown\|html \- 推送消息格式
+/settings dmresults on\|off \- 群组中 /search 和 /latest 结果私聊发送
+/settings autodelete 5m\|off \- 群组中自动删除搜索结果和错误提示
+/settings searchlimit 20\|default \- 每次搜索返回的结果数量
+/settings latestsize 10\|default \- /latest 每页显示的视频数量
+/settings skipvr on\|off \- 不推送 VR 作品
+/settings minduration 30\|clip\|off \- 不推送短于指定分钟数的视频或片段
+/settings recap on\|off \- 每周回顾收到和错过的视频
 
 *搜索命令:*
-/search 关键词 \- 搜索视频（最多10条）
+/search 关键词 \- 搜索视频
+/search actress:演员 tag:标签 min:分钟 sort:new \- 组合条件搜索
 /latest \[页码\] \- 查看最新视频
+/latest \#标签 或 演员名 \[页码\] \- 按标签或演员浏览
+/detail 番号 \- 查看视频详情和预览图
+/similar 番号 \- 推荐演员或标签相同的视频
+/actress 演员名 \- 查看演员资料和最新作品
+/tags \[数量\] \- 查看热门标签，点击浏览或订阅
+/history \[条数\] \- 查看本聊天最近收到的推送
+/mute \[番号\] \- 不再推送某番号到本聊天（不带番号查看列表）
+/unmute 番号 \- 恢复推送某番号
+/watch \[番号\] \- 番号上线后立即推送到本聊天（不带番号查看列表）
+/unwatch 番号 \- 取消关注某番号
+/blacklist tag:标签 或 actress:演员 \- 不推送含该标签或演员的视频
+/blacklist list\|remove tag:标签 \- 查看或移除黑名单
 
 *管理命令:*
-/crawl actor/code/search 关键词 \- 手动爬取
+/crawl actor/code/search/tag 关键词 \- 手动爬取
+/crawl status\|cancel \[编号\] \- 查看或取消正在运行的爬取和导入
+/import 番号列表 \- 批量爬取并保存番号，也可回复番号列表消息或 \.txt 文件
 /status \- 查看机器人状态
+/crawllog \[条数\] \- 查看爬取历史
+/alias 演员 \= 别名 \- 添加演员别名（罗马字/拼音/英文名）
+/duplicates \[merge\|dismiss 编号\] \- 审核疑似重复视频
+/discord Webhook地址 \[演员名\|\#标签\] \- 推送到 Discord 频道
+/revoke 番号 \- 撤回该番号已推送的消息并不再推送
+/delete 番号 \[purge\] \- 删除该番号并禁止再次保存，purge 同时删除已推送的消息
+/refresh 番号 \- 重新爬取详情页并更新视频信息
+/scheduler \[pause\|resume\|run\] \- 暂停、恢复定时爬取或立即爬取一次
+/debug \- 查看运行时诊断信息
+/loglevel \[trace\|debug\|info\|warn\|error\] \- 查看或临时调整日志级别
+/selftest \- 自检数据库、爬虫、解析和消息发送
 
 _提示: 在群组中，机器人会自动订阅所有视频_`
 
-	if err := h.telegram.SendMarkdown(chatID, helpText); err != nil {
+	if _, err := h.telegram.SendMarkdown(chatID, helpText); err != nil {
 		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send help message")
 	}
 }
@@ -142,50 +337,150 @@ func DetermineSubscriptionType(args string) (model.SubscriptionType, string) {
 }
 
 // handleSubscribe handles /subscribe command (Requirements 3.2, 3.3, 3.4)
-func (h *Handler) handleSubscribe(ctx context.Context, chatID int64, chatType string, args string) {
+// Actress and tag keywords unknown to the catalog are confirmed through
+// inline "did you mean" buttons before the subscription is created
+// In groups, /subscribe personal [dm] ... subscribes only the sender, see parsePersonalArgs.
+func (h *Handler) handleSubscribe(ctx context.Context, chatID int64, chatType string, userID int64, args string) {
+	personal, args := h.personalArgs(chatType, args)
+	if personal != model.PersonalNone && !isPersonalSender(userID) {
+		h.sendError(ctx, chatID, "无法识别订阅者，请以个人身份发送命令。")
+		return
+	}
+	sub := &model.Subscription{ChatID: chatID, ChatType: chatType, Personal: personal}
+	if personal != model.PersonalNone {
+		sub.UserID = userID
+	}
 	subType, keyword := DetermineSubscriptionType(args)
 
-	sub := &model.Subscription{
-		ChatID:   chatID,
-		ChatType: chatType,
-		Type:     subType,
-		Keyword:  keyword,
-		Enabled:  true,
+	// Catalog suggestions are answered with buttons that subscribe the whole chat
+	if subType != model.SubTypeAll && personal == model.PersonalNone {
+		var catalog []string
+		var err error
+		if subType == model.SubTypeTag {
+			catalog, err = h.store.GetTagNames(ctx)
+		} else {
+			catalog, err = h.store.GetActressNames(ctx)
+		}
+		if err != nil {
+			// Subscribing must not depend on the catalog being readable
+			log.Warn().Err(err).Int64("chatID", chatID).Msg("Failed to load catalog for subscription suggestions")
+		} else {
+			exact, suggestions := suggestNames(keyword, catalog, maxSuggestions)
+			if exact == "" {
+				h.sendSubscriptionSuggestions(chatID, subType, keyword, suggestions)
+				return
+			}
+			keyword = exact
+		}
 	}
 
-	if err := h.store.CreateSubscription(ctx, sub); err != nil {
-		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to create subscription")
-		h.sendError(chatID, "创建订阅失败，请重试。")
+	sub.Type, sub.Keyword = subType, keyword
+	message, err := h.subscribe(ctx, sub)
+	if err != nil {
+		h.sendError(ctx, chatID, "创建订阅失败，请重试。")
 		return
 	}
 
+	if err := h.telegram.SendMessage(chatID, message); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send subscription confirmation")
+	}
+	h.previewSubscription(ctx, sub)
+}
+
+// subscribe creates a subscription served by this bot and returns the confirmation message
+// sub needs its chat, type and keyword set, and the subscriber for personal subscriptions.
+// Callers follow the confirmation with previewSubscription.
+func (h *Handler) subscribe(ctx context.Context, sub *model.Subscription) (string, error) {
+	sub.Enabled = true
+	sub.BotID = h.botID
+
+	if err := h.store.CreateSubscription(ctx, sub); err != nil {
+		log.Error().Err(err).Int64("chatID", sub.ChatID).Msg("Failed to create subscription")
+		return "", err
+	}
+
 	var message string
-	switch subType {
+	switch sub.Type {
 	case model.SubTypeAll:
 		message = "✅ 已订阅所有新视频！"
 	case model.SubTypeActress:
-		message = fmt.Sprintf("✅ 已订阅演员: %s", keyword)
+		message = fmt.Sprintf("✅ 已订阅演员: %s", sub.Keyword)
 	case model.SubTypeTag:
-		message = fmt.Sprintf("✅ 已订阅标签: #%s", keyword)
+		message = fmt.Sprintf("✅ 已订阅标签: #%s", sub.Keyword)
+	}
+	return message + personalNote(sub.Personal), nil
+}
+
+// sendSubscriptionSuggestions asks the user to pick a catalog name or confirm an unknown keyword
+func (h *Handler) sendSubscriptionSuggestions(chatID int64, subType model.SubscriptionType, keyword string, suggestions []string) {
+	display := keyword
+	if subType == model.SubTypeTag {
+		display = "#" + keyword
 	}
 
-	if err := h.telegram.SendMessage(chatID, message); err != nil {
-		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send subscription confirmation")
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, name := range suggestions {
+		label := name
+		if subType == model.SubTypeTag {
+			label = "#" + name
+		}
+		if data, ok := subscribeCallbackData(subType, name); ok {
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, data)))
+		}
+	}
+	if data, ok := subscribeCallbackData(subType, keyword); ok {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("仍然订阅 %s", display), data)))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("取消", callbackSubscribeCancel)))
+
+	text := fmt.Sprintf("🤔 未在片库中找到 %s", display)
+	if len(suggestions) > 0 {
+		text += "，你是不是要找:"
+	} else {
+		text += "，确认仍要订阅吗？"
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if err := h.telegram.SendMessageWithButtons(chatID, text, keyboard); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send subscription suggestions")
 	}
 }
 
 // handleUnsubscribe handles /unsubscribe command (Requirements 3.5, 3.6)
-func (h *Handler) handleUnsubscribe(ctx context.Context, chatID int64, args string) {
+// Removing all subscriptions requires confirmation, either via the inline
+// buttons or as /unsubscribe confirm
+func (h *Handler) handleUnsubscribe(ctx context.Context, chatID int64, chatType string, userID int64, args string) {
 	args = strings.TrimSpace(args)
+	personal, keywordArgs := h.personalArgs(chatType, args)
+	var subscriber int64
+	if personal != model.PersonalNone {
+		if !isPersonalSender(userID) {
+			h.sendError(ctx, chatID, "无法识别订阅者，请以个人身份发送命令。")
+			return
+		}
+		if keywordArgs == "" {
+			h.sendError(ctx, chatID, "用法: /unsubscribe personal 关键词")
+			return
+		}
+		subscriber = userID
+		args = keywordArgs
+	}
 
 	if args == "" {
+		h.confirmUnsubscribeAll(ctx, chatID)
+		return
+	}
+
+	if strings.EqualFold(args, unsubscribeConfirmArg) {
 		// Unsubscribe from all (Requirement 3.5)
-		if err := h.store.DeleteAllSubscriptions(ctx, chatID); err != nil {
-			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to delete all subscriptions")
-			h.sendError(chatID, "取消订阅失败，请重试。")
+		message, err := h.unsubscribeAll(ctx, chatID)
+		if err != nil {
+			h.sendError(ctx, chatID, "取消订阅失败，请重试。")
 			return
 		}
-		if err := h.telegram.SendMessage(chatID, "✅ 已取消所有订阅。"); err != nil {
+		if err := h.telegram.SendMessage(chatID, message); err != nil {
 			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send unsubscribe confirmation")
 		}
 		return
@@ -193,9 +488,9 @@ func (h *Handler) handleUnsubscribe(ctx context.Context, chatID int64, args stri
 
 	// Unsubscribe from specific keyword (Requirement 3.6)
 	subType, keyword := DetermineSubscriptionType(args)
-	if err := h.store.DeleteSubscription(ctx, chatID, string(subType), keyword); err != nil {
+	if err := h.store.DeleteSubscription(ctx, chatID, subscriber, string(subType), keyword); err != nil {
 		log.Error().Err(err).Int64("chatID", chatID).Str("keyword", keyword).Msg("Failed to delete subscription")
-		h.sendError(chatID, "取消订阅失败，请重试。")
+		h.sendError(ctx, chatID, "取消订阅失败，请重试。")
 		return
 	}
 
@@ -206,12 +501,48 @@ func (h *Handler) handleUnsubscribe(ctx context.Context, chatID int64, args stri
 }
 
 
+// confirmUnsubscribeAll asks for confirmation before removing all of a chat's subscriptions
+func (h *Handler) confirmUnsubscribeAll(ctx context.Context, chatID int64) {
+	subs, err := h.store.GetSubscriptions(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get subscriptions")
+		h.sendError(ctx, chatID, "获取订阅列表失败，请重试。")
+		return
+	}
+
+	if len(subs) == 0 {
+		if err := h.telegram.SendMessage(chatID, "📭 你还没有任何订阅。"); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send empty list message")
+		}
+		return
+	}
+
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("确认取消", callbackUnsubscribeAll),
+		tgbotapi.NewInlineKeyboardButtonData("保留订阅", callbackUnsubscribeCancel),
+	))
+	text := fmt.Sprintf("⚠️ 确定要取消全部 %d 个订阅吗？", len(subs))
+	if err := h.telegram.SendMessageWithButtons(chatID, text, keyboard); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send unsubscribe confirmation prompt")
+	}
+}
+
+// unsubscribeAll removes all of a chat's subscriptions and returns the confirmation message
+func (h *Handler) unsubscribeAll(ctx context.Context, chatID int64) (string, error) {
+	removed, err := h.store.DeleteAllSubscriptions(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to delete all subscriptions")
+		return "", err
+	}
+	return fmt.Sprintf("✅ 已取消所有订阅（共 %d 个）。", removed), nil
+}
+
 // handleList handles /list command (Requirement 3.7)
 func (h *Handler) handleList(ctx context.Context, chatID int64) {
 	subs, err := h.store.GetSubscriptions(ctx, chatID)
 	if err != nil {
 		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get subscriptions")
-		h.sendError(chatID, "获取订阅列表失败，请重试。")
+		h.sendError(ctx, chatID, "获取订阅列表失败，请重试。")
 		return
 	}
 
@@ -225,41 +556,61 @@ func (h *Handler) handleList(ctx context.Context, chatID int64) {
 	var lines []string
 	lines = append(lines, "📋 *我的订阅:*\n")
 	for i, sub := range subs {
-		var line string
-		switch sub.Type {
-		case model.SubTypeAll:
-			line = fmt.Sprintf("%d\\. 🌐 所有视频", i+1)
-		case model.SubTypeActress:
-			line = fmt.Sprintf("%d\\. 👩 演员: %s", i+1, push.EscapeMarkdown(sub.Keyword))
-		case model.SubTypeTag:
-			line = fmt.Sprintf("%d\\. 🏷 标签: \\#%s", i+1, push.EscapeMarkdown(sub.Keyword))
-		}
-		lines = append(lines, line)
+		lines = append(lines, formatSubscriptionLine(i+1, sub))
 	}
 
-	if err := h.telegram.SendMarkdown(chatID, strings.Join(lines, "\n")); err != nil {
+	if _, err := h.telegram.SendMarkdown(chatID, strings.Join(lines, "\n")); err != nil {
 		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send subscription list")
 	}
 }
 
+// formatSubscriptionLine formats a numbered subscription as a MarkdownV2 list line
+func formatSubscriptionLine(n int, sub *model.Subscription) string {
+	var line string
+	switch sub.Type {
+	case model.SubTypeActress:
+		line = fmt.Sprintf("%d\\. 👩 演员: %s", n, push.EscapeMarkdown(sub.Keyword))
+	case model.SubTypeTag:
+		line = fmt.Sprintf("%d\\. 🏷 标签: \\#%s", n, push.EscapeMarkdown(sub.Keyword))
+	default:
+		line = fmt.Sprintf("%d\\. 🌐 所有视频", n)
+	}
+	if sub.IsPersonal() {
+		line += fmt.Sprintf(" \\(个人 `%d`\\)", sub.UserID)
+	}
+	return line
+}
+
 // handleSearch handles /search command (Requirement 3.8)
-// Returns at most 10 results (Property 5)
+// Accepts free text plus structured fields, see parseSearchQuery
+// Returns at most the chat's search limit, 10 by default (Property 5)
 func (h *Handler) handleSearch(ctx context.Context, chatID int64, keyword string) {
 	if keyword == "" {
-		h.sendError(chatID, "请提供搜索关键词。例如: /search ABC-123")
+		h.sendError(ctx, chatID, "请提供搜索关键词。例如: /search ABC-123 或 /search actress:三上悠亜 tag:単体 min:120 sort:new")
+		return
+	}
+
+	filter, err := parseSearchQuery(keyword)
+	if err != nil {
+		h.sendError(ctx, chatID, err.Error())
+		return
+	}
+	if filter.IsEmpty() {
+		h.sendError(ctx, chatID, "请提供搜索条件。例如: /search actress:三上悠亜 tag:単体")
 		return
 	}
 
-	// Limit to 10 results (Requirement 3.8, Property 5)
-	videos, err := h.store.SearchVideos(ctx, keyword, 10)
+	// Limit to the chat's search limit (Requirement 3.8, Property 5)
+	filter.Limit, _ = h.pageSizes(ctx, chatID)
+	videos, err := h.store.FindVideos(ctx, filter)
 	if err != nil {
 		log.Error().Err(err).Int64("chatID", chatID).Str("keyword", keyword).Msg("Failed to search videos")
-		h.sendError(chatID, "搜索失败，请重试。")
+		h.sendError(ctx, chatID, "搜索失败，请重试。")
 		return
 	}
 
 	if len(videos) == 0 {
-		if err := h.telegram.SendMessage(chatID, fmt.Sprintf("🔍 未找到相关视频: %s", keyword)); err != nil {
+		if err := h.sendReply(ctx, chatID, fmt.Sprintf("🔍 未找到相关视频: %s", keyword)); err != nil {
 			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send no results message")
 		}
 		return
@@ -280,42 +631,74 @@ func (h *Handler) handleSearch(ctx context.Context, chatID int64, keyword string
 		lines = append(lines, line)
 	}
 
-	if err := h.telegram.SendMarkdown(chatID, strings.Join(lines, "\n")); err != nil {
+	if err := h.sendMarkdownReply(ctx, chatID, strings.Join(lines, "\n")); err != nil {
 		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send search results")
 	}
 }
 
 
-// handleLatest handles /latest command (Requirement 3.9)
-func (h *Handler) handleLatest(ctx context.Context, chatID int64, args string) {
-	page := 1
-	if args != "" {
-		var err error
-		page, err = strconv.Atoi(args)
-		if err != nil || page < 1 {
-			page = 1
+// parseLatestArgs parses /latest arguments: an optional #tag or actress name
+// followed by an optional page number
+func parseLatestArgs(args string) (subType model.SubscriptionType, keyword string, page int) {
+	page = 1
+	fields := strings.Fields(args)
+	if len(fields) > 0 {
+		if n, err := strconv.Atoi(fields[len(fields)-1]); err == nil {
+			if n > 0 {
+				page = n
+			}
+			fields = fields[:len(fields)-1]
 		}
 	}
 
-	limit := 5
+	subType, keyword = DetermineSubscriptionType(strings.Join(fields, " "))
+	return subType, keyword, page
+}
+
+// handleLatest handles /latest command (Requirement 3.9)
+// Supports /latest [页码], /latest #标签 [页码] and /latest 演员 [页码]
+func (h *Handler) handleLatest(ctx context.Context, chatID int64, args string) {
+	subType, keyword, page := parseLatestArgs(args)
+
+	_, limit := h.pageSizes(ctx, chatID)
 	offset := (page - 1) * limit
 
-	videos, err := h.store.GetLatestVideos(ctx, limit, offset)
+	var videos []*model.Video
+	var err error
+	switch subType {
+	case model.SubTypeTag:
+		videos, err = h.store.FindVideos(ctx, &store.VideoFilter{Tag: keyword, Limit: limit, Offset: offset})
+	case model.SubTypeActress:
+		videos, err = h.store.FindVideos(ctx, &store.VideoFilter{Actress: keyword, Limit: limit, Offset: offset})
+	default:
+		videos, err = h.store.GetLatestVideos(ctx, limit, offset)
+	}
 	if err != nil {
-		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get latest videos")
-		h.sendError(chatID, "获取最新视频失败，请重试。")
+		log.Error().Err(err).Int64("chatID", chatID).Str("keyword", keyword).Msg("Failed to get latest videos")
+		h.sendError(ctx, chatID, "获取最新视频失败，请重试。")
 		return
 	}
 
 	if len(videos) == 0 {
-		if err := h.telegram.SendMessage(chatID, "📭 暂无视频。"); err != nil {
+		if err := h.sendReply(ctx, chatID, "📭 暂无视频。"); err != nil {
 			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send no videos message")
 		}
 		return
 	}
 
+	// Filter prefix shown in the title and repeated in the pagination hint
+	var filterLabel, filterArg string
+	switch subType {
+	case model.SubTypeTag:
+		filterLabel = fmt.Sprintf("标签 \\#%s ", push.EscapeMarkdown(keyword))
+		filterArg = "\\#" + push.EscapeMarkdown(keyword) + " "
+	case model.SubTypeActress:
+		filterLabel = fmt.Sprintf("演员 %s ", push.EscapeMarkdown(keyword))
+		filterArg = push.EscapeMarkdown(keyword) + " "
+	}
+
 	var lines []string
-	lines = append(lines, fmt.Sprintf("📺 *最新视频（第 %d 页）*\n", page))
+	lines = append(lines, fmt.Sprintf("📺 *%s最新视频（第 %d 页）*\n", filterLabel, page))
 	for i, video := range videos {
 		line := fmt.Sprintf("%d\\. *%s*", i+1, push.EscapeMarkdown(video.Code))
 		if video.Actresses != "" {
@@ -329,18 +712,265 @@ func (h *Handler) handleLatest(ctx context.Context, chatID int64, args string) {
 
 	// Add pagination hint
 	if len(videos) == limit {
-		lines = append(lines, fmt.Sprintf("\n_使用 /latest %d 查看下一页_", page+1))
+		lines = append(lines, fmt.Sprintf("\n_使用 /latest %s%d 查看下一页_", filterArg, page+1))
 	}
 
-	if err := h.telegram.SendMarkdown(chatID, strings.Join(lines, "\n")); err != nil {
+	if err := h.sendMarkdownReply(ctx, chatID, strings.Join(lines, "\n")); err != nil {
 		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send latest videos")
 	}
 }
 
+// handleDetail handles /detail command
+// Sends the stored video with its preview gallery as an album, falling back to the cover photo
+func (h *Handler) handleDetail(ctx context.Context, chatID int64, args string) {
+	code := crawler.ExtractCode(args)
+	if code == "" {
+		h.sendError(ctx, chatID, "请提供番号。例如: /detail ABC-123")
+		return
+	}
+
+	video, err := h.store.GetVideoByCode(ctx, code)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("code", code).Msg("Failed to get video")
+		h.sendError(ctx, chatID, "查询失败，请重试。")
+		return
+	}
+	if video == nil {
+		h.sendError(ctx, chatID, fmt.Sprintf("📭 未找到视频: %s\n可使用 /crawl code %s 爬取。", code, code))
+		return
+	}
+
+	mode := model.ParseModeMarkdown
+	if h.pushService != nil {
+		mode = h.pushService.ParseMode(ctx, chatID)
+	}
+	caption, parseMode := push.FormatVideoCaption(video, mode)
+	if len(video.Screenshots) > 0 {
+		videoURL, photos := push.MediaGroup(video)
+		_, err = h.telegram.SendMediaGroup(chatID, videoURL, photos, caption, parseMode)
+		if err == nil {
+			return
+		}
+		log.Warn().Err(err).Int64("chatID", chatID).Str("code", code).Msg("Failed to send detail album, falling back to cover")
+	}
+
+	if video.CoverURL != "" {
+		_, err = h.telegram.SendPhoto(chatID, video.CoverURL, caption, parseMode)
+	} else {
+		message, _ := push.FormatVideo(video, mode)
+		_, err = h.telegram.SendText(chatID, message, parseMode)
+	}
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send video detail")
+	}
+}
+
+// historyDefaultLimit and historyMaxLimit bound the number of videos listed by /history
+const (
+	historyDefaultLimit = 10
+	historyMaxLimit     = 50
+)
+
+// handleHistory handles /history command, listing the videos last pushed to this chat
+func (h *Handler) handleHistory(ctx context.Context, chatID int64, args string) {
+	limit := historyDefaultLimit
+	if args != "" {
+		n, err := strconv.Atoi(args)
+		if err != nil || n <= 0 {
+			h.sendError(ctx, chatID, "用法: /history [条数]")
+			return
+		}
+		limit = min(n, historyMaxLimit)
+	}
+
+	history, err := h.store.GetPushHistory(ctx, chatID, limit)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get push history")
+		h.sendError(ctx, chatID, "获取推送历史失败，请重试。")
+		return
+	}
+	if len(history) == 0 {
+		if err := h.telegram.SendMessage(chatID, "📭 本聊天暂无推送记录。"); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send empty history message")
+		}
+		return
+	}
+
+	lines := []string{"🕘 *最近推送*\n"}
+	for i, entry := range history {
+		video := entry.Video
+		code := push.EscapeMarkdown(video.Code)
+		if video.DetailURL != "" {
+			code = fmt.Sprintf("[%s](%s)", code, push.EscapeMarkdownURL(video.DetailURL))
+		}
+		line := fmt.Sprintf("%d\\. %s %s", i+1, push.EscapeMarkdown(entry.PushedAt.Format("01-02 15:04")), code)
+		if video.Title != "" {
+			title := []rune(video.Title)
+			if len(title) > 40 {
+				title = append(title[:37], []rune("...")...)
+			}
+			line += "\n   " + push.EscapeMarkdown(string(title))
+		}
+		lines = append(lines, line)
+	}
+
+	if _, err := h.telegram.SendMarkdown(chatID, strings.Join(lines, "\n")); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send push history")
+	}
+}
+
+// handleMute handles /mute command
+// Mutes a code so it is never pushed to this chat again; without a code it lists the muted codes.
+func (h *Handler) handleMute(ctx context.Context, chatID int64, args string) {
+	if args == "" {
+		h.listMutedCodes(ctx, chatID)
+		return
+	}
+
+	code := model.CanonicalCode(crawler.ExtractCode(args))
+	if code == "" {
+		h.sendError(ctx, chatID, "请提供番号。例如: /mute ABC-123")
+		return
+	}
+
+	if err := h.store.MuteCode(ctx, chatID, code); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("code", code).Msg("Failed to mute code")
+		h.sendError(ctx, chatID, "屏蔽失败，请重试。")
+		return
+	}
+
+	text := fmt.Sprintf("🔇 已屏蔽 %s，本聊天将不再收到该番号的推送。\n使用 /unmute %s 恢复。", code, code)
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send mute confirmation")
+	}
+}
+
+// handleUnmute handles /unmute command
+func (h *Handler) handleUnmute(ctx context.Context, chatID int64, args string) {
+	code := model.CanonicalCode(crawler.ExtractCode(args))
+	if code == "" {
+		h.sendError(ctx, chatID, "请提供番号。例如: /unmute ABC-123")
+		return
+	}
+
+	unmuted, err := h.store.UnmuteCode(ctx, chatID, code)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("code", code).Msg("Failed to unmute code")
+		h.sendError(ctx, chatID, "取消屏蔽失败，请重试。")
+		return
+	}
+
+	text := fmt.Sprintf("🔊 已恢复推送 %s。", code)
+	if !unmuted {
+		text = fmt.Sprintf("ℹ️ %s 未被屏蔽。", code)
+	}
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send unmute confirmation")
+	}
+}
+
+// handleRevoke handles /revoke command
+// It deletes the messages a video was pushed as and hides it from further pushes.
+func (h *Handler) handleRevoke(ctx context.Context, chatID int64, args string) {
+	code := model.CanonicalCode(crawler.ExtractCode(args))
+	if code == "" {
+		h.sendError(ctx, chatID, "请提供番号。例如: /revoke ABC-123")
+		return
+	}
+
+	result, err := h.pushService.RevokeVideo(ctx, code)
+	if err != nil {
+		log.Error().Err(err).Str("code", code).Msg("Failed to revoke video")
+		h.sendError(ctx, chatID, "撤回失败，请重试。")
+		return
+	}
+
+	if result.Hidden == 0 && result.Deleted == 0 && result.Failed == 0 {
+		h.sendError(ctx, chatID, fmt.Sprintf("未找到番号 %s。", code))
+		return
+	}
+
+	text := fmt.Sprintf("🗑 已撤回 %s：删除 %d 条推送，该番号不会再推送。", code, result.Deleted)
+	if result.Failed > 0 {
+		text += fmt.Sprintf("\n⚠️ %d 条推送删除失败（机器人无删除权限或消息已超过 48 小时）。", result.Failed)
+	}
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send revoke result")
+	}
+}
+
+// handleScheduler handles /scheduler [pause|resume|run]
+// Without arguments it reports whether scheduled crawling is paused or running.
+func (h *Handler) handleScheduler(ctx context.Context, chatID int64, args string) {
+	if h.schedule == nil {
+		h.sendError(ctx, chatID, "定时爬取未启用。")
+		return
+	}
+
+	var text string
+	switch strings.ToLower(strings.TrimSpace(args)) {
+	case "":
+		text = "▶️ 定时爬取运行中。"
+		if h.schedule.IsPaused() {
+			text = "⏸ 定时爬取已暂停。"
+		}
+		if h.schedule.IsRunning() {
+			text += "\n🔄 正在爬取。"
+		}
+		text += "\n使用 /scheduler pause|resume|run 暂停、恢复或立即爬取。"
+	case "pause":
+		text = "⏸ 定时爬取已暂停，使用 /scheduler resume 恢复。"
+		if !h.schedule.Pause() {
+			text = "定时爬取已处于暂停状态。"
+		}
+	case "resume":
+		text = "▶️ 定时爬取已恢复。"
+		if !h.schedule.Resume() {
+			text = "定时爬取未暂停。"
+		}
+	case "run":
+		text = "🔄 已开始爬取，完成后推送新视频。"
+		if !h.schedule.RunNow(ctx) {
+			text = "⏳ 已有爬取任务在运行，请稍后再试。"
+		}
+	default:
+		h.sendError(ctx, chatID, "用法: /scheduler [pause|resume|run]")
+		return
+	}
+
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send scheduler reply")
+	}
+}
+
+// listMutedCodes sends the codes muted in a chat
+func (h *Handler) listMutedCodes(ctx context.Context, chatID int64) {
+	mutes, err := h.store.GetMutedCodes(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get muted codes")
+		h.sendError(ctx, chatID, "获取屏蔽列表失败，请重试。")
+		return
+	}
+
+	text := "📭 本聊天没有屏蔽的番号。\n使用 /mute ABC-123 屏蔽。"
+	if len(mutes) > 0 {
+		codes := make([]string, len(mutes))
+		for i, mute := range mutes {
+			codes[i] = mute.Code
+		}
+		text = fmt.Sprintf("🔇 已屏蔽的番号 (%d):\n%s", len(mutes), strings.Join(codes, "\n"))
+	}
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send muted codes")
+	}
+}
+
 // handleCrawl handles /crawl command (Requirement 3.10)
-func (h *Handler) handleCrawl(ctx context.Context, chatID int64, chatType string, args string) {
+// Admin crawls may exceed the crawler's daily budget; other users are refused once it is used up.
+// Crawls are queued and run one at a time, with at most crawlJobsPerChat per chat.
+func (h *Handler) handleCrawl(ctx context.Context, chatID int64, chatType string, args string, admin bool) {
 	if args == "" {
-		h.sendError(chatID, "请指定爬取类型。例如:\n/crawl actor 三上悠亜\n/crawl code ABC-123\n/crawl search 关键词")
+		h.sendError(ctx, chatID, "请指定爬取类型。例如:\n/crawl actor 三上悠亜\n/crawl code ABC-123\n/crawl search 关键词\n/crawl tag 标签")
 		return
 	}
 
@@ -351,67 +981,176 @@ func (h *Handler) handleCrawl(ctx context.Context, chatID int64, chatType string
 		keyword = strings.TrimSpace(parts[1])
 	}
 
+	switch crawlType {
+	case "status", "cancel":
+		if !admin {
+			h.deny(ctx, chatID)
+			return
+		}
+		if crawlType == "status" {
+			h.handleCrawlStatus(ctx, chatID)
+		} else {
+			h.handleCrawlCancel(ctx, chatID, keyword)
+		}
+		return
+	}
+
+	crawl, ok := crawlTypes[crawlType]
+	if !ok {
+		h.sendError(ctx, chatID, "未知爬取类型。可用: actor, code, search, tag, new")
+		return
+	}
+
 	if keyword == "" && crawlType != "new" {
-		h.sendError(chatID, "请提供爬取关键词。")
+		h.sendError(ctx, chatID, "请提供爬取关键词。")
 		return
 	}
 
-	// Send acknowledgment
-	if err := h.telegram.SendMessage(chatID, "🔄 开始爬取... 请稍候。"); err != nil {
-		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send crawl acknowledgment")
+	if admin {
+		ctx = crawler.WithoutBudget(ctx)
+	} else if crawler.BudgetExhausted(h.crawler) {
+		h.sendError(ctx, chatID, budgetExhaustedMessage)
+		return
 	}
 
-	// Execute crawl asynchronously
-	go func() {
-		var videos []*model.Video
+	// Queue the crawl, which runs past the end of this update
+	job, ahead, err := h.jobs.enqueue(ctx, "crawl "+args, chatID, func(ctx context.Context, job *crawlJob) {
+		if err := h.telegram.SendMessage(chatID, "🔄 开始爬取... 请稍候。"); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send crawl acknowledgment")
+		}
+
+		var result *crawler.CrawlResult
 		var err error
 
-		switch crawlType {
-		case "actor", "actress":
-			videos, err = h.crawler.CrawlByActor(ctx, keyword, 20)
-		case "code":
-			video, crawlErr := h.crawler.CrawlByCode(ctx, keyword)
-			if crawlErr != nil {
-				err = crawlErr
-			} else if video != nil {
-				videos = []*model.Video{video}
+		startTime := time.Now()
+		run := &model.CrawlRun{
+			Trigger:   model.CrawlTriggerCommand,
+			Target:    args,
+			StartedAt: startTime,
+		}
+		defer func() {
+			run.DurationMs = time.Since(startTime).Milliseconds()
+			if recordErr := h.store.RecordCrawlRun(context.WithoutCancel(ctx), run); recordErr != nil {
+				log.Error().Err(recordErr).Msg("Failed to record crawl run")
 			}
-		case "search", "keyword":
-			videos, err = h.crawler.CrawlByKeyword(ctx, keyword, 20)
-		case "new":
-			videos, err = h.crawler.CrawlNewVideos(ctx, 2)
-		default:
-			h.sendError(chatID, "未知爬取类型。可用: actor, code, search, new")
-			return
+		}()
+
+		job.setProgress("正在爬取")
+		if crawlType == "new" {
+			run.Pages = crawlNewPages
 		}
+		result, err = crawl(ctx, h.crawler, keyword)
 
+		result.Fill(run)
 		if err != nil {
+			run.Error = err.Error()
+			if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+				log.Info().Int("job", job.id).Str("type", crawlType).Str("keyword", keyword).Msg("Crawl cancelled")
+				if sendErr := h.telegram.SendMessage(chatID, fmt.Sprintf("⏹ 爬取任务 #%d 已取消。", job.id)); sendErr != nil {
+					log.Error().Err(sendErr).Int64("chatID", chatID).Msg("Failed to send crawl cancelled message")
+				}
+				return
+			}
 			log.Error().Err(err).Str("type", crawlType).Str("keyword", keyword).Msg("Crawl failed")
-			h.sendError(chatID, fmt.Sprintf("❌ 爬取失败: %s", err.Error()))
+			if errors.Is(err, crawler.ErrBudgetExhausted) {
+				h.sendError(ctx, chatID, budgetExhaustedMessage)
+				return
+			}
+			h.sendError(ctx, chatID, fmt.Sprintf("❌ 爬取失败: %s", err.Error()))
 			return
 		}
 
+		videos := result.Videos
 		if len(videos) == 0 {
-			if err := h.telegram.SendMessage(chatID, "📭 未找到视频。"); err != nil {
+			message := "📭 未找到视频。\n" + formatCrawlStats(result, time.Since(startTime))
+			if err := h.telegram.SendMessage(chatID, message); err != nil {
 				log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send no results message")
 			}
 			return
 		}
 
 		// Save videos to database
+		job.setProgress(fmt.Sprintf("正在保存 %d 个视频", len(videos)))
 		saved, duplicates, saveErr := h.store.SaveVideos(ctx, videos)
 		if saveErr != nil {
+			run.Error = saveErr.Error()
 			log.Error().Err(saveErr).Msg("Failed to save crawled videos")
 		}
+		run.Saved = saved
+		run.Duplicates = duplicates
 
-		message := fmt.Sprintf("✅ 爬取完成！\n📊 找到: %d 个视频\n💾 新增: %d 个\n🔄 重复: %d 个", len(videos), saved, duplicates)
+		message := fmt.Sprintf("✅ 爬取完成！\n📊 找到: %d 个视频\n💾 新增: %d 个\n🔄 重复: %d 个\n%s",
+			len(videos), saved, duplicates, formatCrawlStats(result, time.Since(startTime)))
 		if err := h.telegram.SendMessage(chatID, message); err != nil {
 			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send crawl results")
 		}
-	}()
+	})
+	if err != nil {
+		h.sendError(ctx, chatID, tooManyJobsMessage)
+		return
+	}
+	if ahead > 0 {
+		if err := h.telegram.SendMessage(chatID, queuedMessage(job, ahead)); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send crawl queue position")
+		}
+	}
+}
+
+// crawlNewPages is the number of listing pages /crawl new fetches
+const crawlNewPages = 2
+
+// crawlFunc runs the crawl of a /crawl type for its keyword
+type crawlFunc func(ctx context.Context, c crawler.Crawler, keyword string) (*crawler.CrawlResult, error)
+
+// crawlTypes maps each /crawl type to the crawl it runs
+var crawlTypes = map[string]crawlFunc{
+	"actor":   crawlByActor,
+	"actress": crawlByActor,
+	"code": func(ctx context.Context, c crawler.Crawler, keyword string) (*crawler.CrawlResult, error) {
+		return c.CrawlByCode(ctx, keyword)
+	},
+	"search":  crawlByKeyword,
+	"keyword": crawlByKeyword,
+	"tag":     crawlByTag,
+	"genre":   crawlByTag,
+	"new": func(ctx context.Context, c crawler.Crawler, keyword string) (*crawler.CrawlResult, error) {
+		return c.CrawlNewVideos(ctx, crawlNewPages)
+	},
+}
+
+// crawlByActor runs /crawl actor
+func crawlByActor(ctx context.Context, c crawler.Crawler, keyword string) (*crawler.CrawlResult, error) {
+	return c.CrawlByActor(ctx, keyword, 20)
+}
+
+// crawlByKeyword runs /crawl search
+func crawlByKeyword(ctx context.Context, c crawler.Crawler, keyword string) (*crawler.CrawlResult, error) {
+	return c.CrawlByKeyword(ctx, keyword, 20)
+}
+
+// crawlByTag runs /crawl tag, accepting the tag with or without a leading #
+func crawlByTag(ctx context.Context, c crawler.Crawler, keyword string) (*crawler.CrawlResult, error) {
+	return c.CrawlByTag(ctx, strings.TrimPrefix(keyword, "#"), 20)
+}
+
+// budgetExhaustedMessage tells users the crawler has used up today's request budget
+const budgetExhaustedMessage = "⛔ 今日爬取额度已用完，请明天再试。"
+
+// formatCrawlStats summarizes how a crawl fetched its pages
+func formatCrawlStats(result *crawler.CrawlResult, elapsed time.Duration) string {
+	cache := ""
+	if result.CacheHits > 0 {
+		cache = fmt.Sprintf(" / 缓存 %d 次", result.CacheHits)
+	}
+	return fmt.Sprintf("📄 页面: %d (HTTP %d 次 / 浏览器 %d 次%s, 解析失败 %d)\n⏱ 耗时: %s",
+		result.PagesFetched, result.HTTPFetches, result.BrowserFetches, cache, result.ParseFailures,
+		elapsed.Round(time.Second))
 }
 
 
+// statusCommandStatsLimit is the number of commands listed in /status
+const statusCommandStatsLimit = 5
+
 // handleStatus handles /status command (Requirement 3.11)
 func (h *Handler) handleStatus(ctx context.Context, chatID int64) {
 	videoCount, err := h.store.CountVideos(ctx)
@@ -425,15 +1164,290 @@ func (h *Handler) handleStatus(ctx context.Context, chatID int64) {
 
 	var lines []string
 	lines = append(lines, "📊 *机器人状态*\n")
+	lines = append(lines, fmt.Sprintf("🤖 机器人: @%s \\(ID: `%d`\\)", push.EscapeMarkdown(h.telegram.BotUsername()), h.botID))
 	lines = append(lines, fmt.Sprintf("🎬 数据库视频数: %d", videoCount))
+	if counts, err := h.store.CountVideosByCompleteness(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to count videos by completeness")
+	} else if len(counts) > 0 {
+		lines = append(lines, "📋 资料完整度: "+formatCompleteness(counts))
+	}
+	lines = append(lines, h.pushStatusLines(ctx)...)
 	lines = append(lines, fmt.Sprintf("⏱ 运行时间: %s", uptimeStr))
 	lines = append(lines, fmt.Sprintf("🕐 启动时间: %s", h.startTime.Format("2006\\-01\\-02 15:04:05")))
+	if line := h.nextRunLine(); line != "" {
+		lines = append(lines, line)
+	}
+	if reporter, ok := h.crawler.(crawler.BudgetReporter); ok {
+		if used, limit := reporter.BudgetUsage(); limit > 0 {
+			lines = append(lines, fmt.Sprintf("🎫 今日爬取额度: %d/%d", used, limit))
+		}
+	}
+	if reporter, ok := h.crawler.(crawler.RateLimitReporter); ok {
+		if remaining := time.Until(reporter.RateLimitedUntil()); remaining > 0 {
+			lines = append(lines, fmt.Sprintf("🐢 站点正在限流，约 %s 后恢复",
+				push.EscapeMarkdown(remaining.Round(time.Second).String())))
+		}
+	}
+
+	stats, err := h.store.GetCommandStats(ctx, 0, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get command stats")
+	} else if len(stats) > 0 {
+		lines = append(lines, "\n📈 *24小时命令使用*")
+		for i, stat := range stats {
+			if i == statusCommandStatsLimit {
+				break
+			}
+			lines = append(lines, fmt.Sprintf("/%s: %d 次, 失败 %d, 平均 %s",
+				push.EscapeMarkdown(stat.Command), stat.Count, stat.Failures,
+				push.EscapeMarkdown((time.Duration(stat.AvgLatencyMs)*time.Millisecond).Round(time.Millisecond).String())))
+		}
+	}
 
-	if err := h.telegram.SendMarkdown(chatID, strings.Join(lines, "\n")); err != nil {
+	if _, err := h.telegram.SendMarkdown(chatID, strings.Join(lines, "\n")); err != nil {
 		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send status")
 	}
 }
 
+// nextRunLine reports the next scheduled crawl for /status, or "" without a running scheduler
+func (h *Handler) nextRunLine() string {
+	if h.schedule == nil {
+		return ""
+	}
+	next := h.schedule.NextRun()
+	if next.IsZero() {
+		return ""
+	}
+	if h.schedule.IsPaused() {
+		return "⏸ 定时爬取已暂停"
+	}
+	until := time.Until(next).Round(time.Second)
+	if until < 0 {
+		return fmt.Sprintf("⏰ 定时爬取已逾期 %s", push.EscapeMarkdown((-until).String()))
+	}
+	return fmt.Sprintf("⏰ 下次定时爬取: %s \\(%s 后\\)", next.Format("15:04:05"), push.EscapeMarkdown(until.String()))
+}
+
+// pushStatusLines reports the push backlog, today's pushes and the subscriptions for /status
+func (h *Handler) pushStatusLines(ctx context.Context) []string {
+	var lines []string
+	if backlog, err := h.store.CountUnpushedVideos(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to count unpushed videos")
+	} else {
+		lines = append(lines, fmt.Sprintf("📬 待推送视频: %d", backlog))
+	}
+
+	year, month, day := time.Now().Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	if days, err := h.store.GetPushStatsByDay(ctx, today); err != nil {
+		log.Error().Err(err).Msg("Failed to get push stats")
+	} else {
+		var success, failed int64
+		for _, stat := range days {
+			success += stat.Success
+			failed += stat.Failed
+		}
+		lines = append(lines, fmt.Sprintf("📤 今日推送: 成功 %d, 失败 %d", success, failed))
+	}
+
+	if subs, err := h.store.CountSubscriptionsByType(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to count subscriptions")
+	} else {
+		lines = append(lines, fmt.Sprintf("🔔 订阅: 全部 %d, 演员 %d, 标签 %d",
+			subs[model.SubTypeAll], subs[model.SubTypeActress], subs[model.SubTypeTag]))
+	}
+	return lines
+}
+
+// formatCompleteness lists video counts per completeness score, most complete first
+func formatCompleteness(counts map[int]int64) string {
+	var parts []string
+	for score := model.MaxCompleteness; score >= 0; score-- {
+		if counts[score] > 0 {
+			parts = append(parts, fmt.Sprintf("%d/%d: %d", score, model.MaxCompleteness, counts[score]))
+		}
+	}
+	return strings.Join(parts, " \\| ")
+}
+
+// handleCrawlLog handles /crawllog command, listing the most recent crawl runs
+func (h *Handler) handleCrawlLog(ctx context.Context, chatID int64, args string) {
+	limit := 10
+	if args != "" {
+		if n, err := strconv.Atoi(args); err == nil && n > 0 && n <= 50 {
+			limit = n
+		}
+	}
+
+	runs, err := h.store.GetRecentCrawlRuns(ctx, limit)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get crawl runs")
+		h.sendError(ctx, chatID, "获取爬取历史失败，请重试。")
+		return
+	}
+
+	if len(runs) == 0 {
+		if err := h.telegram.SendMessage(chatID, "📭 暂无爬取记录。"); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send empty crawl log message")
+		}
+		return
+	}
+
+	var lines []string
+	lines = append(lines, "🗂 *爬取历史*\n")
+	for _, run := range runs {
+		status := "✅"
+		if run.Error != "" {
+			status = "❌"
+		}
+		line := fmt.Sprintf("%s %s *%s* 找到 %d / 新增 %d / 重复 %d \\(%s\\)",
+			status,
+			push.EscapeMarkdown(run.StartedAt.Format("01-02 15:04")),
+			push.EscapeMarkdown(string(run.Trigger)),
+			run.Found, run.Saved, run.Duplicates,
+			push.EscapeMarkdown((time.Duration(run.DurationMs) * time.Millisecond).Round(time.Second).String()))
+		if run.Target != "" {
+			line += fmt.Sprintf("\n   🎯 %s", push.EscapeMarkdown(run.Target))
+		}
+		if run.PagesFetched > 0 || run.ParseFailures > 0 {
+			line += fmt.Sprintf("\n   📄 页面 %d, HTTP %d / 浏览器 %d, 解析失败 %d",
+				run.PagesFetched, run.HTTPFetches, run.BrowserFetches, run.ParseFailures)
+		}
+		if run.Error != "" {
+			line += fmt.Sprintf("\n   ⚠️ %s", push.EscapeMarkdown(run.Error))
+		}
+		lines = append(lines, line)
+	}
+
+	if _, err := h.telegram.SendMarkdown(chatID, strings.Join(lines, "\n")); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send crawl log")
+	}
+}
+
+// handleAlias handles /alias command (admin only)
+// /alias 演员 lists an actress's aliases; /alias 演员 = 别名 adds a manual alias
+func (h *Handler) handleAlias(ctx context.Context, chatID int64, args string) {
+	name, alias, hasAlias := strings.Cut(args, "=")
+	name = strings.TrimSpace(name)
+	alias = strings.TrimSpace(alias)
+	if name == "" || (hasAlias && alias == "") {
+		h.sendError(ctx, chatID, "用法: /alias 三上悠亜 = Yua Mikami")
+		return
+	}
+
+	if hasAlias {
+		if err := h.store.AddActressAlias(ctx, name, alias); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Str("name", name).Msg("Failed to add actress alias")
+			h.sendError(ctx, chatID, "添加别名失败，请重试。")
+			return
+		}
+		if err := h.telegram.SendMessage(chatID, fmt.Sprintf("✅ 已添加别名: %s → %s", alias, name)); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send alias confirmation")
+		}
+		return
+	}
+
+	aliases, err := h.store.GetActressAliases(ctx, name)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("name", name).Msg("Failed to get actress aliases")
+		h.sendError(ctx, chatID, "获取别名失败，请重试。")
+		return
+	}
+	if len(aliases) == 0 {
+		if err := h.telegram.SendMessage(chatID, fmt.Sprintf("📭 %s 暂无别名。", name)); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send empty alias list")
+		}
+		return
+	}
+
+	lines := []string{fmt.Sprintf("🔤 %s 的别名:", name)}
+	for _, a := range aliases {
+		lines = append(lines, fmt.Sprintf("• %s (%s)", a.Alias, a.Kind))
+	}
+	if err := h.telegram.SendMessage(chatID, strings.Join(lines, "\n")); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send alias list")
+	}
+}
+
+// duplicatesListLimit is the number of pending duplicate candidates listed by /duplicates
+const duplicatesListLimit = 10
+
+// handleDuplicates handles /duplicates command
+// Without arguments it lists pending candidates; "merge ID" suppresses the newer video
+// and "dismiss ID" releases it for pushing.
+func (h *Handler) handleDuplicates(ctx context.Context, chatID int64, args string) {
+	if args == "" {
+		h.listDuplicates(ctx, chatID)
+		return
+	}
+
+	action, idArg, _ := strings.Cut(args, " ")
+	id, err := strconv.ParseUint(strings.TrimSpace(idArg), 10, 64)
+	var status model.DuplicateStatus
+	switch strings.ToLower(action) {
+	case "merge":
+		status = model.DuplicateMerged
+	case "dismiss":
+		status = model.DuplicateDismissed
+	}
+	if status == "" || err != nil {
+		h.sendError(ctx, chatID, "用法: /duplicates merge 编号 或 /duplicates dismiss 编号")
+		return
+	}
+
+	candidate, err := h.store.ResolveDuplicateCandidate(ctx, uint(id), status)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Uint64("id", id).Msg("Failed to resolve duplicate candidate")
+		h.sendError(ctx, chatID, "操作失败，请重试。")
+		return
+	}
+	if candidate == nil {
+		h.sendError(ctx, chatID, fmt.Sprintf("未找到待审核的重复记录 #%d。", id))
+		return
+	}
+
+	message := fmt.Sprintf("✅ 已合并: %s 视为 %s 的重复，不会推送。", candidate.DuplicateCode, candidate.VideoCode)
+	if status == model.DuplicateDismissed {
+		message = fmt.Sprintf("✅ 已忽略: %s 将正常推送。", candidate.DuplicateCode)
+	}
+	if err := h.telegram.SendMessage(chatID, message); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send duplicate resolution")
+	}
+}
+
+// listDuplicates sends the pending duplicate candidates
+func (h *Handler) listDuplicates(ctx context.Context, chatID int64) {
+	candidates, err := h.store.GetDuplicateCandidates(ctx, model.DuplicatePending, duplicatesListLimit)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get duplicate candidates")
+		h.sendError(ctx, chatID, "获取重复记录失败，请重试。")
+		return
+	}
+	if len(candidates) == 0 {
+		if err := h.telegram.SendMessage(chatID, "📭 暂无待审核的疑似重复视频。"); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send empty duplicate list")
+		}
+		return
+	}
+
+	lines := []string{"🔁 疑似重复视频（新视频暂不推送）:"}
+	for _, c := range candidates {
+		lines = append(lines, fmt.Sprintf("#%d %s ≈ %s (%.0f%%)", c.ID, c.DuplicateCode, c.VideoCode, c.Similarity*100))
+	}
+	lines = append(lines, "\n/duplicates merge 编号 - 合并，不再推送\n/duplicates dismiss 编号 - 忽略，正常推送")
+	if err := h.telegram.SendMessage(chatID, strings.Join(lines, "\n")); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send duplicate list")
+	}
+}
+
+// isAdmin reports whether the sender of a message is a configured admin
+func (h *Handler) isAdmin(msg *tgbotapi.Message) bool {
+	if msg.From == nil || h.config == nil {
+		return false
+	}
+	return h.config.IsAdmin(msg.From.ID)
+}
+
 // autoSubscribeGroup auto-subscribes a group chat with ALL type (Requirement 3.12)
 func (h *Handler) autoSubscribeGroup(ctx context.Context, chatID int64, chatType string) {
 	// Check if already subscribed
@@ -455,6 +1469,7 @@ func (h *Handler) autoSubscribeGroup(ctx context.Context, chatID int64, chatType
 		Type:     model.SubTypeAll,
 		Keyword:  "",
 		Enabled:  true,
+		BotID:    h.botID,
 	}
 
 	if err := h.store.CreateSubscription(ctx, sub); err != nil {
@@ -466,8 +1481,26 @@ func (h *Handler) autoSubscribeGroup(ctx context.Context, chatID int64, chatType
 }
 
 // sendError sends an error message to a chat (Requirement 3.13)
-func (h *Handler) sendError(chatID int64, message string) {
-	if err := h.telegram.SendMessage(chatID, "❌ "+message); err != nil {
+// The command handled under ctx is recorded as failed; groups may auto-delete the message
+func (h *Handler) sendError(ctx context.Context, chatID int64, message string) {
+	markCommandOutcome(ctx, model.CommandOutcomeError)
+	if err := h.sendReply(ctx, chatID, "❌ "+message); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send error message")
+	}
+}
+
+// deny tells the user a command is restricted to admins
+func (h *Handler) deny(ctx context.Context, chatID int64) {
+	markCommandOutcome(ctx, model.CommandOutcomeDenied)
+	if err := h.telegram.SendMessage(chatID, "❌ 该命令仅限管理员使用。"); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send error message")
+	}
+}
+
+// denyGroupAdmin tells the user a command is restricted to group admins in this chat
+func (h *Handler) denyGroupAdmin(ctx context.Context, chatID int64) {
+	markCommandOutcome(ctx, model.CommandOutcomeDenied)
+	if err := h.telegram.SendMessage(chatID, "❌ 本群已开启仅管理员管理订阅，该命令仅限群管理员使用。"); err != nil {
 		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send error message")
 	}
 }
@@ -0,0 +1,271 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+const (
+	// importMaxCodes caps the codes one /import crawls
+	importMaxCodes = 100
+	// importMaxFileSize caps the size of a code list file replied to with /import
+	importMaxFileSize = 64 * 1024
+	// importProgressEvery is how many codes /import processes between progress edits
+	importProgressEvery = 5
+	// importListedCodes caps the not found and failed codes listed in the /import result
+	importListedCodes = 20
+)
+
+// parseImportCodes extracts the codes to import from a code list, in canonical form,
+// in order and without duplicates
+func parseImportCodes(text string) []string {
+	var codes []string
+	seen := make(map[string]bool)
+	for _, code := range crawler.ExtractCodes(text) {
+		code = model.CanonicalCode(code)
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// importTally counts the outcome of each code of an /import
+type importTally struct {
+	saved     int
+	existing  int
+	notFound  []string
+	failed    []string
+	skipped   int  // not crawled because the budget ran out or the import was cancelled
+	cancelled bool // stopped with /crawl cancel
+}
+
+// done returns how many codes have been processed
+func (t *importTally) done() int {
+	return t.saved + t.existing + len(t.notFound) + len(t.failed)
+}
+
+// formatImportProgress renders the progress message of a running /import
+func formatImportProgress(tally *importTally, total int) string {
+	return fmt.Sprintf("📥 正在导入 %d/%d...\n💾 新增: %d  🔄 已存在: %d  📭 未找到: %d  ❌ 失败: %d",
+		tally.done(), total, tally.saved, tally.existing, len(tally.notFound), len(tally.failed))
+}
+
+// formatImportResult renders the outcome of a finished /import as plain text
+func formatImportResult(tally *importTally, total int, elapsed time.Duration) string {
+	lines := []string{
+		fmt.Sprintf("✅ 导入完成！共 %d 个番号", total),
+		fmt.Sprintf("💾 新增: %d 个", tally.saved),
+		fmt.Sprintf("🔄 已存在: %d 个", tally.existing),
+	}
+	if len(tally.notFound) > 0 {
+		lines = append(lines, fmt.Sprintf("📭 未找到: %d 个 %s", len(tally.notFound), formatImportCodes(tally.notFound)))
+	}
+	if len(tally.failed) > 0 {
+		lines = append(lines, fmt.Sprintf("❌ 失败: %d 个 %s", len(tally.failed), formatImportCodes(tally.failed)))
+	}
+	if tally.cancelled {
+		lines[0] = fmt.Sprintf("⏹ 导入已取消！共 %d 个番号", total)
+		lines = append(lines, fmt.Sprintf("⏭ %d 个番号未爬取。", tally.skipped))
+	} else if tally.skipped > 0 {
+		lines = append(lines, fmt.Sprintf("⛔ 今日爬取额度已用完，%d 个番号未爬取，请明天重新导入。", tally.skipped))
+	}
+	lines = append(lines, fmt.Sprintf("⏱ 耗时: %s", elapsed.Round(time.Second)))
+	return strings.Join(lines, "\n")
+}
+
+// formatImportCodes lists codes in the /import result, shortening long lists
+func formatImportCodes(codes []string) string {
+	if len(codes) > importListedCodes {
+		return fmt.Sprintf("(%s 等)", strings.Join(codes[:importListedCodes], ", "))
+	}
+	return fmt.Sprintf("(%s)", strings.Join(codes, ", "))
+}
+
+// importText collects the code list of an /import: the command arguments and,
+// when the command replies to a message, its text, caption or attached text file
+func (h *Handler) importText(msg *tgbotapi.Message, args string) (string, error) {
+	parts := []string{args}
+	reply := msg.ReplyToMessage
+	if reply == nil {
+		return args, nil
+	}
+	parts = append(parts, reply.Text, reply.Caption)
+
+	if doc := reply.Document; doc != nil {
+		ext := strings.ToLower(path.Ext(doc.FileName))
+		if !strings.HasPrefix(doc.MimeType, "text/") && ext != ".txt" && ext != ".csv" {
+			return "", fmt.Errorf("仅支持 .txt 或 .csv 文本文件")
+		}
+		if doc.FileSize > importMaxFileSize {
+			return "", fmt.Errorf("文件过大，最大 %d KB", importMaxFileSize/1024)
+		}
+		data, err := h.telegram.DownloadFile(doc.FileID, importMaxFileSize)
+		if err != nil {
+			log.Error().Err(err).Str("file", doc.FileName).Msg("Failed to download import file")
+			return "", fmt.Errorf("下载文件失败，请重试")
+		}
+		parts = append(parts, string(data))
+	}
+	return strings.Join(parts, "\n"), nil
+}
+
+// handleImport handles /import command
+// It crawls the detail page of each listed code that is not stored yet, one at a time
+// through the crawler's rate limiter, saves what it finds and edits a progress message
+// as it goes. Like /crawl, admins may exceed the daily budget; others stop when it runs out.
+// Imports share the /crawl job queue.
+func (h *Handler) handleImport(ctx context.Context, msg *tgbotapi.Message, args string) {
+	chatID := msg.Chat.ID
+	text, err := h.importText(msg, args)
+	if err != nil {
+		h.sendError(ctx, chatID, "❌ "+err.Error())
+		return
+	}
+
+	codes := parseImportCodes(text)
+	if len(codes) == 0 {
+		h.sendError(ctx, chatID, "请提供番号列表。例如:\n/import ABC-123 DEF-456\n或回复一条番号列表消息或 .txt 文件发送 /import")
+		return
+	}
+	if len(codes) > importMaxCodes {
+		h.sendError(ctx, chatID, fmt.Sprintf("一次最多导入 %d 个番号，当前 %d 个。", importMaxCodes, len(codes)))
+		return
+	}
+
+	if h.isAdmin(msg) {
+		ctx = crawler.WithoutBudget(ctx)
+	} else if crawler.BudgetExhausted(h.crawler) {
+		h.sendError(ctx, chatID, budgetExhaustedMessage)
+		return
+	}
+
+	// Queue the import, which runs past the end of this update
+	job, ahead, err := h.jobs.enqueue(ctx, fmt.Sprintf("import %d codes", len(codes)), chatID, func(ctx context.Context, job *crawlJob) {
+		tally := &importTally{}
+		progressID, err := h.telegram.SendText(chatID, formatImportProgress(tally, len(codes)), "")
+		if err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send import acknowledgment")
+		}
+
+		startTime := time.Now()
+		run := &model.CrawlRun{
+			Trigger:   model.CrawlTriggerCommand,
+			Target:    fmt.Sprintf("import %d codes", len(codes)),
+			StartedAt: startTime,
+		}
+		defer func() {
+			run.DurationMs = time.Since(startTime).Milliseconds()
+			if recordErr := h.store.RecordCrawlRun(context.WithoutCancel(ctx), run); recordErr != nil {
+				log.Error().Err(recordErr).Msg("Failed to record crawl run")
+			}
+		}()
+
+		for i, code := range codes {
+			if ctx.Err() != nil {
+				tally.skipped = len(codes) - i
+				tally.cancelled = true
+				break
+			}
+			if h.importCode(ctx, code, tally, run) {
+				tally.skipped = len(codes) - i
+				break
+			}
+			job.setProgress(fmt.Sprintf("%d/%d", tally.done(), len(codes)))
+			if progressID != 0 && (i+1)%importProgressEvery == 0 && i+1 < len(codes) {
+				if err := h.telegram.EditMessageText(chatID, progressID, formatImportProgress(tally, len(codes))); err != nil {
+					log.Warn().Err(err).Int64("chatID", chatID).Msg("Failed to update import progress")
+				}
+			}
+		}
+		log.Info().
+			Int("codes", len(codes)).
+			Int("saved", tally.saved).
+			Int("existing", tally.existing).
+			Int("notFound", len(tally.notFound)).
+			Int("failed", len(tally.failed)).
+			Int("skipped", tally.skipped).
+			Msg("Import finished")
+
+		result := formatImportResult(tally, len(codes), time.Since(startTime))
+		if progressID != 0 {
+			if err := h.telegram.EditMessageText(chatID, progressID, result); err == nil {
+				return
+			}
+		}
+		if err := h.telegram.SendMessage(chatID, result); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send import results")
+		}
+	})
+	if err != nil {
+		h.sendError(ctx, chatID, tooManyJobsMessage)
+		return
+	}
+	if ahead > 0 {
+		if err := h.telegram.SendMessage(chatID, queuedMessage(job, ahead)); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send import queue position")
+		}
+	}
+}
+
+// importCode crawls and saves one code of an /import, counting the outcome in tally
+// and the crawl statistics in run. It returns true when the crawl budget ran out.
+func (h *Handler) importCode(ctx context.Context, code string, tally *importTally, run *model.CrawlRun) bool {
+	exists, err := h.store.ExistsByCode(ctx, code)
+	if err != nil {
+		log.Error().Err(err).Str("code", code).Msg("Failed to check imported code")
+		tally.failed = append(tally.failed, code)
+		return false
+	}
+	if exists {
+		tally.existing++
+		return false
+	}
+
+	result, err := h.crawler.CrawlByCode(ctx, code)
+	if result != nil {
+		run.Found += len(result.Videos)
+		run.PagesFetched += result.PagesFetched
+		run.HTTPFetches += result.HTTPFetches
+		run.BrowserFetches += result.BrowserFetches
+		run.ParseFailures += result.ParseFailures
+	}
+	if err != nil {
+		if errors.Is(err, crawler.ErrBudgetExhausted) {
+			run.Error = err.Error()
+			return true
+		}
+		log.Warn().Err(err).Str("code", code).Msg("Failed to crawl imported code")
+		tally.failed = append(tally.failed, code)
+		return false
+	}
+	if len(result.Videos) == 0 {
+		tally.notFound = append(tally.notFound, code)
+		return false
+	}
+
+	saved, duplicates, err := h.store.SaveVideos(ctx, result.Videos)
+	run.Saved += saved
+	run.Duplicates += duplicates
+	if err != nil {
+		log.Error().Err(err).Str("code", code).Msg("Failed to save imported videos")
+		tally.failed = append(tally.failed, code)
+		return false
+	}
+	if saved > 0 {
+		tally.saved++
+	} else {
+		tally.existing++
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseImportCodes(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"no codes here", nil},
+		{"abc-123 DEF-456", []string{"ABC-123", "DEF-456"}},
+		{"ssis-001\nSSIS-001-uncensored-leak\r\nhttps://missav.ws/ipx-100-chinese-subtitle", []string{"SSIS-001", "IPX-100"}},
+		{"ABC-123, abc-123; DEF-456", []string{"ABC-123", "DEF-456"}},
+	}
+
+	for _, tt := range tests {
+		got := parseImportCodes(tt.text)
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("parseImportCodes(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFormatImportResult(t *testing.T) {
+	tally := &importTally{
+		saved:    3,
+		existing: 2,
+		notFound: []string{"ABC-123", "DEF-456"},
+		failed:   []string{"GHI-789"},
+		skipped:  4,
+	}
+	text := formatImportResult(tally, 12, 90*time.Second)
+	for _, want := range []string{
+		"共 12 个番号",
+		"新增: 3 个",
+		"已存在: 2 个",
+		"未找到: 2 个 (ABC-123, DEF-456)",
+		"失败: 1 个 (GHI-789)",
+		"4 个番号未爬取",
+		"耗时: 1m30s",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result missing %q:\n%s", want, text)
+		}
+	}
+
+	if progress := formatImportProgress(tally, 12); !strings.Contains(progress, "8/12") {
+		t.Errorf("formatImportProgress() = %q, want 8/12 done", progress)
+	}
+}
+
+func TestFormatImportResult_Cancelled(t *testing.T) {
+	tally := &importTally{saved: 2, skipped: 8, cancelled: true}
+	text := formatImportResult(tally, 10, time.Second)
+	for _, want := range []string{"导入已取消", "8 个番号未爬取"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "额度") {
+		t.Errorf("cancelled import blamed the budget:\n%s", text)
+	}
+}
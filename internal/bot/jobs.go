@@ -0,0 +1,254 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// crawlJobsPerChat caps the queued and running crawl jobs of one chat
+const crawlJobsPerChat = 3
+
+// errTooManyJobs is returned when a chat already has crawlJobsPerChat jobs queued or running
+var errTooManyJobs = errors.New("too many crawl jobs for this chat")
+
+// crawlJob is a queued or running /crawl, /import or /refresh, tracked so admins can list and cancel it
+type crawlJob struct {
+	id     int
+	kind   string // the command and its arguments, e.g. "crawl actor 三上悠亜"
+	chatID int64
+	ctx    context.Context
+	cancel context.CancelFunc
+	run    func(ctx context.Context, job *crawlJob)
+
+	mu       sync.Mutex
+	started  time.Time // zero while queued
+	progress string
+}
+
+// setProgress records how far the job has got, shown by /crawl status
+func (j *crawlJob) setProgress(progress string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.progress = progress
+}
+
+// Progress returns what setProgress last recorded
+func (j *crawlJob) Progress() string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.progress
+}
+
+// Started returns when the job left the queue, or the zero time while it is queued
+func (j *crawlJob) Started() time.Time {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.started
+}
+
+// jobRegistry queues the manual crawl jobs of a handler and runs them one at a time,
+// in order, so concurrent requests don't fight over the crawler's rate limiter.
+// The consumer goroutine runs only while jobs are queued; the zero value is ready to use.
+type jobRegistry struct {
+	mu      sync.Mutex
+	next    int
+	jobs    map[int]*crawlJob // queued and running
+	queue   []*crawlJob
+	running bool // the consumer goroutine is running
+	// panicked tells the chat of a job whose run panicked; nil skips the reply
+	panicked func(job *crawlJob)
+}
+
+// enqueue queues run as a job of chatID and returns it with the number of jobs
+// ahead of it. The job runs under a context detached from the update like
+// detachUpdate, which is canceled by /crawl cancel.
+func (r *jobRegistry) enqueue(ctx context.Context, kind string, chatID int64, run func(ctx context.Context, job *crawlJob)) (*crawlJob, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pending := 0
+	for _, job := range r.jobs {
+		if job.chatID == chatID {
+			pending++
+		}
+	}
+	if pending >= crawlJobsPerChat {
+		return nil, 0, errTooManyJobs
+	}
+
+	if r.jobs == nil {
+		r.jobs = make(map[int]*crawlJob)
+	}
+	r.next++
+	job := &crawlJob{id: r.next, kind: kind, chatID: chatID, run: run}
+	job.ctx, job.cancel = detachUpdate(ctx)
+	ahead := len(r.jobs)
+	r.jobs[job.id] = job
+	r.queue = append(r.queue, job)
+
+	if !r.running {
+		r.running = true
+		go r.consume()
+	}
+	return job, ahead, nil
+}
+
+// consume runs the queued jobs in order until the queue is empty
+func (r *jobRegistry) consume() {
+	for {
+		r.mu.Lock()
+		if len(r.queue) == 0 {
+			r.running = false
+			r.mu.Unlock()
+			return
+		}
+		job := r.queue[0]
+		r.queue = r.queue[1:]
+		r.mu.Unlock()
+
+		r.runJob(job)
+	}
+}
+
+// runJob runs a dequeued job unless it was cancelled, then finishes it
+// A panicking job is logged and reported to its chat, so it cannot take down the
+// process or leave the queue stuck.
+func (r *jobRegistry) runJob(job *crawlJob) {
+	defer r.finish(job)
+	defer func() {
+		if p := recover(); p != nil {
+			log.Error().
+				Interface("panic", p).
+				Str("stack", string(debug.Stack())).
+				Int("job", job.id).
+				Str("kind", job.kind).
+				Int64("chatID", job.chatID).
+				Msg("Crawl job panicked")
+			if r.panicked != nil {
+				r.panicked(job)
+			}
+		}
+	}()
+
+	if job.ctx.Err() != nil {
+		return
+	}
+	job.mu.Lock()
+	job.started = time.Now()
+	job.mu.Unlock()
+	job.run(job.ctx, job)
+}
+
+// finish releases a job's context and forgets it
+func (r *jobRegistry) finish(job *crawlJob) {
+	job.cancel()
+	r.mu.Lock()
+	delete(r.jobs, job.id)
+	r.mu.Unlock()
+}
+
+// cancel stops a running job or drops a queued one, and reports whether it was found
+func (r *jobRegistry) cancel(id int) bool {
+	r.mu.Lock()
+	job, ok := r.jobs[id]
+	if ok {
+		for i, queued := range r.queue {
+			if queued == job {
+				r.queue = append(r.queue[:i:i], r.queue[i+1:]...)
+				delete(r.jobs, id)
+				break
+			}
+		}
+	}
+	r.mu.Unlock()
+	if ok {
+		job.cancel()
+	}
+	return ok
+}
+
+// list returns the queued and running jobs, in queue order
+func (r *jobRegistry) list() []*crawlJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	jobs := make([]*crawlJob, 0, len(r.jobs))
+	for _, job := range r.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].id < jobs[j].id })
+	return jobs
+}
+
+// queuedMessage tells the requester where a new job stands in the queue
+func queuedMessage(job *crawlJob, ahead int) string {
+	return fmt.Sprintf("🕒 已加入爬取队列 (#%d)，前面还有 %d 个任务，轮到时会开始。", job.id, ahead)
+}
+
+// tooManyJobsMessage is sent when a chat hits crawlJobsPerChat
+var tooManyJobsMessage = fmt.Sprintf("⏳ 本聊天已有 %d 个爬取任务在排队或运行，请等待完成后再试。", crawlJobsPerChat)
+
+// formatJobs renders the queued and running crawl jobs as plain text
+func formatJobs(jobs []*crawlJob, now time.Time) string {
+	if len(jobs) == 0 {
+		return "📭 没有排队或运行中的爬取任务。"
+	}
+	lines := []string{fmt.Sprintf("🔄 排队和运行中的爬取任务 (%d):", len(jobs))}
+	for _, job := range jobs {
+		line := fmt.Sprintf("#%d %s · 排队中", job.id, job.kind)
+		if started := job.Started(); !started.IsZero() {
+			line = fmt.Sprintf("#%d %s · 已运行 %s", job.id, job.kind, now.Sub(started).Round(time.Second))
+		}
+		if progress := job.Progress(); progress != "" {
+			line += " · " + progress
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "使用 /crawl cancel 编号 取消任务。")
+	return strings.Join(lines, "\n")
+}
+
+// handleCrawlStatus handles /crawl status, listing the queued and running crawl jobs
+func (h *Handler) handleCrawlStatus(ctx context.Context, chatID int64) {
+	if err := h.telegram.SendMessage(chatID, formatJobs(h.jobs.list(), time.Now())); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send crawl jobs")
+	}
+}
+
+// handleCrawlCancel handles /crawl cancel [id]
+// Without an ID it cancels the only job, or lists them when there are several.
+func (h *Handler) handleCrawlCancel(ctx context.Context, chatID int64, args string) {
+	var id int
+	if args == "" {
+		jobs := h.jobs.list()
+		if len(jobs) != 1 {
+			h.handleCrawlStatus(ctx, chatID)
+			return
+		}
+		id = jobs[0].id
+	} else {
+		var err error
+		id, err = strconv.Atoi(strings.TrimPrefix(args, "#"))
+		if err != nil {
+			h.sendError(ctx, chatID, "用法: /crawl cancel [编号]，编号见 /crawl status")
+			return
+		}
+	}
+
+	if !h.jobs.cancel(id) {
+		h.sendError(ctx, chatID, fmt.Sprintf("没有编号为 #%d 的爬取任务，可能已经结束。", id))
+		return
+	}
+	log.Info().Int("job", id).Int64("chatID", chatID).Msg("Crawl job cancelled")
+	if err := h.telegram.SendMessage(chatID, fmt.Sprintf("⏹ 已取消爬取任务 #%d。", id)); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send crawl cancel confirmation")
+	}
+}
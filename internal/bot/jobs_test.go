@@ -0,0 +1,159 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJobRegistry_Queue(t *testing.T) {
+	var jobs jobRegistry
+	release := make(chan struct{})
+	var order []int
+	done := make(chan struct{})
+
+	first, ahead, err := jobs.enqueue(context.Background(), "crawl new", 1, func(ctx context.Context, job *crawlJob) {
+		order = append(order, job.id)
+		<-release
+	})
+	if err != nil || ahead != 0 {
+		t.Fatalf("enqueue() = %d, %v, want 0 ahead", ahead, err)
+	}
+	second, ahead, err := jobs.enqueue(context.Background(), "crawl tag 巨乳", 2, func(ctx context.Context, job *crawlJob) {
+		order = append(order, job.id)
+	})
+	if err != nil || ahead != 1 {
+		t.Fatalf("enqueue() = %d, %v, want 1 ahead", ahead, err)
+	}
+	cancelled, ahead, err := jobs.enqueue(context.Background(), "crawl code ABC-123", 2, func(ctx context.Context, job *crawlJob) {
+		t.Error("cancelled job ran")
+	})
+	if err != nil || ahead != 2 {
+		t.Fatalf("enqueue() = %d, %v, want 2 ahead", ahead, err)
+	}
+	_, _, err = jobs.enqueue(context.Background(), "import 3 codes", 2, func(ctx context.Context, job *crawlJob) {
+		order = append(order, job.id)
+		close(done)
+	})
+	if err != nil {
+		t.Fatalf("enqueue() = %v", err)
+	}
+
+	if list := jobs.list(); len(list) != 4 || list[0] != first || list[1] != second {
+		t.Fatalf("list() = %v, want all jobs in queue order", list)
+	}
+	if !jobs.cancel(cancelled.id) {
+		t.Fatal("cancel() did not find a queued job")
+	}
+	if cancelled.ctx.Err() == nil {
+		t.Error("cancel() did not cancel the queued job's context")
+	}
+	if second.Started() != (time.Time{}) {
+		t.Error("queued job has a start time")
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("queued jobs did not run")
+	}
+	if len(order) != 3 || order[0] != first.id || order[1] != second.id {
+		t.Errorf("jobs ran in order %v, want FIFO", order)
+	}
+}
+
+func TestJobRegistry_PerChatLimit(t *testing.T) {
+	var jobs jobRegistry
+	release := make(chan struct{})
+	defer close(release)
+
+	for i := 0; i < crawlJobsPerChat; i++ {
+		if _, _, err := jobs.enqueue(context.Background(), "crawl new", 1, func(ctx context.Context, job *crawlJob) {
+			<-release
+		}); err != nil {
+			t.Fatalf("enqueue() #%d = %v", i+1, err)
+		}
+	}
+	if _, _, err := jobs.enqueue(context.Background(), "crawl new", 1, nil); !errors.Is(err, errTooManyJobs) {
+		t.Errorf("enqueue() over the limit = %v, want errTooManyJobs", err)
+	}
+	if _, _, err := jobs.enqueue(context.Background(), "crawl new", 2, func(ctx context.Context, job *crawlJob) {}); err != nil {
+		t.Errorf("enqueue() for another chat = %v", err)
+	}
+}
+
+func TestJobRegistry_CancelRunning(t *testing.T) {
+	var jobs jobRegistry
+	running := make(chan struct{})
+	stopped := make(chan struct{})
+
+	job, _, err := jobs.enqueue(context.Background(), "crawl new", 1, func(ctx context.Context, job *crawlJob) {
+		close(running)
+		<-ctx.Done()
+		close(stopped)
+	})
+	if err != nil {
+		t.Fatalf("enqueue() = %v", err)
+	}
+	<-running
+	if !jobs.cancel(job.id) {
+		t.Fatal("cancel() did not find the job")
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("running job was not cancelled")
+	}
+}
+
+func TestJobRegistry_RecoversPanic(t *testing.T) {
+	reported := make(chan int, 1)
+	jobs := jobRegistry{panicked: func(job *crawlJob) { reported <- job.id }}
+	done := make(chan struct{})
+
+	broken, _, err := jobs.enqueue(context.Background(), "crawl new", 1, func(ctx context.Context, job *crawlJob) {
+		panic("broken crawl")
+	})
+	if err != nil {
+		t.Fatalf("enqueue() = %v", err)
+	}
+	if _, _, err := jobs.enqueue(context.Background(), "crawl new", 1, func(ctx context.Context, job *crawlJob) {
+		close(done)
+	}); err != nil {
+		t.Fatalf("enqueue() = %v", err)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("queue stalled after a panicking job")
+	}
+	if id := <-reported; id != broken.id {
+		t.Errorf("panicked() reported job #%d, want #%d", id, broken.id)
+	}
+	for _, job := range jobs.list() {
+		if job == broken {
+			t.Error("panicking job was not finished")
+		}
+	}
+}
+
+func TestFormatJobs(t *testing.T) {
+	now := time.Now()
+	if text := formatJobs(nil, now); !strings.Contains(text, "没有排队或运行中") {
+		t.Errorf("formatJobs(nil) = %q", text)
+	}
+
+	job := &crawlJob{id: 3, kind: "import 10 codes", started: now.Add(-90 * time.Second)}
+	job.setProgress("4/10")
+	queued := &crawlJob{id: 4, kind: "crawl new"}
+	text := formatJobs([]*crawlJob{job, queued}, now)
+	for _, want := range []string{"#3 import 10 codes", "1m30s", "4/10", "#4 crawl new · 排队中", "/crawl cancel"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatJobs() missing %q:\n%s", want, text)
+		}
+	}
+}
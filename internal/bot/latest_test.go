@@ -0,0 +1,32 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestParseLatestArgs(t *testing.T) {
+	tests := []struct {
+		args        string
+		wantType    model.SubscriptionType
+		wantKeyword string
+		wantPage    int
+	}{
+		{"", model.SubTypeAll, "", 1},
+		{"3", model.SubTypeAll, "", 3},
+		{"0", model.SubTypeAll, "", 1},
+		{"#単体", model.SubTypeTag, "単体", 1},
+		{"#単体 2", model.SubTypeTag, "単体", 2},
+		{"三上悠亜", model.SubTypeActress, "三上悠亜", 1},
+		{"Yua Mikami 4", model.SubTypeActress, "Yua Mikami", 4},
+	}
+
+	for _, tt := range tests {
+		subType, keyword, page := parseLatestArgs(tt.args)
+		if subType != tt.wantType || keyword != tt.wantKeyword || page != tt.wantPage {
+			t.Errorf("parseLatestArgs(%q) = (%s, %q, %d), want (%s, %q, %d)",
+				tt.args, subType, keyword, page, tt.wantType, tt.wantKeyword, tt.wantPage)
+		}
+	}
+}
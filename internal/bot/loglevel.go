@@ -0,0 +1,43 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/config"
+)
+
+// handleLogLevel handles /loglevel command
+// Without arguments it shows the current level; a level argument applies until the next
+// restart, after which LOG_LEVEL is used again.
+func (h *Handler) handleLogLevel(ctx context.Context, chatID int64, args string) {
+	current := zerolog.GlobalLevel()
+	if args == "" {
+		message := fmt.Sprintf("📝 当前日志级别: %s\n用法: /loglevel trace|debug|info|warn|error", current)
+		if err := h.sendReply(ctx, chatID, message); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send log level")
+		}
+		return
+	}
+
+	level, err := config.ParseLogLevel(args)
+	if err != nil {
+		h.sendError(ctx, chatID, "未知日志级别，可选: trace、debug、info、warn、error")
+		return
+	}
+
+	// Logged before the change so raising the level does not hide it
+	log.Info().
+		Int64("chatID", chatID).
+		Str("from", current.String()).
+		Str("to", level.String()).
+		Msg("Log level changed")
+	zerolog.SetGlobalLevel(level)
+
+	message := fmt.Sprintf("✅ 日志级别已从 %s 改为 %s，重启后恢复为 LOG_LEVEL 配置。", current, level)
+	if err := h.sendReply(ctx, chatID, message); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send log level confirmation")
+	}
+}
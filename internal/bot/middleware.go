@@ -0,0 +1,186 @@
+package bot
+
+import (
+	"context"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+)
+
+// commandRequest is a command invocation passed down the middleware chain
+type commandRequest struct {
+	msg      *tgbotapi.Message
+	chatID   int64
+	chatType string
+	userID   int64 // sender, 0 when the message has none
+	command  string
+	args     string
+	// label names the command in usage records; the router replaces unknown commands
+	label string
+}
+
+// newCommandRequest parses a command message
+func newCommandRequest(msg *tgbotapi.Message) *commandRequest {
+	var userID int64
+	if msg.From != nil {
+		userID = msg.From.ID
+	}
+	return &commandRequest{
+		msg:      msg,
+		chatID:   msg.Chat.ID,
+		chatType: msg.Chat.Type,
+		userID:   userID,
+		command:  msg.Command(),
+		args:     strings.TrimSpace(msg.CommandArguments()),
+		label:    msg.Command(),
+	}
+}
+
+// commandFunc handles a command request
+type commandFunc func(ctx context.Context, req *commandRequest)
+
+// commandMiddleware wraps a commandFunc with behavior shared by all commands
+type commandMiddleware func(next commandFunc) commandFunc
+
+// chainCommands wraps handler in middlewares; the first middleware runs outermost
+func chainCommands(handler commandFunc, middlewares ...commandMiddleware) commandFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// commandChain builds the pipeline every command runs through:
+// logging, panic recovery, throttling, usage metrics and authorization, then routing.
+// Recovery sits right inside logging so panics in the other middlewares are caught too.
+// Throttled commands are dropped before they are recorded, so spam costs no store writes.
+func (h *Handler) commandChain() commandFunc {
+	return chainCommands(h.routeCommand,
+		h.logCommands,
+		h.recoverCommands,
+		h.throttleCommands,
+		h.measureCommands,
+		h.authorizeCommands,
+	)
+}
+
+// logCommands logs each received command and how long it took
+func (h *Handler) logCommands(next commandFunc) commandFunc {
+	return func(ctx context.Context, req *commandRequest) {
+		log.Info().
+			Int64("chatID", req.chatID).
+			Str("command", req.command).
+			Str("args", req.args).
+			Msg("Received command")
+
+		start := time.Now()
+		next(ctx, req)
+		log.Debug().
+			Int64("chatID", req.chatID).
+			Str("command", req.command).
+			Dur("duration", time.Since(start)).
+			Msg("Handled command")
+	}
+}
+
+// throttleCommands drops commands over the per-user rate limit
+func (h *Handler) throttleCommands(next commandFunc) commandFunc {
+	return func(ctx context.Context, req *commandRequest) {
+		if !h.allowCommand(req.msg) {
+			return
+		}
+		next(ctx, req)
+	}
+}
+
+// measureCommands records the outcome and latency of each command
+func (h *Handler) measureCommands(next commandFunc) commandFunc {
+	return func(ctx context.Context, req *commandRequest) {
+		start := time.Now()
+		usage := &commandUsage{}
+		ctx = withCommandUsage(ctx, usage)
+		defer func() {
+			h.recordCommandUsage(ctx, req.chatID, req.label, start, usage)
+		}()
+		next(ctx, req)
+	}
+}
+
+// recoverCommands turns a panic in a command handler into an error reply,
+// so one broken command cannot take down the update loop
+func (h *Handler) recoverCommands(next commandFunc) commandFunc {
+	return func(ctx context.Context, req *commandRequest) {
+		defer h.recoverUpdate(ctx, req.chatID, "command", req.command)
+		next(ctx, req)
+	}
+}
+
+// recoverUpdate, deferred by an update handler, logs a panic and replies with an error
+// key and value name what was being handled, e.g. the command or callback data
+func (h *Handler) recoverUpdate(ctx context.Context, chatID int64, key, value string) {
+	if r := recover(); r != nil {
+		log.Error().
+			Interface("panic", r).
+			Str("stack", string(debug.Stack())).
+			Int64("chatID", chatID).
+			Str(key, value).
+			Msg("Update handler panicked")
+		h.sendError(ctx, chatID, "命令执行出错，请稍后重试。")
+	}
+}
+
+// commandAccess is who may run a command
+type commandAccess int
+
+const (
+	// accessAnyone lets every user run the command
+	accessAnyone commandAccess = iota
+	// accessManager limits the command to those managing the chat, see requireManager
+	accessManager
+	// accessAdmin limits the command to the bot admins
+	accessAdmin
+)
+
+// commandAccessLevels lists the restricted commands; all others are open to anyone
+var commandAccessLevels = map[string]commandAccess{
+	"subscribe":   accessManager,
+	"unsubscribe": accessManager,
+	"watch":       accessManager,
+	"unwatch":     accessManager,
+	"mute":        accessManager,
+	"unmute":      accessManager,
+	"blacklist":   accessManager,
+	"settings":    accessManager,
+	"crawllog":    accessAdmin,
+	"alias":       accessAdmin,
+	"duplicates":  accessAdmin,
+	"discord":     accessAdmin,
+	"revoke":      accessAdmin,
+	"delete":      accessAdmin,
+	"refresh":     accessAdmin,
+	"scheduler":   accessAdmin,
+	"debug":       accessAdmin,
+	"loglevel":    accessAdmin,
+	"selftest":    accessAdmin,
+}
+
+// authorizeCommands rejects restricted commands from users without access
+func (h *Handler) authorizeCommands(next commandFunc) commandFunc {
+	return func(ctx context.Context, req *commandRequest) {
+		switch commandAccessLevels[req.command] {
+		case accessManager:
+			if !h.requireManager(ctx, req.msg) {
+				return
+			}
+		case accessAdmin:
+			if !h.isAdmin(req.msg) {
+				h.deny(ctx, req.chatID)
+				return
+			}
+		}
+		next(ctx, req)
+	}
+}
@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestChainCommands_FirstMiddlewareRunsOutermost(t *testing.T) {
+	var calls []string
+	mark := func(name string) commandMiddleware {
+		return func(next commandFunc) commandFunc {
+			return func(ctx context.Context, req *commandRequest) {
+				calls = append(calls, name+">")
+				next(ctx, req)
+				calls = append(calls, "<"+name)
+			}
+		}
+	}
+
+	handler := chainCommands(func(ctx context.Context, req *commandRequest) {
+		calls = append(calls, "handler")
+	}, mark("a"), mark("b"))
+	handler(context.Background(), &commandRequest{})
+
+	got := strings.Join(calls, " ")
+	if want := "a> b> handler <b <a"; got != want {
+		t.Errorf("call order = %q, want %q", got, want)
+	}
+}
+
+func TestCommandAccessLevels_PushSetupNeedsManager(t *testing.T) {
+	for _, command := range []string{"subscribe", "unsubscribe", "watch", "unwatch"} {
+		if commandAccessLevels[command] != accessManager {
+			t.Errorf("/%s access = %v, want accessManager", command, commandAccessLevels[command])
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// updateOffsetSaveInterval is how often the polling offset is written to the store
+// A crash replays at most the updates processed since the last save.
+const updateOffsetSaveInterval = 10 * time.Second
+
+// updateOffset remembers the next Telegram update to process and when it was last stored
+type updateOffset struct {
+	mu      sync.Mutex
+	next    int
+	saved   int
+	savedAt time.Time
+}
+
+// StartOffset returns the offset to start polling from
+// It resumes after the last processed update, or skips everything Telegram still
+// holds when BOT_DROP_PENDING_UPDATES is set. Errors fall back to Telegram's default.
+func (h *Handler) StartOffset(ctx context.Context) int {
+	var offset int
+	var err error
+	if h.config != nil && h.config.DropPendingUpdates {
+		offset, err = h.telegram.SkipPendingUpdates()
+	} else {
+		offset, err = h.store.GetUpdateOffset(ctx, h.botID)
+	}
+	if err != nil {
+		log.Warn().Err(err).Int64("botID", h.botID).Msg("Failed to determine update offset, polling from the oldest pending update")
+		offset = 0
+	}
+
+	h.offset.mu.Lock()
+	h.offset.next, h.offset.saved, h.offset.savedAt = offset, offset, time.Now()
+	h.offset.mu.Unlock()
+
+	log.Info().Int64("botID", h.botID).Int("offset", offset).Msg("Polling Telegram updates")
+	return offset
+}
+
+// MarkProcessed records that an update was handled, storing the offset when it is due
+func (h *Handler) MarkProcessed(ctx context.Context, updateID int) {
+	h.offset.mu.Lock()
+	if updateID+1 > h.offset.next {
+		h.offset.next = updateID + 1
+	}
+	due := time.Since(h.offset.savedAt) >= updateOffsetSaveInterval
+	h.offset.mu.Unlock()
+
+	if due {
+		h.SaveOffset(ctx)
+	}
+}
+
+// SaveOffset stores the offset following the last processed update, if it changed
+// Called on shutdown once polling has stopped.
+func (h *Handler) SaveOffset(ctx context.Context) {
+	h.offset.mu.Lock()
+	defer h.offset.mu.Unlock()
+	if h.offset.next == h.offset.saved {
+		return
+	}
+
+	// A failed save is retried after the interval rather than on every update
+	h.offset.savedAt = time.Now()
+	if err := h.store.SaveUpdateOffset(ctx, h.botID, h.offset.next); err != nil {
+		log.Warn().Err(err).Int64("botID", h.botID).Int("offset", h.offset.next).Msg("Failed to save update offset")
+		return
+	}
+	h.offset.saved = h.offset.next
+}
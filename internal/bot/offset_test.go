@@ -0,0 +1,51 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+// offsetStore keeps a bot's update offset in memory
+type offsetStore struct {
+	store.Store
+	offset int
+	saves  int
+}
+
+func (s *offsetStore) GetUpdateOffset(ctx context.Context, botID int64) (int, error) {
+	return s.offset, nil
+}
+
+func (s *offsetStore) SaveUpdateOffset(ctx context.Context, botID int64, offset int) error {
+	s.offset = offset
+	s.saves++
+	return nil
+}
+
+func TestUpdateOffset_ResumesAfterLastProcessed(t *testing.T) {
+	st := &offsetStore{offset: 100}
+	h := &Handler{store: st}
+	ctx := context.Background()
+
+	if got := h.StartOffset(ctx); got != 100 {
+		t.Fatalf("StartOffset() = %d, want 100", got)
+	}
+
+	// Offsets are written at most once per interval while polling
+	h.MarkProcessed(ctx, 100)
+	h.MarkProcessed(ctx, 101)
+	if st.saves != 0 {
+		t.Errorf("saved %d times within the interval, want 0", st.saves)
+	}
+
+	h.SaveOffset(ctx)
+	if st.offset != 102 {
+		t.Errorf("saved offset = %d, want 102", st.offset)
+	}
+	h.SaveOffset(ctx)
+	if st.saves != 1 {
+		t.Errorf("saved %d times, want an unchanged offset saved once", st.saves)
+	}
+}
@@ -0,0 +1,91 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/config"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// latestPageSize is the number of /latest videos per page when BOT_LATEST_PAGE_SIZE is unset
+const latestPageSize = 5
+
+// parsePageSize parses a searchlimit or latestsize setting value: a count, or default
+// Default parses as 0, which falls back to the configured size.
+// Returns false as the second value when the input is not recognized or out of range.
+func parsePageSize(value string) (int, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "default" || value == "默认" {
+		return 0, true
+	}
+	size, err := strconv.Atoi(value)
+	if err != nil || size < 1 || size > config.MaxResultPageSize {
+		return 0, false
+	}
+	return size, true
+}
+
+// pageSizeLabel describes a page size setting in Chinese
+func pageSizeLabel(size int) string {
+	if size <= 0 {
+		return "默认"
+	}
+	return fmt.Sprintf("%d 条", size)
+}
+
+// setPageSize handles /settings searchlimit|latestsize <count>|default
+func (h *Handler) setPageSize(ctx context.Context, chatID int64, settings *model.ChatSettings, key string, value string) {
+	size, ok := parsePageSize(value)
+	if !ok {
+		h.sendError(ctx, chatID, fmt.Sprintf("用法: /settings %s 1-%d|default", key, config.MaxResultPageSize))
+		return
+	}
+
+	label := "搜索结果数量"
+	if key == settingSearchLimit {
+		settings.SearchLimit = size
+	} else {
+		settings.LatestPageSize = size
+		label = "/latest 每页数量"
+	}
+	if err := h.store.SaveChatSettings(ctx, settings); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to save chat settings")
+		h.sendError(ctx, chatID, "保存设置失败，请重试。")
+		return
+	}
+
+	if err := h.telegram.SendMessage(chatID, fmt.Sprintf("✅ %s: %s", label, pageSizeLabel(size))); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send settings confirmation")
+	}
+}
+
+// pageSizes returns the /search result limit and /latest page size of a chat:
+// its own settings, else BOT_SEARCH_LIMIT and BOT_LATEST_PAGE_SIZE, else the built-in sizes
+func (h *Handler) pageSizes(ctx context.Context, chatID int64) (search int, latest int) {
+	search, latest = searchResultLimit, latestPageSize
+	if h.config != nil {
+		if h.config.SearchLimit > 0 {
+			search = h.config.SearchLimit
+		}
+		if h.config.LatestPageSize > 0 {
+			latest = h.config.LatestPageSize
+		}
+	}
+
+	settings, err := h.store.GetChatSettings(ctx, chatID)
+	if err != nil {
+		log.Warn().Err(err).Int64("chatID", chatID).Msg("Failed to get chat settings, using default page sizes")
+		return search, latest
+	}
+	if settings.SearchLimit > 0 {
+		search = settings.SearchLimit
+	}
+	if settings.LatestPageSize > 0 {
+		latest = settings.LatestPageSize
+	}
+	return search, latest
+}
@@ -0,0 +1,61 @@
+package bot
+
+import (
+	"strings"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// personalArg and personalDMArg prefix the arguments of personal subscriptions
+const (
+	personalArg   = "personal"
+	personalDMArg = "dm"
+)
+
+// groupAnonymousBotID is the sender Telegram shows for anonymous group admins
+const groupAnonymousBotID = 1087968824
+
+// parsePersonalArgs splits a leading "personal" or "personal dm" off subscription arguments
+// "personal" mentions the subscriber in the group; "personal dm" messages them privately.
+// Returns model.PersonalNone and the arguments unchanged when there is no prefix.
+func parsePersonalArgs(args string) (model.PersonalMode, string) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], personalArg) {
+		return model.PersonalNone, args
+	}
+	fields = fields[1:]
+	mode := model.PersonalMention
+	if len(fields) > 0 && strings.EqualFold(fields[0], personalDMArg) {
+		mode = model.PersonalDM
+		fields = fields[1:]
+	}
+	return mode, strings.Join(fields, " ")
+}
+
+// personalArgs parses personal subscription arguments in groups
+// Private chats belong to one user already, so the prefix is dropped there.
+func (h *Handler) personalArgs(chatType string, args string) (model.PersonalMode, string) {
+	mode, rest := parsePersonalArgs(args)
+	if chatType != "group" && chatType != "supergroup" {
+		return model.PersonalNone, rest
+	}
+	return mode, rest
+}
+
+// isPersonalSender reports whether a command sender can own a personal subscription
+// Channels and anonymous admins post without a user to mention or message.
+func isPersonalSender(userID int64) bool {
+	return userID != 0 && userID != groupAnonymousBotID
+}
+
+// personalNote explains how a new personal subscription is delivered
+func personalNote(mode model.PersonalMode) string {
+	switch mode {
+	case model.PersonalMention:
+		return "\n👤 个人订阅：匹配的视频推送到本群时会@你。"
+	case model.PersonalDM:
+		return "\n👤 个人订阅：匹配的视频将私聊发送给你，请确保已私聊机器人发送过 /start。"
+	default:
+		return ""
+	}
+}
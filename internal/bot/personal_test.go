@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestParsePersonalArgs(t *testing.T) {
+	tests := []struct {
+		args     string
+		wantMode model.PersonalMode
+		wantRest string
+	}{
+		{"", model.PersonalNone, ""},
+		{"三上悠亜", model.PersonalNone, "三上悠亜"},
+		{"personal", model.PersonalMention, ""},
+		{"personal #単体", model.PersonalMention, "#単体"},
+		{"Personal DM Yua Mikami", model.PersonalDM, "Yua Mikami"},
+		{"personality", model.PersonalNone, "personality"},
+	}
+
+	for _, tt := range tests {
+		mode, rest := parsePersonalArgs(tt.args)
+		if mode != tt.wantMode || rest != tt.wantRest {
+			t.Errorf("parsePersonalArgs(%q) = (%q, %q), want (%q, %q)",
+				tt.args, mode, rest, tt.wantMode, tt.wantRest)
+		}
+	}
+}
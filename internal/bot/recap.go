@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// setRecap handles /settings recap on|off
+// Enabling it starts the first recap week now, so the chat gets its first recap in a week.
+func (h *Handler) setRecap(ctx context.Context, chatID int64, settings *model.ChatSettings, value string) {
+	enabled, ok := parseToggle(value)
+	if !ok {
+		h.sendError(ctx, chatID, fmt.Sprintf("用法: /settings %s on|off", settingRecap))
+		return
+	}
+
+	if enabled && !settings.WeeklyRecap {
+		now := time.Now()
+		settings.RecapSentAt = &now
+	}
+	settings.WeeklyRecap = enabled
+	if err := h.store.SaveChatSettings(ctx, settings); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to save chat settings")
+		h.sendError(ctx, chatID, "保存设置失败，请重试。")
+		return
+	}
+
+	message := "✅ 已关闭每周回顾。"
+	if enabled {
+		message = "✅ 已开启每周回顾：每周汇总本聊天收到的视频（按演员和标签统计）以及因推送上限错过的视频。"
+	}
+	if err := h.telegram.SendMessage(chatID, message); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send settings confirmation")
+	}
+}
@@ -0,0 +1,180 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// refreshValueMaxRunes shortens long values, such as titles, in the /refresh diff
+const refreshValueMaxRunes = 40
+
+// fieldChange is a metadata field updated by /refresh
+type fieldChange struct {
+	field  string
+	before string
+	after  string
+}
+
+// mergeDetail overwrites video's metadata with the fields present on its freshly
+// crawled detail page and returns what changed
+// Fields the page lacks keep their stored value; Pushed and the code are never touched.
+func mergeDetail(video, detail *model.Video) []fieldChange {
+	var changes []fieldChange
+	setString := func(field string, stored *string, crawled string) {
+		if crawled != "" && crawled != *stored {
+			changes = append(changes, fieldChange{field: field, before: *stored, after: crawled})
+			*stored = crawled
+		}
+	}
+
+	setString("标题", &video.Title, detail.Title)
+	setString("演员", &video.Actresses, detail.Actresses)
+	setString("标签", &video.Tags, detail.Tags)
+	setString("封面", &video.CoverURL, detail.CoverURL)
+	setString("预览", &video.PreviewURL, detail.PreviewURL)
+
+	if detail.Duration > 0 && detail.Duration != video.Duration {
+		changes = append(changes, fieldChange{field: "时长", before: formatRefreshMinutes(video.Duration), after: formatRefreshMinutes(detail.Duration)})
+		video.Duration = detail.Duration
+	}
+	if detail.ReleaseDate != nil && (video.ReleaseDate == nil || !detail.ReleaseDate.Equal(*video.ReleaseDate)) {
+		changes = append(changes, fieldChange{field: "发行日期", before: formatRefreshDate(video.ReleaseDate), after: formatRefreshDate(detail.ReleaseDate)})
+		video.ReleaseDate = detail.ReleaseDate
+	}
+	if len(detail.Screenshots) > 0 && strings.Join(detail.Screenshots, "\n") != strings.Join(video.Screenshots, "\n") {
+		changes = append(changes, fieldChange{field: "截图", before: fmt.Sprintf("%d 张", len(video.Screenshots)), after: fmt.Sprintf("%d 张", len(detail.Screenshots))})
+		video.Screenshots = detail.Screenshots
+	}
+
+	video.Completeness = video.CompletenessScore()
+	return changes
+}
+
+// formatRefreshMinutes formats a duration in minutes for the /refresh diff
+func formatRefreshMinutes(minutes int) string {
+	if minutes <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d 分钟", minutes)
+}
+
+// formatRefreshDate formats a release date for the /refresh diff
+func formatRefreshDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format(time.DateOnly)
+}
+
+// formatRefreshValue shortens a value for the /refresh diff, marking empty ones
+func formatRefreshValue(value string) string {
+	if value == "" {
+		return "（空）"
+	}
+	runes := []rune(value)
+	if len(runes) > refreshValueMaxRunes {
+		return string(runes[:refreshValueMaxRunes-1]) + "…"
+	}
+	return value
+}
+
+// formatRefreshResult renders the outcome of /refresh as plain text
+func formatRefreshResult(code string, changes []fieldChange) string {
+	if len(changes) == 0 {
+		return fmt.Sprintf("🔄 已重新爬取 %s，信息没有变化。", code)
+	}
+	lines := []string{fmt.Sprintf("🔄 已刷新 %s，%d 项有变化:", code, len(changes))}
+	for _, change := range changes {
+		lines = append(lines, fmt.Sprintf("• %s: %s → %s", change.field, formatRefreshValue(change.before), formatRefreshValue(change.after)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// handleRefresh handles /refresh command
+// It crawls a stored video's detail page again, bypassing the page cache, stores the
+// updated metadata and replies with what changed. Messages already pushed for the video
+// are edited to match. The crawl is queued with the /crawl jobs and runs past the end
+// of the update.
+func (h *Handler) handleRefresh(ctx context.Context, chatID int64, args string) {
+	code := crawler.ExtractCode(args)
+	if code == "" {
+		h.sendError(ctx, chatID, "请提供番号。例如: /refresh ABC-123")
+		return
+	}
+
+	video, err := h.store.GetVideoByCode(ctx, code)
+	if err != nil {
+		log.Error().Err(err).Str("code", code).Msg("Failed to get video")
+		h.sendError(ctx, chatID, "查询失败，请重试。")
+		return
+	}
+	if video == nil {
+		h.sendError(ctx, chatID, fmt.Sprintf("📭 未找到视频: %s\n可使用 /crawl code %s 爬取。", code, code))
+		return
+	}
+	if video.DetailURL == "" {
+		h.sendError(ctx, chatID, fmt.Sprintf("%s 没有详情页链接，无法刷新。", code))
+		return
+	}
+
+	// Queue the refresh with the other crawl jobs, so it can be listed and cancelled
+	ctx = crawler.WithoutCache(crawler.WithoutBudget(ctx))
+	job, ahead, err := h.jobs.enqueue(ctx, "refresh "+video.Code, chatID, func(ctx context.Context, job *crawlJob) {
+		if err := h.telegram.SendMessage(chatID, "🔄 正在重新爬取... 请稍候。"); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send refresh acknowledgment")
+		}
+
+		job.setProgress("正在爬取")
+		detail, err := h.crawler.CrawlVideoDetail(ctx, video.DetailURL)
+		if err != nil {
+			if errors.Is(err, context.Canceled) && ctx.Err() != nil {
+				log.Info().Int("job", job.id).Str("code", video.Code).Msg("Refresh cancelled")
+				if sendErr := h.telegram.SendMessage(chatID, fmt.Sprintf("⏹ 爬取任务 #%d 已取消。", job.id)); sendErr != nil {
+					log.Error().Err(sendErr).Int64("chatID", chatID).Msg("Failed to send refresh cancelled message")
+				}
+				return
+			}
+			log.Warn().Err(err).Str("code", video.Code).Msg("Failed to refresh video")
+			h.sendError(ctx, chatID, fmt.Sprintf("爬取 %s 失败: %v", video.Code, err))
+			return
+		}
+
+		before := video.Completeness
+		changes := mergeDetail(video, detail)
+		now := time.Now()
+		video.EnrichedAt = &now
+		if err := h.store.UpdateVideoDetails(ctx, video); err != nil {
+			log.Error().Err(err).Str("code", video.Code).Msg("Failed to save refreshed video")
+			h.sendError(ctx, chatID, "保存失败，请重试。")
+			return
+		}
+		log.Info().Str("code", video.Code).Int("changes", len(changes)).Int("before", before).Int("after", video.Completeness).Msg("Refreshed video")
+
+		if video.Pushed && len(changes) > 0 && h.pushService != nil {
+			job.setProgress("正在更新已推送的消息")
+			if err := h.pushService.RefreshPushedVideo(ctx, video); err != nil {
+				log.Warn().Err(err).Str("code", video.Code).Msg("Failed to refresh pushed messages")
+			}
+		}
+
+		if err := h.telegram.SendMessage(chatID, formatRefreshResult(video.Code, changes)); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send refresh result")
+		}
+	})
+	if err != nil {
+		h.sendError(ctx, chatID, tooManyJobsMessage)
+		return
+	}
+	if ahead > 0 {
+		if err := h.telegram.SendMessage(chatID, queuedMessage(job, ahead)); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send refresh queue position")
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestMergeDetail(t *testing.T) {
+	release := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	video := &model.Video{
+		Code:      "SSIS-001",
+		Title:     "Old title",
+		Actresses: "三上悠亜",
+		Pushed:    true,
+	}
+	detail := &model.Video{
+		Title:       "New title",
+		CoverURL:    "https://example.com/cover.jpg",
+		Duration:    120,
+		ReleaseDate: &release,
+	}
+
+	changes := mergeDetail(video, detail)
+	if len(changes) != 4 {
+		t.Fatalf("changes = %+v, want title, cover, duration and release date", changes)
+	}
+	if video.Title != "New title" || video.CoverURL == "" || video.Duration != 120 || video.ReleaseDate == nil {
+		t.Errorf("video = %+v, want the crawled fields", video)
+	}
+	if video.Actresses != "三上悠亜" || !video.Pushed {
+		t.Errorf("video = %+v, want fields missing from the page and Pushed kept", video)
+	}
+	if video.Completeness != video.CompletenessScore() {
+		t.Errorf("Completeness = %d, want it rescored", video.Completeness)
+	}
+
+	if changes := mergeDetail(video, detail); len(changes) != 0 {
+		t.Errorf("second merge changes = %+v, want none", changes)
+	}
+}
+
+func TestFormatRefreshResult(t *testing.T) {
+	text := formatRefreshResult("SSIS-001", []fieldChange{
+		{field: "标题", before: "", after: strings.Repeat("长", 50)},
+		{field: "时长", before: "60 分钟", after: "120 分钟"},
+	})
+	for _, want := range []string{"2 项有变化", "标题: （空） → " + strings.Repeat("长", 39) + "…", "时长: 60 分钟 → 120 分钟"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result missing %q:\n%s", want, text)
+		}
+	}
+	if text := formatRefreshResult("SSIS-001", nil); !strings.Contains(text, "没有变化") {
+		t.Errorf("unchanged result = %q", text)
+	}
+}
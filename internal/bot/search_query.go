@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+// searchResultLimit is the number of /search results when BOT_SEARCH_LIMIT is unset (Requirement 3.8)
+const searchResultLimit = 10
+
+// searchSortAliases maps sort: values to store sort orders
+var searchSortAliases = map[string]store.VideoSort{
+	"new":      store.SortNewest,
+	"newest":   store.SortNewest,
+	"old":      store.SortOldest,
+	"oldest":   store.SortOldest,
+	"release":  store.SortRelease,
+	"long":     store.SortLongest,
+	"longest":  store.SortLongest,
+	"duration": store.SortLongest,
+}
+
+// parseSearchQuery parses /search arguments into a structured filter
+// Supported fields: actress:NAME, tag:NAME (or #NAME), min:MINUTES, max:MINUTES
+// and sort:new|old|release|long. Values may be double-quoted to include spaces.
+// Remaining words form the free-text keyword.
+func parseSearchQuery(query string) (*store.VideoFilter, error) {
+	filter := &store.VideoFilter{Limit: searchResultLimit}
+	var keywords []string
+
+	for _, token := range tokenizeSearchQuery(query) {
+		if strings.HasPrefix(token, "#") && len(token) > 1 {
+			filter.Tag = token[1:]
+			continue
+		}
+
+		field, value, ok := strings.Cut(token, ":")
+		if !ok || value == "" {
+			keywords = append(keywords, token)
+			continue
+		}
+
+		switch strings.ToLower(field) {
+		case "actress", "a":
+			filter.Actress = value
+		case "tag", "t":
+			filter.Tag = strings.TrimPrefix(value, "#")
+		case "min":
+			minutes, err := strconv.Atoi(value)
+			if err != nil || minutes < 0 {
+				return nil, fmt.Errorf("min 需要分钟数，例如 min:120")
+			}
+			filter.MinDuration = minutes
+		case "max":
+			minutes, err := strconv.Atoi(value)
+			if err != nil || minutes < 0 {
+				return nil, fmt.Errorf("max 需要分钟数，例如 max:180")
+			}
+			filter.MaxDuration = minutes
+		case "sort":
+			sort, ok := searchSortAliases[strings.ToLower(value)]
+			if !ok {
+				return nil, fmt.Errorf("未知排序: %s（可用: new, old, release, long）", value)
+			}
+			filter.Sort = sort
+		default:
+			// Not a field, e.g. a URL or a code containing a colon
+			keywords = append(keywords, token)
+		}
+	}
+
+	filter.Keyword = strings.Join(keywords, " ")
+	return filter, nil
+}
+
+// tokenizeSearchQuery splits a query on whitespace, keeping double-quoted sections together
+// Quotes are removed from the resulting tokens
+func tokenizeSearchQuery(query string) []string {
+	var tokens []string
+	var current strings.Builder
+	inQuotes := false
+
+	flush := func() {
+		if current.Len() > 0 {
+			tokens = append(tokens, current.String())
+			current.Reset()
+		}
+	}
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case unicode.IsSpace(r) && !inQuotes:
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return tokens
+}
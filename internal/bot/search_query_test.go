@@ -0,0 +1,73 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  store.VideoFilter
+	}{
+		{
+			name:  "plain keyword",
+			query: "ABC-123",
+			want:  store.VideoFilter{Keyword: "ABC-123", Limit: searchResultLimit},
+		},
+		{
+			name:  "all fields",
+			query: "actress:三上悠亜 tag:単体 min:120 sort:new",
+			want: store.VideoFilter{
+				Actress:     "三上悠亜",
+				Tag:         "単体",
+				MinDuration: 120,
+				Sort:        store.SortNewest,
+				Limit:       searchResultLimit,
+			},
+		},
+		{
+			name:  "hashtag and keyword",
+			query: "#巨乳 office lady max:90 sort:long",
+			want: store.VideoFilter{
+				Keyword:     "office lady",
+				Tag:         "巨乳",
+				MaxDuration: 90,
+				Sort:        store.SortLongest,
+				Limit:       searchResultLimit,
+			},
+		},
+		{
+			name:  "quoted value",
+			query: `actress:"Yua Mikami" SSIS`,
+			want:  store.VideoFilter{Keyword: "SSIS", Actress: "Yua Mikami", Limit: searchResultLimit},
+		},
+		{
+			name:  "unknown field kept as keyword",
+			query: "foo:bar",
+			want:  store.VideoFilter{Keyword: "foo:bar", Limit: searchResultLimit},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSearchQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parseSearchQuery(%q) error = %v", tt.query, err)
+			}
+			if *got != tt.want {
+				t.Errorf("parseSearchQuery(%q) = %+v, want %+v", tt.query, *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSearchQuery_InvalidValues(t *testing.T) {
+	for _, query := range []string{"min:abc", "max:-5", "sort:random"} {
+		if _, err := parseSearchQuery(query); err == nil {
+			t.Errorf("parseSearchQuery(%q) expected error, got nil", query)
+		}
+	}
+}
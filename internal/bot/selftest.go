@@ -0,0 +1,124 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/push"
+)
+
+// selftestStage is the outcome of one /selftest check
+type selftestStage struct {
+	name string
+	// skipped is set when an earlier stage failed and this one could not run
+	skipped  bool
+	err      error
+	detail   string
+	duration time.Duration
+}
+
+// handleSelftest handles /selftest command, checking the database, a one-page crawl,
+// the parser and sending, and reporting each stage
+// It runs past the end of the update because the crawl can take longer than its timeout.
+func (h *Handler) handleSelftest(ctx context.Context, chatID int64) {
+	if err := h.telegram.SendMessage(chatID, "🧪 开始自检... 请稍候。"); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send selftest acknowledgment")
+	}
+
+	ctx, cancel := detachUpdate(crawler.WithoutBudget(ctx))
+	go func() {
+		defer cancel()
+		stages := []selftestStage{h.checkDatabase(ctx)}
+
+		crawlStage, result := h.checkCrawl(ctx)
+		stages = append(stages, crawlStage)
+		if result != nil {
+			stages = append(stages, checkParse(result))
+		} else {
+			stages = append(stages, selftestStage{name: "解析", skipped: true})
+		}
+
+		start := time.Now()
+		err := h.telegram.SendMessage(chatID, "🧪 自检测试消息，收到即表示发送正常。")
+		stages = append(stages, selftestStage{name: "发送", err: err, duration: time.Since(start)})
+
+		if _, err := h.telegram.SendMarkdown(chatID, formatSelftestReport(stages)); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send selftest report")
+		}
+	}()
+}
+
+// checkDatabase pings the database
+func (h *Handler) checkDatabase(ctx context.Context) selftestStage {
+	start := time.Now()
+	err := h.store.Ping(ctx)
+	return selftestStage{name: "数据库", err: err, duration: time.Since(start)}
+}
+
+// checkCrawl fetches the first page of the latest video listing
+// The result is nil when the crawl failed.
+func (h *Handler) checkCrawl(ctx context.Context) (selftestStage, *crawler.CrawlResult) {
+	start := time.Now()
+	result, err := h.crawler.CrawlNewVideos(ctx, 1)
+	stage := selftestStage{name: "爬取", err: err, duration: time.Since(start)}
+	if err != nil || result == nil {
+		return stage, nil
+	}
+	stage.detail = fmt.Sprintf("抓取 %d 页, HTTP %d 次, 浏览器 %d 次", result.PagesFetched, result.HTTPFetches, result.BrowserFetches)
+	return stage, result
+}
+
+// checkParse validates that the crawl parsed videos with a code and detail URL
+// An empty listing means the site changed in a way the parser no longer understands.
+func checkParse(result *crawler.CrawlResult) selftestStage {
+	stage := selftestStage{name: "解析"}
+	if len(result.Videos) == 0 {
+		stage.err = fmt.Errorf("未解析到任何视频, 解析失败 %d 页", result.ParseFailures)
+		return stage
+	}
+
+	incomplete := 0
+	for _, video := range result.Videos {
+		if video.Code == "" || video.DetailURL == "" {
+			incomplete++
+		}
+	}
+	if incomplete > 0 {
+		stage.err = fmt.Errorf("%d/%d 个视频缺少番号或链接", incomplete, len(result.Videos))
+		return stage
+	}
+	stage.detail = fmt.Sprintf("解析到 %d 个视频, 如 %s", len(result.Videos), result.Videos[0].Code)
+	return stage
+}
+
+// formatSelftestReport renders the stage outcomes as MarkdownV2
+func formatSelftestReport(stages []selftestStage) string {
+	passed := 0
+	lines := make([]string, 0, len(stages)+1)
+	for _, stage := range stages {
+		var line string
+		switch {
+		case stage.skipped:
+			line = fmt.Sprintf("⏭ %s: 跳过", stage.name)
+		case stage.err != nil:
+			line = fmt.Sprintf("❌ %s: %s", stage.name, stage.err.Error())
+		default:
+			passed++
+			line = fmt.Sprintf("✅ %s: 通过", stage.name)
+			if stage.detail != "" {
+				line += ", " + stage.detail
+			}
+		}
+		if !stage.skipped {
+			line += fmt.Sprintf(" (%s)", stage.duration.Round(time.Millisecond))
+		}
+		lines = append(lines, push.EscapeMarkdown(line))
+	}
+
+	header := fmt.Sprintf("🧪 *自检结果: %d/%d 通过*\n", passed, len(stages))
+	return header + strings.Join(lines, "\n")
+}
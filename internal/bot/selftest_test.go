@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestCheckParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		videos  []*model.Video
+		wantErr string
+	}{
+		{name: "no videos", wantErr: "未解析到任何视频"},
+		{
+			name:    "missing code",
+			videos:  []*model.Video{{Code: "SSIS-001", DetailURL: "https://missav.ws/ssis-001"}, {DetailURL: "https://missav.ws/x"}},
+			wantErr: "1/2 个视频缺少番号或链接",
+		},
+		{
+			name:   "complete",
+			videos: []*model.Video{{Code: "SSIS-001", DetailURL: "https://missav.ws/ssis-001"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage := checkParse(&crawler.CrawlResult{Videos: tt.videos})
+			if tt.wantErr == "" {
+				if stage.err != nil || !strings.Contains(stage.detail, "SSIS-001") {
+					t.Errorf("checkParse() = %+v, want a pass naming SSIS-001", stage)
+				}
+				return
+			}
+			if stage.err == nil || !strings.Contains(stage.err.Error(), tt.wantErr) {
+				t.Errorf("checkParse() error = %v, want %q", stage.err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatSelftestReport(t *testing.T) {
+	text := formatSelftestReport([]selftestStage{
+		{name: "数据库", duration: 3 * time.Millisecond},
+		{name: "爬取", err: errors.New("timeout"), duration: time.Second},
+		{name: "解析", skipped: true},
+		{name: "发送", duration: 80 * time.Millisecond},
+	})
+	for _, want := range []string{
+		"自检结果: 2/4 通过",
+		"✅ 数据库: 通过 \\(3ms\\)",
+		"❌ 爬取: timeout \\(1s\\)",
+		"⏭ 解析: 跳过\n",
+		"✅ 发送: 通过 \\(80ms\\)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("report missing %q:\n%s", want, text)
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/config"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// settingAdminOnly is the /settings key of the group-admin-only option
+const settingAdminOnly = "adminonly"
+
+// settingPushMode is the /settings key of the push mode option
+const settingPushMode = "pushmode"
+
+// settingParseMode is the /settings key of the message format option
+const settingParseMode = "parsemode"
+
+// settingDMResults is the /settings key of the private search results option
+const settingDMResults = "dmresults"
+
+// settingAutoDelete is the /settings key of the reply auto-delete option
+const settingAutoDelete = "autodelete"
+
+// settingSearchLimit is the /settings key of the /search result limit
+const settingSearchLimit = "searchlimit"
+
+// settingLatestSize is the /settings key of the /latest page size
+const settingLatestSize = "latestsize"
+
+// settingSkipVR is the /settings key of the VR push filter
+const settingSkipVR = "skipvr"
+
+// settingMinDuration is the /settings key of the minimum pushed duration
+const settingMinDuration = "minduration"
+
+// settingRecap is the /settings key of the weekly recap option
+const settingRecap = "recap"
+
+// settingsUsage explains how to change chat settings
+var settingsUsage = fmt.Sprintf("用法:\n/settings %s on|off\n/settings %s auto|single|batch\n/settings %s default|markdown|html\n/settings %s on|off\n/settings %s 5m|off\n/settings %s 1-%d|default\n/settings %s 1-%d|default\n/settings %s on|off\n/settings %s 分钟|clip|off\n/settings %s on|off",
+	settingAdminOnly, settingPushMode, settingParseMode, settingDMResults, settingAutoDelete,
+	settingSearchLimit, config.MaxResultPageSize, settingLatestSize, config.MaxResultPageSize,
+	settingSkipVR, settingMinDuration, settingRecap)
+
+// parsePushMode parses a push mode setting value
+// Returns false as the second value when the input is not recognized.
+func parsePushMode(value string) (model.PushMode, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "auto", "自动":
+		return model.PushModeAuto, true
+	case string(model.PushModeSingle), "逐条":
+		return model.PushModeSingle, true
+	case string(model.PushModeBatch), "合并":
+		return model.PushModeBatch, true
+	}
+	return "", false
+}
+
+// pushModeLabel describes a push mode in Chinese
+func pushModeLabel(mode model.PushMode) string {
+	switch mode {
+	case model.PushModeSingle:
+		return "逐条推送"
+	case model.PushModeBatch:
+		return "合并为列表"
+	default:
+		return "自动（新视频较多时合并）"
+	}
+}
+
+// parseMessageFormat parses a message format setting value
+// Returns false as the second value when the input is not recognized.
+func parseMessageFormat(value string) (model.ParseMode, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "default", "默认":
+		return model.ParseModeDefault, true
+	case string(model.ParseModeMarkdown), "markdownv2", "md":
+		return model.ParseModeMarkdown, true
+	case string(model.ParseModeHTML):
+		return model.ParseModeHTML, true
+	}
+	return "", false
+}
+
+// parseModeLabel describes a message format setting
+func parseModeLabel(mode model.ParseMode) string {
+	switch mode {
+	case model.ParseModeMarkdown:
+		return "MarkdownV2"
+	case model.ParseModeHTML:
+		return "HTML"
+	default:
+		return "默认"
+	}
+}
+
+// parseToggle parses an on/off setting value
+// Returns false as the second value when the input is not recognized.
+func parseToggle(value string) (enabled bool, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "on", "true", "yes", "1", "开", "开启":
+		return true, true
+	case "off", "false", "no", "0", "关", "关闭":
+		return false, true
+	}
+	return false, false
+}
+
+// toggleLabel describes an on/off setting in Chinese
+func toggleLabel(enabled bool) string {
+	if enabled {
+		return "开启"
+	}
+	return "关闭"
+}
+
+// isGroupChat reports whether a chat is a group or supergroup
+func isGroupChat(chat *tgbotapi.Chat) bool {
+	return chat.IsGroup() || chat.IsSuperGroup()
+}
+
+// isChatAdmin reports whether the sender administers the chat
+// Bot admins always qualify, as do anonymous group admins posting as the chat itself.
+func (h *Handler) isChatAdmin(chat *tgbotapi.Chat, from *tgbotapi.User, senderChat *tgbotapi.Chat) (bool, error) {
+	if senderChat != nil && senderChat.ID == chat.ID {
+		return true, nil
+	}
+	if from == nil {
+		return false, nil
+	}
+	if h.config != nil && h.config.IsAdmin(from.ID) {
+		return true, nil
+	}
+	return h.telegram.IsChatAdmin(chat.ID, from.ID)
+}
+
+// canManageChat reports whether the sender may change the chat's subscriptions and settings
+// Everyone may, unless the chat is a group with admin-only management enabled.
+func (h *Handler) canManageChat(ctx context.Context, chat *tgbotapi.Chat, from *tgbotapi.User, senderChat *tgbotapi.Chat) (bool, error) {
+	if !isGroupChat(chat) {
+		return true, nil
+	}
+	settings, err := h.store.GetChatSettings(ctx, chat.ID)
+	if err != nil {
+		return false, err
+	}
+	if !settings.AdminOnly {
+		return true, nil
+	}
+	return h.isChatAdmin(chat, from, senderChat)
+}
+
+// requireManager checks canManageChat for a command and replies when the sender is refused
+func (h *Handler) requireManager(ctx context.Context, msg *tgbotapi.Message) bool {
+	allowed, err := h.canManageChat(ctx, msg.Chat, msg.From, msg.SenderChat)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", msg.Chat.ID).Msg("Failed to check chat permissions")
+		h.sendError(ctx, msg.Chat.ID, "检查权限失败，请重试。")
+		return false
+	}
+	if !allowed {
+		h.denyGroupAdmin(ctx, msg.Chat.ID)
+		return false
+	}
+	return true
+}
+
+// handleSettings handles /settings command
+// /settings shows the chat settings; /settings adminonly on|off,
+// /settings pushmode auto|single|batch, /settings parsemode default|markdown|html and the
+// other keys in settingsUsage change them.
+// Changing adminonly in a group always requires group admin rights.
+func (h *Handler) handleSettings(ctx context.Context, msg *tgbotapi.Message, args string) {
+	chatID := msg.Chat.ID
+	fields := strings.Fields(args)
+
+	settings, err := h.store.GetChatSettings(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get chat settings")
+		h.sendError(ctx, chatID, "获取设置失败，请重试。")
+		return
+	}
+
+	if len(fields) == 0 {
+		text := fmt.Sprintf("⚙️ 聊天设置\n\n仅群管理员可管理订阅 (%s): %s\n推送方式 (%s): %s\n消息格式 (%s): %s\n搜索结果私聊发送 (%s): %s\n自动删除回复 (%s): %s\n搜索结果数量 (%s): %s\n/latest 每页数量 (%s): %s\n跳过 VR 作品 (%s): %s\n最短时长 (%s): %s\n每周回顾 (%s): %s\n\n%s",
+			settingAdminOnly, toggleLabel(settings.AdminOnly), settingPushMode, pushModeLabel(settings.PushMode),
+			settingParseMode, parseModeLabel(settings.ParseMode),
+			settingDMResults, toggleLabel(settings.DMResults),
+			settingAutoDelete, autoDeleteLabel(settings.AutoDeleteSeconds),
+			settingSearchLimit, pageSizeLabel(settings.SearchLimit),
+			settingLatestSize, pageSizeLabel(settings.LatestPageSize),
+			settingSkipVR, toggleLabel(settings.SkipVR),
+			settingMinDuration, minDurationLabel(settings.MinDuration),
+			settingRecap, toggleLabel(settings.WeeklyRecap), settingsUsage)
+		if err := h.telegram.SendMessage(chatID, text); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send settings")
+		}
+		return
+	}
+
+	if len(fields) != 2 {
+		h.sendError(ctx, chatID, settingsUsage)
+		return
+	}
+
+	switch strings.ToLower(fields[0]) {
+	case settingAdminOnly:
+		h.setAdminOnly(ctx, msg, settings, fields[1])
+	case settingPushMode:
+		h.setPushMode(ctx, chatID, settings, fields[1])
+	case settingParseMode:
+		h.setParseMode(ctx, chatID, settings, fields[1])
+	case settingDMResults:
+		h.setDMResults(ctx, msg, settings, fields[1])
+	case settingAutoDelete:
+		h.setAutoDelete(ctx, msg, settings, fields[1])
+	case settingSearchLimit, settingLatestSize:
+		h.setPageSize(ctx, chatID, settings, strings.ToLower(fields[0]), fields[1])
+	case settingSkipVR:
+		h.setSkipVR(ctx, chatID, settings, fields[1])
+	case settingMinDuration:
+		h.setMinDuration(ctx, chatID, settings, fields[1])
+	case settingRecap:
+		h.setRecap(ctx, chatID, settings, fields[1])
+	default:
+		h.sendError(ctx, chatID, settingsUsage)
+	}
+}
+
+// setAdminOnly handles /settings adminonly on|off, which requires group admin rights
+func (h *Handler) setAdminOnly(ctx context.Context, msg *tgbotapi.Message, settings *model.ChatSettings, value string) {
+	chatID := msg.Chat.ID
+	enabled, ok := parseToggle(value)
+	if !ok {
+		h.sendError(ctx, chatID, fmt.Sprintf("用法: /settings %s on|off", settingAdminOnly))
+		return
+	}
+	if !isGroupChat(msg.Chat) {
+		h.sendError(ctx, chatID, "该设置仅适用于群组。")
+		return
+	}
+
+	admin, err := h.isChatAdmin(msg.Chat, msg.From, msg.SenderChat)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to check chat permissions")
+		h.sendError(ctx, chatID, "检查权限失败，请重试。")
+		return
+	}
+	if !admin {
+		h.denyGroupAdmin(ctx, chatID)
+		return
+	}
+
+	settings.AdminOnly = enabled
+	if err := h.store.SaveChatSettings(ctx, settings); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to save chat settings")
+		h.sendError(ctx, chatID, "保存设置失败，请重试。")
+		return
+	}
+
+	message := "✅ 所有成员均可管理订阅。"
+	if enabled {
+		message = "✅ 已开启：仅群管理员可管理订阅和设置。"
+	}
+	if err := h.telegram.SendMessage(chatID, message); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send settings confirmation")
+	}
+}
+
+// setPushMode handles /settings pushmode auto|single|batch
+func (h *Handler) setPushMode(ctx context.Context, chatID int64, settings *model.ChatSettings, value string) {
+	mode, ok := parsePushMode(value)
+	if !ok {
+		h.sendError(ctx, chatID, fmt.Sprintf("用法: /settings %s auto|single|batch", settingPushMode))
+		return
+	}
+
+	settings.PushMode = mode
+	if err := h.store.SaveChatSettings(ctx, settings); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to save chat settings")
+		h.sendError(ctx, chatID, "保存设置失败，请重试。")
+		return
+	}
+
+	if err := h.telegram.SendMessage(chatID, "✅ 推送方式: "+pushModeLabel(mode)); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send settings confirmation")
+	}
+}
+
+// setParseMode handles /settings parsemode default|markdown|html
+func (h *Handler) setParseMode(ctx context.Context, chatID int64, settings *model.ChatSettings, value string) {
+	mode, ok := parseMessageFormat(value)
+	if !ok {
+		h.sendError(ctx, chatID, fmt.Sprintf("用法: /settings %s default|markdown|html", settingParseMode))
+		return
+	}
+
+	settings.ParseMode = mode
+	if err := h.store.SaveChatSettings(ctx, settings); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to save chat settings")
+		h.sendError(ctx, chatID, "保存设置失败，请重试。")
+		return
+	}
+
+	if err := h.telegram.SendMessage(chatID, "✅ 消息格式: "+parseModeLabel(mode)); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send settings confirmation")
+	}
+}
+
+// setDMResults handles /settings dmresults on|off, which only applies to groups
+func (h *Handler) setDMResults(ctx context.Context, msg *tgbotapi.Message, settings *model.ChatSettings, value string) {
+	chatID := msg.Chat.ID
+	enabled, ok := parseToggle(value)
+	if !ok {
+		h.sendError(ctx, chatID, fmt.Sprintf("用法: /settings %s on|off", settingDMResults))
+		return
+	}
+	if !isGroupChat(msg.Chat) {
+		h.sendError(ctx, chatID, "该设置仅适用于群组。")
+		return
+	}
+
+	settings.DMResults = enabled
+	if err := h.store.SaveChatSettings(ctx, settings); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to save chat settings")
+		h.sendError(ctx, chatID, "保存设置失败，请重试。")
+		return
+	}
+
+	message := "✅ /search 和 /latest 的结果将发送在群内。"
+	if enabled {
+		message = "✅ 已开启：/search 和 /latest 的结果将私聊发送给提问者。"
+	}
+	if err := h.telegram.SendMessage(chatID, message); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send settings confirmation")
+	}
+}
+
+// resultChat returns the chat that /search and /latest results of a command go to:
+// the requester's private chat when the group enabled dmresults, otherwise the command's chat
+func (h *Handler) resultChat(ctx context.Context, req *commandRequest) int64 {
+	if !isGroupChat(req.msg.Chat) || !isPersonalSender(req.userID) {
+		return req.chatID
+	}
+	settings, err := h.store.GetChatSettings(ctx, req.chatID)
+	if err != nil {
+		log.Warn().Err(err).Int64("chatID", req.chatID).Msg("Failed to get chat settings, replying in the group")
+		return req.chatID
+	}
+	if !settings.DMResults {
+		return req.chatID
+	}
+	return req.userID
+}
+
+// replyWithResults runs a results command against resultChat and, when the results
+// went to the requester privately, leaves a short note in the group
+func (h *Handler) replyWithResults(ctx context.Context, req *commandRequest, run func(chatID int64)) {
+	chatID := h.resultChat(ctx, req)
+	run(chatID)
+	if chatID == req.chatID {
+		return
+	}
+	if err := h.telegram.SendMessageWithReply(req.chatID, "📬 结果已私聊发送给你。如未收到，请先私聊机器人发送 /start。", req.msg.MessageID); err != nil {
+		log.Error().Err(err).Int64("chatID", req.chatID).Msg("Failed to send private results note")
+	}
+}
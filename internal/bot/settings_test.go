@@ -0,0 +1,149 @@
+package bot
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestParseToggle(t *testing.T) {
+	tests := []struct {
+		input   string
+		enabled bool
+		ok      bool
+	}{
+		{"on", true, true},
+		{"ON", true, true},
+		{" true ", true, true},
+		{"开启", true, true},
+		{"off", false, true},
+		{"0", false, true},
+		{"关", false, true},
+		{"maybe", false, false},
+		{"", false, false},
+	}
+
+	for _, tt := range tests {
+		enabled, ok := parseToggle(tt.input)
+		if enabled != tt.enabled || ok != tt.ok {
+			t.Errorf("parseToggle(%q) = %v, %v; want %v, %v", tt.input, enabled, ok, tt.enabled, tt.ok)
+		}
+	}
+}
+
+func TestParsePushMode(t *testing.T) {
+	tests := []struct {
+		input string
+		mode  model.PushMode
+		ok    bool
+	}{
+		{"auto", model.PushModeAuto, true},
+		{" Single ", model.PushModeSingle, true},
+		{"batch", model.PushModeBatch, true},
+		{"合并", model.PushModeBatch, true},
+		{"digest", "", false},
+	}
+
+	for _, tt := range tests {
+		mode, ok := parsePushMode(tt.input)
+		if mode != tt.mode || ok != tt.ok {
+			t.Errorf("parsePushMode(%q) = %q, %v; want %q, %v", tt.input, mode, ok, tt.mode, tt.ok)
+		}
+	}
+}
+
+func TestParseMessageFormat(t *testing.T) {
+	tests := []struct {
+		input string
+		mode  model.ParseMode
+		ok    bool
+	}{
+		{"default", model.ParseModeDefault, true},
+		{" HTML ", model.ParseModeHTML, true},
+		{"markdown", model.ParseModeMarkdown, true},
+		{"MarkdownV2", model.ParseModeMarkdown, true},
+		{"bbcode", "", false},
+	}
+
+	for _, tt := range tests {
+		mode, ok := parseMessageFormat(tt.input)
+		if mode != tt.mode || ok != tt.ok {
+			t.Errorf("parseMessageFormat(%q) = %q, %v; want %q, %v", tt.input, mode, ok, tt.mode, tt.ok)
+		}
+	}
+}
+
+func TestParseAutoDelete(t *testing.T) {
+	tests := []struct {
+		input string
+		ttl   time.Duration
+		ok    bool
+	}{
+		{"off", 0, true},
+		{"0", 0, true},
+		{"5m", 5 * time.Minute, true},
+		{" 1H ", time.Hour, true},
+		{"90.5s", 90 * time.Second, true},
+		{"5s", 0, false},
+		{"72h", 0, false},
+		{"on", 0, false},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		ttl, ok := parseAutoDelete(tt.input)
+		if ttl != tt.ttl || ok != tt.ok {
+			t.Errorf("parseAutoDelete(%q) = %v, %v; want %v, %v", tt.input, ttl, ok, tt.ttl, tt.ok)
+		}
+	}
+}
+
+func TestParsePageSize(t *testing.T) {
+	tests := []struct {
+		input string
+		size  int
+		ok    bool
+	}{
+		{"default", 0, true},
+		{" 默认 ", 0, true},
+		{"1", 1, true},
+		{"20", 20, true},
+		{"30", 30, true},
+		{"0", 0, false},
+		{"31", 0, false},
+		{"-5", 0, false},
+		{"many", 0, false},
+	}
+
+	for _, tt := range tests {
+		size, ok := parsePageSize(tt.input)
+		if size != tt.size || ok != tt.ok {
+			t.Errorf("parsePageSize(%q) = %v, %v; want %v, %v", tt.input, size, ok, tt.size, tt.ok)
+		}
+	}
+}
+
+func TestParseMinDuration(t *testing.T) {
+	tests := []struct {
+		input   string
+		minutes int
+		ok      bool
+	}{
+		{"30", 30, true},
+		{" 45m ", 45, true},
+		{"clip", model.ClipMaxDuration, true},
+		{"off", 0, true},
+		{"0", 0, true},
+		{"301", 0, false},
+		{"-5", 0, false},
+		{"long", 0, false},
+	}
+
+	for _, tt := range tests {
+		minutes, ok := parseMinDuration(tt.input)
+		if minutes != tt.minutes || ok != tt.ok {
+			t.Errorf("parseMinDuration(%q) = %d, %v; want %d, %v", tt.input, minutes, ok, tt.minutes, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+const (
+	// similarCandidates is the number of stored videos sharing an actress or tag that /similar ranks
+	similarCandidates = 200
+	// similarListed caps the videos listed in the /similar reply
+	similarListed = 10
+	// similarTitleRunes shortens long titles in the /similar reply
+	similarTitleRunes = 40
+	// similarActressWeight and similarTagWeight score each shared actress and tag;
+	// a shared actress says more about a video than a shared tag
+	similarActressWeight = store.RelatedActressWeight
+	similarTagWeight     = store.RelatedTagWeight
+)
+
+// similarVideo is a video recommended by /similar with what it shares with the requested one
+type similarVideo struct {
+	video     *model.Video
+	actresses []string
+	tags      []string
+	score     int
+}
+
+// rankSimilar scores candidates by the actresses and tags they share with a video and
+// returns those sharing any, best first; ties keep the candidates' order
+func rankSimilar(video *model.Video, candidates []*model.Video) []similarVideo {
+	actresses := splitVideoField(video.Actresses)
+	tags := splitVideoField(video.Tags)
+
+	var ranked []similarVideo
+	for _, candidate := range candidates {
+		similar := similarVideo{
+			video:     candidate,
+			actresses: sharedValues(actresses, splitVideoField(candidate.Actresses)),
+			tags:      sharedValues(tags, splitVideoField(candidate.Tags)),
+		}
+		similar.score = len(similar.actresses)*similarActressWeight + len(similar.tags)*similarTagWeight
+		if similar.score > 0 {
+			ranked = append(ranked, similar)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].score > ranked[j].score })
+	return ranked
+}
+
+// splitVideoField splits a comma-separated actresses or tags field into trimmed, non-empty values
+func splitVideoField(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// sharedValues returns the values of want that also appear in have, ignoring case
+func sharedValues(want []string, have []string) []string {
+	var shared []string
+	for _, w := range want {
+		for _, h := range have {
+			if strings.EqualFold(w, h) {
+				shared = append(shared, w)
+				break
+			}
+		}
+	}
+	return shared
+}
+
+// formatSimilar renders the /similar reply as plain text
+func formatSimilar(video *model.Video, ranked []similarVideo) string {
+	if len(ranked) == 0 {
+		return fmt.Sprintf("📭 没有找到与 %s 有相同演员或标签的视频。", video.Code)
+	}
+
+	lines := []string{fmt.Sprintf("🔍 与 %s 相似的视频:", video.Code)}
+	for i, similar := range ranked {
+		if i == similarListed {
+			break
+		}
+		title := []rune(strings.TrimSpace(strings.TrimPrefix(similar.video.Title, similar.video.Code)))
+		if len(title) > similarTitleRunes {
+			title = append(title[:similarTitleRunes-3], []rune("...")...)
+		}
+		lines = append(lines, strings.TrimSpace(fmt.Sprintf("%d. %s %s", i+1, similar.video.Code, string(title))))
+
+		var shared []string
+		if len(similar.actresses) > 0 {
+			shared = append(shared, "👩 "+strings.Join(similar.actresses, ", "))
+		}
+		if len(similar.tags) > 0 {
+			shared = append(shared, "🏷 "+strings.Join(similar.tags, ", "))
+		}
+		lines = append(lines, "   "+strings.Join(shared, " · "))
+	}
+	lines = append(lines, "使用 /detail 番号 查看详情。")
+	return strings.Join(lines, "\n")
+}
+
+// handleSimilar handles /similar command
+// It recommends stored videos sharing the most actresses and tags with a code.
+func (h *Handler) handleSimilar(ctx context.Context, chatID int64, args string) {
+	code := crawler.ExtractCode(args)
+	if code == "" {
+		h.sendError(ctx, chatID, "请提供番号。例如: /similar ABC-123")
+		return
+	}
+
+	video, err := h.store.GetVideoByCode(ctx, code)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("code", code).Msg("Failed to get video")
+		h.sendError(ctx, chatID, "查询失败，请重试。")
+		return
+	}
+	if video == nil {
+		h.sendError(ctx, chatID, fmt.Sprintf("📭 未找到视频: %s\n可使用 /crawl code %s 爬取。", code, code))
+		return
+	}
+	if video.Actresses == "" && video.Tags == "" {
+		h.sendError(ctx, chatID, fmt.Sprintf("%s 没有演员或标签信息，无法推荐相似视频。", video.Code))
+		return
+	}
+
+	candidates, err := h.store.GetRelatedVideos(ctx, video, similarCandidates)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("code", code).Msg("Failed to get related videos")
+		h.sendError(ctx, chatID, "查询失败，请重试。")
+		return
+	}
+
+	if err := h.telegram.SendMessage(chatID, formatSimilar(video, rankSimilar(video, candidates))); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send similar videos")
+	}
+}
@@ -0,0 +1,44 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestRankSimilar(t *testing.T) {
+	video := &model.Video{Code: "ABC-001", Actresses: "Alice, Bob", Tags: "Drama, Comedy, Office"}
+	candidates := []*model.Video{
+		{Code: "TAG-001", Tags: "drama, Comedy"},
+		{Code: "NONE-001", Actresses: "Alicia", Tags: "Romance"},
+		{Code: "BOTH-001", Actresses: "Alice", Tags: "Office"},
+		{Code: "ACT-001", Actresses: "Bob"},
+		{Code: "TAG-002", Tags: "Office, Comedy"},
+	}
+
+	ranked := rankSimilar(video, candidates)
+	var codes []string
+	for _, similar := range ranked {
+		codes = append(codes, similar.video.Code)
+	}
+	if got, want := strings.Join(codes, ","), "BOTH-001,TAG-001,ACT-001,TAG-002"; got != want {
+		t.Fatalf("rankSimilar() = %s, want %s", got, want)
+	}
+	if ranked[0].score != similarActressWeight+similarTagWeight {
+		t.Errorf("score of BOTH-001 = %d, want one actress and one tag", ranked[0].score)
+	}
+	if strings.Join(ranked[1].tags, ",") != "Drama,Comedy" {
+		t.Errorf("shared tags of TAG-001 = %v, want Drama and Comedy ignoring case", ranked[1].tags)
+	}
+
+	text := formatSimilar(video, ranked)
+	for _, want := range []string{"与 ABC-001 相似", "1. BOTH-001", "👩 Alice · 🏷 Office", "/detail"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("reply missing %q:\n%s", want, text)
+		}
+	}
+	if text := formatSimilar(video, nil); !strings.Contains(text, "没有找到") {
+		t.Errorf("formatSimilar(nil) = %q", text)
+	}
+}
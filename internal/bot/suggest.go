@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"sort"
+	"strings"
+)
+
+// maxSuggestions is the number of "did you mean" suggestions offered
+const maxSuggestions = 5
+
+// suggestNames matches a subscription keyword against a catalog of known names
+// If the keyword names a catalog entry (case-insensitive), exact is that entry.
+// Otherwise suggestions lists up to limit close names: prefix matches first,
+// then substring matches, then names within a small edit distance.
+func suggestNames(keyword string, catalog []string, limit int) (exact string, suggestions []string) {
+	query := strings.ToLower(strings.TrimSpace(keyword))
+	if query == "" {
+		return "", nil
+	}
+
+	type candidate struct {
+		name  string
+		rank  int
+		score int
+	}
+	var candidates []candidate
+
+	queryLen := len([]rune(query))
+	maxDistance := queryLen / 3
+	if maxDistance < 1 {
+		maxDistance = 1
+	}
+
+	for _, name := range catalog {
+		lower := strings.ToLower(name)
+		switch {
+		case lower == query:
+			return name, nil
+		case strings.HasPrefix(lower, query):
+			candidates = append(candidates, candidate{name, 0, len(lower)})
+		case strings.Contains(lower, query):
+			candidates = append(candidates, candidate{name, 1, len(lower)})
+		default:
+			if d := levenshtein(query, lower); d <= maxDistance {
+				candidates = append(candidates, candidate{name, 2, d})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].rank != candidates[j].rank {
+			return candidates[i].rank < candidates[j].rank
+		}
+		return candidates[i].score < candidates[j].score
+	})
+
+	for _, c := range candidates {
+		if len(suggestions) == limit {
+			break
+		}
+		suggestions = append(suggestions, c.name)
+	}
+	return "", suggestions
+}
+
+// levenshtein returns the edit distance between two strings, counted in runes
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
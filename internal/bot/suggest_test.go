@@ -0,0 +1,62 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestSuggestNames(t *testing.T) {
+	catalog := []string{"三上悠亜", "三上悠", "河北彩花", "Yua Mikami", "Yui Hatano", "Aoi"}
+
+	tests := []struct {
+		name      string
+		keyword   string
+		wantExact string
+		wantFirst string
+	}{
+		{"exact case-insensitive", "yua mikami", "Yua Mikami", ""},
+		{"prefix", "三上", "", "三上悠"},
+		{"substring", "Mikami", "", "Yua Mikami"},
+		{"typo", "Yua Mikmai", "", "Yua Mikami"},
+		{"no match", "zzzzzz", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exact, suggestions := suggestNames(tt.keyword, catalog, maxSuggestions)
+			if exact != tt.wantExact {
+				t.Errorf("exact = %q, want %q", exact, tt.wantExact)
+			}
+			first := ""
+			if len(suggestions) > 0 {
+				first = suggestions[0]
+			}
+			if first != tt.wantFirst {
+				t.Errorf("first suggestion = %q, want %q (all: %v)", first, tt.wantFirst, suggestions)
+			}
+			if len(suggestions) > maxSuggestions {
+				t.Errorf("got %d suggestions, want at most %d", len(suggestions), maxSuggestions)
+			}
+		})
+	}
+}
+
+func TestSubscribeCallbackData_RoundTrip(t *testing.T) {
+	data, ok := subscribeCallbackData(model.SubTypeTag, "単体")
+	if !ok {
+		t.Fatal("subscribeCallbackData() rejected a short keyword")
+	}
+	subType, keyword, ok := parseSubscribeCallbackData(data)
+	if !ok || subType != model.SubTypeTag || keyword != "単体" {
+		t.Errorf("parseSubscribeCallbackData(%q) = %s, %q, %v", data, subType, keyword, ok)
+	}
+
+	if _, ok := subscribeCallbackData(model.SubTypeActress, strings.Repeat("長", 30)); ok {
+		t.Error("subscribeCallbackData() accepted data over the 64-byte limit")
+	}
+	if _, _, ok := parseSubscribeCallbackData(callbackSubscribeCancel); ok {
+		t.Error("parseSubscribeCallbackData() decoded the cancel button as a subscription")
+	}
+}
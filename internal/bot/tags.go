@@ -0,0 +1,99 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+// Number of tags listed by /tags
+// Each tag takes two inline buttons and Telegram allows at most 100 per message.
+const (
+	defaultPopularTags = 20
+	maxPopularTags     = 40
+)
+
+// callbackTagLatestPrefix prefixes the /tags buttons that list a tag's latest videos
+const callbackTagLatestPrefix = "tags:l:"
+
+// parseTagsArgs parses /tags arguments: an optional number of tags
+// Returns false as the second value when the argument is not a number in range.
+func parseTagsArgs(args string) (int, bool) {
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return defaultPopularTags, true
+	}
+	n, err := strconv.Atoi(args)
+	if err != nil || n < 1 || n > maxPopularTags {
+		return 0, false
+	}
+	return n, true
+}
+
+// tagLatestCallbackData encodes a button listing a tag's latest videos
+// Returns false when the encoded data exceeds Telegram's limit
+func tagLatestCallbackData(tag string) (string, bool) {
+	data := callbackTagLatestPrefix + tag
+	if len(data) > callbackDataLimit {
+		return "", false
+	}
+	return data, true
+}
+
+// handleTags handles /tags command
+// Lists the most common tags as buttons that open the tag's latest videos or subscribe to it.
+func (h *Handler) handleTags(ctx context.Context, chatID int64, args string) {
+	limit, ok := parseTagsArgs(args)
+	if !ok {
+		h.sendError(ctx, chatID, fmt.Sprintf("用法: /tags [数量]（1 到 %d）", maxPopularTags))
+		return
+	}
+
+	tags, err := h.store.GetPopularTags(ctx, limit)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get popular tags")
+		h.sendError(ctx, chatID, "获取热门标签失败，请重试。")
+		return
+	}
+
+	keyboard := popularTagsKeyboard(tags)
+	if len(keyboard.InlineKeyboard) == 0 {
+		if err := h.sendReply(ctx, chatID, "📭 暂无标签。"); err != nil {
+			log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send no tags message")
+		}
+		return
+	}
+
+	text := fmt.Sprintf("🏷 热门标签（前 %d 个）\n点击标签查看最新视频，点击 🔔 订阅该标签。", len(keyboard.InlineKeyboard))
+	id, err := h.telegram.SendTextWithButtons(chatID, text, "", keyboard)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send popular tags")
+		return
+	}
+	h.autoDelete(ctx, chatID, id)
+}
+
+// popularTagsKeyboard builds one button row per tag: its latest videos and a subscription
+// Tags too long for callback data are left out.
+func popularTagsKeyboard(tags []*store.TagCount) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, tag := range tags {
+		data, ok := tagLatestCallbackData(tag.Tag)
+		if !ok {
+			continue
+		}
+		row := tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("#%s (%d)", tag.Tag, tag.Count), data))
+		if data, ok := quickSubscribeCallbackData(model.SubTypeTag, tag.Tag); ok {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData("🔔", data))
+		}
+		rows = append(rows, row)
+	}
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+func TestParseTagsArgs(t *testing.T) {
+	tests := []struct {
+		args string
+		want int
+		ok   bool
+	}{
+		{"", defaultPopularTags, true},
+		{" 10 ", 10, true},
+		{"40", 40, true},
+		{"0", 0, false},
+		{"41", 0, false},
+		{"abc", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseTagsArgs(tt.args)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseTagsArgs(%q) = %d, %v, want %d, %v", tt.args, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPopularTagsKeyboard(t *testing.T) {
+	tags := []*store.TagCount{
+		{Tag: "巨乳", Count: 12},
+		{Tag: strings.Repeat("長", 30), Count: 3},
+	}
+
+	rows := popularTagsKeyboard(tags).InlineKeyboard
+	if len(rows) != 1 {
+		t.Fatalf("got %d rows, want 1 (tags too long for callback data are left out)", len(rows))
+	}
+	if len(rows[0]) != 2 {
+		t.Fatalf("got %d buttons, want a listing and a subscribe button", len(rows[0]))
+	}
+	if rows[0][0].Text != "#巨乳 (12)" || *rows[0][0].CallbackData != callbackTagLatestPrefix+"巨乳" {
+		t.Errorf("listing button = %q %q", rows[0][0].Text, *rows[0][0].CallbackData)
+	}
+	if *rows[0][1].CallbackData != callbackQuickSubscribePrefix+"t:巨乳" {
+		t.Errorf("subscribe button data = %q", *rows[0][1].CallbackData)
+	}
+}
@@ -1,7 +1,11 @@
 package bot
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -12,27 +16,75 @@ type Client struct {
 }
 
 // NewClient creates a new Telegram client with the given bot token
-func NewClient(token string) (*Client, error) {
-	api, err := tgbotapi.NewBotAPI(token)
+// apiEndpoint is the base URL of a self-hosted Bot API server; empty uses api.telegram.org.
+// The token is verified with getMe, so an invalid one fails here rather than when polling.
+func NewClient(token string, apiEndpoint string) (*Client, error) {
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint(token, botAPIEndpoint(apiEndpoint))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create bot API: %w", err)
+		var apiErr *tgbotapi.Error
+		if errors.As(err, &apiErr) && (apiErr.Code == http.StatusUnauthorized || apiErr.Code == http.StatusNotFound) {
+			return nil, fmt.Errorf("invalid bot token %s: %s", RedactToken(token), apiErr.Message)
+		}
+		// Request errors carry the API URL, which contains the token
+		return nil, fmt.Errorf("failed to verify bot token %s: %s", RedactToken(token),
+			strings.ReplaceAll(err.Error(), token, RedactToken(token)))
 	}
 
 	return &Client{api: api}, nil
 }
 
+// botAPIEndpoint turns a Bot API server base URL into the endpoint format of tgbotapi
+func botAPIEndpoint(baseURL string) string {
+	if baseURL == "" {
+		return tgbotapi.APIEndpoint
+	}
+	return strings.TrimRight(baseURL, "/") + "/bot%s/%s"
+}
+
+// RedactToken hides the secret part of a bot token, keeping the bot ID before the colon
+func RedactToken(token string) string {
+	id, _, found := strings.Cut(token, ":")
+	if !found || id == "" {
+		return "***"
+	}
+	return id + ":***"
+}
+
 // GetAPI returns the underlying bot API for advanced operations
 func (c *Client) GetAPI() *tgbotapi.BotAPI {
 	return c.api
 }
 
-// GetUpdates returns a channel for receiving updates from Telegram
-func (c *Client) GetUpdates() tgbotapi.UpdatesChannel {
-	u := tgbotapi.NewUpdate(0)
+// BotID returns the Telegram user ID of the bot
+func (c *Client) BotID() int64 {
+	return c.api.Self.ID
+}
+
+// BotUsername returns the username of the bot
+func (c *Client) BotUsername() string {
+	return c.api.Self.UserName
+}
+
+// GetUpdates returns a channel for receiving updates from Telegram, starting at offset
+func (c *Client) GetUpdates(offset int) tgbotapi.UpdatesChannel {
+	u := tgbotapi.NewUpdate(offset)
 	u.Timeout = 60
 	return c.api.GetUpdatesChan(u)
 }
 
+// SkipPendingUpdates returns the offset following the newest pending update
+// Polling from it confirms, and so drops, every update Telegram still holds.
+func (c *Client) SkipPendingUpdates() (int, error) {
+	updates, err := c.api.GetUpdates(tgbotapi.UpdateConfig{Offset: -1, Limit: 1})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pending updates: %w", err)
+	}
+	if len(updates) == 0 {
+		return 0, nil
+	}
+	return updates[len(updates)-1].UpdateID + 1, nil
+}
+
 // StopReceivingUpdates stops the update channel
 func (c *Client) StopReceivingUpdates() {
 	c.api.StopReceivingUpdates()
@@ -48,45 +100,88 @@ func (c *Client) SendMessage(chatID int64, text string) error {
 	return nil
 }
 
-// SendMarkdown sends a message with MarkdownV2 formatting to a chat
-func (c *Client) SendMarkdown(chatID int64, text string) error {
+// SendMarkdown sends a message with MarkdownV2 formatting to a chat and returns its message ID
+func (c *Client) SendMarkdown(chatID int64, text string) (int, error) {
+	return c.SendText(chatID, text, tgbotapi.ModeMarkdownV2)
+}
+
+// SendText sends a message formatted in a parse mode, e.g. tgbotapi.ModeHTML,
+// to a chat and returns its message ID
+func (c *Client) SendText(chatID int64, text string, parseMode string) (int, error) {
 	msg := tgbotapi.NewMessage(chatID, text)
-	msg.ParseMode = tgbotapi.ModeMarkdownV2
-	_, err := c.api.Send(msg)
+	msg.ParseMode = parseMode
+	sent, err := c.api.Send(msg)
 	if err != nil {
-		return fmt.Errorf("failed to send markdown message: %w", err)
+		return 0, fmt.Errorf("failed to send %s message: %w", parseMode, err)
 	}
-	return nil
+	return sent.MessageID, nil
 }
 
 
-// SendPhoto sends a photo with caption to a chat
+// SendPhoto sends a photo with a caption in the given parse mode to a chat and returns its message ID
 // The photoURL can be a URL or a file_id
-func (c *Client) SendPhoto(chatID int64, photoURL string, caption string) error {
+func (c *Client) SendPhoto(chatID int64, photoURL string, caption string, parseMode string) (int, error) {
 	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(photoURL))
 	photo.Caption = caption
-	photo.ParseMode = tgbotapi.ModeMarkdownV2
-	_, err := c.api.Send(photo)
+	photo.ParseMode = parseMode
+	sent, err := c.api.Send(photo)
 	if err != nil {
-		return fmt.Errorf("failed to send photo: %w", err)
+		return 0, fmt.Errorf("failed to send photo: %w", err)
 	}
-	return nil
+	return sent.MessageID, nil
 }
 
-// SendVideo sends a video with thumbnail and caption to a chat
+// SendVideo sends a video with thumbnail and a caption in the given parse mode to a chat
+// and returns its message ID
 // The videoURL and thumbURL can be URLs or file_ids
-func (c *Client) SendVideo(chatID int64, videoURL string, thumbURL string, caption string) error {
+func (c *Client) SendVideo(chatID int64, videoURL string, thumbURL string, caption string, parseMode string) (int, error) {
 	video := tgbotapi.NewVideo(chatID, tgbotapi.FileURL(videoURL))
 	video.Caption = caption
-	video.ParseMode = tgbotapi.ModeMarkdownV2
+	video.ParseMode = parseMode
 	if thumbURL != "" {
 		video.Thumb = tgbotapi.FileURL(thumbURL)
 	}
-	_, err := c.api.Send(video)
+	sent, err := c.api.Send(video)
 	if err != nil {
-		return fmt.Errorf("failed to send video: %w", err)
+		return 0, fmt.Errorf("failed to send video: %w", err)
 	}
-	return nil
+	return sent.MessageID, nil
+}
+
+// SendMediaGroup sends an album to a chat: the video first, if given, then the photos
+// The caption is attached to the first item so clients show it under the album.
+// It returns the message ID of the first item, which carries the caption.
+func (c *Client) SendMediaGroup(chatID int64, videoURL string, photoURLs []string, caption string, parseMode string) (int, error) {
+	var media []interface{}
+	if videoURL != "" {
+		media = append(media, tgbotapi.NewInputMediaVideo(tgbotapi.FileURL(videoURL)))
+	}
+	for _, photoURL := range photoURLs {
+		media = append(media, tgbotapi.NewInputMediaPhoto(tgbotapi.FileURL(photoURL)))
+	}
+	if len(media) == 0 {
+		return 0, fmt.Errorf("failed to send media group: no media")
+	}
+
+	switch first := media[0].(type) {
+	case tgbotapi.InputMediaVideo:
+		first.Caption = caption
+		first.ParseMode = parseMode
+		media[0] = first
+	case tgbotapi.InputMediaPhoto:
+		first.Caption = caption
+		first.ParseMode = parseMode
+		media[0] = first
+	}
+
+	sent, err := c.api.SendMediaGroup(tgbotapi.NewMediaGroup(chatID, media))
+	if err != nil {
+		return 0, fmt.Errorf("failed to send media group: %w", err)
+	}
+	if len(sent) == 0 {
+		return 0, nil
+	}
+	return sent[0].MessageID, nil
 }
 
 // SendMessageWithReply sends a message as a reply to another message
@@ -111,3 +206,132 @@ func (c *Client) SendMarkdownWithReply(chatID int64, text string, replyToMessage
 	}
 	return nil
 }
+
+// SendMessageWithButtons sends a plain text message with an inline keyboard
+func (c *Client) SendMessageWithButtons(chatID int64, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	_, err := c.api.Send(msg)
+	if err != nil {
+		return fmt.Errorf("failed to send message with buttons: %w", err)
+	}
+	return nil
+}
+
+// SendTextWithButtons sends a message formatted in a parse mode with an inline keyboard
+// and returns its message ID. An empty keyboard is left out.
+func (c *Client) SendTextWithButtons(chatID int64, text string, parseMode string, keyboard tgbotapi.InlineKeyboardMarkup) (int, error) {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = parseMode
+	if len(keyboard.InlineKeyboard) > 0 {
+		msg.ReplyMarkup = keyboard
+	}
+	sent, err := c.api.Send(msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send message with buttons: %w", err)
+	}
+	return sent.MessageID, nil
+}
+
+// SendPhotoWithButtons sends a photo with a caption in the given parse mode and an inline
+// keyboard, and returns its message ID. An empty keyboard is left out.
+func (c *Client) SendPhotoWithButtons(chatID int64, photoURL string, caption string, parseMode string, keyboard tgbotapi.InlineKeyboardMarkup) (int, error) {
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(photoURL))
+	photo.Caption = caption
+	photo.ParseMode = parseMode
+	if len(keyboard.InlineKeyboard) > 0 {
+		photo.ReplyMarkup = keyboard
+	}
+	sent, err := c.api.Send(photo)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send photo with buttons: %w", err)
+	}
+	return sent.MessageID, nil
+}
+
+// EditMessageText replaces the text of a sent message and removes its inline keyboard
+func (c *Client) EditMessageText(chatID int64, messageID int, text string) error {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	_, err := c.api.Send(edit)
+	if err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+	return nil
+}
+
+// EditText replaces the text of a sent message with text formatted in a parse mode
+func (c *Client) EditText(chatID int64, messageID int, text string, parseMode string) error {
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.ParseMode = parseMode
+	if _, err := c.api.Send(edit); err != nil {
+		return fmt.Errorf("failed to edit message: %w", err)
+	}
+	return nil
+}
+
+// EditCaption replaces the caption of a sent photo, video or album item
+func (c *Client) EditCaption(chatID int64, messageID int, caption string, parseMode string) error {
+	edit := tgbotapi.NewEditMessageCaption(chatID, messageID, caption)
+	edit.ParseMode = parseMode
+	if _, err := c.api.Send(edit); err != nil {
+		return fmt.Errorf("failed to edit caption: %w", err)
+	}
+	return nil
+}
+
+// DownloadFile downloads a file sent to the bot, refusing files larger than maxBytes
+func (c *Client) DownloadFile(fileID string, maxBytes int64) ([]byte, error) {
+	url, err := c.api.GetFileDirectURL(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file: %w", err)
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		// Request errors carry the file URL, which contains the token
+		return nil, fmt.Errorf("failed to download file: %s",
+			strings.ReplaceAll(err.Error(), c.api.Token, RedactToken(c.api.Token)))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download file: status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("file is larger than %d bytes", maxBytes)
+	}
+	return data, nil
+}
+
+// DeleteMessage deletes a message the bot may delete in a chat
+func (c *Client) DeleteMessage(chatID int64, messageID int) error {
+	if _, err := c.api.Request(tgbotapi.NewDeleteMessage(chatID, messageID)); err != nil {
+		return fmt.Errorf("failed to delete message: %w", err)
+	}
+	return nil
+}
+
+// AnswerCallback acknowledges a callback query, optionally showing a notification
+func (c *Client) AnswerCallback(callbackID string, text string) error {
+	callback := tgbotapi.NewCallback(callbackID, text)
+	if _, err := c.api.Request(callback); err != nil {
+		return fmt.Errorf("failed to answer callback: %w", err)
+	}
+	return nil
+}
+
+// IsChatAdmin reports whether a user is the creator or an administrator of a chat
+func (c *Client) IsChatAdmin(chatID int64, userID int64) (bool, error) {
+	member, err := c.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{
+			ChatID: chatID,
+			UserID: userID,
+		},
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get chat member: %w", err)
+	}
+	return member.IsCreator() || member.IsAdministrator(), nil
+}
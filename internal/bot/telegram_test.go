@@ -0,0 +1,25 @@
+package bot
+
+import "testing"
+
+func TestRedactToken(t *testing.T) {
+	tests := map[string]string{
+		"123456:ABC-secret": "123456:***",
+		"no-colon":          "***",
+		":secret":           "***",
+	}
+	for token, want := range tests {
+		if got := RedactToken(token); got != want {
+			t.Errorf("RedactToken(%q) = %q, want %q", token, got, want)
+		}
+	}
+}
+
+func TestBotAPIEndpoint(t *testing.T) {
+	if got := botAPIEndpoint(""); got != "https://api.telegram.org/bot%s/%s" {
+		t.Errorf("botAPIEndpoint(\"\") = %q, want the cloud Bot API", got)
+	}
+	if got := botAPIEndpoint("http://telegram-bot-api:8081/"); got != "http://telegram-bot-api:8081/bot%s/%s" {
+		t.Errorf("botAPIEndpoint() = %q", got)
+	}
+}
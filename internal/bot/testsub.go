@@ -0,0 +1,116 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/push"
+)
+
+const (
+	// testSubWindow is the number of latest stored videos /testsub and the preview sent
+	// after subscribing match against
+	testSubWindow = 50
+	// testSubListed caps the matching videos listed in the /testsub reply
+	testSubListed = 10
+	// testSubTitleRunes shortens long titles in the /testsub reply
+	testSubTitleRunes = 40
+)
+
+// matchTestSubscription returns the videos a subscription would have matched, newest first
+func matchTestSubscription(sub *model.Subscription, videos []*model.Video) []*model.Video {
+	var matched []*model.Video
+	for _, video := range videos {
+		if push.MatchesSubscription(video, sub) {
+			matched = append(matched, video)
+		}
+	}
+	return matched
+}
+
+// formatTestSubResult renders the outcome of /testsub as plain text
+func formatTestSubResult(sub *model.Subscription, matched []*model.Video, total int) string {
+	target := "演员: " + sub.Keyword
+	if sub.Type == model.SubTypeTag {
+		target = "标签: #" + sub.Keyword
+	}
+
+	if len(matched) == 0 {
+		return fmt.Sprintf("🧪 订阅测试（%s）\n最近 %d 个视频中没有匹配的视频。\n关键词需与视频的演员或标签部分一致，可尝试更短或其他写法的关键词。", target, total)
+	}
+
+	lines := []string{fmt.Sprintf("🧪 订阅测试（%s）\n最近 %d 个视频中有 %d 个匹配:", target, total, len(matched))}
+	for i, video := range matched {
+		if i == testSubListed {
+			lines = append(lines, fmt.Sprintf("…等 %d 个", len(matched)))
+			break
+		}
+		title := []rune(video.Title)
+		if len(title) > testSubTitleRunes {
+			title = append(title[:testSubTitleRunes-3], []rune("...")...)
+		}
+		lines = append(lines, fmt.Sprintf("• %s %s", video.Code, string(title)))
+	}
+	if len(matched)*2 > total {
+		lines = append(lines, "⚠️ 超过一半的视频匹配，订阅后推送可能较多，可使用更具体的关键词。")
+	}
+	lines = append(lines, fmt.Sprintf("满意的话使用 /subscribe %s 订阅。", subscribeArgs(sub)))
+	return strings.Join(lines, "\n")
+}
+
+// subscribeArgs returns the /subscribe arguments creating a subscription
+func subscribeArgs(sub *model.Subscription) string {
+	if sub.Type == model.SubTypeTag {
+		return "#" + sub.Keyword
+	}
+	return sub.Keyword
+}
+
+// handleTestSub handles /testsub command
+// It shows which of the latest stored videos a subscription to a keyword would have
+// matched, without creating the subscription.
+func (h *Handler) handleTestSub(ctx context.Context, chatID int64, args string) {
+	subType, keyword := DetermineSubscriptionType(args)
+	if keyword == "" {
+		h.sendError(ctx, chatID, "请提供要测试的关键词。例如:\n/testsub 演员名\n/testsub #标签")
+		return
+	}
+
+	videos, err := h.store.GetLatestVideos(ctx, testSubWindow, 0)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get latest videos")
+		h.sendError(ctx, chatID, "获取视频失败，请重试。")
+		return
+	}
+
+	sub := &model.Subscription{Type: subType, Keyword: keyword, Enabled: true}
+	text := formatTestSubResult(sub, matchTestSubscription(sub, videos), len(videos))
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send subscription test result")
+	}
+}
+
+// previewSubscription sends a new subscription the latest stored video it matches,
+// formatted as it would be pushed, so users see right away that it works
+func (h *Handler) previewSubscription(ctx context.Context, sub *model.Subscription) {
+	if h.pushService == nil {
+		return
+	}
+	videos, err := h.store.GetLatestVideos(ctx, testSubWindow, 0)
+	if err != nil {
+		log.Warn().Err(err).Int64("chatID", sub.ChatID).Msg("Failed to get videos for subscription preview")
+		return
+	}
+	for _, video := range matchTestSubscription(sub, videos) {
+		if video.Hidden {
+			continue
+		}
+		if err := h.pushService.PreviewSubscription(ctx, sub, video); err != nil {
+			log.Warn().Err(err).Int64("chatID", sub.ChatID).Str("code", video.Code).Msg("Failed to send subscription preview")
+		}
+		return
+	}
+}
@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestMatchTestSubscription(t *testing.T) {
+	videos := []*model.Video{
+		{Code: "SSIS-001", Actresses: "三上悠亜", Tags: "巨乳,单体作品"},
+		{Code: "IPX-100", Actresses: "桃乃木かな", Tags: "单体作品"},
+		{Code: "ABP-200", Actresses: "三上悠亜, 桃乃木かな", Tags: "共演"},
+	}
+
+	tests := []struct {
+		args string
+		want []string
+	}{
+		{"三上悠亜", []string{"SSIS-001", "ABP-200"}},
+		{"#单体作品", []string{"SSIS-001", "IPX-100"}},
+		{"#共演", []string{"ABP-200"}},
+		{"不存在", nil},
+	}
+	for _, tt := range tests {
+		subType, keyword := DetermineSubscriptionType(tt.args)
+		matched := matchTestSubscription(&model.Subscription{Type: subType, Keyword: keyword}, videos)
+		var codes []string
+		for _, video := range matched {
+			codes = append(codes, video.Code)
+		}
+		if strings.Join(codes, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("matchTestSubscription(%q) = %v, want %v", tt.args, codes, tt.want)
+		}
+	}
+}
+
+func TestFormatTestSubResult(t *testing.T) {
+	sub := &model.Subscription{Type: model.SubTypeTag, Keyword: "单体作品"}
+
+	text := formatTestSubResult(sub, nil, 50)
+	if !strings.Contains(text, "标签: #单体作品") || !strings.Contains(text, "最近 50 个视频中没有匹配") {
+		t.Errorf("no match result = %q", text)
+	}
+
+	matched := make([]*model.Video, 12)
+	for i := range matched {
+		matched[i] = &model.Video{Code: "SSIS-001", Title: strings.Repeat("标", 50)}
+	}
+	text = formatTestSubResult(sub, matched, 20)
+	for _, want := range []string{
+		"最近 20 个视频中有 12 个匹配",
+		"…等 12 个",
+		"超过一半的视频匹配",
+		"/subscribe #单体作品",
+		strings.Repeat("标", 37) + "...",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("result missing %q:\n%s", want, text)
+		}
+	}
+	if n := strings.Count(text, "• SSIS-001"); n != testSubListed {
+		t.Errorf("listed %d videos, want %d", n, testSubListed)
+	}
+}
@@ -0,0 +1,89 @@
+package bot
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// throttlePruneSize is the number of tracked senders above which idle entries are dropped
+const throttlePruneSize = 1024
+
+// throttleKey identifies a command sender within a chat
+type throttleKey struct {
+	chatID int64
+	userID int64
+}
+
+// throttleEntry is the token bucket of a single sender
+type throttleEntry struct {
+	limiter  *rate.Limiter
+	warned   bool
+	lastSeen time.Time
+}
+
+// commandThrottle limits how many commands each user may issue per chat
+// Senders over the limit are warned once and then ignored until their bucket refills.
+type commandThrottle struct {
+	limit   rate.Limit
+	burst   int
+	idle    time.Duration
+	entries map[throttleKey]*throttleEntry
+	mu      sync.Mutex
+}
+
+// newCommandThrottle creates a throttle allowing perMinute commands per sender
+// Returns nil when perMinute is not positive, which disables throttling.
+func newCommandThrottle(perMinute int) *commandThrottle {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &commandThrottle{
+		limit:   rate.Every(time.Minute / time.Duration(perMinute)),
+		burst:   perMinute,
+		idle:    time.Minute,
+		entries: make(map[throttleKey]*throttleEntry),
+	}
+}
+
+// Allow reports whether a command from the sender may run at now
+// warn is true for the first rejected command after the sender was last allowed.
+func (t *commandThrottle) Allow(chatID, userID int64, now time.Time) (allowed bool, warn bool) {
+	if t == nil {
+		return true, false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	key := throttleKey{chatID: chatID, userID: userID}
+	entry, ok := t.entries[key]
+	if !ok {
+		if len(t.entries) >= throttlePruneSize {
+			t.prune(now)
+		}
+		entry = &throttleEntry{limiter: rate.NewLimiter(t.limit, t.burst)}
+		t.entries[key] = entry
+	}
+	entry.lastSeen = now
+
+	if entry.limiter.AllowN(now, 1) {
+		entry.warned = false
+		return true, false
+	}
+	if entry.warned {
+		return false, false
+	}
+	entry.warned = true
+	return false, true
+}
+
+// prune drops senders idle long enough for their bucket to have refilled
+func (t *commandThrottle) prune(now time.Time) {
+	for key, entry := range t.entries {
+		if now.Sub(entry.lastSeen) >= t.idle {
+			delete(t.entries, key)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCommandThrottle_WarnsOnceThenIgnores(t *testing.T) {
+	throttle := newCommandThrottle(3)
+	now := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if allowed, _ := throttle.Allow(1, 10, now); !allowed {
+			t.Fatalf("command %d rejected within burst", i+1)
+		}
+	}
+
+	allowed, warn := throttle.Allow(1, 10, now)
+	if allowed || !warn {
+		t.Errorf("first excess command: allowed=%v warn=%v, want false true", allowed, warn)
+	}
+	allowed, warn = throttle.Allow(1, 10, now)
+	if allowed || warn {
+		t.Errorf("second excess command: allowed=%v warn=%v, want false false", allowed, warn)
+	}
+
+	// Other senders and chats have their own buckets
+	if allowed, _ := throttle.Allow(1, 11, now); !allowed {
+		t.Error("other user in same chat was throttled")
+	}
+	if allowed, _ := throttle.Allow(2, 10, now); !allowed {
+		t.Error("same user in other chat was throttled")
+	}
+
+	// A refilled token allows the sender again and re-arms the warning
+	now = now.Add(20 * time.Second)
+	if allowed, _ := throttle.Allow(1, 10, now); !allowed {
+		t.Error("command rejected after refill")
+	}
+	if _, warn := throttle.Allow(1, 10, now); !warn {
+		t.Error("warning not re-armed after sender was allowed again")
+	}
+}
+
+func TestCommandThrottle_Disabled(t *testing.T) {
+	throttle := newCommandThrottle(0)
+	for i := 0; i < 100; i++ {
+		if allowed, _ := throttle.Allow(1, 10, time.Now()); !allowed {
+			t.Fatal("disabled throttle rejected a command")
+		}
+	}
+}
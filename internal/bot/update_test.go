@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDetachUpdate_OutlivesDeadlineButNotRoot(t *testing.T) {
+	root, cancelRoot := context.WithCancel(context.Background())
+	defer cancelRoot()
+
+	ctx := context.WithValue(root, updateRootKey{}, root)
+	ctx, cancelUpdate := context.WithTimeout(ctx, time.Millisecond)
+	defer cancelUpdate()
+
+	detached, cancel := detachUpdate(ctx)
+	defer cancel()
+
+	<-ctx.Done()
+	if err := detached.Err(); err != nil {
+		t.Fatalf("detached context ended with the update: %v", err)
+	}
+
+	cancelRoot()
+	select {
+	case <-detached.Done():
+	case <-time.After(time.Second):
+		t.Fatal("detached context not canceled with the update loop")
+	}
+}
@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// unknownCommandLabel replaces unrecognized command names in usage records
+// so arbitrary user input cannot create unbounded metric series
+const unknownCommandLabel = "unknown"
+
+// throttledCommandLabel labels throttled invocations, which are counted before the command is resolved
+const throttledCommandLabel = "throttled"
+
+var (
+	commandsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "missav_bot_commands_total",
+		Help: "Total number of bot command invocations by outcome",
+	}, []string{"command", "outcome"})
+
+	commandDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "missav_bot_command_duration_seconds",
+		Help:    "Duration of bot command handling in seconds",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"command"})
+)
+
+func init() {
+	prometheus.MustRegister(commandsTotal)
+	prometheus.MustRegister(commandDurationSeconds)
+}
+
+// commandUsage tracks the outcome of a single command invocation
+// Handlers mark it through sendError and deny, which find it in the context.
+type commandUsage struct {
+	outcome atomic.Value // model.CommandOutcome
+}
+
+type commandUsageKey struct{}
+
+// withCommandUsage attaches a usage tracker to the context
+func withCommandUsage(ctx context.Context, usage *commandUsage) context.Context {
+	return context.WithValue(ctx, commandUsageKey{}, usage)
+}
+
+// markCommandOutcome records the outcome of the command handled under ctx, if tracked
+// The first non-OK outcome wins.
+func markCommandOutcome(ctx context.Context, outcome model.CommandOutcome) {
+	usage, ok := ctx.Value(commandUsageKey{}).(*commandUsage)
+	if !ok {
+		return
+	}
+	usage.outcome.CompareAndSwap(nil, outcome)
+}
+
+// recordCommandUsage stores and exports a finished command invocation
+func (h *Handler) recordCommandUsage(ctx context.Context, chatID int64, command string, start time.Time, usage *commandUsage) {
+	latency := time.Since(start)
+	outcome := model.CommandOutcomeOK
+	if o, ok := usage.outcome.Load().(model.CommandOutcome); ok {
+		outcome = o
+	}
+
+	commandsTotal.WithLabelValues(command, string(outcome)).Inc()
+	commandDurationSeconds.WithLabelValues(command).Observe(latency.Seconds())
+
+	entry := &model.CommandLog{
+		ChatID:    chatID,
+		Command:   command,
+		Outcome:   outcome,
+		LatencyMs: latency.Milliseconds(),
+	}
+	if err := h.store.RecordCommand(ctx, entry); err != nil {
+		log.Warn().Err(err).Str("command", command).Msg("Failed to record command usage")
+	}
+}
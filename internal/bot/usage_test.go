@@ -0,0 +1,23 @@
+package bot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestMarkCommandOutcome_FirstFailureWins(t *testing.T) {
+	usage := &commandUsage{}
+	ctx := withCommandUsage(context.Background(), usage)
+
+	markCommandOutcome(ctx, model.CommandOutcomeDenied)
+	markCommandOutcome(ctx, model.CommandOutcomeError)
+
+	if got := usage.outcome.Load(); got != model.CommandOutcomeDenied {
+		t.Errorf("outcome = %v, want %v", got, model.CommandOutcomeDenied)
+	}
+
+	// Untracked contexts are ignored
+	markCommandOutcome(context.Background(), model.CommandOutcomeError)
+}
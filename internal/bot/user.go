@@ -0,0 +1,160 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/push"
+)
+
+// userTouchInterval is how often a user's activity is written to the store
+// Messages in between only update the in-memory timestamp.
+const userTouchInterval = 5 * time.Minute
+
+// userActivityPruneSize is the number of tracked users above which stale entries are dropped
+const userActivityPruneSize = 4096
+
+// userActivity remembers when each user's activity was last stored
+type userActivity struct {
+	interval time.Duration
+	touched  map[int64]time.Time
+	mu       sync.Mutex
+}
+
+// newUserActivity creates a tracker storing each user's activity at most once per interval
+func newUserActivity(interval time.Duration) *userActivity {
+	return &userActivity{
+		interval: interval,
+		touched:  make(map[int64]time.Time),
+	}
+}
+
+// Due reports whether the user's activity at now should be stored, and if so marks it stored
+func (a *userActivity) Due(userID int64, now time.Time) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if last, ok := a.touched[userID]; ok && now.Sub(last) < a.interval {
+		return false
+	}
+	if len(a.touched) >= userActivityPruneSize {
+		for id, last := range a.touched {
+			if now.Sub(last) >= a.interval {
+				delete(a.touched, id)
+			}
+		}
+	}
+	a.touched[userID] = now
+	return true
+}
+
+// Forget drops the user so the next activity is stored again, used when storing failed
+func (a *userActivity) Forget(userID int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.touched, userID)
+}
+
+// touchUser records the sender of a message in the users table
+// Bots and anonymous senders are skipped.
+func (h *Handler) touchUser(ctx context.Context, from *tgbotapi.User) {
+	if from == nil || from.IsBot {
+		return
+	}
+	now := time.Now()
+	if !h.users.Due(from.ID, now) {
+		return
+	}
+
+	user := &model.User{
+		ID:           from.ID,
+		Username:     from.UserName,
+		LanguageCode: from.LanguageCode,
+		LastActiveAt: now,
+	}
+	if err := h.store.TouchUser(ctx, user); err != nil {
+		h.users.Forget(from.ID)
+		log.Warn().Err(err).Int64("userID", from.ID).Msg("Failed to record user activity")
+	}
+}
+
+// handleMe handles /me command
+// Shows the sender's account and the subscriptions and pushes of their private chat with the bot.
+func (h *Handler) handleMe(ctx context.Context, msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+	if msg.From == nil {
+		h.sendError(ctx, chatID, "无法识别你的账户，请在私聊中使用 /me。")
+		return
+	}
+	userID := msg.From.ID
+
+	user, err := h.store.GetUser(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("userID", userID).Msg("Failed to get user")
+		h.sendError(ctx, chatID, "获取账户信息失败，请重试。")
+		return
+	}
+	subs, err := h.store.GetSubscriptions(ctx, userID)
+	if err != nil {
+		log.Error().Err(err).Int64("userID", userID).Msg("Failed to get subscriptions")
+		h.sendError(ctx, chatID, "获取订阅列表失败，请重试。")
+		return
+	}
+	total, err := h.store.CountPushesSince(ctx, userID, time.Time{})
+	if err != nil {
+		log.Error().Err(err).Int64("userID", userID).Msg("Failed to count pushes")
+		h.sendError(ctx, chatID, "获取推送统计失败，请重试。")
+		return
+	}
+	recent, err := h.store.CountPushesSince(ctx, userID, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		log.Error().Err(err).Int64("userID", userID).Msg("Failed to count pushes")
+		h.sendError(ctx, chatID, "获取推送统计失败，请重试。")
+		return
+	}
+
+	if _, err := h.telegram.SendMarkdown(chatID, formatMe(msg.From, user, subs, total, recent)); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send user info")
+	}
+}
+
+// formatMe builds the MarkdownV2 /me reply
+// user is nil for senders not stored yet.
+func formatMe(from *tgbotapi.User, user *model.User, subs []*model.Subscription, total, recent int64) string {
+	name := from.FirstName
+	if from.UserName != "" {
+		name = "@" + from.UserName
+	}
+
+	lines := []string{
+		"👤 *我的账户*\n",
+		fmt.Sprintf("ID: `%d`", from.ID),
+		"用户名: " + push.EscapeMarkdown(name),
+	}
+	if user != nil {
+		lines = append(lines,
+			"首次使用: "+push.EscapeMarkdown(user.FirstSeenAt.Format("2006-01-02 15:04")),
+			"最近活跃: "+push.EscapeMarkdown(user.LastActiveAt.Format("2006-01-02 15:04")),
+		)
+	}
+
+	lines = append(lines, "", "📋 *私聊订阅*")
+	if len(subs) == 0 {
+		lines = append(lines, "暂无，私聊机器人发送 /subscribe 开始订阅。")
+	}
+	for i, sub := range subs {
+		lines = append(lines, formatSubscriptionLine(i+1, sub))
+	}
+
+	lines = append(lines, "", "📨 *私聊推送*",
+		fmt.Sprintf("累计: %d 条", total),
+		fmt.Sprintf("最近24小时: %d 条", recent),
+	)
+	return strings.Join(lines, "\n")
+}
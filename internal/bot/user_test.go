@@ -0,0 +1,30 @@
+package bot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserActivity_StoresOncePerInterval(t *testing.T) {
+	activity := newUserActivity(5 * time.Minute)
+	now := time.Now()
+
+	if !activity.Due(1, now) {
+		t.Fatal("first activity not due")
+	}
+	if activity.Due(1, now.Add(time.Minute)) {
+		t.Error("activity due again within the interval")
+	}
+	if !activity.Due(2, now.Add(time.Minute)) {
+		t.Error("other user's first activity not due")
+	}
+	if !activity.Due(1, now.Add(5*time.Minute)) {
+		t.Error("activity not due after the interval")
+	}
+
+	// A failed store is retried on the next message
+	activity.Forget(2)
+	if !activity.Due(2, now.Add(2*time.Minute)) {
+		t.Error("forgotten user's activity not due")
+	}
+}
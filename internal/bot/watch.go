@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// watchMaxPerChat caps the codes a chat may watch at once
+const watchMaxPerChat = 50
+
+// handleWatch handles /watch command
+// Watches a code that is not in the database yet; the scheduler pushes it to this chat
+// once it appears and then drops the watch. Without a code it lists the watched codes.
+func (h *Handler) handleWatch(ctx context.Context, chatID int64, userID int64, args string) {
+	if args == "" {
+		h.listCodeWatches(ctx, chatID)
+		return
+	}
+
+	code := model.CanonicalCode(crawler.ExtractCode(args))
+	if code == "" {
+		h.sendError(ctx, chatID, "请提供番号。例如: /watch ABC-123")
+		return
+	}
+
+	video, err := h.store.GetVideoByCode(ctx, code)
+	if err != nil {
+		log.Error().Err(err).Str("code", code).Msg("Failed to get video")
+		h.sendError(ctx, chatID, "查询失败，请重试。")
+		return
+	}
+	if video != nil {
+		h.sendError(ctx, chatID, fmt.Sprintf("ℹ️ %s 已在数据库中，使用 /detail %s 查看。", code, code))
+		return
+	}
+
+	watches, err := h.store.GetCodeWatches(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get code watches")
+		h.sendError(ctx, chatID, "关注失败，请重试。")
+		return
+	}
+	if len(watches) >= watchMaxPerChat {
+		h.sendError(ctx, chatID, fmt.Sprintf("每个聊天最多关注 %d 个番号，请先使用 /unwatch 取消一些。", watchMaxPerChat))
+		return
+	}
+
+	added, err := h.store.AddCodeWatch(ctx, &model.CodeWatch{ChatID: chatID, Code: code, UserID: userID})
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("code", code).Msg("Failed to add code watch")
+		h.sendError(ctx, chatID, "关注失败，请重试。")
+		return
+	}
+
+	text := fmt.Sprintf("👀 已关注 %s，上线后会立即推送到本聊天。\n使用 /unwatch %s 取消。", code, code)
+	if !added {
+		text = fmt.Sprintf("ℹ️ 本聊天已在关注 %s。", code)
+	}
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send watch confirmation")
+	}
+}
+
+// handleUnwatch handles /unwatch command
+func (h *Handler) handleUnwatch(ctx context.Context, chatID int64, args string) {
+	code := model.CanonicalCode(crawler.ExtractCode(args))
+	if code == "" {
+		h.sendError(ctx, chatID, "请提供番号。例如: /unwatch ABC-123")
+		return
+	}
+
+	removed, err := h.store.RemoveCodeWatch(ctx, chatID, code)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Str("code", code).Msg("Failed to remove code watch")
+		h.sendError(ctx, chatID, "取消关注失败，请重试。")
+		return
+	}
+
+	text := fmt.Sprintf("✅ 已取消关注 %s。", code)
+	if !removed {
+		text = fmt.Sprintf("ℹ️ 本聊天未关注 %s。", code)
+	}
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send unwatch confirmation")
+	}
+}
+
+// listCodeWatches replies with the codes watched in a chat
+func (h *Handler) listCodeWatches(ctx context.Context, chatID int64) {
+	watches, err := h.store.GetCodeWatches(ctx, chatID)
+	if err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to get code watches")
+		h.sendError(ctx, chatID, "获取关注列表失败，请重试。")
+		return
+	}
+
+	text := "📭 本聊天没有关注的番号。\n使用 /watch ABC-123 在番号上线时收到推送。"
+	if len(watches) > 0 {
+		codes := make([]string, len(watches))
+		for i, watch := range watches {
+			codes[i] = watch.Code
+		}
+		text = fmt.Sprintf("👀 关注中的番号 (%d):\n%s", len(watches), strings.Join(codes, "\n"))
+	}
+	if err := h.telegram.SendMessage(chatID, text); err != nil {
+		log.Error().Err(err).Int64("chatID", chatID).Msg("Failed to send code watches")
+	}
+}
@@ -2,9 +2,12 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/kelseyhightower/envconfig"
+	"github.com/rs/zerolog"
 )
 
 // Config holds all application configuration
@@ -13,15 +16,45 @@ type Config struct {
 	DB      DBConfig
 	Crawler CrawlerConfig
 	Server  ServerConfig
+	Redis   RedisConfig
+	Push    PushConfig
+	Log     LogConfig
 }
 
 // BotConfig holds Telegram bot configuration
 type BotConfig struct {
-	Token         string `envconfig:"BOT_TOKEN" required:"true"`
-	Username      string `envconfig:"BOT_USERNAME" default:"MissavBot"`
-	DefaultChatID int64  `envconfig:"BOT_CHAT_ID" default:"0"`
+	Token         string  `envconfig:"BOT_TOKEN" required:"true"`
+	Username      string  `envconfig:"BOT_USERNAME" default:"MissavBot"`
+	DefaultChatID int64   `envconfig:"BOT_CHAT_ID" default:"0"`
+	AdminIDs      []int64 `envconfig:"BOT_ADMIN_IDS"`
+
+	// ExtraTokens are further bots run alongside the primary one, sharing the store and crawler
+	ExtraTokens []string `envconfig:"BOT_TOKENS"`
+
+	// CommandRateLimit is the number of commands each user may send per chat per minute (0 disables)
+	CommandRateLimit int `envconfig:"BOT_COMMAND_RATE_LIMIT" default:"10"`
+
+	// UpdateTimeout bounds the handling of each Telegram update (0 disables)
+	UpdateTimeout time.Duration `envconfig:"BOT_UPDATE_TIMEOUT" default:"30s"`
+
+	// DropPendingUpdates skips updates sent while the bot was down instead of resuming
+	// from the last processed one
+	DropPendingUpdates bool `envconfig:"BOT_DROP_PENDING_UPDATES" default:"false"`
+
+	// APIEndpoint is the base URL of a self-hosted telegram-bot-api server, e.g.
+	// http://telegram-bot-api:8081 (empty uses api.telegram.org)
+	APIEndpoint string `envconfig:"BOT_API_ENDPOINT"`
+
+	// SearchLimit and LatestPageSize are the number of /search results and /latest videos
+	// per page (0 uses 10 and 5); chats can override them with /settings, up to MaxResultPageSize
+	SearchLimit    int `envconfig:"BOT_SEARCH_LIMIT" default:"10"`
+	LatestPageSize int `envconfig:"BOT_LATEST_PAGE_SIZE" default:"5"`
 }
 
+// MaxResultPageSize caps /search and /latest pages so a page stays within
+// Telegram's message length limit
+const MaxResultPageSize = 30
+
 // DBConfig holds database configuration
 type DBConfig struct {
 	Host     string `envconfig:"DB_HOST" default:"localhost"`
@@ -30,26 +63,185 @@ type DBConfig struct {
 	Password string `envconfig:"DB_PASSWORD" required:"true"`
 	Database string `envconfig:"DB_NAME" default:"missav_bot"`
 	MaxConns int    `envconfig:"DB_MAX_CONNS" default:"10"`
+	ReadHost string `envconfig:"DB_READ_HOST"`
+
+	SlowQueryThreshold time.Duration `envconfig:"DB_SLOW_QUERY_THRESHOLD" default:"200ms"`
 }
 
 // CrawlerConfig holds crawler configuration
 type CrawlerConfig struct {
-	Enabled      bool          `envconfig:"CRAWLER_ENABLED" default:"true"`
-	Interval     time.Duration `envconfig:"CRAWLER_INTERVAL" default:"15m"`
-	InitialPages int           `envconfig:"CRAWLER_INITIAL_PAGES" default:"2"`
-	RateLimit    float64       `envconfig:"CRAWLER_RATE_LIMIT" default:"0.5"`
-	Timeout      time.Duration `envconfig:"CRAWLER_TIMEOUT" default:"30s"`
-	MaxRetries   int           `envconfig:"CRAWLER_MAX_RETRIES" default:"3"`
-	Concurrency  int           `envconfig:"CRAWLER_CONCURRENCY" default:"3"`
-	UserAgent    string        `envconfig:"CRAWLER_USER_AGENT"`
-	ProxyURL     string        `envconfig:"CRAWLER_PROXY_URL"`
+	Enabled         bool          `envconfig:"CRAWLER_ENABLED" default:"true"`
+	Interval        time.Duration `envconfig:"CRAWLER_INTERVAL" default:"15m"`
+	InitialPages    int           `envconfig:"CRAWLER_INITIAL_PAGES" default:"2"`
+	RateLimit       float64       `envconfig:"CRAWLER_RATE_LIMIT" default:"0.5"`
+	Timeout         time.Duration `envconfig:"CRAWLER_TIMEOUT" default:"30s"`
+	MaxRetries      int           `envconfig:"CRAWLER_MAX_RETRIES" default:"3"`
+	Concurrency     int           `envconfig:"CRAWLER_CONCURRENCY" default:"3"`
+	UserAgent       string        `envconfig:"CRAWLER_USER_AGENT"`
+	ProxyURL        string        `envconfig:"CRAWLER_PROXY_URL"`
+	DistributedLock bool          `envconfig:"CRAWLER_DISTRIBUTED_LOCK" default:"false"`
+
+	// TagCrawlLimit is the number of videos crawled per subscribed tag each cycle (0 disables)
+	TagCrawlLimit int `envconfig:"CRAWLER_TAG_CRAWL_LIMIT" default:"12"`
+	// TagCrawlsPerRun caps how many subscribed tags are crawled per cycle; tags rotate across cycles
+	TagCrawlsPerRun int `envconfig:"CRAWLER_TAG_CRAWLS_PER_RUN" default:"5"`
+	// ActressCrawlLimit is the number of videos crawled per subscribed actress each cycle (0 disables)
+	ActressCrawlLimit int `envconfig:"CRAWLER_ACTRESS_CRAWL_LIMIT" default:"12"`
+	// ActressCrawlsPerRun caps how many subscribed actresses are crawled per cycle; actresses rotate across cycles
+	ActressCrawlsPerRun int `envconfig:"CRAWLER_ACTRESS_CRAWLS_PER_RUN" default:"3"`
+	// ImpersonateTLS sends crawler HTTP requests with Chrome's TLS fingerprint instead of Go's
+	ImpersonateTLS bool `envconfig:"CRAWLER_IMPERSONATE_TLS" default:"false"`
+	// DetailCacheTTL is how long fetched detail pages are reused (0 disables the cache)
+	DetailCacheTTL time.Duration `envconfig:"CRAWLER_DETAIL_CACHE_TTL" default:"30m"`
+	// DetailCacheDir keeps cached detail pages on disk instead of in memory
+	DetailCacheDir string `envconfig:"CRAWLER_DETAIL_CACHE_DIR"`
+	// ProxyUsername and ProxyPassword authenticate to CRAWLER_PROXY_URL when it carries no credentials
+	ProxyUsername string `envconfig:"CRAWLER_PROXY_USERNAME"`
+	ProxyPassword string `envconfig:"CRAWLER_PROXY_PASSWORD"`
+	// SessionRefreshInterval is how often the site session cookies are checked in the
+	// background and refreshed before they expire (0 disables)
+	SessionRefreshInterval time.Duration `envconfig:"CRAWLER_SESSION_REFRESH_INTERVAL" default:"2m"`
+	// DailyBudget caps HTTP and browser requests per day; when used up only admin crawls run (0 disables)
+	DailyBudget int `envconfig:"CRAWLER_DAILY_BUDGET" default:"0"`
+	// BrowserMaxPages and BrowserMaxAge bound the headless browser's lifetime before it is relaunched (0 disables)
+	BrowserMaxPages int           `envconfig:"CRAWLER_BROWSER_MAX_PAGES" default:"200"`
+	BrowserMaxAge   time.Duration `envconfig:"CRAWLER_BROWSER_MAX_AGE" default:"2h"`
+	// BrowserNavigationDeadline aborts browser navigations (e.g. a hung Cloudflare challenge) that run longer
+	BrowserNavigationDeadline time.Duration `envconfig:"CRAWLER_BROWSER_NAVIGATION_DEADLINE" default:"2m"`
+	// BrowserRemoteURL renders pages on an external Chrome/browserless instance instead of a local Chrome
+	BrowserRemoteURL string `envconfig:"BROWSER_REMOTE_URL"`
+	// BrowserBinPath launches this Chrome binary instead of CHROME_PATH or a downloaded one
+	BrowserBinPath string `envconfig:"BROWSER_BIN_PATH"`
+	// BrowserExtraFlags are space-separated Chrome switches added at launch, e.g. "--lang=ja-JP"
+	BrowserExtraFlags string `envconfig:"BROWSER_EXTRA_FLAGS"`
+	// BrowserHeadful shows Chrome's window and BrowserDevtools opens DevTools, for local debugging
+	BrowserHeadful  bool `envconfig:"BROWSER_HEADFUL" default:"false"`
+	BrowserDevtools bool `envconfig:"BROWSER_DEVTOOLS" default:"false"`
+	// SnapshotDir keeps HTML and screenshots of browser crawls that found nothing, for admins to inspect
+	SnapshotDir  string `envconfig:"CRAWLER_SNAPSHOT_DIR"`
+	SnapshotKeep int    `envconfig:"CRAWLER_SNAPSHOT_KEEP" default:"50"`
+	// Sources are the sites crawled, primary first
+	Sources []string `envconfig:"CRAWLER_SOURCES" default:"missav"`
+	// MergePriority picks the source of each field of videos several sources found,
+	// e.g. "title=missav,javdb;tags=javdb"
+	MergePriority string `envconfig:"CRAWLER_MERGE_PRIORITY"`
+	// EnrichPerRun is the number of incomplete videos whose detail pages are crawled each cycle
+	EnrichPerRun int `envconfig:"CRAWLER_ENRICH_PER_RUN" default:"5"`
+	// EnrichWindow limits enrichment to videos created within this window
+	EnrichWindow time.Duration `envconfig:"CRAWLER_ENRICH_WINDOW" default:"72h"`
+	// WatchProbesPerRun is the number of /watch codes missing from the database whose
+	// detail pages are probed each cycle; watched codes rotate across cycles (0 disables probing)
+	WatchProbesPerRun int `envconfig:"CRAWLER_WATCH_PROBES_PER_RUN" default:"5"`
+	// DuplicateThreshold is the title similarity (0-1) at which videos with different
+	// codes are flagged as duplicates (0 disables the scan)
+	DuplicateThreshold float64       `envconfig:"CRAWLER_DUPLICATE_THRESHOLD" default:"0.9"`
+	DuplicateWindow    time.Duration `envconfig:"CRAWLER_DUPLICATE_WINDOW" default:"168h"`
+}
+
+// PushConfig holds push delivery configuration
+type PushConfig struct {
+	Workers int `envconfig:"PUSH_WORKERS" default:"4"`
+
+	// Outbound webhooks notified of each new matched video
+	WebhookURLs    []string      `envconfig:"PUSH_WEBHOOK_URLS"`
+	WebhookSecret  string        `envconfig:"PUSH_WEBHOOK_SECRET"`
+	WebhookTimeout time.Duration `envconfig:"PUSH_WEBHOOK_TIMEOUT" default:"10s"`
+
+	// DailyCap is the maximum number of videos pushed to a Telegram chat per day (0 disables)
+	DailyCap int `envconfig:"PUSH_DAILY_CAP" default:"0"`
+
+	// BatchSize is the maximum number of videos listed in one combined push message
+	BatchSize int `envconfig:"PUSH_BATCH_SIZE" default:"10"`
+	// BatchThreshold combines a delivery into list messages once it brings a chat this many videos (0 disables)
+	BatchThreshold int `envconfig:"PUSH_BATCH_THRESHOLD" default:"5"`
+
+	// ParseMode is the default markup of pushed messages: markdown (MarkdownV2) or html
+	ParseMode string `envconfig:"PUSH_PARSE_MODE" default:"markdown"`
+
+	// ChatDelay is the minimum spacing between messages to one Telegram chat (0 disables)
+	ChatDelay time.Duration `envconfig:"PUSH_CHAT_DELAY" default:"1s"`
+}
+
+// RedisConfig holds optional Redis cache configuration
+type RedisConfig struct {
+	URL      string        `envconfig:"REDIS_URL"`
+	CacheTTL time.Duration `envconfig:"REDIS_CACHE_TTL" default:"30s"`
 }
 
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
 	Port int `envconfig:"SERVER_PORT" default:"8080"`
+	// APIToken authorizes admin API calls as a bearer token; admin endpoints are disabled when empty
+	APIToken string `envconfig:"SERVER_API_TOKEN"`
 }
 
+// LogConfig holds logging configuration
+type LogConfig struct {
+	// Level is the minimum level logged: trace, debug, info, warn or error
+	// Admins can change it until the next restart with /loglevel.
+	Level string `envconfig:"LOG_LEVEL" default:"info"`
+	// Format is json for structured logs or console for human-readable local output
+	Format string `envconfig:"LOG_FORMAT" default:"json"`
+
+	// File additionally writes JSON logs to this path (empty disables)
+	// It is rotated once it exceeds FileMaxSize megabytes or has been written to for
+	// FileMaxAge, keeping FileMaxBackups rotated files.
+	File           string        `envconfig:"LOG_FILE"`
+	FileMaxSize    int           `envconfig:"LOG_FILE_MAX_SIZE" default:"100"`
+	FileMaxAge     time.Duration `envconfig:"LOG_FILE_MAX_AGE" default:"24h"`
+	FileMaxBackups int           `envconfig:"LOG_FILE_MAX_BACKUPS" default:"7"`
+
+	// AlertChatID receives error logs as Telegram messages (0 disables)
+	// Repeats of an error are sent at most once per AlertCooldown, with a count of those skipped.
+	AlertChatID   int64         `envconfig:"LOG_ALERT_CHAT_ID"`
+	AlertCooldown time.Duration `envconfig:"LOG_ALERT_COOLDOWN" default:"15m"`
+}
+
+// ParseLogLevel parses a LOG_LEVEL value; empty means info
+func ParseLogLevel(level string) (zerolog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(level)) {
+	case "":
+		return zerolog.InfoLevel, nil
+	case "trace":
+		return zerolog.TraceLevel, nil
+	case "debug":
+		return zerolog.DebugLevel, nil
+	case "info":
+		return zerolog.InfoLevel, nil
+	case "warn":
+		return zerolog.WarnLevel, nil
+	case "error":
+		return zerolog.ErrorLevel, nil
+	default:
+		return zerolog.NoLevel, fmt.Errorf("unknown log level %q, use trace, debug, info, warn or error", level)
+	}
+}
+
+
+// IsAdmin reports whether the given Telegram user ID is a configured admin
+func (c *BotConfig) IsAdmin(userID int64) bool {
+	for _, id := range c.AdminIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// Tokens returns the primary bot token followed by the extra ones, without duplicates
+func (c *BotConfig) Tokens() []string {
+	tokens := []string{c.Token}
+	seen := map[string]bool{c.Token: true}
+	for _, token := range c.ExtraTokens {
+		token = strings.TrimSpace(token)
+		if token == "" || seen[token] {
+			continue
+		}
+		seen[token] = true
+		tokens = append(tokens, token)
+	}
+	return tokens
+}
 
 // DSN returns the MySQL data source name
 func (c *DBConfig) DSN() string {
@@ -57,6 +249,16 @@ func (c *DBConfig) DSN() string {
 		c.User, c.Password, c.Host, c.Port, c.Database)
 }
 
+// ReadDSN returns the data source name of the read replica, or "" when none is configured
+// The replica shares the primary's port, credentials and database name.
+func (c *DBConfig) ReadDSN() string {
+	if c.ReadHost == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		c.User, c.Password, c.ReadHost, c.Port, c.Database)
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	var cfg Config
@@ -77,6 +279,27 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to load server config: %w", err)
 	}
 
+	if err := envconfig.Process("", &cfg.Redis); err != nil {
+		return nil, fmt.Errorf("failed to load redis config: %w", err)
+	}
+
+	if err := envconfig.Process("", &cfg.Push); err != nil {
+		return nil, fmt.Errorf("failed to load push config: %w", err)
+	}
+
+	if err := envconfig.Process("", &cfg.Log); err != nil {
+		return nil, fmt.Errorf("failed to load log config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+// LoadDB loads only the database configuration, for tools that do not run the bot
+func LoadDB() (*DBConfig, error) {
+	var cfg DBConfig
+	if err := envconfig.Process("", &cfg); err != nil {
+		return nil, fmt.Errorf("failed to load db config: %w", err)
+	}
 	return &cfg, nil
 }
 
@@ -88,14 +311,87 @@ func (c *Config) Validate() error {
 	if c.DB.Password == "" {
 		return fmt.Errorf("DB_PASSWORD is required")
 	}
+	if c.Bot.UpdateTimeout < 0 {
+		return fmt.Errorf("BOT_UPDATE_TIMEOUT must not be negative")
+	}
+	if c.Bot.APIEndpoint != "" {
+		u, err := url.Parse(c.Bot.APIEndpoint)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("BOT_API_ENDPOINT must be an http or https URL")
+		}
+	}
+	if c.Bot.SearchLimit < 0 || c.Bot.SearchLimit > MaxResultPageSize {
+		return fmt.Errorf("BOT_SEARCH_LIMIT must be between 1 and %d", MaxResultPageSize)
+	}
+	if c.Bot.LatestPageSize < 0 || c.Bot.LatestPageSize > MaxResultPageSize {
+		return fmt.Errorf("BOT_LATEST_PAGE_SIZE must be between 1 and %d", MaxResultPageSize)
+	}
 	if c.Crawler.RateLimit <= 0 {
 		return fmt.Errorf("CRAWLER_RATE_LIMIT must be positive")
 	}
 	if c.Crawler.Concurrency <= 0 {
 		return fmt.Errorf("CRAWLER_CONCURRENCY must be positive")
 	}
+	if c.Crawler.DailyBudget < 0 {
+		return fmt.Errorf("CRAWLER_DAILY_BUDGET must not be negative")
+	}
+	if c.Crawler.DuplicateThreshold < 0 || c.Crawler.DuplicateThreshold > 1 {
+		return fmt.Errorf("CRAWLER_DUPLICATE_THRESHOLD must be between 0 and 1")
+	}
+	if c.Crawler.EnrichPerRun < 0 {
+		return fmt.Errorf("CRAWLER_ENRICH_PER_RUN must not be negative")
+	}
+	if c.Crawler.SessionRefreshInterval < 0 {
+		return fmt.Errorf("CRAWLER_SESSION_REFRESH_INTERVAL must not be negative")
+	}
+	if c.Crawler.WatchProbesPerRun < 0 {
+		return fmt.Errorf("CRAWLER_WATCH_PROBES_PER_RUN must not be negative")
+	}
+	if c.Crawler.BrowserMaxPages < 0 {
+		return fmt.Errorf("CRAWLER_BROWSER_MAX_PAGES must not be negative")
+	}
+	if c.Push.Workers < 0 {
+		return fmt.Errorf("PUSH_WORKERS must not be negative")
+	}
+	if c.Push.DailyCap < 0 {
+		return fmt.Errorf("PUSH_DAILY_CAP must not be negative")
+	}
+	if c.Push.BatchSize < 0 {
+		return fmt.Errorf("PUSH_BATCH_SIZE must not be negative")
+	}
+	if c.Push.BatchThreshold < 0 {
+		return fmt.Errorf("PUSH_BATCH_THRESHOLD must not be negative")
+	}
+	switch c.Push.ParseMode {
+	case "", "markdown", "html":
+	default:
+		return fmt.Errorf("PUSH_PARSE_MODE must be markdown or html")
+	}
+	if c.Push.ChatDelay < 0 {
+		return fmt.Errorf("PUSH_CHAT_DELAY must not be negative")
+	}
 	if c.Server.Port <= 0 || c.Server.Port > 65535 {
 		return fmt.Errorf("SERVER_PORT must be between 1 and 65535")
 	}
+	if _, err := ParseLogLevel(c.Log.Level); err != nil {
+		return fmt.Errorf("LOG_LEVEL must be trace, debug, info, warn or error")
+	}
+	switch c.Log.Format {
+	case "", "json", "console":
+	default:
+		return fmt.Errorf("LOG_FORMAT must be json or console")
+	}
+	if c.Log.FileMaxSize < 0 {
+		return fmt.Errorf("LOG_FILE_MAX_SIZE must not be negative")
+	}
+	if c.Log.FileMaxAge < 0 {
+		return fmt.Errorf("LOG_FILE_MAX_AGE must not be negative")
+	}
+	if c.Log.FileMaxBackups < 0 {
+		return fmt.Errorf("LOG_FILE_MAX_BACKUPS must not be negative")
+	}
+	if c.Log.AlertCooldown < 0 {
+		return fmt.Errorf("LOG_ALERT_COOLDOWN must not be negative")
+	}
 	return nil
 }
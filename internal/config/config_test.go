@@ -2,8 +2,11 @@ package config
 
 import (
 	"os"
+	"reflect"
 	"testing"
 	"time"
+
+	"github.com/rs/zerolog"
 )
 
 func TestLoad_WithRequiredEnvVars(t *testing.T) {
@@ -93,6 +96,34 @@ func TestLoad_DefaultValues(t *testing.T) {
 	if cfg.Server.Port != 8080 {
 		t.Errorf("Server.Port = %v, want %v", cfg.Server.Port, 8080)
 	}
+
+	// Test Log defaults
+	if cfg.Log.Level != "info" || cfg.Log.Format != "json" {
+		t.Errorf("Log = %+v, want info level and json format", cfg.Log)
+	}
+	if cfg.Bot.SearchLimit != 10 || cfg.Bot.LatestPageSize != 5 {
+		t.Errorf("Bot page sizes = %d, %d; want 10, 5", cfg.Bot.SearchLimit, cfg.Bot.LatestPageSize)
+	}
+	if cfg.Log.AlertChatID != 0 || cfg.Log.AlertCooldown != 15*time.Minute {
+		t.Errorf("Log = %+v, want alerts disabled with a 15m cooldown", cfg.Log)
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	tests := map[string]zerolog.Level{
+		"":      zerolog.InfoLevel,
+		"debug": zerolog.DebugLevel,
+		"WARN":  zerolog.WarnLevel,
+		"trace": zerolog.TraceLevel,
+	}
+	for input, want := range tests {
+		if got, err := ParseLogLevel(input); err != nil || got != want {
+			t.Errorf("ParseLogLevel(%q) = %v, %v; want %v", input, got, err, want)
+		}
+	}
+	if _, err := ParseLogLevel("fatal"); err == nil {
+		t.Error("ParseLogLevel(\"fatal\") should fail")
+	}
 }
 
 
@@ -175,6 +206,68 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "negative daily budget",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token"},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3, DailyBudget: -1},
+				Server:  ServerConfig{Port: 8080},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative browser max pages",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token"},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3, BrowserMaxPages: -1},
+				Server:  ServerConfig{Port: 8080},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown parse mode",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token"},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3},
+				Server:  ServerConfig{Port: 8080},
+				Push:    PushConfig{ParseMode: "bbcode"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative chat delay",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token"},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3},
+				Server:  ServerConfig{Port: 8080},
+				Push:    PushConfig{ChatDelay: -time.Second},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid bot API endpoint",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token", APIEndpoint: "telegram-bot-api:8081"},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3},
+				Server:  ServerConfig{Port: 8080},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative update timeout",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token", UpdateTimeout: -time.Second},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3},
+				Server:  ServerConfig{Port: 8080},
+			},
+			wantErr: true,
+		},
 		{
 			name: "invalid port",
 			cfg: Config{
@@ -185,6 +278,71 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "console logs at debug level",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token"},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3},
+				Server:  ServerConfig{Port: 8080},
+				Log:     LogConfig{Level: "debug", Format: "console"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown log level",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token"},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3},
+				Server:  ServerConfig{Port: 8080},
+				Log:     LogConfig{Level: "verbose"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative log file size",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token"},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3},
+				Server:  ServerConfig{Port: 8080},
+				Log:     LogConfig{File: "bot.log", FileMaxSize: -1},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown log format",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token"},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3},
+				Server:  ServerConfig{Port: 8080},
+				Log:     LogConfig{Format: "text"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "search limit above the cap",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token", SearchLimit: MaxResultPageSize + 1},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3},
+				Server:  ServerConfig{Port: 8080},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative alert cooldown",
+			cfg: Config{
+				Bot:     BotConfig{Token: "token"},
+				DB:      DBConfig{Password: "pass"},
+				Crawler: CrawlerConfig{RateLimit: 0.5, Concurrency: 3},
+				Server:  ServerConfig{Port: 8080},
+				Log:     LogConfig{AlertChatID: -100123, AlertCooldown: -time.Minute},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -197,6 +355,50 @@ func TestConfig_Validate(t *testing.T) {
 	}
 }
 
+func TestLoad_AdminIDs(t *testing.T) {
+	os.Setenv("BOT_TOKEN", "test-token")
+	os.Setenv("DB_PASSWORD", "test-pass")
+	os.Setenv("BOT_ADMIN_IDS", "1001,2002")
+	defer func() {
+		os.Unsetenv("BOT_TOKEN")
+		os.Unsetenv("DB_PASSWORD")
+		os.Unsetenv("BOT_ADMIN_IDS")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	if !cfg.Bot.IsAdmin(1001) || !cfg.Bot.IsAdmin(2002) {
+		t.Errorf("Bot.AdminIDs = %v, want [1001 2002]", cfg.Bot.AdminIDs)
+	}
+	if cfg.Bot.IsAdmin(3003) {
+		t.Errorf("IsAdmin(3003) = true, want false")
+	}
+}
+
+func TestLoad_ExtraBotTokens(t *testing.T) {
+	os.Setenv("BOT_TOKEN", "primary")
+	os.Setenv("DB_PASSWORD", "test-pass")
+	os.Setenv("BOT_TOKENS", "second, primary,third,,second")
+	defer func() {
+		os.Unsetenv("BOT_TOKEN")
+		os.Unsetenv("DB_PASSWORD")
+		os.Unsetenv("BOT_TOKENS")
+	}()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []string{"primary", "second", "third"}
+	if got := cfg.Bot.Tokens(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Bot.Tokens() = %v, want %v", got, want)
+	}
+}
+
 func TestDBConfig_DSN(t *testing.T) {
 	cfg := DBConfig{
 		Host:     "localhost",
@@ -211,3 +413,23 @@ func TestDBConfig_DSN(t *testing.T) {
 		t.Errorf("DSN() = %v, want %v", got, expected)
 	}
 }
+
+func TestDBConfig_ReadDSN(t *testing.T) {
+	cfg := DBConfig{
+		Host:     "primary",
+		Port:     3306,
+		User:     "root",
+		Password: "secret",
+		Database: "testdb",
+	}
+
+	if got := cfg.ReadDSN(); got != "" {
+		t.Errorf("ReadDSN() without DB_READ_HOST = %v, want empty", got)
+	}
+
+	cfg.ReadHost = "replica"
+	expected := "root:secret@tcp(replica:3306)/testdb?charset=utf8mb4&parseTime=True&loc=Local"
+	if got := cfg.ReadDSN(); got != expected {
+		t.Errorf("ReadDSN() = %v, want %v", got, expected)
+	}
+}
@@ -3,14 +3,21 @@ package crawler
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
 	"github.com/go-rod/rod/lib/proto"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/logctx"
 )
 
 const (
@@ -18,14 +25,32 @@ const (
 	DefaultWaitTimeout = 15 * time.Second
 	// DefaultPageLoadTimeout is the maximum time to wait for page load
 	DefaultPageLoadTimeout = 30 * time.Second
+	// browserUserAgent is the user agent the browser presents when rendering pages
+	browserUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
 )
 
+var browserRestartsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "missav_bot_browser_restarts_total",
+	Help: "Total number of headless browser restarts by reason",
+}, []string{"reason"})
+
+func init() {
+	prometheus.MustRegister(browserRestartsTotal)
+}
+
 // Browser wraps rod browser for headless browsing with instance reuse
 type Browser struct {
-	browser  *rod.Browser
-	launcher *launcher.Launcher
-	mu       sync.Mutex
-	closed   bool
+	browser     *rod.Browser
+	launcher    *launcher.Launcher
+	relay       *proxyRelay
+	disconnect  context.CancelFunc // set for remote browsers, which must not be shut down
+	screenshots bool
+	mu          sync.Mutex
+	closed      atomic.Bool
+	launchedAt  time.Time
+	pages       atomic.Int64 // pages opened since launch
+	watch       browserWatch
+	stopWatch   chan struct{}
 }
 
 // BrowserConfig holds configuration for the browser
@@ -36,13 +61,49 @@ type BrowserConfig struct {
 	UserAgent string
 	// ProxyURL is the proxy server URL
 	ProxyURL string
+	// ProxyUsername and ProxyPassword authenticate to the proxy when ProxyURL has no credentials
+	ProxyUsername string
+	ProxyPassword string
+	// NavigationDeadline aborts a navigation that runs longer than this (0 disables)
+	NavigationDeadline time.Duration
+	// RemoteURL connects to an external Chrome or browserless instance instead of launching one
+	RemoteURL string
+	// CaptureScreenshots takes a full-page screenshot of every rendered page
+	CaptureScreenshots bool
+	// BinPath is the Chrome binary to launch; empty falls back to CHROME_PATH, then to a download
+	BinPath string
+	// ExtraFlags are added to the launch flags, overriding the defaults of the same name
+	ExtraFlags []BrowserFlag
+	// Devtools opens DevTools for every tab, for debugging with Headless off
+	Devtools bool
+}
+
+// BrowserFlag is a Chrome command line switch, such as --lang=ja-JP
+type BrowserFlag struct {
+	Name  string
+	Value string // empty for boolean switches
+}
+
+// ParseBrowserFlags parses space-separated Chrome switches such as "--lang=ja-JP --start-maximized"
+func ParseBrowserFlags(s string) ([]BrowserFlag, error) {
+	var parsed []BrowserFlag
+	for _, field := range strings.Fields(s) {
+		name, value, _ := strings.Cut(field, "=")
+		name = strings.TrimLeft(name, "-")
+		if name == "" {
+			return nil, fmt.Errorf("invalid browser flag %q", field)
+		}
+		parsed = append(parsed, BrowserFlag{Name: name, Value: value})
+	}
+	return parsed, nil
 }
 
 // DefaultBrowserConfig returns default browser configuration
 func DefaultBrowserConfig() *BrowserConfig {
 	return &BrowserConfig{
-		Headless:  true,
-		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		Headless:           true,
+		UserAgent:          browserUserAgent,
+		NavigationDeadline: DefaultNavigationDeadline,
 	}
 }
 
@@ -56,13 +117,19 @@ func NewBrowserWithConfig(cfg *BrowserConfig) (*Browser, error) {
 	if cfg == nil {
 		cfg = DefaultBrowserConfig()
 	}
+	if cfg.RemoteURL != "" {
+		return connectRemoteBrowser(cfg)
+	}
 
-	// Try to use system Chrome first (set via CHROME_PATH env var)
+	// Try to use system Chrome first (BROWSER_BIN_PATH, or the older CHROME_PATH env var)
 	// This is important for Docker containers where we install chromium via apk
 	var l *launcher.Launcher
 	
 	// Check for system Chrome path
-	chromePath := os.Getenv("CHROME_PATH")
+	chromePath := cfg.BinPath
+	if chromePath == "" {
+		chromePath = os.Getenv("CHROME_PATH")
+	}
 	if chromePath != "" {
 		log.Info().Str("chromePath", chromePath).Msg("Using system Chrome")
 		l = launcher.New().Bin(chromePath)
@@ -85,17 +152,44 @@ func NewBrowserWithConfig(cfg *BrowserConfig) (*Browser, error) {
 		Set("mute-audio").
 		Set("no-first-run").
 		Set("safebrowsing-disable-auto-update").
-		Set("disable-setuid-sandbox")
+		Set("disable-setuid-sandbox").
+		Devtools(cfg.Devtools)
+	for _, flag := range cfg.ExtraFlags {
+		if flag.Value == "" {
+			l = l.Set(flags.Flag(flag.Name))
+		} else {
+			l = l.Set(flags.Flag(flag.Name), flag.Value)
+		}
+	}
+	if !cfg.Headless {
+		log.Warn().Msg("Launching browser with a visible window, which needs a display")
+	}
 
 	// Configure proxy if provided
+	var relay *proxyRelay
 	if cfg.ProxyURL != "" {
-		l = l.Proxy(cfg.ProxyURL)
+		proxyURL, err := parseProxyURL(cfg.ProxyURL, cfg.ProxyUsername, cfg.ProxyPassword)
+		if err != nil {
+			return nil, err
+		}
+		if proxyURL.User != nil {
+			// Chrome can't send proxy credentials itself, so go through a local relay that does
+			relay, err = startProxyRelay(proxyURL)
+			if err != nil {
+				return nil, err
+			}
+			l = l.Proxy(relay.URL())
+			log.Info().Str("proxy", proxyURL.Redacted()).Str("relay", relay.URL()).Msg("Browser using authenticated proxy via local relay")
+		} else {
+			l = l.Proxy(proxyURL.String())
+		}
 	}
 
 	// Launch browser
 	log.Info().Msg("Launching browser...")
 	controlURL, err := l.Launch()
 	if err != nil {
+		closeRelay(relay)
 		return nil, fmt.Errorf("failed to launch browser: %w", err)
 	}
 	log.Info().Str("controlURL", controlURL).Msg("Browser launched")
@@ -103,62 +197,156 @@ func NewBrowserWithConfig(cfg *BrowserConfig) (*Browser, error) {
 	// Connect to browser
 	browser := rod.New().ControlURL(controlURL)
 	if err := browser.Connect(); err != nil {
+		l.Cleanup()
+		closeRelay(relay)
 		return nil, fmt.Errorf("failed to connect to browser: %w", err)
 	}
 
 	log.Info().Msg("Browser connected successfully")
 
-	return &Browser{
-		browser:  browser,
-		launcher: l,
-		closed:   false,
-	}, nil
+	b := &Browser{
+		browser:     browser,
+		launcher:    l,
+		relay:       relay,
+		screenshots: cfg.CaptureScreenshots,
+		launchedAt:  time.Now(),
+		stopWatch:   make(chan struct{}),
+	}
+	go b.runWatchdog(cfg.NavigationDeadline, b.stopWatch)
+
+	return b, nil
+}
+
+// connectRemoteBrowser connects to an externally managed Chrome (e.g. browserless)
+// Launch flags and the proxy are the remote instance's business; closing the
+// Browser only drops the connection.
+func connectRemoteBrowser(cfg *BrowserConfig) (*Browser, error) {
+	controlURL := cfg.RemoteURL
+	if !strings.HasPrefix(controlURL, "ws://") && !strings.HasPrefix(controlURL, "wss://") {
+		// An http://host:9222 endpoint has to be resolved to its DevTools websocket
+		resolved, err := launcher.ResolveURL(controlURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve remote browser URL: %w", err)
+		}
+		controlURL = resolved
+	}
+	if cfg.ProxyURL != "" {
+		log.Warn().Msg("Proxy settings are not applied to a remote browser, configure them on the remote instance")
+	}
+	if cfg.BinPath != "" || len(cfg.ExtraFlags) > 0 || !cfg.Headless || cfg.Devtools {
+		log.Warn().Msg("Browser launch settings are not applied to a remote browser")
+	}
+
+	ctx, disconnect := context.WithCancel(context.Background())
+	browser := rod.New().Context(ctx).ControlURL(controlURL)
+	if err := browser.Connect(); err != nil {
+		disconnect()
+		return nil, fmt.Errorf("failed to connect to remote browser: %w", err)
+	}
+	log.Info().Str("remote", remoteHost(cfg.RemoteURL)).Msg("Connected to remote browser")
+
+	b := &Browser{
+		browser:     browser,
+		disconnect:  disconnect,
+		screenshots: cfg.CaptureScreenshots,
+		launchedAt:  time.Now(),
+		stopWatch:   make(chan struct{}),
+	}
+	go b.runWatchdog(cfg.NavigationDeadline, b.stopWatch)
+
+	return b, nil
+}
+
+// remoteHost returns the host of a remote browser URL for logging, leaving out tokens in the query
+func remoteHost(remoteURL string) string {
+	u, err := url.Parse(remoteURL)
+	if err != nil || u.Host == "" {
+		return "(invalid)"
+	}
+	return u.Host
+}
+
+// closeRelay stops the proxy relay if one was started
+func closeRelay(relay *proxyRelay) {
+	if relay != nil {
+		relay.Close()
+	}
+}
+
+// RenderedPage is the result of rendering a page in the browser
+type RenderedPage struct {
+	HTML string
+	// Cookies holds the cookies the browser has for the page, including
+	// the Cloudflare clearance once the challenge has been passed
+	Cookies []*http.Cookie
+	// UserAgent is the user agent the page was rendered with
+	UserAgent string
+	// SelectorFound is false when none of the content selectors appeared in time
+	SelectorFound bool
+	// Screenshot is a full-page PNG, captured when the browser is configured to
+	Screenshot []byte
 }
 
 // FetchRenderedHTML fetches a page and waits for JavaScript rendering
 // waitSelector is the CSS selector to wait for (max 15 seconds)
 func (b *Browser) FetchRenderedHTML(ctx context.Context, url string, waitSelector string) (string, error) {
+	rendered, err := b.FetchRenderedPage(ctx, url, waitSelector)
+	if err != nil {
+		return "", err
+	}
+	return rendered.HTML, nil
+}
+
+// FetchRenderedPage fetches a page like FetchRenderedHTML and also captures its cookies
+func (b *Browser) FetchRenderedPage(ctx context.Context, url string, waitSelector string) (*RenderedPage, error) {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if b.closed {
-		return "", fmt.Errorf("browser is closed")
+	if b.closed.Load() {
+		return nil, fmt.Errorf("browser is closed")
 	}
 
 	// Create a new page
 	page, err := b.browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
 	if err != nil {
-		return "", fmt.Errorf("failed to create page: %w", err)
+		return nil, fmt.Errorf("failed to create page: %w", err)
 	}
 	defer page.Close()
+	b.pages.Add(1)
 
-	// Set page timeout (longer for Cloudflare challenge) - use independent timeout, not ctx
-	page = page.Timeout(90 * time.Second)
+	// Set page timeout (longer for Cloudflare challenge) - use independent timeout, not ctx's deadline
+	// The watchdog cancels navCtx if the whole navigation overruns its hard deadline,
+	// and cancelling ctx cancels it too.
+	navCtx, endNavigation := b.beginNavigation(ctx, url)
+	defer endNavigation()
+	page = page.Context(navCtx).Timeout(90 * time.Second)
 
 	// Set user agent
 	err = page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
-		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+		UserAgent: browserUserAgent,
 	})
 	if err != nil {
 		// Non-fatal error, continue
 	}
 
-	log.Info().Str("url", url).Msg("Browser navigating to URL")
+	logctx.From(ctx).Info().Str("url", url).Msg("Browser navigating to URL")
 
 	// Navigate to URL
 	if err := page.Navigate(url); err != nil {
-		return "", fmt.Errorf("failed to navigate to %s: %w", url, err)
+		return nil, fmt.Errorf("failed to navigate to %s: %w", url, err)
 	}
 
 	// Wait for page to load
 	if err := page.WaitLoad(); err != nil {
-		return "", fmt.Errorf("failed to wait for page load: %w", err)
+		return nil, fmt.Errorf("failed to wait for page load: %w", err)
 	}
 
-	log.Info().Msg("Page loaded, waiting for Cloudflare challenge...")
+	logctx.From(ctx).Info().Msg("Page loaded, waiting for Cloudflare challenge...")
 
 	// Wait longer for Cloudflare challenge to complete (8 seconds)
-	time.Sleep(8 * time.Second)
+	if err := sleepContext(navCtx, 8*time.Second); err != nil {
+		return nil, fmt.Errorf("navigation to %s aborted: %w", url, err)
+	}
 
 	// Try multiple selectors for video content - use independent timeout
 	selectors := []string{waitSelector, "div.group", "div[class*=thumbnail]", "article", "main"}
@@ -172,29 +360,53 @@ func (b *Browser) FetchRenderedHTML(ctx context.Context, url string, waitSelecto
 		pageWithTimeout := page.Timeout(10 * time.Second)
 		elem, err := pageWithTimeout.Element(selector)
 		if err == nil && elem != nil {
-			log.Info().Str("selector", selector).Msg("Found target element")
+			logctx.From(ctx).Info().Str("selector", selector).Msg("Found target element")
 			found = true
 			break
 		}
 	}
 
 	if !found {
-		log.Warn().Msg("No video selectors found, continuing anyway")
+		logctx.From(ctx).Warn().Msg("No video selectors found, continuing anyway")
 	}
 
 	// Additional wait for dynamic content
-	time.Sleep(3 * time.Second)
+	if err := sleepContext(navCtx, 3*time.Second); err != nil {
+		return nil, fmt.Errorf("navigation to %s aborted: %w", url, err)
+	}
 
 	// Get rendered HTML - use a fresh timeout
 	pageWithTimeout := page.Timeout(30 * time.Second)
 	html, err := pageWithTimeout.HTML()
 	if err != nil {
-		return "", fmt.Errorf("failed to get HTML: %w", err)
+		return nil, fmt.Errorf("failed to get HTML: %w", err)
 	}
 
-	log.Info().Int("htmlLength", len(html)).Msg("Browser got HTML")
+	logctx.From(ctx).Info().Int("htmlLength", len(html)).Msg("Browser got HTML")
 
-	return html, nil
+	// Capture cookies so the HTTP client can reuse the Cloudflare clearance
+	cookies, err := page.Cookies([]string{url})
+	if err != nil {
+		logctx.From(ctx).Warn().Err(err).Msg("Failed to read browser cookies")
+	}
+
+	var screenshot []byte
+	if b.screenshots {
+		screenshot, err = page.Timeout(15*time.Second).Screenshot(true, &proto.PageCaptureScreenshot{
+			Format: proto.PageCaptureScreenshotFormatPng,
+		})
+		if err != nil {
+			logctx.From(ctx).Warn().Err(err).Msg("Failed to capture screenshot")
+		}
+	}
+
+	return &RenderedPage{
+		HTML:          html,
+		Cookies:       convertBrowserCookies(cookies),
+		UserAgent:     browserUserAgent,
+		SelectorFound: found,
+		Screenshot:    screenshot,
+	}, nil
 }
 
 // FetchRenderedHTMLWithWait fetches a page with custom wait time
@@ -202,7 +414,7 @@ func (b *Browser) FetchRenderedHTMLWithWait(ctx context.Context, url string, wai
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if b.closed {
+	if b.closed.Load() {
 		return "", fmt.Errorf("browser is closed")
 	}
 
@@ -212,9 +424,12 @@ func (b *Browser) FetchRenderedHTMLWithWait(ctx context.Context, url string, wai
 		return "", fmt.Errorf("failed to create page: %w", err)
 	}
 	defer page.Close()
+	b.pages.Add(1)
 
 	// Set page timeout
-	page = page.Timeout(DefaultPageLoadTimeout)
+	navCtx, endNavigation := b.beginNavigation(ctx, url)
+	defer endNavigation()
+	page = page.Context(navCtx).Timeout(DefaultPageLoadTimeout)
 
 	// Navigate to URL
 	if err := page.Navigate(url); err != nil {
@@ -252,7 +467,7 @@ func (b *Browser) ExecuteScript(ctx context.Context, url string, script string)
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if b.closed {
+	if b.closed.Load() {
 		return nil, fmt.Errorf("browser is closed")
 	}
 
@@ -262,9 +477,12 @@ func (b *Browser) ExecuteScript(ctx context.Context, url string, script string)
 		return nil, fmt.Errorf("failed to create page: %w", err)
 	}
 	defer page.Close()
+	b.pages.Add(1)
 
 	// Set page timeout
-	page = page.Timeout(DefaultPageLoadTimeout)
+	navCtx, endNavigation := b.beginNavigation(ctx, url)
+	defer endNavigation()
+	page = page.Context(navCtx).Timeout(DefaultPageLoadTimeout)
 
 	// Navigate to URL
 	if err := page.Navigate(url); err != nil {
@@ -290,16 +508,21 @@ func (b *Browser) Close() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if b.closed {
+	if b.closed.Load() {
 		return nil
 	}
 
-	b.closed = true
+	b.closed.Store(true)
+	if b.stopWatch != nil {
+		close(b.stopWatch)
+	}
 
 	var errs []error
 
-	// Close browser
-	if b.browser != nil {
+	// Close browser; a remote browser is shared, so only disconnect from it
+	if b.disconnect != nil {
+		b.disconnect()
+	} else if b.browser != nil {
 		if err := b.browser.Close(); err != nil {
 			errs = append(errs, fmt.Errorf("failed to close browser: %w", err))
 		}
@@ -310,6 +533,13 @@ func (b *Browser) Close() error {
 		b.launcher.Cleanup()
 	}
 
+	// Stop the proxy relay once Chrome no longer needs it
+	if b.relay != nil {
+		if err := b.relay.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close proxy relay: %w", err))
+		}
+	}
+
 	if len(errs) > 0 {
 		return errs[0]
 	}
@@ -319,9 +549,26 @@ func (b *Browser) Close() error {
 
 // IsClosed returns whether the browser has been closed
 func (b *Browser) IsClosed() bool {
-	b.mu.Lock()
-	defer b.mu.Unlock()
-	return b.closed
+	return b.closed.Load()
+}
+
+// PagesServed returns the number of pages opened since the browser was launched
+func (b *Browser) PagesServed() int {
+	return int(b.pages.Load())
+}
+
+// Age returns how long ago the browser was launched
+func (b *Browser) Age() time.Duration {
+	return time.Since(b.launchedAt)
+}
+
+// Alive reports whether the browser process still answers over the DevTools connection
+func (b *Browser) Alive() bool {
+	if b.IsClosed() {
+		return false
+	}
+	_, err := b.browser.Timeout(watchdogPingTimeout).Version()
+	return err == nil
 }
 
 // Reconnect attempts to reconnect to the browser if connection was lost
@@ -329,7 +576,7 @@ func (b *Browser) Reconnect() error {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 
-	if b.closed {
+	if b.closed.Load() {
 		return fmt.Errorf("browser is closed, cannot reconnect")
 	}
 
@@ -0,0 +1,144 @@
+package crawler
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newTestBrowser returns a Browser that was launched age ago and has served pages
+// No Chrome process is started.
+func newTestBrowser(age time.Duration, pages int) *Browser {
+	b := &Browser{launchedAt: time.Now().Add(-age)}
+	b.pages.Store(int64(pages))
+	return b
+}
+
+func TestBrowserRetireReason(t *testing.T) {
+	cfg := DefaultCrawlerConfig()
+	cfg.BrowserMaxPages = 10
+	cfg.BrowserMaxAge = time.Hour
+	c, err := NewHTTPCrawler(cfg)
+	if err != nil {
+		t.Fatalf("NewHTTPCrawler() error = %v", err)
+	}
+
+	closed := newTestBrowser(0, 0)
+	closed.closed.Store(true)
+
+	tests := []struct {
+		name    string
+		browser *Browser
+		want    string
+	}{
+		{"fresh", newTestBrowser(0, 3), ""},
+		{"too many pages", newTestBrowser(0, 10), "max_pages"},
+		{"too old", newTestBrowser(2*time.Hour, 0), "max_age"},
+		{"closed", closed, "closed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.browserRetireReason(tt.browser); got != tt.want {
+				t.Errorf("browserRetireReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Zero limits never retire a running browser
+	c.config.BrowserMaxPages = 0
+	c.config.BrowserMaxAge = 0
+	if got := c.browserRetireReason(newTestBrowser(48*time.Hour, 1000)); got != "" {
+		t.Errorf("browserRetireReason() without limits = %q, want \"\"", got)
+	}
+}
+
+func TestBrowser_KillOverdueNavigation(t *testing.T) {
+	b := newTestBrowser(0, 0)
+	navCtx, end := b.beginNavigation(context.Background(), BaseURL+"/new")
+	defer end()
+
+	if health := b.Health(); health.Navigating != BaseURL+"/new" {
+		t.Errorf("Health().Navigating = %q", health.Navigating)
+	}
+
+	b.killOverdueNavigation(time.Hour)
+	if navCtx.Err() != nil {
+		t.Fatal("navigation within deadline was aborted")
+	}
+
+	b.watch.mu.Lock()
+	b.watch.navStart = time.Now().Add(-2 * time.Hour)
+	b.watch.mu.Unlock()
+	b.killOverdueNavigation(time.Hour)
+	if navCtx.Err() == nil {
+		t.Error("overdue navigation was not aborted")
+	}
+}
+
+func TestBrowser_NavigationFollowsCancellation(t *testing.T) {
+	b := newTestBrowser(0, 0)
+
+	// A caller's deadline does not cut the page's own timeouts short
+	parent, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	navCtx, end := b.beginNavigation(parent, BaseURL+"/new")
+	<-parent.Done()
+	time.Sleep(10 * time.Millisecond)
+	if navCtx.Err() != nil {
+		t.Error("navigation aborted when the caller's deadline passed")
+	}
+	end()
+
+	// Cancelling the caller, e.g. on shutdown, aborts the navigation
+	parent, cancel = context.WithCancel(context.Background())
+	navCtx, end = b.beginNavigation(parent, BaseURL+"/new")
+	defer end()
+	cancel()
+	select {
+	case <-navCtx.Done():
+	case <-time.After(time.Second):
+		t.Error("navigation not aborted when the caller was cancelled")
+	}
+}
+
+func TestRemoteHost(t *testing.T) {
+	tests := map[string]string{
+		"ws://browserless:3000?token=secret": "browserless:3000",
+		"http://chrome:9222":                 "chrome:9222",
+		"9222":                               "(invalid)",
+	}
+	for remote, want := range tests {
+		if got := remoteHost(remote); got != want {
+			t.Errorf("remoteHost(%q) = %q, want %q", remote, got, want)
+		}
+	}
+}
+
+func TestNewBrowserWithConfig_UnreachableRemote(t *testing.T) {
+	cfg := DefaultBrowserConfig()
+	cfg.RemoteURL = "http://127.0.0.1:1"
+	if _, err := NewBrowserWithConfig(cfg); err == nil {
+		t.Error("expected error connecting to an unreachable remote browser")
+	}
+}
+
+func TestParseBrowserFlags(t *testing.T) {
+	got, err := ParseBrowserFlags(" --lang=ja-JP  --window-size=1366,768 start-maximized ")
+	if err != nil {
+		t.Fatalf("ParseBrowserFlags() error = %v", err)
+	}
+	want := []BrowserFlag{
+		{Name: "lang", Value: "ja-JP"},
+		{Name: "window-size", Value: "1366,768"},
+		{Name: "start-maximized"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseBrowserFlags() = %v, want %v", got, want)
+	}
+
+	if _, err := ParseBrowserFlags("--=x"); err == nil {
+		t.Error("expected error for a flag without a name")
+	}
+}
@@ -0,0 +1,174 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// DefaultNavigationDeadline is the hard limit for a single browser navigation,
+	// including the Cloudflare challenge wait
+	DefaultNavigationDeadline = 2 * time.Minute
+	// watchdogInterval is how often the watchdog pings the browser and checks navigations
+	watchdogInterval = 15 * time.Second
+	// watchdogPingTimeout bounds a single health ping
+	watchdogPingTimeout = 5 * time.Second
+)
+
+var (
+	browserUp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "missav_bot_browser_up",
+		Help: "Whether the headless browser answered its last health ping (1) or not (0)",
+	})
+
+	browserNavigationsKilledTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "missav_bot_browser_navigations_killed_total",
+		Help: "Total number of browser navigations aborted for exceeding the hard deadline",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(browserUp)
+	prometheus.MustRegister(browserNavigationsKilledTotal)
+}
+
+// BrowserHealth describes the state of the headless browser
+type BrowserHealth struct {
+	// Running is false when no browser has been launched yet
+	Running     bool          `json:"running"`
+	Remote      bool          `json:"remote"`
+	Healthy     bool          `json:"healthy"`
+	LastPing    time.Time     `json:"lastPing,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	PagesServed int           `json:"pagesServed"`
+	Age         time.Duration `json:"age"`
+	// Navigating is the URL currently being rendered, if any
+	Navigating string `json:"navigating,omitempty"`
+}
+
+// BrowserHealthReporter is implemented by crawlers that use a headless browser
+type BrowserHealthReporter interface {
+	BrowserHealth() BrowserHealth
+}
+
+// browserWatch holds the watchdog's view of a browser, separate from Browser.mu,
+// which stays locked for the whole of a navigation
+type browserWatch struct {
+	mu        sync.Mutex
+	healthy   bool
+	lastPing  time.Time
+	pingErr   string
+	navURL    string
+	navStart  time.Time
+	navCancel context.CancelFunc
+}
+
+// beginNavigation registers a navigation to url and returns the context its page
+// operations must use; the watchdog cancels it once the deadline passes
+// The navigation is also aborted when parent is cancelled, but not when parent's
+// deadline passes: pages keep their own timeouts, longer than most callers'.
+func (b *Browser) beginNavigation(parent context.Context, url string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := context.AfterFunc(parent, func() {
+		if errors.Is(parent.Err(), context.Canceled) {
+			cancel()
+		}
+	})
+
+	b.watch.mu.Lock()
+	b.watch.navURL = url
+	b.watch.navStart = time.Now()
+	b.watch.navCancel = cancel
+	b.watch.mu.Unlock()
+
+	return ctx, func() {
+		stop()
+		cancel()
+		b.watch.mu.Lock()
+		b.watch.navURL = ""
+		b.watch.navCancel = nil
+		b.watch.mu.Unlock()
+	}
+}
+
+// runWatchdog pings the browser and aborts overdue navigations until stop is closed
+func (b *Browser) runWatchdog(navDeadline time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+
+	b.ping()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			b.ping()
+			b.killOverdueNavigation(navDeadline)
+		}
+	}
+}
+
+// ping checks the DevTools connection without waiting for a running navigation
+func (b *Browser) ping() {
+	_, err := b.browser.Timeout(watchdogPingTimeout).Version()
+
+	b.watch.mu.Lock()
+	defer b.watch.mu.Unlock()
+
+	b.watch.lastPing = time.Now()
+	b.watch.healthy = err == nil
+	b.watch.pingErr = ""
+	if err != nil {
+		b.watch.pingErr = err.Error()
+		log.Warn().Err(err).Msg("Browser health ping failed")
+		browserUp.Set(0)
+		return
+	}
+	browserUp.Set(1)
+}
+
+// killOverdueNavigation cancels the running navigation if it has exceeded navDeadline
+// A hung Cloudflare challenge would otherwise hold the browser mutex indefinitely.
+func (b *Browser) killOverdueNavigation(navDeadline time.Duration) {
+	if navDeadline <= 0 {
+		return
+	}
+
+	b.watch.mu.Lock()
+	defer b.watch.mu.Unlock()
+
+	if b.watch.navCancel == nil || time.Since(b.watch.navStart) < navDeadline {
+		return
+	}
+	log.Warn().
+		Str("url", b.watch.navURL).
+		Dur("elapsed", time.Since(b.watch.navStart)).
+		Msg("Browser navigation exceeded deadline, aborting")
+	b.watch.navCancel()
+	b.watch.navCancel = nil
+	browserNavigationsKilledTotal.Inc()
+}
+
+// Health returns the browser's state as last observed by the watchdog
+func (b *Browser) Health() BrowserHealth {
+	health := BrowserHealth{
+		Running: !b.IsClosed(),
+		Remote:  b.disconnect != nil,
+		Age:     b.Age(),
+	}
+	health.PagesServed = b.PagesServed()
+
+	b.watch.mu.Lock()
+	defer b.watch.mu.Unlock()
+
+	health.Healthy = health.Running && b.watch.healthy
+	health.LastPing = b.watch.lastPing
+	health.Error = b.watch.pingErr
+	health.Navigating = b.watch.navURL
+	return health
+}
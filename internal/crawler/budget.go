@@ -0,0 +1,113 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrBudgetExhausted is returned when the daily request budget has been used up
+var ErrBudgetExhausted = errors.New("daily crawl budget exhausted")
+
+var (
+	crawlBudgetUsed = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "missav_bot_crawl_budget_used",
+		Help: "Number of requests sent to the site today",
+	})
+
+	crawlBudgetRejectedTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "missav_bot_crawl_budget_rejected_total",
+		Help: "Total number of fetches refused because the daily budget was exhausted",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(crawlBudgetUsed)
+	prometheus.MustRegister(crawlBudgetRejectedTotal)
+}
+
+// BudgetReporter is implemented by crawlers that enforce a daily request budget
+type BudgetReporter interface {
+	// BudgetUsage returns the requests spent today and the daily limit (0 when unlimited)
+	BudgetUsage() (used, limit int)
+}
+
+// budgetExemptKey marks contexts whose fetches may exceed the daily budget
+type budgetExemptKey struct{}
+
+// WithoutBudget returns a context whose crawls are allowed past the daily budget
+// Used for crawls requested by bot admins.
+func WithoutBudget(ctx context.Context) context.Context {
+	return context.WithValue(ctx, budgetExemptKey{}, true)
+}
+
+// isBudgetExempt reports whether ctx was created by WithoutBudget
+func isBudgetExempt(ctx context.Context) bool {
+	exempt, _ := ctx.Value(budgetExemptKey{}).(bool)
+	return exempt
+}
+
+// Budget counts requests sent to the site per calendar day, shared by HTTP and browser fetches
+// A zero limit counts requests without enforcing a cap.
+type Budget struct {
+	limit int
+	now   func() time.Time
+
+	mu   sync.Mutex
+	day  string
+	used int
+}
+
+// NewBudget creates a budget allowing limit requests per day (0 for unlimited)
+func NewBudget(limit int) *Budget {
+	return &Budget{limit: limit, now: time.Now}
+}
+
+// Spend records one request, failing with ErrBudgetExhausted once today's limit
+// is reached unless ctx is exempt
+func (b *Budget) Spend(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	if b.limit > 0 && b.used >= b.limit && !isBudgetExempt(ctx) {
+		crawlBudgetRejectedTotal.Inc()
+		return ErrBudgetExhausted
+	}
+	b.used++
+	crawlBudgetUsed.Set(float64(b.used))
+	return nil
+}
+
+// Usage returns the requests spent today and the daily limit
+func (b *Budget) Usage() (used, limit int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.rollover()
+	return b.used, b.limit
+}
+
+// rollover resets the count when the day has changed; callers hold b.mu
+func (b *Budget) rollover() {
+	today := b.now().Format("2006-01-02")
+	if today != b.day {
+		b.day = today
+		b.used = 0
+		crawlBudgetUsed.Set(0)
+	}
+}
+
+// BudgetExhausted reports whether a crawler's daily budget has been used up
+// Crawlers without a budget are never exhausted.
+func BudgetExhausted(c Crawler) bool {
+	reporter, ok := c.(BudgetReporter)
+	if !ok {
+		return false
+	}
+	used, limit := reporter.BudgetUsage()
+	return limit > 0 && used >= limit
+}
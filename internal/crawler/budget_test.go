@@ -0,0 +1,71 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBudget_Spend(t *testing.T) {
+	now := time.Date(2024, 1, 1, 23, 0, 0, 0, time.UTC)
+	b := NewBudget(2)
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := b.Spend(ctx); err != nil {
+			t.Fatalf("Spend() #%d error = %v", i+1, err)
+		}
+	}
+	if err := b.Spend(ctx); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("Spend() over budget error = %v, want ErrBudgetExhausted", err)
+	}
+
+	// Admin crawls are still allowed and counted
+	if err := b.Spend(WithoutBudget(ctx)); err != nil {
+		t.Errorf("exempt Spend() error = %v", err)
+	}
+	if used, limit := b.Usage(); used != 3 || limit != 2 {
+		t.Errorf("Usage() = %d/%d, want 3/2", used, limit)
+	}
+
+	// The budget resets on the next day
+	now = now.Add(2 * time.Hour)
+	if err := b.Spend(ctx); err != nil {
+		t.Errorf("Spend() next day error = %v", err)
+	}
+	if used, _ := b.Usage(); used != 1 {
+		t.Errorf("used after rollover = %d, want 1", used)
+	}
+}
+
+func TestBudget_Unlimited(t *testing.T) {
+	b := NewBudget(0)
+	for i := 0; i < 100; i++ {
+		if err := b.Spend(context.Background()); err != nil {
+			t.Fatalf("Spend() error = %v", err)
+		}
+	}
+}
+
+func TestBudgetExhausted(t *testing.T) {
+	cfg := DefaultCrawlerConfig()
+	cfg.DailyBudget = 1
+	c, err := NewHTTPCrawler(cfg)
+	if err != nil {
+		t.Fatalf("NewHTTPCrawler() error = %v", err)
+	}
+	if BudgetExhausted(c) {
+		t.Fatal("fresh budget reported as exhausted")
+	}
+	c.budget.Spend(context.Background())
+	if !BudgetExhausted(c) {
+		t.Error("spent budget not reported as exhausted")
+	}
+
+	// The crawler refuses to fetch once the budget is used up
+	if _, err := c.fetchWithRetry(context.Background(), BaseURL); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("fetchWithRetry() error = %v, want ErrBudgetExhausted", err)
+	}
+}
@@ -0,0 +1,56 @@
+package crawler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ErrChallenge is returned when the site answers with a Cloudflare challenge instead of content
+// Retrying over plain HTTP won't help; the page has to be fetched with the browser.
+var ErrChallenge = errors.New("cloudflare challenge page")
+
+var crawlChallengesTotal = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "missav_bot_crawl_challenges_total",
+	Help: "Total number of fetched pages that were Cloudflare challenge pages",
+})
+
+func init() {
+	prometheus.MustRegister(crawlChallengesTotal)
+}
+
+// challengeMarkers are fragments only found in Cloudflare challenge and interstitial pages
+// The /cdn-cgi/challenge-platform/ script is deliberately absent: Cloudflare injects it into normal pages too.
+var challengeMarkers = []string{
+	"<title>just a moment...</title>",
+	"<title>just a moment…</title>",
+	"<title>attention required! | cloudflare</title>",
+	"window._cf_chl_opt",
+	"id=\"challenge-error-text\"",
+}
+
+// isChallengeResponse reports whether a response is a Cloudflare challenge
+func isChallengeResponse(header http.Header, body string) bool {
+	if header.Get("Cf-Mitigated") == "challenge" {
+		return true
+	}
+	return isChallengePage(body)
+}
+
+// isChallengePage reports whether html is a Cloudflare challenge page
+func isChallengePage(html string) bool {
+	// Challenge markers appear in the document head; don't lowercase whole listing pages
+	head := html
+	if len(head) > 16*1024 {
+		head = head[:16*1024]
+	}
+	head = strings.ToLower(head)
+	for _, marker := range challengeMarkers {
+		if strings.Contains(head, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestIsChallengePage(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want bool
+	}{
+		{"just a moment title", "<html><head><title>Just a moment...</title></head></html>", true},
+		{"ellipsis title", "<title>Just a moment…</title>", true},
+		{"challenge options", "<script>window._cf_chl_opt={cvId: '3'}</script>", true},
+		{"listing page", `<html><head><title>MissAV</title></head><body><div class="group"></div></body></html>`, false},
+		{"injected platform script", `<title>MissAV</title><script src="/cdn-cgi/challenge-platform/scripts/jsd/main.js"></script>`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isChallengePage(tt.html); got != tt.want {
+				t.Errorf("isChallengePage() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetchWithRetry_ChallengeStopsRetrying(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Cf-Mitigated", "challenge")
+		w.WriteHeader(http.StatusForbidden)
+		io.WriteString(w, "<title>Just a moment...</title>")
+	}))
+	defer server.Close()
+
+	cfg := DefaultCrawlerConfig()
+	cfg.RateLimit = 100
+	c, err := NewHTTPCrawler(cfg)
+	if err != nil {
+		t.Fatalf("NewHTTPCrawler() error = %v", err)
+	}
+
+	_, err = c.fetchWithRetry(context.Background(), server.URL)
+	if !errors.Is(err, ErrChallenge) {
+		t.Errorf("fetchWithRetry() error = %v, want ErrChallenge", err)
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("server received %d requests, want 1", n)
+	}
+}
@@ -2,31 +2,42 @@ package crawler
 
 import (
 	"context"
+	"time"
 
 	"github.com/user/missav-bot-go/internal/model"
 )
 
 // Crawler defines the interface for crawling video data
+// Crawl methods return a non-nil CrawlResult, even alongside an error.
 type Crawler interface {
 	// CrawlNewVideos crawls the latest video list
-	CrawlNewVideos(ctx context.Context, pages int) ([]*model.Video, error)
+	CrawlNewVideos(ctx context.Context, pages int) (*CrawlResult, error)
 
 	// CrawlVideoDetail crawls video details from a detail URL
 	CrawlVideoDetail(ctx context.Context, detailURL string) (*model.Video, error)
 
 	// CrawlByActor crawls videos by actor name
-	CrawlByActor(ctx context.Context, actorName string, limit int) ([]*model.Video, error)
+	CrawlByActor(ctx context.Context, actorName string, limit int) (*CrawlResult, error)
 
 	// CrawlByCode crawls a video by its code
-	CrawlByCode(ctx context.Context, code string) (*model.Video, error)
+	CrawlByCode(ctx context.Context, code string) (*CrawlResult, error)
 
 	// CrawlByKeyword searches and crawls videos by keyword
-	CrawlByKeyword(ctx context.Context, keyword string, limit int) ([]*model.Video, error)
+	CrawlByKeyword(ctx context.Context, keyword string, limit int) (*CrawlResult, error)
+
+	// CrawlByTag crawls videos listed under a tag (genre)
+	CrawlByTag(ctx context.Context, tag string, limit int) (*CrawlResult, error)
 
 	// Close releases crawler resources
 	Close() error
 }
 
+// SessionWarmer is implemented by crawlers whose site session is kept warm by cookies
+type SessionWarmer interface {
+	// WarmSession refreshes the session when it is missing or expires within the given duration
+	WarmSession(ctx context.Context, within time.Duration)
+}
+
 // CrawlerConfig holds configuration for the crawler
 type CrawlerConfig struct {
 	// Enabled indicates if crawling is enabled
@@ -43,8 +54,40 @@ type CrawlerConfig struct {
 	UserAgent string
 	// ProxyURL is the proxy server URL (HTTP or SOCKS5)
 	ProxyURL string
+	// ProxyUsername and ProxyPassword authenticate to the proxy when ProxyURL has no credentials
+	ProxyUsername string
+	ProxyPassword string
 	// InitialPages is the number of pages to crawl initially
 	InitialPages int
+	// ImpersonateTLS makes HTTP requests with Chrome's TLS and HTTP/2 fingerprint
+	ImpersonateTLS bool
+	// DetailCacheTTL is how long fetched detail pages are cached (0 disables)
+	DetailCacheTTL time.Duration
+	// DetailCacheDir stores cached detail pages on disk instead of in memory
+	DetailCacheDir string
+	// DailyBudget caps requests sent to the site per day (0 for unlimited)
+	DailyBudget int
+	// BrowserMaxPages relaunches the headless browser after it has opened this many pages (0 for no limit)
+	BrowserMaxPages int
+	// BrowserMaxAge relaunches the headless browser once it has run this long (0 for no limit)
+	BrowserMaxAge time.Duration
+	// BrowserNavigationDeadline is the hard limit for one browser navigation (0 uses DefaultNavigationDeadline)
+	BrowserNavigationDeadline time.Duration
+	// BrowserRemoteURL connects to an external Chrome/browserless endpoint instead of launching Chrome locally
+	BrowserRemoteURL string
+	// BrowserBinPath is the local Chrome binary to launch (empty uses CHROME_PATH or downloads one)
+	BrowserBinPath string
+	// BrowserExtraFlags are added to the local Chrome's launch flags
+	BrowserExtraFlags []BrowserFlag
+	// BrowserHeadful shows the local Chrome's window and BrowserDevtools opens DevTools, for debugging
+	BrowserHeadful  bool
+	BrowserDevtools bool
+	// SnapshotDir stores the HTML and a screenshot of failed browser renders (empty disables)
+	SnapshotDir string
+	// SnapshotKeep is the number of snapshots retained in SnapshotDir (0 keeps all)
+	SnapshotKeep int
+	// MergePriority picks the source of each field when several sources find a video
+	MergePriority MergePriority
 }
 
 // DefaultCrawlerConfig returns default crawler configuration
@@ -59,3 +102,15 @@ func DefaultCrawlerConfig() *CrawlerConfig {
 		InitialPages: 2,
 	}
 }
+
+// sleepContext sleeps for d or until ctx is done, returning ctx's error in that case
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -2,10 +2,12 @@ package crawler
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
@@ -13,7 +15,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/logctx"
 	"github.com/user/missav-bot-go/internal/model"
 	"golang.org/x/time/rate"
 )
@@ -22,6 +26,7 @@ const (
 	newVideosPath = "/new"
 	actressesPath = "/actresses/"
 	searchPath    = "/search/"
+	genresPath    = "/genres/"
 	// Cookie 有效期 10 分钟
 	cookieExpireDuration = 10 * time.Minute
 )
@@ -36,6 +41,11 @@ type HTTPCrawler struct {
 	browserMu      sync.Mutex
 	cookieInitTime time.Time
 	cookieMu       sync.Mutex
+	session        *browserSession // guarded by cookieMu
+	detailCache    PageCache
+	budget         *Budget
+	rateLimit      rateLimitState
+	snapshots      *snapshotStore // nil unless failed browser renders are kept
 }
 
 // NewHTTPCrawler creates a new HTTP crawler instance
@@ -58,16 +68,36 @@ func NewHTTPCrawler(cfg *CrawlerConfig) (*HTTPCrawler, error) {
 	}
 
 	// Configure proxy if provided
+	var proxyURL *url.URL
 	if cfg.ProxyURL != "" {
-		proxyURL, err := url.Parse(cfg.ProxyURL)
+		proxyURL, err = parseProxyURL(cfg.ProxyURL, cfg.ProxyUsername, cfg.ProxyPassword)
 		if err != nil {
-			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+			return nil, err
+		}
+		if isSOCKSProxy(proxyURL) {
+			// Dial SOCKS5 ourselves so username/password auth goes through the same path as the TLS impersonation transport
+			dial, err := newProxyDialer(proxyURL, &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second})
+			if err != nil {
+				return nil, err
+			}
+			transport.DialContext = dial
+		} else {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+		log.Info().Str("proxy", proxyURL.Redacted()).Msg("Crawler using proxy")
+	}
+
+	// Present Chrome's TLS fingerprint instead of Go's when enabled
+	var roundTripper http.RoundTripper = transport
+	if cfg.ImpersonateTLS {
+		roundTripper, err = newChromeTransport(proxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TLS impersonation transport: %w", err)
 		}
-		transport.Proxy = http.ProxyURL(proxyURL)
 	}
 
 	client := &http.Client{
-		Transport: transport,
+		Transport: roundTripper,
 		Timeout:   time.Duration(cfg.Timeout) * time.Second,
 		Jar:       jar, // Enable cookie handling
 	}
@@ -76,32 +106,65 @@ func NewHTTPCrawler(cfg *CrawlerConfig) (*HTTPCrawler, error) {
 	// rate.Limit is events per second
 	limiter := rate.NewLimiter(rate.Limit(cfg.RateLimit), 1)
 
+	// Cache detail pages so repeated lookups of a title don't refetch the site
+	var detailCache PageCache
+	if cfg.DetailCacheTTL > 0 {
+		detailCache, err = NewPageCache(cfg.DetailCacheTTL, cfg.DetailCacheDir)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Keep failed browser renders around for inspection
+	var snapshots *snapshotStore
+	if cfg.SnapshotDir != "" {
+		snapshots, err = newSnapshotStore(cfg.SnapshotDir, cfg.SnapshotKeep)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &HTTPCrawler{
-		client:  client,
-		limiter: limiter,
-		config:  cfg,
-		parser:  NewParser(),
+		client:      client,
+		limiter:     limiter,
+		config:      cfg,
+		parser:      NewParser(),
+		detailCache: detailCache,
+		budget:      NewBudget(cfg.DailyBudget),
+		snapshots:   snapshots,
 	}, nil
 }
 
+// WarmSession re-establishes the session cookies when they are missing or expire within
+// the given duration, so crawls find a warm session instead of warming it up themselves
+func (c *HTTPCrawler) WarmSession(ctx context.Context, within time.Duration) {
+	c.initCookies(ctx, within)
+}
+
+// cookiesValid reports whether cookies initialized at initTime are still valid after within
+func cookiesValid(initTime time.Time, within time.Duration) bool {
+	return !initTime.IsZero() && time.Since(initTime)+within < cookieExpireDuration
+}
+
 // initCookies initializes cookies by making warmup requests to establish a session
 // This is required because the website needs multiple requests to establish a valid session
-func (c *HTTPCrawler) initCookies(ctx context.Context) {
+// Cookies still valid after within are kept.
+func (c *HTTPCrawler) initCookies(ctx context.Context, within time.Duration) {
 	c.cookieMu.Lock()
 	defer c.cookieMu.Unlock()
 
 	// Check if cookies are still valid
-	if !c.cookieInitTime.IsZero() && time.Since(c.cookieInitTime) < cookieExpireDuration {
-		log.Debug().
+	if cookiesValid(c.cookieInitTime, within) {
+		logctx.From(ctx).Debug().
 			Dur("age", time.Since(c.cookieInitTime)).
 			Msg("Cookies still valid, skipping initialization")
 		return
 	}
 
 	if c.cookieInitTime.IsZero() {
-		log.Info().Msg("Initializing cookies (warming up session)...")
+		logctx.From(ctx).Info().Msg("Initializing cookies (warming up session)...")
 	} else {
-		log.Info().
+		logctx.From(ctx).Info().
 			Time("lastInit", c.cookieInitTime).
 			Msg("Cookies expired, re-initializing...")
 	}
@@ -109,37 +172,46 @@ func (c *HTTPCrawler) initCookies(ctx context.Context) {
 	// Make 3 warmup requests to establish session (like Java version)
 	warmupURL := BaseURL + newVideosPath + "?page=2"
 	for i := 1; i <= 3; i++ {
-		select {
-		case <-ctx.Done():
+		_, err := c.fetch(ctx, warmupURL)
+		if ctx.Err() != nil {
 			return
-		default:
 		}
-
-		_, err := c.fetch(ctx, warmupURL)
 		if err != nil {
-			log.Warn().Err(err).Int("attempt", i).Msg("Cookie warmup request failed")
+			logctx.From(ctx).Warn().Err(err).Int("attempt", i).Msg("Cookie warmup request failed")
 		} else {
-			log.Debug().Int("attempt", i).Msg("Cookie warmup request completed")
+			logctx.From(ctx).Debug().Int("attempt", i).Msg("Cookie warmup request completed")
 		}
 
 		if i < 3 {
-			time.Sleep(1 * time.Second)
+			if err := sleepContext(ctx, 1*time.Second); err != nil {
+				return
+			}
 		}
 	}
 
 	c.cookieInitTime = time.Now()
-	log.Info().Msg("Cookie initialization completed")
+	logctx.From(ctx).Info().Msg("Cookie initialization completed")
+}
+
+// Name identifies the site the HTTP crawler crawls
+func (c *HTTPCrawler) Name() string {
+	return model.SourceMissAV
+}
+
+// Owns reports whether a detail URL is on the crawled site
+func (c *HTTPCrawler) Owns(detailURL string) bool {
+	return ownsHost(detailURL, strings.TrimPrefix(BaseURL, "https://"))
 }
 
 // CrawlNewVideos crawls the latest video list
 // Uses headless browser as primary method due to Cloudflare protection
-func (c *HTTPCrawler) CrawlNewVideos(ctx context.Context, pages int) ([]*model.Video, error) {
-	var allVideos []*model.Video
+func (c *HTTPCrawler) CrawlNewVideos(ctx context.Context, pages int) (*CrawlResult, error) {
+	result := &CrawlResult{}
 
 	for page := 1; page <= pages; page++ {
 		select {
 		case <-ctx.Done():
-			return allVideos, ctx.Err()
+			return result, ctx.Err()
 		default:
 		}
 
@@ -148,147 +220,245 @@ func (c *HTTPCrawler) CrawlNewVideos(ctx context.Context, pages int) ([]*model.V
 			pageURL = fmt.Sprintf("%s?page=%d", pageURL, page)
 		}
 
-		log.Info().Str("url", pageURL).Int("page", page).Msg("Crawling new videos page")
+		logctx.From(ctx).Info().Str("url", pageURL).Int("page", page).Msg("Crawling new videos page")
 
-		// Try headless browser first (bypasses Cloudflare)
-		videos, err := c.crawlWithBrowser(ctx, pageURL)
+		// Browser first, falling back to HTTP (might work if no Cloudflare)
+		videos, err := c.crawlListPage(ctx, pageURL, true, result)
+		if errors.Is(err, ErrBudgetExhausted) {
+			return result, err
+		}
 		if err != nil {
-			log.Warn().Err(err).Str("url", pageURL).Msg("Browser crawl failed, trying HTTP")
-			// Fallback to HTTP (might work if no Cloudflare)
-			html, httpErr := c.fetchWithRetry(ctx, pageURL)
-			if httpErr != nil {
-				log.Warn().Err(httpErr).Msg("HTTP fetch also failed")
-				continue
-			}
-			videos, err = c.parser.ParseVideoList(html)
-			if err != nil {
-				log.Warn().Err(err).Msg("Failed to parse video list")
-				continue
-			}
+			continue
 		}
 
-		log.Info().Int("count", len(videos)).Int("page", page).Msg("Parsed videos")
-		allVideos = append(allVideos, videos...)
+		logctx.From(ctx).Info().Int("count", len(videos)).Int("page", page).Msg("Parsed videos")
+		result.Videos = append(result.Videos, videos...)
 
 		// Add delay between pages
 		if page < pages {
-			time.Sleep(3 * time.Second)
+			if err := sleepContext(ctx, 3*time.Second); err != nil {
+				return result, err
+			}
 		}
 	}
 
-	return allVideos, nil
+	return result, nil
 }
 
 // CrawlVideoDetail crawls video details from a detail URL
 func (c *HTTPCrawler) CrawlVideoDetail(ctx context.Context, detailURL string) (*model.Video, error) {
-	html, err := c.fetchWithRetry(ctx, detailURL)
-	if err != nil {
-		// Fallback to headless browser
-		return c.crawlDetailWithBrowser(ctx, detailURL)
+	return c.crawlDetail(ctx, detailURL, &CrawlResult{})
+}
+
+// crawlDetail crawls a detail page over HTTP, falling back to the headless browser
+// Recently fetched pages are served from the detail cache when it is enabled, unless
+// ctx comes from WithoutCache.
+func (c *HTTPCrawler) crawlDetail(ctx context.Context, detailURL string, result *CrawlResult) (*model.Video, error) {
+	start := time.Now()
+	stat := PageStat{URL: detailURL, Method: FetchHTTP}
+
+	var html string
+	var cached bool
+	if !skipsCache(ctx) {
+		html, cached = c.cachedDetail(detailURL)
+	}
+	var err error
+	if cached {
+		stat.Method = FetchCache
+		result.CacheHits++
+	} else {
+		result.HTTPFetches++
+		html, err = c.fetchWithRetry(ctx, detailURL)
+		if err != nil {
+			// Fallback to headless browser
+			stat.Method = FetchBrowser
+			result.BrowserFetches++
+			html, err = c.fetchWithBrowser(ctx, detailURL, "body")
+		}
+	}
+
+	var video *model.Video
+	if err == nil {
+		result.PagesFetched++
+		video, err = c.parser.ParseVideoDetail(html, detailURL)
+		if err != nil {
+			result.addParseFailure()
+		} else {
+			video.Source = c.Name()
+			stat.Videos = 1
+			if !cached {
+				c.storeDetail(detailURL, html)
+			}
+		}
 	}
 
-	return c.parser.ParseVideoDetail(html, detailURL)
+	stat.Duration = time.Since(start)
+	if err != nil {
+		stat.Error = err.Error()
+	}
+	result.addPage(stat)
+	return video, err
 }
 
 // CrawlByActor crawls videos by actor name
 // Uses headless browser as primary method due to Cloudflare protection
-func (c *HTTPCrawler) CrawlByActor(ctx context.Context, actorName string, limit int) ([]*model.Video, error) {
-	var allVideos []*model.Video
+func (c *HTTPCrawler) CrawlByActor(ctx context.Context, actorName string, limit int) (*CrawlResult, error) {
+	return c.crawlListing(ctx, BaseURL+actressesPath+url.PathEscape(actorName), limit,
+		logctx.From(ctx).With().Str("actor", actorName).Logger())
+}
+
+// CrawlByCode crawls a video by its code
+func (c *HTTPCrawler) CrawlByCode(ctx context.Context, code string) (*CrawlResult, error) {
+	result := &CrawlResult{}
+	detailURL := BaseURL + "/" + strings.ToLower(code)
+	video, err := c.crawlDetail(ctx, detailURL, result)
+	if video != nil {
+		result.Videos = []*model.Video{video}
+	}
+	return result, err
+}
+
+// CrawlByKeyword searches and crawls videos by keyword
+// Uses headless browser as primary method due to Cloudflare protection
+func (c *HTTPCrawler) CrawlByKeyword(ctx context.Context, keyword string, limit int) (*CrawlResult, error) {
+	return c.crawlListing(ctx, BaseURL+searchPath+url.PathEscape(keyword), limit,
+		logctx.From(ctx).With().Str("keyword", keyword).Logger())
+}
+
+// CrawlByTag crawls videos listed under a tag (genre)
+// Uses headless browser as primary method due to Cloudflare protection
+func (c *HTTPCrawler) CrawlByTag(ctx context.Context, tag string, limit int) (*CrawlResult, error) {
+	return c.crawlListing(ctx, BaseURL+genresPath+url.PathEscape(tag), limit,
+		logctx.From(ctx).With().Str("tag", tag).Logger())
+}
+
+// crawlListing pages through a listing with the headless browser until limit videos are found
+// or a page comes back empty
+func (c *HTTPCrawler) crawlListing(ctx context.Context, listURL string, limit int, logger zerolog.Logger) (*CrawlResult, error) {
+	result := &CrawlResult{}
 	page := 1
 	// Estimate max pages needed (assuming ~12 videos per page)
 	maxPages := (limit + 11) / 12
 
-	encodedName := url.PathEscape(actorName)
-
 	for page <= maxPages {
 		select {
 		case <-ctx.Done():
-			return allVideos, ctx.Err()
+			return result, ctx.Err()
 		default:
 		}
 
-		pageURL := BaseURL + actressesPath + encodedName
+		pageURL := listURL
 		if page > 1 {
 			pageURL = fmt.Sprintf("%s?page=%d", pageURL, page)
 		}
 
-		log.Info().Str("url", pageURL).Str("actor", actorName).Int("page", page).Msg("Crawling actor videos")
+		logger.Info().Str("url", pageURL).Int("page", page).Msg("Crawling listing page")
 
-		// Try headless browser (bypasses Cloudflare)
-		videos, err := c.crawlWithBrowser(ctx, pageURL)
+		videos, err := c.crawlListPage(ctx, pageURL, false, result)
+		if errors.Is(err, ErrBudgetExhausted) {
+			return result, err
+		}
 		if err != nil || len(videos) == 0 {
-			if err != nil {
-				log.Warn().Err(err).Msg("Browser crawl failed")
-			}
 			break
 		}
 
-		allVideos = append(allVideos, videos...)
-		log.Info().Int("pageCount", len(videos)).Int("total", len(allVideos)).Msg("Parsed actor videos")
+		result.Videos = append(result.Videos, videos...)
+		logger.Info().Int("pageCount", len(videos)).Int("total", len(result.Videos)).Msg("Parsed listing videos")
 
-		if len(allVideos) >= limit {
-			allVideos = allVideos[:limit]
+		if len(result.Videos) >= limit {
+			result.Videos = result.Videos[:limit]
 			break
 		}
 
 		page++
-		time.Sleep(3 * time.Second)
+		if err := sleepContext(ctx, 3*time.Second); err != nil {
+			return result, err
+		}
 	}
 
-	return allVideos, nil
-}
-
-// CrawlByCode crawls a video by its code
-func (c *HTTPCrawler) CrawlByCode(ctx context.Context, code string) (*model.Video, error) {
-	detailURL := BaseURL + "/" + strings.ToLower(code)
-	return c.CrawlVideoDetail(ctx, detailURL)
+	return result, nil
 }
 
-// CrawlByKeyword searches and crawls videos by keyword
-// Uses headless browser as primary method due to Cloudflare protection
-func (c *HTTPCrawler) CrawlByKeyword(ctx context.Context, keyword string, limit int) ([]*model.Video, error) {
-	var allVideos []*model.Video
-	page := 1
-	maxPages := (limit + 11) / 12
-
-	encodedKeyword := url.PathEscape(keyword)
-
-	for page <= maxPages {
-		select {
-		case <-ctx.Done():
-			return allVideos, ctx.Err()
-		default:
-		}
-
-		pageURL := BaseURL + searchPath + encodedKeyword
-		if page > 1 {
-			pageURL = fmt.Sprintf("%s?page=%d", pageURL, page)
+// crawlListPage fetches and parses a single listing page with the headless browser
+// While a browser clearance is active the page is fetched over plain HTTP first.
+// When httpFallback is set, a failed browser crawl is retried over plain HTTP.
+// The page outcome is recorded in result.
+func (c *HTTPCrawler) crawlListPage(ctx context.Context, pageURL string, httpFallback bool, result *CrawlResult) ([]*model.Video, error) {
+	start := time.Now()
+	stat := PageStat{URL: pageURL, Method: FetchBrowser}
+
+	var videos []*model.Video
+	var err error
+
+	// Reuse a Cloudflare clearance solved by the browser over plain HTTP
+	usedSession := c.hasBrowserSession()
+	if usedSession {
+		stat.Method = FetchHTTP
+		result.HTTPFetches++
+		html, fetchErr := c.fetchOnce(ctx, pageURL)
+		videos, err = c.parseListPage(ctx, html, fetchErr, result)
+		if err != nil {
+			logctx.From(ctx).Warn().Err(err).Str("url", pageURL).Msg("HTTP crawl with browser cookies failed, falling back to browser")
+			c.dropBrowserSession()
 		}
+	}
 
-		log.Info().Str("url", pageURL).Str("keyword", keyword).Int("page", page).Msg("Crawling keyword search")
-
+	if !usedSession || err != nil {
 		// Try headless browser first (bypasses Cloudflare)
-		videos, err := c.crawlWithBrowser(ctx, pageURL)
-		if err != nil || len(videos) == 0 {
+		stat.Method = FetchBrowser
+		result.BrowserFetches++
+		rendered, fetchErr := c.renderWithBrowser(ctx, pageURL, "div.group")
+		var html string
+		if fetchErr == nil {
+			html = rendered.HTML
+		}
+		videos, err = c.parseListPage(ctx, html, fetchErr, result)
+		if fetchErr == nil && (err != nil || len(videos) == 0) {
+			reason := snapshotNoVideos
+			if !rendered.SelectorFound {
+				reason = snapshotSelectorTimeout
+			}
+			c.saveSnapshot(pageURL, reason, rendered)
+		}
+	}
+	if err != nil {
+		logctx.From(ctx).Warn().Err(err).Str("url", pageURL).Msg("Browser crawl failed")
+		if httpFallback && !usedSession {
+			stat.Method = FetchHTTP
+			result.HTTPFetches++
+			html, fetchErr := c.fetchWithRetry(ctx, pageURL)
+			videos, err = c.parseListPage(ctx, html, fetchErr, result)
 			if err != nil {
-				log.Warn().Err(err).Msg("Browser crawl failed")
+				logctx.From(ctx).Warn().Err(err).Str("url", pageURL).Msg("HTTP crawl also failed")
 			}
-			break
 		}
+	}
 
-		allVideos = append(allVideos, videos...)
-		log.Info().Int("pageCount", len(videos)).Int("total", len(allVideos)).Msg("Parsed keyword search videos")
-
-		if len(allVideos) >= limit {
-			allVideos = allVideos[:limit]
-			break
-		}
+	stat.Duration = time.Since(start)
+	stat.Videos = len(videos)
+	if err != nil {
+		stat.Error = err.Error()
+	}
+	result.addPage(stat)
+	return videos, err
+}
 
-		page++
-		time.Sleep(3 * time.Second)
+// parseListPage parses a fetched listing page, counting fetched pages and parse failures
+func (c *HTTPCrawler) parseListPage(ctx context.Context, html string, fetchErr error, result *CrawlResult) ([]*model.Video, error) {
+	if fetchErr != nil {
+		return nil, fetchErr
 	}
+	result.PagesFetched++
 
-	return allVideos, nil
+	videos, err := c.parser.ParseVideoList(ctx, html)
+	if err != nil {
+		result.addParseFailure()
+		return nil, err
+	}
+	for _, video := range videos {
+		video.Source = c.Name()
+	}
+	return videos, nil
 }
 
 // Close releases crawler resources
@@ -316,6 +486,10 @@ func (c *HTTPCrawler) fetchWithRetry(ctx context.Context, targetURL string) (str
 		if err == nil {
 			return html, nil
 		}
+		// Neither an exhausted budget nor a challenge page goes away by retrying
+		if errors.Is(err, ErrBudgetExhausted) || errors.Is(err, ErrChallenge) {
+			return "", err
+		}
 
 		lastErr = err
 
@@ -323,10 +497,15 @@ func (c *HTTPCrawler) fetchWithRetry(ctx context.Context, targetURL string) (str
 		if attempt < c.config.MaxRetries {
 			// Exponential backoff: 1s, 2s, 4s
 			backoff := time.Duration(math.Pow(2, float64(attempt))) * time.Second
-			select {
-			case <-ctx.Done():
-				return "", ctx.Err()
-			case <-time.After(backoff):
+
+			// Wait as long as the site asks when it is rate limiting us
+			var limited *rateLimitError
+			if errors.As(err, &limited) && limited.RetryAfter > backoff {
+				backoff = min(limited.RetryAfter, maxRetryAfter)
+			}
+
+			if err := sleepContext(ctx, backoff); err != nil {
+				return "", err
 			}
 		}
 	}
@@ -334,14 +513,24 @@ func (c *HTTPCrawler) fetchWithRetry(ctx context.Context, targetURL string) (str
 	return "", fmt.Errorf("max retries exceeded: %w", lastErr)
 }
 
+// fetchOnce fetches a URL with rate limiting but without retrying
+func (c *HTTPCrawler) fetchOnce(ctx context.Context, targetURL string) (string, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return "", fmt.Errorf("rate limiter error: %w", err)
+	}
+	return c.fetch(ctx, targetURL)
+}
+
 // fetch performs a single HTTP request
 func (c *HTTPCrawler) fetch(ctx context.Context, targetURL string) (string, error) {
+	if err := c.budget.Spend(ctx); err != nil {
+		return "", err
+	}
+
 	// Add random delay to avoid being blocked (1-3 seconds like Java version)
 	delay := time.Duration(1000+rand.Intn(2000)) * time.Millisecond
-	select {
-	case <-ctx.Done():
-		return "", ctx.Err()
-	case <-time.After(delay):
+	if err := sleepContext(ctx, delay); err != nil {
+		return "", err
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
@@ -350,7 +539,7 @@ func (c *HTTPCrawler) fetch(ctx context.Context, targetURL string) (string, erro
 	}
 
 	// Set headers
-	req.Header.Set("User-Agent", c.config.UserAgent)
+	req.Header.Set("User-Agent", c.userAgent())
 	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,image/avif,image/webp,image/apng,*/*;q=0.8")
 	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9,en-US;q=0.8,en;q=0.7")
 	req.Header.Set("Referer", BaseURL+"/")
@@ -363,44 +552,94 @@ func (c *HTTPCrawler) fetch(ctx context.Context, targetURL string) (string, erro
 	}
 	defer resp.Body.Close()
 
-	log.Debug().
+	logctx.From(ctx).Debug().
 		Int("status", resp.StatusCode).
 		Str("url", targetURL).
 		Str("finalURL", resp.Request.URL.String()).
 		Msg("HTTP response")
 
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP status %d", resp.StatusCode)
-	}
-
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", fmt.Errorf("read body error: %w", err)
 	}
-
 	html := string(body)
-	log.Debug().Int("length", len(html)).Msg("Received HTML")
+
+	// Cloudflare wants a real browser; retrying over HTTP would only parse the challenge as "0 videos"
+	if isChallengeResponse(resp.Header, html) {
+		crawlChallengesTotal.Inc()
+		logctx.From(ctx).Warn().
+			Int("status", resp.StatusCode).
+			Str("cfRay", resp.Header.Get("Cf-Ray")).
+			Str("url", targetURL).
+			Msg("Received Cloudflare challenge page")
+		return "", ErrChallenge
+	}
+
+	if isRateLimitStatus(resp.StatusCode) {
+		limited := &rateLimitError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			Ray:        resp.Header.Get("Cf-Ray"),
+		}
+		c.rateLimit.limit(limited)
+		logctx.From(ctx).Warn().
+			Int("status", limited.StatusCode).
+			Str("cfRay", limited.Ray).
+			Dur("retryAfter", limited.RetryAfter).
+			Str("url", targetURL).
+			Msg("Site is rate limiting us")
+		return "", limited
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+	c.rateLimit.clear()
+
+	logctx.From(ctx).Debug().Int("length", len(html)).Msg("Received HTML")
 
 	return html, nil
 }
 
-// crawlWithBrowser uses headless browser to crawl a page
-func (c *HTTPCrawler) crawlWithBrowser(ctx context.Context, pageURL string) ([]*model.Video, error) {
-	log.Info().Str("url", pageURL).Msg("Starting browser crawl")
+// fetchWithBrowser uses the headless browser to fetch the rendered HTML of a page
+func (c *HTTPCrawler) fetchWithBrowser(ctx context.Context, pageURL string, waitSelector string) (string, error) {
+	rendered, err := c.renderWithBrowser(ctx, pageURL, waitSelector)
+	if err != nil {
+		return "", err
+	}
+	return rendered.HTML, nil
+}
+
+// renderWithBrowser renders a page in the headless browser
+func (c *HTTPCrawler) renderWithBrowser(ctx context.Context, pageURL string, waitSelector string) (*RenderedPage, error) {
+	logctx.From(ctx).Info().Str("url", pageURL).Msg("Starting browser crawl")
+
+	if err := c.budget.Spend(ctx); err != nil {
+		return nil, err
+	}
 
 	browser, err := c.getBrowser()
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to get browser instance")
+		logctx.From(ctx).Error().Err(err).Msg("Failed to get browser instance")
 		return nil, err
 	}
 
-	html, err := browser.FetchRenderedHTML(ctx, pageURL, "div.group")
+	rendered, err := browser.FetchRenderedPage(ctx, pageURL, waitSelector)
 	if err != nil {
-		log.Error().Err(err).Msg("Browser failed to fetch HTML")
+		logctx.From(ctx).Error().Err(err).Msg("Browser failed to fetch HTML")
+		c.discardCrashedBrowser(browser)
 		return nil, err
 	}
+	if isChallengePage(rendered.HTML) {
+		crawlChallengesTotal.Inc()
+		logctx.From(ctx).Warn().Str("url", pageURL).Msg("Browser is still on the Cloudflare challenge page")
+		c.saveSnapshot(pageURL, snapshotChallenge, rendered)
+		return nil, fmt.Errorf("browser did not pass challenge: %w", ErrChallenge)
+	}
+	c.adoptBrowserSession(pageURL, rendered)
+
+	html := rendered.HTML
 
-	log.Info().Int("htmlLength", len(html)).Msg("Browser fetched HTML")
+	logctx.From(ctx).Info().Int("htmlLength", len(html)).Msg("Browser fetched HTML")
 
 	// Log first 500 chars for debugging
 	if len(html) > 0 {
@@ -408,41 +647,58 @@ func (c *HTTPCrawler) crawlWithBrowser(ctx context.Context, pageURL string) ([]*
 		if len(preview) > 500 {
 			preview = preview[:500]
 		}
-		log.Debug().Str("preview", preview).Msg("HTML preview")
-	}
-
-	videos, err := c.parser.ParseVideoList(html)
-	if err != nil {
-		log.Error().Err(err).Msg("Failed to parse browser HTML")
-		return nil, err
+		logctx.From(ctx).Debug().Str("preview", preview).Msg("HTML preview")
 	}
 
-	log.Info().Int("count", len(videos)).Msg("Browser crawl completed")
-	return videos, nil
+	return rendered, nil
 }
 
-// crawlDetailWithBrowser uses headless browser to crawl video detail
-func (c *HTTPCrawler) crawlDetailWithBrowser(ctx context.Context, detailURL string) (*model.Video, error) {
-	browser, err := c.getBrowser()
-	if err != nil {
-		return nil, err
+// saveSnapshot keeps a failed browser render on disk when snapshots are enabled
+func (c *HTTPCrawler) saveSnapshot(pageURL, reason string, rendered *RenderedPage) {
+	if c.snapshots == nil {
+		return
 	}
-
-	html, err := browser.FetchRenderedHTML(ctx, detailURL, "body")
+	path, err := c.snapshots.Save(pageURL, reason, rendered)
 	if err != nil {
-		return nil, err
+		log.Warn().Err(err).Str("url", pageURL).Msg("Failed to save browser snapshot")
+		return
 	}
-
-	return c.parser.ParseVideoDetail(html, detailURL)
+	log.Info().Str("url", pageURL).Str("reason", reason).Str("snapshot", path).Msg("Saved browser snapshot")
 }
 
 // getBrowser returns the browser instance, creating it if necessary
+// A browser that has served too many pages or lived too long is closed and
+// relaunched, since Chrome keeps growing in memory over its lifetime.
 func (c *HTTPCrawler) getBrowser() (*Browser, error) {
 	c.browserMu.Lock()
 	defer c.browserMu.Unlock()
 
+	if c.browser != nil {
+		if reason := c.browserRetireReason(c.browser); reason != "" {
+			log.Info().
+				Str("reason", reason).
+				Int("pages", c.browser.PagesServed()).
+				Dur("age", c.browser.Age()).
+				Msg("Restarting browser")
+			c.closeBrowserLocked(reason)
+		}
+	}
+
 	if c.browser == nil {
-		browser, err := NewBrowser()
+		browserCfg := DefaultBrowserConfig()
+		browserCfg.ProxyURL = c.config.ProxyURL
+		browserCfg.ProxyUsername = c.config.ProxyUsername
+		browserCfg.ProxyPassword = c.config.ProxyPassword
+		browserCfg.RemoteURL = c.config.BrowserRemoteURL
+		browserCfg.BinPath = c.config.BrowserBinPath
+		browserCfg.ExtraFlags = c.config.BrowserExtraFlags
+		browserCfg.Headless = !c.config.BrowserHeadful
+		browserCfg.Devtools = c.config.BrowserDevtools
+		browserCfg.CaptureScreenshots = c.snapshots != nil
+		if c.config.BrowserNavigationDeadline > 0 {
+			browserCfg.NavigationDeadline = c.config.BrowserNavigationDeadline
+		}
+		browser, err := NewBrowserWithConfig(browserCfg)
 		if err != nil {
 			return nil, err
 		}
@@ -452,6 +708,70 @@ func (c *HTTPCrawler) getBrowser() (*Browser, error) {
 	return c.browser, nil
 }
 
+// BudgetUsage returns the requests spent today and the daily budget (0 when unlimited)
+func (c *HTTPCrawler) BudgetUsage() (used, limit int) {
+	return c.budget.Usage()
+}
+
+// RateLimitedUntil returns when the site's last back-off request expires
+func (c *HTTPCrawler) RateLimitedUntil() time.Time {
+	return c.rateLimit.Until()
+}
+
+// LimiterStatus returns the request rate and how long the next request would wait for a token
+func (c *HTTPCrawler) LimiterStatus() (limit float64, wait time.Duration) {
+	return float64(c.limiter.Limit()), limiterWait(c.limiter, time.Now())
+}
+
+// BrowserHealth reports the state of the headless browser; Running is false until it is first needed
+func (c *HTTPCrawler) BrowserHealth() BrowserHealth {
+	c.browserMu.Lock()
+	browser := c.browser
+	c.browserMu.Unlock()
+
+	if browser == nil {
+		return BrowserHealth{}
+	}
+	return browser.Health()
+}
+
+// browserRetireReason returns why browser should be relaunched, or "" if it can keep serving
+func (c *HTTPCrawler) browserRetireReason(browser *Browser) string {
+	switch {
+	case browser.IsClosed():
+		return "closed"
+	case c.config.BrowserMaxPages > 0 && browser.PagesServed() >= c.config.BrowserMaxPages:
+		return "max_pages"
+	case c.config.BrowserMaxAge > 0 && browser.Age() >= c.config.BrowserMaxAge:
+		return "max_age"
+	}
+	return ""
+}
+
+// discardCrashedBrowser drops browser if it no longer responds, so the next fallback launches a new one
+func (c *HTTPCrawler) discardCrashedBrowser(browser *Browser) {
+	if browser.Alive() {
+		return
+	}
+
+	c.browserMu.Lock()
+	defer c.browserMu.Unlock()
+
+	if c.browser == browser {
+		log.Warn().Msg("Browser is not responding, it will be relaunched on next use")
+		c.closeBrowserLocked("crashed")
+	}
+}
+
+// closeBrowserLocked closes the current browser; callers hold browserMu
+func (c *HTTPCrawler) closeBrowserLocked(reason string) {
+	browserRestartsTotal.WithLabelValues(reason).Inc()
+	if err := c.browser.Close(); err != nil {
+		log.Warn().Err(err).Msg("Failed to close browser")
+	}
+	c.browser = nil
+}
+
 // GetLimiter returns the rate limiter for testing purposes
 func (c *HTTPCrawler) GetLimiter() *rate.Limiter {
 	return c.limiter
@@ -0,0 +1,231 @@
+package crawler
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	utls "github.com/refraction-networking/utls"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/proxy"
+)
+
+// dialFunc dials a network connection
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// chromeTransport is an http.RoundTripper that performs TLS handshakes with
+// Chrome's ClientHello (JA3 fingerprint) via utls and speaks HTTP/2 whenever the
+// server negotiates it, like Chrome does, instead of Go's default TLS stack.
+type chromeTransport struct {
+	dial      dialFunc
+	tlsConfig *utls.Config
+	helloID   utls.ClientHelloID
+	h1        *http.Transport
+	h2        *http2.Transport
+
+	mu     sync.Mutex
+	protos map[string]string   // negotiated ALPN protocol per host:port
+	probes map[string]net.Conn // handshaken connections waiting to be reused
+}
+
+// newChromeTransport creates a Chrome-impersonating transport, dialing through proxyURL if set
+func newChromeTransport(proxyURL *url.URL) (*chromeTransport, error) {
+	dial, err := newProxyDialer(proxyURL, &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	t := &chromeTransport{
+		dial:      dial,
+		tlsConfig: &utls.Config{},
+		helloID:   utls.HelloChrome_Auto,
+		protos:    make(map[string]string),
+		probes:    make(map[string]net.Conn),
+	}
+	t.h1 = &http.Transport{
+		DialContext:         dial,
+		DialTLSContext:      t.dialTLS,
+		MaxIdleConns:        100,
+		MaxIdleConnsPerHost: 10,
+		IdleConnTimeout:     90 * time.Second,
+	}
+	t.h2 = &http2.Transport{
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return t.dialTLS(ctx, network, addr)
+		},
+		ReadIdleTimeout: 30 * time.Second,
+	}
+	return t, nil
+}
+
+// RoundTrip sends the request over HTTP/2 or HTTP/1.1, depending on what the server negotiated
+func (t *chromeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Scheme != "https" {
+		return t.h1.RoundTrip(req)
+	}
+
+	proto, err := t.negotiatedProtocol(req.Context(), tlsAddr(req.URL))
+	if err != nil {
+		return nil, err
+	}
+	if proto == http2.NextProtoTLS {
+		return t.h2.RoundTrip(req)
+	}
+	return t.h1.RoundTrip(req)
+}
+
+// CloseIdleConnections closes idle connections of both protocols
+func (t *chromeTransport) CloseIdleConnections() {
+	t.h1.CloseIdleConnections()
+	t.h2.CloseIdleConnections()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for addr, conn := range t.probes {
+		conn.Close()
+		delete(t.probes, addr)
+	}
+}
+
+// negotiatedProtocol returns the ALPN protocol a host negotiates, handshaking once to learn it
+// The probing connection is kept and handed to the first dial for the host.
+func (t *chromeTransport) negotiatedProtocol(ctx context.Context, addr string) (string, error) {
+	t.mu.Lock()
+	proto, ok := t.protos[addr]
+	t.mu.Unlock()
+	if ok {
+		return proto, nil
+	}
+
+	conn, err := t.handshake(ctx, "tcp", addr)
+	if err != nil {
+		return "", err
+	}
+	proto = conn.ConnectionState().NegotiatedProtocol
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.protos[addr] = proto
+	if old, ok := t.probes[addr]; ok {
+		old.Close()
+	}
+	t.probes[addr] = conn
+	return proto, nil
+}
+
+// dialTLS returns a TLS connection with Chrome's fingerprint, reusing a probing connection when available
+func (t *chromeTransport) dialTLS(ctx context.Context, network, addr string) (net.Conn, error) {
+	t.mu.Lock()
+	conn, ok := t.probes[addr]
+	delete(t.probes, addr)
+	t.mu.Unlock()
+	if ok {
+		return conn, nil
+	}
+	return t.handshake(ctx, network, addr)
+}
+
+// handshake dials addr and performs a TLS handshake with the configured ClientHello
+func (t *chromeTransport) handshake(ctx context.Context, network, addr string) (*utls.UConn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", addr, err)
+	}
+
+	raw, err := t.dial(ctx, network, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := t.tlsConfig.Clone()
+	cfg.ServerName = host
+	conn := utls.UClient(raw, cfg, t.helloID)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		raw.Close()
+		return nil, fmt.Errorf("tls handshake error: %w", err)
+	}
+	return conn, nil
+}
+
+// tlsAddr returns the host:port of an https URL
+func tlsAddr(u *url.URL) string {
+	if u.Port() != "" {
+		return u.Host
+	}
+	return net.JoinHostPort(u.Hostname(), "443")
+}
+
+// newProxyDialer returns a dial function that connects through proxyURL (HTTP CONNECT or SOCKS5)
+func newProxyDialer(proxyURL *url.URL, dialer *net.Dialer) (dialFunc, error) {
+	if proxyURL == nil {
+		return dialer.DialContext, nil
+	}
+
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		// proxy.FromURL authenticates with the username/password in the URL
+		d, err := proxy.FromURL(proxyURL, dialer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SOCKS5 proxy: %w", err)
+		}
+		contextDialer, ok := d.(proxy.ContextDialer)
+		if !ok {
+			return nil, fmt.Errorf("SOCKS5 proxy dialer does not support contexts")
+		}
+		return contextDialer.DialContext, nil
+	case "http":
+		return func(ctx context.Context, network, addr string) (net.Conn, error) {
+			conn, err := dialer.DialContext(ctx, "tcp", proxyURL.Host)
+			if err != nil {
+				return nil, fmt.Errorf("proxy dial error: %w", err)
+			}
+			if err := connectTunnel(ctx, conn, proxyURL, addr); err != nil {
+				conn.Close()
+				return nil, err
+			}
+			return conn, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+}
+
+// connectTunnel asks an HTTP proxy to open a tunnel to addr over conn
+func connectTunnel(ctx context.Context, conn net.Conn, proxyURL *url.URL, addr string) error {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if user := proxyURL.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("proxy CONNECT error: %w", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("proxy CONNECT error: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy CONNECT status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package crawler
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	utls "github.com/refraction-networking/utls"
+)
+
+func newTestChromeTransport(t *testing.T, proxyURL *url.URL) *chromeTransport {
+	t.Helper()
+	transport, err := newChromeTransport(proxyURL)
+	if err != nil {
+		t.Fatalf("newChromeTransport() error = %v", err)
+	}
+	// httptest servers use self-signed certificates
+	transport.tlsConfig = &utls.Config{InsecureSkipVerify: true}
+	t.Cleanup(transport.CloseIdleConnections)
+	return transport
+}
+
+func TestChromeTransport_NegotiatesProtocol(t *testing.T) {
+	for _, enableHTTP2 := range []bool{false, true} {
+		server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.WriteString(w, r.Proto)
+		}))
+		server.EnableHTTP2 = enableHTTP2
+		server.StartTLS()
+
+		client := &http.Client{Transport: newTestChromeTransport(t, nil)}
+		want := "HTTP/1.1"
+		if enableHTTP2 {
+			want = "HTTP/2.0"
+		}
+
+		// The second request reuses the learned protocol
+		for i := 0; i < 2; i++ {
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("GET (http2=%v) error = %v", enableHTTP2, err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if string(body) != want {
+				t.Errorf("server saw %q, want %q", body, want)
+			}
+		}
+		server.Close()
+	}
+}
+
+func TestChromeTransport_HTTPProxy(t *testing.T) {
+	target := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "ok")
+	}))
+	defer target.Close()
+
+	var tunnels atomic.Int32
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect || r.Header.Get("Proxy-Authorization") == "" {
+			http.Error(w, "expected authenticated CONNECT", http.StatusBadRequest)
+			return
+		}
+		tunnels.Add(1)
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() { io.Copy(upstream, conn); upstream.Close() }()
+		go func() { io.Copy(conn, upstream); conn.Close() }()
+	}))
+	defer proxyServer.Close()
+
+	proxyURL, _ := url.Parse(proxyServer.URL)
+	proxyURL.User = url.UserPassword("user", "secret")
+
+	client := &http.Client{Transport: newTestChromeTransport(t, proxyURL)}
+	resp, err := client.Get(target.URL)
+	if err != nil {
+		t.Fatalf("GET through proxy error = %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" || tunnels.Load() == 0 {
+		t.Errorf("body = %q, tunnels = %d", body, tunnels.Load())
+	}
+}
+
+func TestNewProxyDialer_UnsupportedScheme(t *testing.T) {
+	if _, err := newProxyDialer(&url.URL{Scheme: "ftp", Host: "proxy:21"}, &net.Dialer{}); err == nil {
+		t.Error("expected error for unsupported proxy scheme")
+	}
+}
+
+func TestNewHTTPCrawler_ImpersonateTLS(t *testing.T) {
+	cfg := DefaultCrawlerConfig()
+	cfg.ImpersonateTLS = true
+	c, err := NewHTTPCrawler(cfg)
+	if err != nil {
+		t.Fatalf("NewHTTPCrawler() error = %v", err)
+	}
+	if _, ok := c.client.Transport.(*chromeTransport); !ok {
+		t.Errorf("transport = %T, want *chromeTransport", c.client.Transport)
+	}
+}
@@ -0,0 +1,180 @@
+package crawler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// Fields merged across sources; they are also the keys of Video.Provenance
+const (
+	FieldTitle       = "title"
+	FieldActresses   = "actresses"
+	FieldTags        = "tags"
+	FieldDuration    = "duration"
+	FieldReleaseDate = "release_date"
+	FieldCover       = "cover"
+	FieldPreview     = "preview"
+	FieldScreenshots = "screenshots"
+)
+
+// mergeField reads and copies one video field
+type mergeField struct {
+	name  string
+	isSet func(v *model.Video) bool
+	copy  func(dst, src *model.Video)
+}
+
+var mergeFields = []mergeField{
+	{FieldTitle, func(v *model.Video) bool { return v.Title != "" }, func(d, s *model.Video) { d.Title = s.Title }},
+	{FieldActresses, func(v *model.Video) bool { return v.Actresses != "" }, func(d, s *model.Video) { d.Actresses = s.Actresses }},
+	{FieldTags, func(v *model.Video) bool { return v.Tags != "" }, func(d, s *model.Video) { d.Tags = s.Tags }},
+	{FieldDuration, func(v *model.Video) bool { return v.Duration > 0 }, func(d, s *model.Video) { d.Duration = s.Duration }},
+	{FieldReleaseDate, func(v *model.Video) bool { return v.ReleaseDate != nil }, func(d, s *model.Video) { d.ReleaseDate = s.ReleaseDate }},
+	{FieldCover, func(v *model.Video) bool { return v.CoverURL != "" }, func(d, s *model.Video) { d.CoverURL = s.CoverURL }},
+	{FieldPreview, func(v *model.Video) bool { return v.PreviewURL != "" }, func(d, s *model.Video) { d.PreviewURL = s.PreviewURL }},
+	{FieldScreenshots, func(v *model.Video) bool { return len(v.Screenshots) > 0 }, func(d, s *model.Video) { d.Screenshots = s.Screenshots }},
+}
+
+// MergePriority lists, per field, the sources to take the field from first
+// Sources not listed follow in crawler order.
+type MergePriority map[string][]string
+
+// ParseMergePriority parses a priority spec such as "title=missav,javdb;tags=javdb"
+func ParseMergePriority(spec string) (MergePriority, error) {
+	priority := make(MergePriority)
+	for _, rule := range strings.Split(spec, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		field, list, ok := strings.Cut(rule, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || !isMergeField(field) {
+			return nil, fmt.Errorf("invalid merge priority rule %q", rule)
+		}
+		for _, name := range strings.Split(list, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				priority[field] = append(priority[field], name)
+			}
+		}
+	}
+	return priority, nil
+}
+
+// isMergeField reports whether name is a merged field
+func isMergeField(name string) bool {
+	for _, f := range mergeFields {
+		if f.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// order returns the sources to try for field: configured ones first, then the rest in crawler order
+func (p MergePriority) order(field string, sources []string) []string {
+	order := append([]string(nil), p[field]...)
+	for _, name := range sources {
+		if !containsString(order, name) {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeVideos folds videos of the same canonical code from several sources into one
+// The merged video keeps the identity (source, code and detail URL) of the highest
+// ranked source that found it and takes each field from the first source in the
+// field's priority that has it, recording where each field came from.
+func mergeVideos(videos []*model.Video, sources []string, priority MergePriority) []*model.Video {
+	type group struct {
+		bySource map[string][]*model.Video
+	}
+	groups := make(map[string]*group)
+	var codes []string
+
+	for _, video := range videos {
+		code := model.CanonicalCode(video.Code)
+		g, ok := groups[code]
+		if !ok {
+			g = &group{bySource: make(map[string][]*model.Video)}
+			groups[code] = g
+			codes = append(codes, code)
+		}
+		g.bySource[video.Source] = append(g.bySource[video.Source], video)
+	}
+
+	merged := make([]*model.Video, 0, len(videos))
+	for _, code := range codes {
+		g := groups[code]
+		if len(g.bySource) < 2 {
+			for _, vs := range g.bySource {
+				merged = append(merged, vs...)
+			}
+			continue
+		}
+
+		// Sources outside the crawler order (e.g. untagged videos) rank last
+		ranked := sources
+		var extra []string
+		for name := range g.bySource {
+			if !containsString(sources, name) {
+				extra = append(extra, name)
+			}
+		}
+		if len(extra) > 0 {
+			sort.Strings(extra)
+			ranked = append(append([]string(nil), sources...), extra...)
+		}
+
+		// The first video of each source is the candidate for its fields
+		candidates := make(map[string]*model.Video, len(g.bySource))
+		var baseSource string
+		for _, name := range ranked {
+			if vs := g.bySource[name]; len(vs) > 0 {
+				candidates[name] = vs[0]
+				if baseSource == "" {
+					baseSource = name
+				}
+			}
+		}
+
+		// Variants of a release on the base source (e.g. -UNCENSORED-LEAK) each get enriched
+		for _, base := range g.bySource[baseSource] {
+			video := *base
+			video.Provenance = make(map[string]string, len(mergeFields))
+			for _, f := range mergeFields {
+				for _, name := range priority.order(f.name, ranked) {
+					if name == baseSource {
+						// Prefer the variant's own value over its sibling's
+						if f.isSet(base) {
+							video.Provenance[f.name] = name
+							break
+						}
+						continue
+					}
+					if src := candidates[name]; src != nil && f.isSet(src) {
+						f.copy(&video, src)
+						video.Provenance[f.name] = name
+						break
+					}
+				}
+			}
+			merged = append(merged, &video)
+		}
+	}
+	return merged
+}
@@ -0,0 +1,84 @@
+package crawler
+
+import (
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestParseMergePriority(t *testing.T) {
+	priority, err := ParseMergePriority(" title = missav, javdb ; TAGS=javdb;")
+	if err != nil {
+		t.Fatalf("ParseMergePriority() error = %v", err)
+	}
+	if got := priority[FieldTitle]; len(got) != 2 || got[0] != "missav" || got[1] != "javdb" {
+		t.Errorf("title priority = %v", got)
+	}
+	if got := priority[FieldTags]; len(got) != 1 || got[0] != "javdb" {
+		t.Errorf("tags priority = %v", got)
+	}
+
+	for _, spec := range []string{"plot=javdb", "title"} {
+		if _, err := ParseMergePriority(spec); err == nil {
+			t.Errorf("ParseMergePriority(%q) succeeded, want error", spec)
+		}
+	}
+}
+
+func TestMergeVideos(t *testing.T) {
+	primary := &model.Video{
+		Source:     "missav",
+		Code:       "ABC-123",
+		Title:      "ABC-123 Primary Title",
+		Tags:       "Drama",
+		DetailURL:  "https://missav.ai/abc-123",
+		PreviewURL: "https://missav.ai/preview.mp4",
+	}
+	metadata := &model.Video{
+		Source:    "javdb",
+		Code:      "abc-123",
+		Title:     "Metadata Title",
+		Actresses: "Actress One",
+		Tags:      "Drama, Romance",
+		Duration:  120,
+		DetailURL: "https://javdb.com/v/xyz",
+	}
+	other := &model.Video{Source: "javdb", Code: "DEF-456", Title: "Only on javdb"}
+
+	priority := MergePriority{FieldTags: {"javdb"}}
+	videos := mergeVideos([]*model.Video{primary, metadata, other}, []string{"missav", "javdb"}, priority)
+	if len(videos) != 2 {
+		t.Fatalf("mergeVideos() returned %d videos, want 2", len(videos))
+	}
+
+	merged := videos[0]
+	if merged.Source != "missav" || merged.DetailURL != primary.DetailURL {
+		t.Errorf("merged identity = %s %s, want the primary source's", merged.Source, merged.DetailURL)
+	}
+	if merged.Title != primary.Title || merged.Tags != metadata.Tags || merged.Actresses != metadata.Actresses || merged.Duration != 120 {
+		t.Errorf("merged video = %+v", merged)
+	}
+
+	want := map[string]string{
+		FieldTitle:     "missav",
+		FieldTags:      "javdb",
+		FieldActresses: "javdb",
+		FieldDuration:  "javdb",
+		FieldPreview:   "missav",
+	}
+	for field, source := range want {
+		if merged.Provenance[field] != source {
+			t.Errorf("Provenance[%s] = %q, want %q", field, merged.Provenance[field], source)
+		}
+	}
+	if _, ok := merged.Provenance[FieldCover]; ok {
+		t.Error("Provenance records a field no source had")
+	}
+
+	if videos[1] != other {
+		t.Errorf("video found by one source was changed: %+v", videos[1])
+	}
+	if primary.Tags != "Drama" {
+		t.Error("mergeVideos modified its input")
+	}
+}
@@ -0,0 +1,216 @@
+package crawler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+)
+
+// maxMemoryPageCacheEntries bounds the in-memory page cache
+const maxMemoryPageCacheEntries = 512
+
+var detailCacheTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "missav_bot_crawl_detail_cache_total",
+	Help: "Total number of detail page cache lookups by result",
+}, []string{"result"})
+
+func init() {
+	prometheus.MustRegister(detailCacheTotal)
+}
+
+// PageCache stores fetched page HTML for a limited time
+type PageCache interface {
+	// Get returns the cached HTML for key, if present and not expired
+	Get(key string) (string, bool)
+	// Set stores the HTML for key
+	Set(key string, html string)
+}
+
+// NewPageCache creates a page cache with the given TTL
+// Pages are kept on disk under dir, or in memory when dir is empty.
+func NewPageCache(ttl time.Duration, dir string) (PageCache, error) {
+	if dir == "" {
+		return newMemoryPageCache(ttl, maxMemoryPageCacheEntries), nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create page cache directory: %w", err)
+	}
+	return &diskPageCache{dir: dir, ttl: ttl}, nil
+}
+
+// memoryPageCacheEntry is a cached page and its expiry
+type memoryPageCacheEntry struct {
+	html    string
+	expires time.Time
+}
+
+// memoryPageCache keeps pages in memory, evicting the oldest entries when full
+type memoryPageCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]memoryPageCacheEntry
+}
+
+func newMemoryPageCache(ttl time.Duration, maxEntries int) *memoryPageCache {
+	return &memoryPageCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]memoryPageCacheEntry),
+	}
+}
+
+// Get returns the cached HTML for key
+func (c *memoryPageCache) Get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(entry.expires) {
+		delete(c.entries, key)
+		return "", false
+	}
+	return entry.html, true
+}
+
+// Set stores the HTML for key
+func (c *memoryPageCache) Set(key string, html string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if _, ok := c.entries[key]; !ok && len(c.entries) >= c.maxEntries {
+		c.evict(now)
+	}
+	c.entries[key] = memoryPageCacheEntry{html: html, expires: now.Add(c.ttl)}
+}
+
+// evict drops expired entries, or the entry closest to expiring if none have expired
+func (c *memoryPageCache) evict(now time.Time) {
+	oldestKey := ""
+	var oldest time.Time
+	for key, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, key)
+			continue
+		}
+		if oldestKey == "" || entry.expires.Before(oldest) {
+			oldestKey, oldest = key, entry.expires
+		}
+	}
+	if len(c.entries) >= c.maxEntries && oldestKey != "" {
+		delete(c.entries, oldestKey)
+	}
+}
+
+// diskPageCache keeps pages as files, using their modification time for expiry
+type diskPageCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// path returns the file holding the page for key
+func (c *diskPageCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".html")
+}
+
+// Get returns the cached HTML for key
+func (c *diskPageCache) Get(key string) (string, bool) {
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", false
+	}
+	if time.Since(info.ModTime()) > c.ttl {
+		os.Remove(path)
+		return "", false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// Set stores the HTML for key, writing to a temporary file first so readers never see partial pages
+func (c *diskPageCache) Set(key string, html string) {
+	tmp, err := os.CreateTemp(c.dir, "page-*.tmp")
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to create page cache file")
+		return
+	}
+	if _, err := tmp.WriteString(html); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		log.Warn().Err(err).Msg("Failed to write page cache file")
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		log.Warn().Err(err).Msg("Failed to write page cache file")
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		log.Warn().Err(err).Msg("Failed to store page cache file")
+	}
+}
+
+// freshKey marks contexts whose detail crawls skip the page cache
+type freshKey struct{}
+
+// WithoutCache returns a context whose detail crawls fetch the page even when it is
+// cached; the fresh page replaces the cached one. Used when an admin refreshes a video.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshKey{}, true)
+}
+
+// skipsCache reports whether ctx was created by WithoutCache
+func skipsCache(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshKey{}).(bool)
+	return fresh
+}
+
+// detailCacheKey returns the cache key of a detail page: its normalized video code
+func detailCacheKey(detailURL string) string {
+	return extractCodeFromURL(detailURL)
+}
+
+// cachedDetail returns the cached HTML of a detail page
+func (c *HTTPCrawler) cachedDetail(detailURL string) (string, bool) {
+	key := detailCacheKey(detailURL)
+	if c.detailCache == nil || key == "" {
+		return "", false
+	}
+	html, ok := c.detailCache.Get(key)
+	if ok {
+		detailCacheTotal.WithLabelValues("hit").Inc()
+	} else {
+		detailCacheTotal.WithLabelValues("miss").Inc()
+	}
+	return html, ok
+}
+
+// storeDetail caches the HTML of a successfully parsed detail page
+func (c *HTTPCrawler) storeDetail(detailURL string, html string) {
+	if c.detailCache == nil {
+		return
+	}
+	if key := detailCacheKey(detailURL); key != "" {
+		c.detailCache.Set(key, html)
+	}
+}
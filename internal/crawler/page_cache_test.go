@@ -0,0 +1,99 @@
+package crawler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestMemoryPageCache(t *testing.T) {
+	cache := newMemoryPageCache(time.Hour, 2)
+
+	if _, ok := cache.Get("ABC-123"); ok {
+		t.Fatal("expected miss on empty cache")
+	}
+	cache.Set("ABC-123", "<html>1</html>")
+	if html, ok := cache.Get("ABC-123"); !ok || html != "<html>1</html>" {
+		t.Errorf("Get() = %q, %v", html, ok)
+	}
+
+	// Filling the cache evicts the entry closest to expiring
+	cache.Set("ABC-124", "<html>2</html>")
+	cache.Set("ABC-125", "<html>3</html>")
+	if _, ok := cache.Get("ABC-123"); ok {
+		t.Error("expected oldest entry to be evicted")
+	}
+	if _, ok := cache.Get("ABC-125"); !ok {
+		t.Error("expected newest entry to be cached")
+	}
+
+	expired := newMemoryPageCache(-time.Second, 2)
+	expired.Set("ABC-123", "<html></html>")
+	if _, ok := expired.Get("ABC-123"); ok {
+		t.Error("expected expired entry to miss")
+	}
+}
+
+func TestDiskPageCache(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pages")
+	cache, err := NewPageCache(time.Hour, dir)
+	if err != nil {
+		t.Fatalf("NewPageCache() error = %v", err)
+	}
+
+	cache.Set("ABC-123", "<html>1</html>")
+	if html, ok := cache.Get("ABC-123"); !ok || html != "<html>1</html>" {
+		t.Errorf("Get() = %q, %v", html, ok)
+	}
+
+	// Entries older than the TTL are removed
+	path := cache.(*diskPageCache).path("ABC-123")
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+	if _, ok := cache.Get("ABC-123"); ok {
+		t.Error("expected expired page to miss")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("expected expired page file to be removed")
+	}
+}
+
+func TestDetailCacheKey(t *testing.T) {
+	tests := map[string]string{
+		BaseURL + "/abc-123":                  "ABC-123",
+		BaseURL + "/dm1/en/abc-123?ref=x":     "ABC-123",
+		BaseURL + "/abc-123-uncensored-leak/": "ABC-123-UNCENSORED-LEAK",
+	}
+	for url, want := range tests {
+		if got := detailCacheKey(url); got != want {
+			t.Errorf("detailCacheKey(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestCrawlDetail_ServesFromCache(t *testing.T) {
+	c := &HTTPCrawler{parser: NewParser(), config: DefaultCrawlerConfig(), detailCache: newMemoryPageCache(time.Hour, 10)}
+	detailURL := BaseURL + "/abc-123"
+	c.storeDetail(detailURL, `<html><head><title>ABC-123 Test Title</title></head><body></body></html>`)
+
+	result := &CrawlResult{}
+	video, err := c.crawlDetail(context.Background(), BaseURL+"/ABC-123", result)
+	if err != nil {
+		t.Fatalf("crawlDetail() error = %v", err)
+	}
+	if video == nil || result.CacheHits != 1 || result.HTTPFetches != 0 || result.BrowserFetches != 0 {
+		t.Errorf("video = %v, result = %+v", video, result)
+	}
+	if video != nil && video.Source != model.SourceMissAV {
+		t.Errorf("video.Source = %q, want %q", video.Source, model.SourceMissAV)
+	}
+	if len(result.Pages) != 1 || result.Pages[0].Method != FetchCache {
+		t.Errorf("pages = %+v, want a single cache page", result.Pages)
+	}
+}
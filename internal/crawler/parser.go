@@ -1,17 +1,21 @@
 package crawler
 
 import (
+	"context"
 	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
-	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/logctx"
 	"github.com/user/missav-bot-go/internal/model"
 )
 
 const (
 	BaseURL = "https://missav.ai"
+
+	// maxScreenshots caps the preview gallery images kept per video
+	maxScreenshots = 20
 )
 
 var (
@@ -19,6 +23,8 @@ var (
 	codePattern = regexp.MustCompile(`(?i)([A-Z]+-\d+)`)
 	// DURATION_PATTERN matches duration in minutes like "120分" or "120 分"
 	durationPattern = regexp.MustCompile(`(\d+)\s*分`)
+	// imagePattern matches links to image files
+	imagePattern = regexp.MustCompile(`(?i)\.(jpe?g|png|webp)(\?.*)?$`)
 )
 
 // Parser handles HTML parsing for video data extraction
@@ -30,7 +36,8 @@ func NewParser() *Parser {
 }
 
 // ParseVideoList parses HTML and extracts a list of videos from a listing page
-func (p *Parser) ParseVideoList(html string) ([]*model.Video, error) {
+// Progress is logged with the correlation IDs of ctx.
+func (p *Parser) ParseVideoList(ctx context.Context, html string) ([]*model.Video, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
 	if err != nil {
 		return nil, err
@@ -40,12 +47,12 @@ func (p *Parser) ParseVideoList(html string) ([]*model.Video, error) {
 
 	// Log page title for debugging
 	title := doc.Find("title").Text()
-	log.Info().Str("pageTitle", title).Int("htmlLen", len(html)).Msg("Parsing video list")
+	logctx.From(ctx).Info().Str("pageTitle", title).Int("htmlLen", len(html)).Msg("Parsing video list")
 
 	// Try to extract from JSON in script tags first (for client-rendered pages)
 	jsonVideos := p.extractVideosFromJSON(doc)
 	if len(jsonVideos) > 0 {
-		log.Info().Int("count", len(jsonVideos)).Msg("Extracted videos from JSON")
+		logctx.From(ctx).Info().Int("count", len(jsonVideos)).Msg("Extracted videos from JSON")
 		return jsonVideos, nil
 	}
 
@@ -62,7 +69,7 @@ func (p *Parser) ParseVideoList(html string) ([]*model.Video, error) {
 	for _, selector := range selectors {
 		cards := doc.Find(selector)
 		if cards.Length() > 0 {
-			log.Info().Str("selector", selector).Int("count", cards.Length()).Msg("Found video cards")
+			logctx.From(ctx).Info().Str("selector", selector).Int("count", cards.Length()).Msg("Found video cards")
 			videoCards = cards
 			break
 		}
@@ -70,7 +77,7 @@ func (p *Parser) ParseVideoList(html string) ([]*model.Video, error) {
 
 	// If no cards found, try to find links with video codes
 	if videoCards == nil || videoCards.Length() == 0 {
-		log.Info().Msg("No video cards found, trying to find links with video codes")
+		logctx.From(ctx).Info().Msg("No video cards found, trying to find links with video codes")
 		doc.Find("a[href]").Each(func(i int, s *goquery.Selection) {
 			href, exists := s.Attr("href")
 			if !exists {
@@ -83,7 +90,7 @@ func (p *Parser) ParseVideoList(html string) ([]*model.Video, error) {
 				}
 			}
 		})
-		log.Info().Int("count", len(videos)).Msg("Found videos from links")
+		logctx.From(ctx).Info().Int("count", len(videos)).Msg("Found videos from links")
 		return videos, nil
 	}
 
@@ -95,7 +102,7 @@ func (p *Parser) ParseVideoList(html string) ([]*model.Video, error) {
 		}
 	})
 
-	log.Info().Int("count", len(videos)).Msg("Parsed videos from cards")
+	logctx.From(ctx).Info().Int("count", len(videos)).Msg("Parsed videos from cards")
 	return videos, nil
 }
 
@@ -170,6 +177,9 @@ func (p *Parser) ParseVideoDetail(html string, detailURL string) (*model.Video,
 		video.PreviewURL = p.extractPreviewFromScripts(doc)
 	}
 
+	// Extract preview gallery
+	video.Screenshots = p.extractScreenshots(doc, video.CoverURL)
+
 	// Extract duration
 	durationEl := doc.Find(".duration, [class*=duration], span:contains(分钟), span:contains(分)").First()
 	if durationEl.Length() > 0 {
@@ -318,6 +328,37 @@ func (p *Parser) extractImageURL(img *goquery.Selection) string {
 	return ""
 }
 
+// extractScreenshots extracts the preview gallery image URLs of a detail page
+// Gallery links usually point at the full-size image; the img is used otherwise.
+func (p *Parser) extractScreenshots(doc *goquery.Document, coverURL string) []string {
+	seen := map[string]bool{coverURL: true}
+	var screenshots []string
+
+	doc.Find(".screenshots a, .video-screenshots a, .gallery a, [class*=screenshot] a, .screenshots img, .video-screenshots img, .gallery img, [class*=screenshot] img").Each(func(i int, s *goquery.Selection) {
+		if len(screenshots) >= maxScreenshots {
+			return
+		}
+
+		var url string
+		if goquery.NodeName(s) == "a" {
+			if href, exists := s.Attr("href"); exists && imagePattern.MatchString(href) {
+				url = p.normalizeURL(href)
+			}
+		} else if !imagePattern.MatchString(s.ParentFiltered("a").AttrOr("href", "")) {
+			// Thumbnails inside a full-size link were taken from the link
+			url = p.extractImageURL(s)
+		}
+
+		if url == "" || seen[url] {
+			return
+		}
+		seen[url] = true
+		screenshots = append(screenshots, url)
+	})
+
+	return screenshots
+}
+
 // extractVideoURL extracts video URL from a video element
 func (p *Parser) extractVideoURL(videoEl *goquery.Selection) string {
 	// Try data-src first
@@ -388,6 +429,21 @@ func ExtractCode(text string) string {
 	return ""
 }
 
+// ExtractCodes extracts every video code from text, normalized to uppercase,
+// in order of first appearance and without duplicates
+func ExtractCodes(text string) []string {
+	var codes []string
+	seen := make(map[string]bool)
+	for _, matches := range codePattern.FindAllStringSubmatch(text, -1) {
+		code := NormalizeCode(matches[1])
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
 // NormalizeCode normalizes a video code to uppercase format
 func NormalizeCode(code string) string {
 	if code == "" {
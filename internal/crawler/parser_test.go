@@ -1,6 +1,8 @@
 package crawler
 
 import (
+	"context"
+	"strings"
 	"testing"
 )
 
@@ -30,6 +32,29 @@ func TestExtractCode(t *testing.T) {
 	}
 }
 
+func TestExtractCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"empty string", "", nil},
+		{"no code", "no code here", nil},
+		{"one per line", "abc-123\nDEF-456\r\nghi-789", []string{"ABC-123", "DEF-456", "GHI-789"}},
+		{"separated list", "ABC-123, def-456; https://missav.ai/ghi-789", []string{"ABC-123", "DEF-456", "GHI-789"}},
+		{"duplicates", "ABC-123 abc-123 DEF-456 ABC-123", []string{"ABC-123", "DEF-456"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ExtractCodes(tt.input)
+			if strings.Join(result, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("ExtractCodes(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestNormalizeCode(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -150,7 +175,7 @@ func TestParseVideoList(t *testing.T) {
 	</html>
 	`
 
-	videos, err := parser.ParseVideoList(html)
+	videos, err := parser.ParseVideoList(context.Background(), html)
 	if err != nil {
 		t.Fatalf("ParseVideoList failed: %v", err)
 	}
@@ -212,6 +237,47 @@ func TestParseVideoDetail(t *testing.T) {
 	}
 }
 
+func TestParseVideoDetail_Screenshots(t *testing.T) {
+	parser := NewParser()
+
+	html := `
+	<html>
+	<head>
+		<meta property="og:image" content="https://example.com/cover.jpg">
+	</head>
+	<body>
+		<h1>ABC-123 Amazing Video Title</h1>
+		<div class="screenshots">
+			<a href="https://example.com/shot-1.jpg"><img src="https://example.com/thumb-1.jpg"></a>
+			<a href="https://example.com/shot-2.jpg"><img src="https://example.com/thumb-2.jpg"></a>
+			<a href="https://example.com/shot-1.jpg"><img src="https://example.com/thumb-1.jpg"></a>
+			<img data-src="/shot-3.webp" src="data:image/gif;base64,R0lGOD">
+			<img src="https://example.com/cover.jpg">
+		</div>
+	</body>
+	</html>
+	`
+
+	video, err := parser.ParseVideoDetail(html, "https://missav.ai/abc-123")
+	if err != nil {
+		t.Fatalf("ParseVideoDetail failed: %v", err)
+	}
+
+	want := []string{
+		"https://example.com/shot-1.jpg",
+		"https://example.com/shot-2.jpg",
+		"https://missav.ai/shot-3.webp",
+	}
+	if len(video.Screenshots) != len(want) {
+		t.Fatalf("Expected screenshots %v, got %v", want, video.Screenshots)
+	}
+	for i := range want {
+		if video.Screenshots[i] != want[i] {
+			t.Errorf("Expected screenshot %d to be %s, got %s", i, want[i], video.Screenshots[i])
+		}
+	}
+}
+
 func TestParseVideoListWithJSON(t *testing.T) {
 	parser := NewParser()
 
@@ -229,7 +295,7 @@ func TestParseVideoListWithJSON(t *testing.T) {
 	</html>
 	`
 
-	videos, err := parser.ParseVideoList(html)
+	videos, err := parser.ParseVideoList(context.Background(), html)
 	if err != nil {
 		t.Fatalf("ParseVideoList failed: %v", err)
 	}
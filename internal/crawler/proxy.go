@@ -0,0 +1,218 @@
+package crawler
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// parseProxyURL parses a proxy URL and applies separately configured credentials
+// when the URL carries none, so passwords don't have to be URL-escaped
+func parseProxyURL(raw, username, password string) (*url.URL, error) {
+	proxyURL, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+	}
+	if proxyURL.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL: missing host")
+	}
+	if proxyURL.User == nil && username != "" {
+		proxyURL.User = url.UserPassword(username, password)
+	}
+	return proxyURL, nil
+}
+
+// isSOCKSProxy reports whether proxyURL points at a SOCKS5 proxy
+func isSOCKSProxy(proxyURL *url.URL) bool {
+	return proxyURL.Scheme == "socks5" || proxyURL.Scheme == "socks5h"
+}
+
+// SOCKS5 protocol constants (RFC 1928)
+const (
+	socksVersion      = 0x05
+	socksNoAuth       = 0x00
+	socksNoAcceptable = 0xff
+	socksConnect      = 0x01
+	socksAddrIPv4     = 0x01
+	socksAddrDomain   = 0x03
+	socksAddrIPv6     = 0x04
+	socksSucceeded    = 0x00
+	socksFailure      = 0x01
+	socksUnsupported  = 0x07
+)
+
+// proxyRelay is a local, unauthenticated SOCKS5 server that forwards every
+// connection through an upstream proxy that requires credentials. Chrome ignores
+// credentials in --proxy-server and can't authenticate to SOCKS5 proxies at all,
+// so the browser is pointed at the relay and the relay logs in to the real proxy.
+type proxyRelay struct {
+	listener net.Listener
+	dial     dialFunc
+	wg       sync.WaitGroup
+}
+
+// startProxyRelay starts a relay on a random loopback port that dials through upstream
+func startProxyRelay(upstream *url.URL) (*proxyRelay, error) {
+	dial, err := newProxyDialer(upstream, &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to start proxy relay: %w", err)
+	}
+
+	r := &proxyRelay{listener: listener, dial: dial}
+	r.wg.Add(1)
+	go r.serve()
+	return r, nil
+}
+
+// URL returns the proxy URL clients should use to reach the relay
+func (r *proxyRelay) URL() string {
+	return "socks5://" + r.listener.Addr().String()
+}
+
+// Close stops accepting connections; tunnels already open run until either side closes
+func (r *proxyRelay) Close() error {
+	err := r.listener.Close()
+	r.wg.Wait()
+	return err
+}
+
+func (r *proxyRelay) serve() {
+	defer r.wg.Done()
+	for {
+		conn, err := r.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Warn().Err(err).Msg("Proxy relay stopped accepting connections")
+			}
+			return
+		}
+		go r.handle(conn)
+	}
+}
+
+// handle performs the SOCKS5 handshake with the client and pipes the
+// connection to the requested address through the upstream proxy
+func (r *proxyRelay) handle(conn net.Conn) {
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(30 * time.Second))
+	addr, err := readSOCKSRequest(conn)
+	if err != nil {
+		log.Debug().Err(err).Msg("Proxy relay handshake failed")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	upstream, err := r.dial(ctx, "tcp", addr)
+	cancel()
+	if err != nil {
+		log.Debug().Err(err).Str("addr", addr).Msg("Proxy relay failed to reach upstream")
+		writeSOCKSReply(conn, socksFailure)
+		return
+	}
+	defer upstream.Close()
+
+	if err := writeSOCKSReply(conn, socksSucceeded); err != nil {
+		return
+	}
+	conn.SetDeadline(time.Time{})
+
+	done := make(chan struct{}, 2)
+	go func() { io.Copy(upstream, conn); done <- struct{}{} }()
+	go func() { io.Copy(conn, upstream); done <- struct{}{} }()
+	<-done
+}
+
+// readSOCKSRequest negotiates no authentication and reads a CONNECT request,
+// returning the target host:port
+func readSOCKSRequest(conn net.Conn) (string, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return "", err
+	}
+	if header[0] != socksVersion {
+		return "", fmt.Errorf("unsupported SOCKS version %d", header[0])
+	}
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return "", err
+	}
+	method := byte(socksNoAcceptable)
+	for _, m := range methods {
+		if m == socksNoAuth {
+			method = socksNoAuth
+			break
+		}
+	}
+	if _, err := conn.Write([]byte{socksVersion, method}); err != nil {
+		return "", err
+	}
+	if method == socksNoAcceptable {
+		return "", fmt.Errorf("client offered no acceptable auth method")
+	}
+
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return "", err
+	}
+	if request[1] != socksConnect {
+		writeSOCKSReply(conn, socksUnsupported)
+		return "", fmt.Errorf("unsupported SOCKS command %d", request[1])
+	}
+
+	var host string
+	switch request[3] {
+	case socksAddrIPv4, socksAddrIPv6:
+		size := net.IPv4len
+		if request[3] == socksAddrIPv6 {
+			size = net.IPv6len
+		}
+		ip := make([]byte, size)
+		if _, err := io.ReadFull(conn, ip); err != nil {
+			return "", err
+		}
+		host = net.IP(ip).String()
+	case socksAddrDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(conn, length); err != nil {
+			return "", err
+		}
+		domain := make([]byte, length[0])
+		if _, err := io.ReadFull(conn, domain); err != nil {
+			return "", err
+		}
+		host = string(domain)
+	default:
+		return "", fmt.Errorf("unsupported SOCKS address type %d", request[3])
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return "", err
+	}
+	return net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port)))), nil
+}
+
+// writeSOCKSReply sends a reply with an unspecified bound address
+func writeSOCKSReply(conn net.Conn, status byte) error {
+	_, err := conn.Write([]byte{socksVersion, status, 0x00, socksAddrIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
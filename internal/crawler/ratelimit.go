@@ -0,0 +1,135 @@
+package crawler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// maxRetryAfter caps how long a single Retry-After hint can stall a fetch
+	maxRetryAfter = 2 * time.Minute
+	// defaultRateLimitWindow is how long we report being rate limited when no Retry-After is sent
+	defaultRateLimitWindow = time.Minute
+)
+
+var (
+	crawlRateLimitedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "missav_bot_crawl_rate_limited_total",
+		Help: "Total number of responses where the site asked us to back off, by status code",
+	}, []string{"status"})
+
+	crawlRateLimited = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "missav_bot_crawl_rate_limited",
+		Help: "Whether the site is currently rate limiting the crawler (1) or not (0)",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(crawlRateLimitedTotal)
+	prometheus.MustRegister(crawlRateLimited)
+}
+
+// RateLimitReporter is implemented by crawlers that track whether the site is rate limiting them
+type RateLimitReporter interface {
+	// RateLimitedUntil returns when the site's last back-off request expires; zero if never limited
+	RateLimitedUntil() time.Time
+}
+
+// LimiterReporter is implemented by crawlers that pace their requests with a token bucket
+type LimiterReporter interface {
+	// LimiterStatus returns the request rate per second and how long the next request would wait
+	LimiterStatus() (limit float64, wait time.Duration)
+}
+
+// limiterWait returns how long a request made at now would wait for a token of l
+// The reservation is cancelled right away, which hands its token back.
+func limiterWait(l *rate.Limiter, now time.Time) time.Duration {
+	r := l.ReserveN(now, 1)
+	if !r.OK() {
+		return 0
+	}
+	wait := r.DelayFrom(now)
+	r.CancelAt(now)
+	return wait
+}
+
+// rateLimitError is returned for 429 and 503 responses, carrying the server's back-off hint
+type rateLimitError struct {
+	StatusCode int
+	// RetryAfter is the wait requested by the Retry-After header (0 when absent)
+	RetryAfter time.Duration
+	// Ray is the Cloudflare request ID, useful when reporting blocks
+	Ray string
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("HTTP status %d (rate limited)", e.StatusCode)
+}
+
+// isRateLimitStatus reports whether a status code asks the client to slow down
+func isRateLimitStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil && date.After(now) {
+		return date.Sub(now)
+	}
+	return 0
+}
+
+// rateLimitState remembers until when the site asked us to back off
+type rateLimitState struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// limit records a back-off request from the site
+func (s *rateLimitState) limit(e *rateLimitError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wait := e.RetryAfter
+	if wait <= 0 {
+		wait = defaultRateLimitWindow
+	}
+	if until := time.Now().Add(min(wait, maxRetryAfter)); until.After(s.until) {
+		s.until = until
+	}
+	crawlRateLimitedTotal.WithLabelValues(strconv.Itoa(e.StatusCode)).Inc()
+	crawlRateLimited.Set(1)
+}
+
+// clear marks the site as serving us again after a successful response
+func (s *rateLimitState) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.until.After(time.Now()) {
+		s.until = time.Now()
+	}
+	crawlRateLimited.Set(0)
+}
+
+// Until returns when the last back-off request expires
+func (s *rateLimitState) Until() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.until
+}
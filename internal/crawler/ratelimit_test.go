@@ -0,0 +1,83 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"30", 30 * time.Second},
+		{"-5", 0},
+		{"Mon, 01 Jan 2024 12:01:30 GMT", 90 * time.Second},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFetch_RateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "45")
+		w.Header().Set("Cf-Ray", "8a1b2c3d4e5f-NRT")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	c, err := NewHTTPCrawler(DefaultCrawlerConfig())
+	if err != nil {
+		t.Fatalf("NewHTTPCrawler() error = %v", err)
+	}
+
+	_, err = c.fetch(context.Background(), server.URL)
+	var limited *rateLimitError
+	if !errors.As(err, &limited) {
+		t.Fatalf("fetch() error = %v, want rateLimitError", err)
+	}
+	if limited.RetryAfter != 45*time.Second || limited.Ray != "8a1b2c3d4e5f-NRT" {
+		t.Errorf("rateLimitError = %+v", limited)
+	}
+
+	until := time.Until(c.RateLimitedUntil())
+	if until < 40*time.Second || until > 45*time.Second {
+		t.Errorf("RateLimitedUntil() in %v, want ~45s", until)
+	}
+
+	c.rateLimit.clear()
+	if c.RateLimitedUntil().After(time.Now()) {
+		t.Error("successful response did not clear the rate limit")
+	}
+}
+
+func TestLimiterWait_ReturnsToken(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	limiter := rate.NewLimiter(rate.Limit(2), 1)
+
+	if wait := limiterWait(limiter, now); wait != 0 {
+		t.Fatalf("limiterWait() on a full bucket = %v, want 0", wait)
+	}
+	// Peeking must not use up the token
+	if !limiter.AllowN(now, 1) {
+		t.Fatal("token was not handed back after limiterWait()")
+	}
+	if wait := limiterWait(limiter, now); wait != 500*time.Millisecond {
+		t.Errorf("limiterWait() on an empty bucket = %v, want 500ms", wait)
+	}
+}
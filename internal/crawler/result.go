@@ -0,0 +1,108 @@
+package crawler
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// FetchMethod identifies how a page was fetched
+type FetchMethod string
+
+const (
+	FetchHTTP    FetchMethod = "http"
+	FetchBrowser FetchMethod = "browser"
+	FetchCache   FetchMethod = "cache"
+)
+
+var (
+	crawlPagesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "missav_bot_crawl_pages_total",
+		Help: "Total number of crawled pages by fetch method and status",
+	}, []string{"method", "status"})
+
+	crawlPageDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "missav_bot_crawl_page_duration_seconds",
+		Help:    "Duration of crawling a single page in seconds",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 8),
+	}, []string{"method"})
+
+	crawlParseFailuresTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "missav_bot_crawl_parse_failures_total",
+		Help: "Total number of fetched pages that could not be parsed",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(crawlPagesTotal)
+	prometheus.MustRegister(crawlPageDurationSeconds)
+	prometheus.MustRegister(crawlParseFailuresTotal)
+}
+
+// PageStat describes the crawl of a single page
+type PageStat struct {
+	URL string `json:"url"`
+	// Method is the fetch method that produced the final outcome
+	Method   FetchMethod   `json:"method"`
+	Videos   int           `json:"videos"`
+	Duration time.Duration `json:"duration"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// CrawlResult holds the videos found by a crawl and how they were obtained
+// Crawler methods return a result even when they fail, describing the work done so far.
+type CrawlResult struct {
+	Videos []*model.Video `json:"-"`
+	// PagesFetched counts pages whose content was retrieved
+	PagesFetched int `json:"pagesFetched"`
+	// HTTPFetches and BrowserFetches count fetch attempts, including fallbacks
+	HTTPFetches    int `json:"httpFetches"`
+	BrowserFetches int `json:"browserFetches"`
+	// ParseFailures counts fetched pages that could not be parsed
+	ParseFailures int        `json:"parseFailures"`
+	Pages         []PageStat `json:"pages"`
+	// CacheHits counts pages served from the detail page cache
+	CacheHits int `json:"cacheHits"`
+}
+
+// addPage records the outcome of a crawled page
+func (r *CrawlResult) addPage(stat PageStat) {
+	r.Pages = append(r.Pages, stat)
+
+	status := "success"
+	if stat.Error != "" {
+		status = "failed"
+	}
+	crawlPagesTotal.WithLabelValues(string(stat.Method), status).Inc()
+	crawlPageDurationSeconds.WithLabelValues(string(stat.Method)).Observe(stat.Duration.Seconds())
+}
+
+// addParseFailure records a fetched page that could not be parsed
+func (r *CrawlResult) addParseFailure() {
+	r.ParseFailures++
+	crawlParseFailuresTotal.Inc()
+}
+
+// Merge adds the videos and statistics of another crawl to the result
+func (r *CrawlResult) Merge(other *CrawlResult) {
+	r.Videos = append(r.Videos, other.Videos...)
+	r.PagesFetched += other.PagesFetched
+	r.HTTPFetches += other.HTTPFetches
+	r.BrowserFetches += other.BrowserFetches
+	r.ParseFailures += other.ParseFailures
+	r.CacheHits += other.CacheHits
+	r.Pages = append(r.Pages, other.Pages...)
+}
+
+// Fill copies the crawl statistics into a crawl run record; a nil result leaves it untouched
+func (r *CrawlResult) Fill(run *model.CrawlRun) {
+	if r == nil {
+		return
+	}
+	run.Found = len(r.Videos)
+	run.PagesFetched = r.PagesFetched
+	run.HTTPFetches = r.HTTPFetches
+	run.BrowserFetches = r.BrowserFetches
+	run.ParseFailures = r.ParseFailures
+}
@@ -0,0 +1,50 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestCrawlResult_Fill(t *testing.T) {
+	result := &CrawlResult{
+		Videos:         []*model.Video{{Code: "ABC-123"}, {Code: "ABC-124"}},
+		PagesFetched:   2,
+		HTTPFetches:    1,
+		BrowserFetches: 3,
+		ParseFailures:  1,
+	}
+	result.addPage(PageStat{URL: "https://example.com", Method: FetchBrowser, Duration: time.Second})
+
+	run := &model.CrawlRun{}
+	result.Fill(run)
+
+	if run.Found != 2 || run.PagesFetched != 2 || run.HTTPFetches != 1 || run.BrowserFetches != 3 || run.ParseFailures != 1 {
+		t.Errorf("Fill() = %+v", run)
+	}
+	if len(result.Pages) != 1 {
+		t.Errorf("pages = %d, want 1", len(result.Pages))
+	}
+}
+
+func TestParseListPage_CountsFetches(t *testing.T) {
+	c := &HTTPCrawler{parser: NewParser()}
+	result := &CrawlResult{}
+
+	if _, err := c.parseListPage(context.Background(), "", errors.New("fetch failed"), result); err == nil {
+		t.Error("expected fetch error to be returned")
+	}
+	if result.PagesFetched != 0 {
+		t.Errorf("PagesFetched = %d after failed fetch, want 0", result.PagesFetched)
+	}
+
+	if _, err := c.parseListPage(context.Background(), "<html><body></body></html>", nil, result); err != nil {
+		t.Fatalf("parseListPage() error = %v", err)
+	}
+	if result.PagesFetched != 1 || result.ParseFailures != 0 {
+		t.Errorf("result = %+v, want one fetched page without failures", result)
+	}
+}
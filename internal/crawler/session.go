@@ -0,0 +1,117 @@
+package crawler
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/rs/zerolog/log"
+)
+
+// clearanceCookie is the cookie Cloudflare issues once its challenge has been passed
+const clearanceCookie = "cf_clearance"
+
+// browserSession is a Cloudflare clearance obtained by the headless browser
+// The clearance is bound to the user agent that solved the challenge, so HTTP
+// requests must present the same user agent for the cookies to be accepted.
+type browserSession struct {
+	userAgent string
+	expires   time.Time
+}
+
+// convertBrowserCookies converts cookies captured from the browser into HTTP cookies
+func convertBrowserCookies(cookies []*proto.NetworkCookie) []*http.Cookie {
+	converted := make([]*http.Cookie, 0, len(cookies))
+	for _, cookie := range cookies {
+		if cookie == nil || cookie.Name == "" {
+			continue
+		}
+		httpCookie := &http.Cookie{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HTTPOnly,
+		}
+		// Session cookies report a non-positive expiry
+		if !cookie.Session && cookie.Expires > 0 {
+			httpCookie.Expires = cookie.Expires.Time()
+		}
+		converted = append(converted, httpCookie)
+	}
+	return converted
+}
+
+// clearanceExpiry returns when the Cloudflare clearance among cookies expires
+// Session clearances are assumed to live for cookieExpireDuration.
+func clearanceExpiry(cookies []*http.Cookie, now time.Time) (time.Time, bool) {
+	for _, cookie := range cookies {
+		if cookie.Name != clearanceCookie || cookie.Value == "" {
+			continue
+		}
+		if cookie.Expires.IsZero() {
+			return now.Add(cookieExpireDuration), true
+		}
+		if cookie.Expires.After(now) {
+			return cookie.Expires, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// adoptBrowserSession copies the cookies and user agent of a browser that passed
+// the Cloudflare challenge into the HTTP client, so later requests can skip the browser
+func (c *HTTPCrawler) adoptBrowserSession(pageURL string, page *RenderedPage) {
+	now := time.Now()
+	expires, ok := clearanceExpiry(page.Cookies, now)
+	if !ok || page.UserAgent == "" {
+		return
+	}
+
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return
+	}
+
+	c.cookieMu.Lock()
+	defer c.cookieMu.Unlock()
+
+	c.client.Jar.SetCookies(u, page.Cookies)
+	c.session = &browserSession{userAgent: page.UserAgent, expires: expires}
+	c.cookieInitTime = now
+
+	log.Info().
+		Int("cookies", len(page.Cookies)).
+		Time("expires", expires).
+		Msg("Adopted browser Cloudflare clearance for HTTP requests")
+}
+
+// hasBrowserSession reports whether a browser clearance is available for HTTP requests
+func (c *HTTPCrawler) hasBrowserSession() bool {
+	c.cookieMu.Lock()
+	defer c.cookieMu.Unlock()
+	return c.session != nil && time.Now().Before(c.session.expires)
+}
+
+// dropBrowserSession forgets the browser clearance after it stopped working
+func (c *HTTPCrawler) dropBrowserSession() {
+	c.cookieMu.Lock()
+	defer c.cookieMu.Unlock()
+	if c.session != nil {
+		log.Info().Msg("Browser clearance rejected, dropping it")
+	}
+	c.session = nil
+}
+
+// userAgent returns the user agent for HTTP requests
+// While a browser clearance is active the browser's user agent is used.
+func (c *HTTPCrawler) userAgent() string {
+	c.cookieMu.Lock()
+	defer c.cookieMu.Unlock()
+	if c.session != nil && time.Now().Before(c.session.expires) {
+		return c.session.userAgent
+	}
+	return c.config.UserAgent
+}
@@ -0,0 +1,152 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestConvertBrowserCookies(t *testing.T) {
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	cookies := convertBrowserCookies([]*proto.NetworkCookie{
+		{Name: clearanceCookie, Value: "abc", Domain: ".missav.ai", Path: "/", Expires: proto.TimeSinceEpoch(expires.Unix()), Secure: true, HTTPOnly: true},
+		{Name: "session", Value: "xyz", Domain: "missav.ai", Path: "/", Expires: -1, Session: true},
+		{Name: ""},
+		nil,
+	})
+
+	if len(cookies) != 2 {
+		t.Fatalf("converted %d cookies, want 2", len(cookies))
+	}
+	if c := cookies[0]; c.Name != clearanceCookie || c.Value != "abc" || !c.Secure || !c.HttpOnly || !c.Expires.Equal(expires) {
+		t.Errorf("clearance cookie = %+v", c)
+	}
+	if c := cookies[1]; !c.Expires.IsZero() {
+		t.Errorf("session cookie expires = %v, want zero", c.Expires)
+	}
+}
+
+func TestClearanceExpiry(t *testing.T) {
+	now := time.Now()
+
+	if _, ok := clearanceExpiry([]*http.Cookie{{Name: "session", Value: "x"}}, now); ok {
+		t.Error("expected no clearance without cf_clearance cookie")
+	}
+	if _, ok := clearanceExpiry([]*http.Cookie{{Name: clearanceCookie, Value: "x", Expires: now.Add(-time.Minute)}}, now); ok {
+		t.Error("expected expired clearance to be ignored")
+	}
+	if exp, ok := clearanceExpiry([]*http.Cookie{{Name: clearanceCookie, Value: "x"}}, now); !ok || !exp.Equal(now.Add(cookieExpireDuration)) {
+		t.Errorf("session clearance expiry = %v, %v", exp, ok)
+	}
+	want := now.Add(time.Hour)
+	if exp, ok := clearanceExpiry([]*http.Cookie{{Name: clearanceCookie, Value: "x", Expires: want}}, now); !ok || !exp.Equal(want) {
+		t.Errorf("clearance expiry = %v, want %v", exp, want)
+	}
+}
+
+func TestAdoptBrowserSession(t *testing.T) {
+	c, err := NewHTTPCrawler(DefaultCrawlerConfig())
+	if err != nil {
+		t.Fatalf("NewHTTPCrawler() error = %v", err)
+	}
+
+	// Pages without a clearance leave the HTTP client untouched
+	c.adoptBrowserSession(BaseURL+"/new", &RenderedPage{UserAgent: browserUserAgent})
+	if c.hasBrowserSession() || c.userAgent() != c.config.UserAgent {
+		t.Fatal("session adopted without clearance cookie")
+	}
+
+	c.adoptBrowserSession(BaseURL+"/new", &RenderedPage{
+		Cookies:   []*http.Cookie{{Name: clearanceCookie, Value: "abc", Path: "/"}},
+		UserAgent: "solver-agent",
+	})
+	if !c.hasBrowserSession() {
+		t.Fatal("expected browser session after clearance")
+	}
+	if ua := c.userAgent(); ua != "solver-agent" {
+		t.Errorf("userAgent() = %q, want browser user agent", ua)
+	}
+
+	u, _ := url.Parse(BaseURL + "/dm1/en/abc-123")
+	found := false
+	for _, cookie := range c.client.Jar.Cookies(u) {
+		if cookie.Name == clearanceCookie && cookie.Value == "abc" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("clearance cookie not copied into the HTTP cookie jar")
+	}
+
+	c.dropBrowserSession()
+	if c.hasBrowserSession() || c.userAgent() != c.config.UserAgent {
+		t.Error("expected config user agent after dropping session")
+	}
+}
+
+func TestCookiesValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		age    time.Duration
+		within time.Duration
+		want   bool
+	}{
+		{"never initialized", 0, 0, false},
+		{"fresh", time.Minute, 0, true},
+		{"expired", cookieExpireDuration, 0, false},
+		{"expires before next refresh", 6 * time.Minute, 5 * time.Minute, false},
+		{"outlives next refresh", 2 * time.Minute, 5 * time.Minute, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var initTime time.Time
+			if tt.age > 0 {
+				initTime = time.Now().Add(-tt.age)
+			}
+			if got := cookiesValid(initTime, tt.within); got != tt.want {
+				t.Errorf("cookiesValid(age %v, within %v) = %v, want %v", tt.age, tt.within, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWarmSession_Cancelled(t *testing.T) {
+	c, err := NewHTTPCrawler(DefaultCrawlerConfig())
+	if err != nil {
+		t.Fatalf("NewHTTPCrawler() error = %v", err)
+	}
+
+	// Cancelled during the first request's pacing delay, before anything is sent
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	c.WarmSession(ctx, 0)
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("WarmSession() returned %v after cancellation, want promptly", elapsed-50*time.Millisecond)
+	}
+	if !c.cookieInitTime.IsZero() {
+		t.Error("cancelled warm-up marked the session as initialized")
+	}
+}
+
+func TestSleepContext(t *testing.T) {
+	if err := sleepContext(context.Background(), time.Millisecond); err != nil {
+		t.Errorf("sleepContext() = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	if err := sleepContext(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("sleepContext() = %v, want context.Canceled", err)
+	}
+	if time.Since(start) > time.Second {
+		t.Error("sleepContext() ignored the cancelled context")
+	}
+}
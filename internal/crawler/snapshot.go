@@ -0,0 +1,88 @@
+package crawler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Reasons a failed browser render is snapshotted
+const (
+	snapshotSelectorTimeout = "selector_timeout"
+	snapshotNoVideos        = "no_videos"
+	snapshotChallenge       = "challenge"
+)
+
+// snapshotStore keeps the HTML and a screenshot of failed browser renders on disk
+// so an admin can see what Cloudflare or the page actually displayed. Only the
+// newest keep snapshots are retained.
+type snapshotStore struct {
+	dir  string
+	keep int
+
+	mu  sync.Mutex
+	seq int // keeps names unique and ordered within the same millisecond
+}
+
+// newSnapshotStore creates a snapshot store under dir
+func newSnapshotStore(dir string, keep int) (*snapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+	return &snapshotStore{dir: dir, keep: keep}, nil
+}
+
+// Save writes rendered as <name>.html and, if captured, <name>.png and returns the path prefix
+func (s *snapshotStore) Save(pageURL, reason string, rendered *RenderedPage) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq = (s.seq + 1) % 10000
+	sum := sha256.Sum256([]byte(pageURL))
+	name := fmt.Sprintf("%s-%04d-%s-%s", time.Now().Format("20060102-150405.000"), s.seq, reason, hex.EncodeToString(sum[:4]))
+	base := filepath.Join(s.dir, name)
+
+	// Record the URL in the snapshot itself; the file name only carries a hash
+	html := fmt.Sprintf("<!-- %s -->\n%s", pageURL, rendered.HTML)
+	if err := os.WriteFile(base+".html", []byte(html), 0o644); err != nil {
+		return "", fmt.Errorf("failed to write HTML snapshot: %w", err)
+	}
+	if len(rendered.Screenshot) > 0 {
+		if err := os.WriteFile(base+".png", rendered.Screenshot, 0o644); err != nil {
+			return "", fmt.Errorf("failed to write screenshot: %w", err)
+		}
+	}
+
+	s.prune()
+	return base, nil
+}
+
+// prune removes the oldest snapshots beyond the retention limit; callers hold s.mu
+func (s *snapshotStore) prune() {
+	if s.keep <= 0 {
+		return
+	}
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*.html"))
+	if err != nil || len(matches) <= s.keep {
+		return
+	}
+
+	// Names start with a timestamp, so lexical order is chronological
+	sort.Strings(matches)
+	for _, path := range matches[:len(matches)-s.keep] {
+		base := strings.TrimSuffix(path, ".html")
+		for _, file := range []string{base + ".html", base + ".png"} {
+			if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+				log.Warn().Err(err).Str("path", file).Msg("Failed to remove old snapshot")
+			}
+		}
+	}
+}
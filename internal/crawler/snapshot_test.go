@@ -0,0 +1,46 @@
+package crawler
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotStore_SaveAndPrune(t *testing.T) {
+	dir := t.TempDir()
+	store, err := newSnapshotStore(dir, 2)
+	if err != nil {
+		t.Fatalf("newSnapshotStore() error = %v", err)
+	}
+
+	rendered := &RenderedPage{HTML: "<title>Just a moment...</title>", Screenshot: []byte("png")}
+	var paths []string
+	for i := 0; i < 3; i++ {
+		path, err := store.Save(BaseURL+"/new", snapshotChallenge, rendered)
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		paths = append(paths, path)
+	}
+
+	html, err := os.ReadFile(paths[2] + ".html")
+	if err != nil {
+		t.Fatalf("reading HTML snapshot: %v", err)
+	}
+	if !strings.Contains(string(html), BaseURL+"/new") || !strings.Contains(string(html), rendered.HTML) {
+		t.Errorf("HTML snapshot = %q", html)
+	}
+	if _, err := os.Stat(paths[2] + ".png"); err != nil {
+		t.Errorf("screenshot missing: %v", err)
+	}
+
+	// Only the newest two snapshots are kept
+	files, _ := filepath.Glob(filepath.Join(dir, "*"))
+	if len(files) != 4 {
+		t.Errorf("snapshot files = %d, want 4", len(files))
+	}
+	if _, err := os.Stat(paths[0] + ".html"); !os.IsNotExist(err) {
+		t.Error("oldest snapshot was not pruned")
+	}
+}
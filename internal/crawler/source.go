@@ -0,0 +1,267 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// Source is a site videos are crawled from
+// Every video a source returns carries its Name in Video.Source.
+type Source interface {
+	Crawler
+
+	// Name identifies the source, e.g. model.SourceMissAV
+	Name() string
+
+	// Owns reports whether a detail URL belongs to this source
+	Owns(detailURL string) bool
+}
+
+// SourceFactory creates a source from the shared crawler configuration
+type SourceFactory func(cfg *CrawlerConfig) (Source, error)
+
+var (
+	sourcesMu sync.RWMutex
+	sources   = make(map[string]SourceFactory)
+)
+
+func init() {
+	RegisterSource(model.SourceMissAV, func(cfg *CrawlerConfig) (Source, error) {
+		return NewHTTPCrawler(cfg)
+	})
+}
+
+// RegisterSource makes a source available by name
+// It panics if a source with the same name is already registered.
+func RegisterSource(name string, factory SourceFactory) {
+	sourcesMu.Lock()
+	defer sourcesMu.Unlock()
+
+	if _, exists := sources[name]; exists {
+		panic("crawler: source registered twice: " + name)
+	}
+	sources[name] = factory
+}
+
+// RegisteredSources returns the names of all registered sources, sorted
+func RegisteredSources() []string {
+	sourcesMu.RLock()
+	defer sourcesMu.RUnlock()
+
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewSource creates the registered source called name
+func NewSource(name string, cfg *CrawlerConfig) (Source, error) {
+	sourcesMu.RLock()
+	factory, ok := sources[name]
+	sourcesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown crawler source %q (available: %s)", name, strings.Join(RegisteredSources(), ", "))
+	}
+	source, err := factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source %s: %w", name, err)
+	}
+	return source, nil
+}
+
+// NewCrawler creates a crawler over the named sources; the first is the primary source
+// A single source is returned as is, several are combined into a MultiCrawler.
+func NewCrawler(cfg *CrawlerConfig, names []string) (Crawler, error) {
+	if len(names) == 0 {
+		names = []string{model.SourceMissAV}
+	}
+
+	var created []Source
+	for _, name := range names {
+		source, err := NewSource(name, cfg)
+		if err != nil {
+			for _, s := range created {
+				s.Close()
+			}
+			return nil, err
+		}
+		created = append(created, source)
+	}
+
+	if len(created) == 1 {
+		return created[0], nil
+	}
+	m := NewMultiCrawler(created...)
+	m.SetMergePriority(cfg.MergePriority)
+	return m, nil
+}
+
+// MultiCrawler crawls several sources as one Crawler
+// The primary source decides whether a crawl fails; errors of the other sources
+// are logged and their results left out, so a broken metadata site never blocks crawling.
+// Videos several sources found are merged into one (see mergeVideos).
+// Budget, rate limit, browser health and session are those of the primary source.
+type MultiCrawler struct {
+	sources  []Source
+	priority MergePriority
+}
+
+// NewMultiCrawler combines sources; the first is the primary source
+func NewMultiCrawler(sources ...Source) *MultiCrawler {
+	return &MultiCrawler{sources: sources}
+}
+
+// SetMergePriority sets which sources fields of merged videos are taken from first
+func (m *MultiCrawler) SetMergePriority(priority MergePriority) {
+	m.priority = priority
+}
+
+// Sources returns the combined sources, primary first
+func (m *MultiCrawler) Sources() []Source {
+	return m.sources
+}
+
+// crawlAll runs crawl against every source and merges the results
+func (m *MultiCrawler) crawlAll(ctx context.Context, crawl func(Source) (*CrawlResult, error)) (*CrawlResult, error) {
+	merged := &CrawlResult{}
+	var primaryErr error
+
+	for i, source := range m.sources {
+		result, err := crawl(source)
+		if result != nil {
+			merged.Merge(result)
+		}
+		if err == nil {
+			continue
+		}
+		if i == 0 {
+			primaryErr = err
+			continue
+		}
+		logctx.From(ctx).Warn().Err(err).Str("source", source.Name()).Msg("Secondary source crawl failed")
+	}
+
+	names := make([]string, len(m.sources))
+	for i, source := range m.sources {
+		names[i] = source.Name()
+	}
+	merged.Videos = mergeVideos(merged.Videos, names, m.priority)
+	return merged, primaryErr
+}
+
+// CrawlNewVideos crawls the latest videos of every source
+func (m *MultiCrawler) CrawlNewVideos(ctx context.Context, pages int) (*CrawlResult, error) {
+	return m.crawlAll(ctx, func(s Source) (*CrawlResult, error) {
+		return s.CrawlNewVideos(ctx, pages)
+	})
+}
+
+// CrawlVideoDetail crawls a detail URL with the source that owns it, or the primary source
+func (m *MultiCrawler) CrawlVideoDetail(ctx context.Context, detailURL string) (*model.Video, error) {
+	for _, source := range m.sources {
+		if source.Owns(detailURL) {
+			return source.CrawlVideoDetail(ctx, detailURL)
+		}
+	}
+	return m.sources[0].CrawlVideoDetail(ctx, detailURL)
+}
+
+// CrawlByActor crawls videos by actor name on every source
+func (m *MultiCrawler) CrawlByActor(ctx context.Context, actorName string, limit int) (*CrawlResult, error) {
+	return m.crawlAll(ctx, func(s Source) (*CrawlResult, error) {
+		return s.CrawlByActor(ctx, actorName, limit)
+	})
+}
+
+// CrawlByCode crawls a video by its code on every source
+func (m *MultiCrawler) CrawlByCode(ctx context.Context, code string) (*CrawlResult, error) {
+	return m.crawlAll(ctx, func(s Source) (*CrawlResult, error) {
+		return s.CrawlByCode(ctx, code)
+	})
+}
+
+// CrawlByKeyword searches every source by keyword
+func (m *MultiCrawler) CrawlByKeyword(ctx context.Context, keyword string, limit int) (*CrawlResult, error) {
+	return m.crawlAll(ctx, func(s Source) (*CrawlResult, error) {
+		return s.CrawlByKeyword(ctx, keyword, limit)
+	})
+}
+
+// CrawlByTag crawls videos listed under a tag on every source
+func (m *MultiCrawler) CrawlByTag(ctx context.Context, tag string, limit int) (*CrawlResult, error) {
+	return m.crawlAll(ctx, func(s Source) (*CrawlResult, error) {
+		return s.CrawlByTag(ctx, tag, limit)
+	})
+}
+
+// Close releases the resources of every source
+func (m *MultiCrawler) Close() error {
+	var errs []error
+	for _, source := range m.sources {
+		if err := source.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", source.Name(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// BudgetUsage reports the daily budget of the primary source
+func (m *MultiCrawler) BudgetUsage() (used, limit int) {
+	if reporter, ok := m.sources[0].(BudgetReporter); ok {
+		return reporter.BudgetUsage()
+	}
+	return 0, 0
+}
+
+// RateLimitedUntil reports when the primary source stops throttling
+func (m *MultiCrawler) RateLimitedUntil() time.Time {
+	if reporter, ok := m.sources[0].(RateLimitReporter); ok {
+		return reporter.RateLimitedUntil()
+	}
+	return time.Time{}
+}
+
+// LimiterStatus reports the request rate limiter of the primary source
+func (m *MultiCrawler) LimiterStatus() (limit float64, wait time.Duration) {
+	if reporter, ok := m.sources[0].(LimiterReporter); ok {
+		return reporter.LimiterStatus()
+	}
+	return 0, 0
+}
+
+// WarmSession keeps the session of the primary source warm
+func (m *MultiCrawler) WarmSession(ctx context.Context, within time.Duration) {
+	if warmer, ok := m.sources[0].(SessionWarmer); ok {
+		warmer.WarmSession(ctx, within)
+	}
+}
+
+// BrowserHealth reports the headless browser of the primary source
+func (m *MultiCrawler) BrowserHealth() BrowserHealth {
+	if reporter, ok := m.sources[0].(BrowserHealthReporter); ok {
+		return reporter.BrowserHealth()
+	}
+	return BrowserHealth{}
+}
+
+// ownsHost reports whether rawURL is on host or one of its subdomains
+func ownsHost(rawURL, host string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	h := strings.ToLower(u.Hostname())
+	return h == host || strings.HasSuffix(h, "."+host)
+}
@@ -0,0 +1,132 @@
+package crawler
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// fakeSource is a Source returning fixed results
+type fakeSource struct {
+	name   string
+	host   string
+	videos []*model.Video
+	err    error
+	closed bool
+}
+
+func (f *fakeSource) Name() string               { return f.name }
+func (f *fakeSource) Owns(detailURL string) bool { return ownsHost(detailURL, f.host) }
+func (f *fakeSource) Close() error               { f.closed = true; return nil }
+
+func (f *fakeSource) result() (*CrawlResult, error) {
+	return &CrawlResult{Videos: f.videos, PagesFetched: 1}, f.err
+}
+
+func (f *fakeSource) CrawlNewVideos(ctx context.Context, pages int) (*CrawlResult, error) {
+	return f.result()
+}
+
+func (f *fakeSource) CrawlVideoDetail(ctx context.Context, detailURL string) (*model.Video, error) {
+	return &model.Video{Source: f.name, DetailURL: detailURL}, f.err
+}
+
+func (f *fakeSource) CrawlByActor(ctx context.Context, actorName string, limit int) (*CrawlResult, error) {
+	return f.result()
+}
+
+func (f *fakeSource) CrawlByCode(ctx context.Context, code string) (*CrawlResult, error) {
+	return f.result()
+}
+
+func (f *fakeSource) CrawlByKeyword(ctx context.Context, keyword string, limit int) (*CrawlResult, error) {
+	return f.result()
+}
+
+func (f *fakeSource) CrawlByTag(ctx context.Context, tag string, limit int) (*CrawlResult, error) {
+	return f.result()
+}
+
+func (f *fakeSource) RateLimitedUntil() time.Time {
+	return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNewSource(t *testing.T) {
+	source, err := NewSource(model.SourceMissAV, DefaultCrawlerConfig())
+	if err != nil {
+		t.Fatalf("NewSource() error = %v", err)
+	}
+	defer source.Close()
+	if source.Name() != model.SourceMissAV {
+		t.Errorf("Name() = %q, want %q", source.Name(), model.SourceMissAV)
+	}
+	if !source.Owns(BaseURL+"/abc-123") || source.Owns("https://example.com/abc-123") {
+		t.Error("Owns() did not match the crawled site")
+	}
+
+	_, err = NewSource("nosuchsite", DefaultCrawlerConfig())
+	if err == nil || !strings.Contains(err.Error(), model.SourceMissAV) {
+		t.Errorf("NewSource(unknown) error = %v, want one listing available sources", err)
+	}
+}
+
+func TestNewCrawler_SingleSourceUnwrapped(t *testing.T) {
+	c, err := NewCrawler(DefaultCrawlerConfig(), nil)
+	if err != nil {
+		t.Fatalf("NewCrawler() error = %v", err)
+	}
+	defer c.Close()
+	if _, ok := c.(*HTTPCrawler); !ok {
+		t.Errorf("NewCrawler() = %T, want *HTTPCrawler", c)
+	}
+}
+
+func TestMultiCrawler_MergesSources(t *testing.T) {
+	primary := &fakeSource{name: "a", host: "a.example", videos: []*model.Video{{Code: "ABC-123", Source: "a"}}}
+	secondary := &fakeSource{name: "b", host: "b.example", videos: []*model.Video{{Code: "DEF-456", Source: "b"}}}
+	m := NewMultiCrawler(primary, secondary)
+
+	result, err := m.CrawlNewVideos(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("CrawlNewVideos() error = %v", err)
+	}
+	if len(result.Videos) != 2 || result.PagesFetched != 2 {
+		t.Errorf("result = %+v, want videos and pages of both sources", result)
+	}
+
+	video, err := m.CrawlVideoDetail(context.Background(), "https://b.example/abc-123")
+	if err != nil || video.Source != "b" {
+		t.Errorf("CrawlVideoDetail() = %+v, %v; want the owning source", video, err)
+	}
+	video, _ = m.CrawlVideoDetail(context.Background(), "https://unknown.example/abc-123")
+	if video.Source != "a" {
+		t.Errorf("CrawlVideoDetail(unowned) source = %q, want primary", video.Source)
+	}
+
+	if got := m.RateLimitedUntil(); !got.Equal(primary.RateLimitedUntil()) {
+		t.Errorf("RateLimitedUntil() = %v, want the primary source's", got)
+	}
+
+	if err := m.Close(); err != nil || !primary.closed || !secondary.closed {
+		t.Errorf("Close() = %v, closed = %v/%v", err, primary.closed, secondary.closed)
+	}
+}
+
+func TestMultiCrawler_SecondaryErrorIgnored(t *testing.T) {
+	primary := &fakeSource{name: "a", videos: []*model.Video{{Code: "ABC-123"}}}
+	secondary := &fakeSource{name: "b", err: errors.New("down")}
+
+	result, err := NewMultiCrawler(primary, secondary).CrawlByCode(context.Background(), "ABC-123")
+	if err != nil || len(result.Videos) != 1 {
+		t.Errorf("CrawlByCode() = %+v, %v; want the primary result", result, err)
+	}
+
+	primary.err = errors.New("blocked")
+	if _, err := NewMultiCrawler(primary, secondary).CrawlByCode(context.Background(), "ABC-123"); !errors.Is(err, primary.err) {
+		t.Errorf("CrawlByCode() error = %v, want the primary error", err)
+	}
+}
@@ -0,0 +1,50 @@
+// Package logctx carries correlation IDs through contexts into log entries
+// A crawl run and each push delivery get an ID, so one video's path from crawl to
+// delivery can be followed by grepping the logs for it.
+package logctx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+// Log fields of the correlation IDs
+const (
+	// RunID identifies a crawl cycle and the saving and matching it triggers
+	RunID = "runID"
+	// PushID identifies the delivery of one message to one chat
+	PushID = "pushID"
+)
+
+// loggerKey is the context key of the logger carrying a context's correlation IDs
+type loggerKey struct{}
+
+// NewID returns a random 16 character correlation ID
+func NewID() string {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "0000000000000000"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// With returns a context whose logger adds the correlation ID id as the field key
+// IDs added by outer contexts are kept.
+func With(ctx context.Context, key, id string) context.Context {
+	logger := From(ctx).With().Str(key, id).Logger()
+	return context.WithValue(ctx, loggerKey{}, &logger)
+}
+
+// From returns the logger of ctx, or the global logger if ctx carries no correlation IDs
+func From(ctx context.Context) *zerolog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerKey{}).(*zerolog.Logger); ok {
+			return logger
+		}
+	}
+	return &log.Logger
+}
@@ -0,0 +1,43 @@
+package logctx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+)
+
+func TestWith_AddsCorrelationIDs(t *testing.T) {
+	var buf bytes.Buffer
+	original := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = original }()
+
+	ctx := With(context.Background(), RunID, "run1")
+	ctx = With(ctx, PushID, "push1")
+	From(ctx).Info().Msg("delivered")
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("failed to decode log entry %q: %v", buf.String(), err)
+	}
+	if entry[RunID] != "run1" || entry[PushID] != "push1" {
+		t.Errorf("log entry = %v, want both correlation IDs", entry)
+	}
+}
+
+func TestFrom_FallsBackToGlobalLogger(t *testing.T) {
+	if From(context.Background()) != &log.Logger {
+		t.Error("From() without correlation IDs should return the global logger")
+	}
+}
+
+func TestNewID(t *testing.T) {
+	a, b := NewID(), NewID()
+	if len(a) != 16 || a == b {
+		t.Errorf("NewID() = %q, %q; want distinct 16 character IDs", a, b)
+	}
+}
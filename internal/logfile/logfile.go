@@ -0,0 +1,189 @@
+// Package logfile writes logs to a file that is rotated by size and age
+package logfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat stamps rotated files; it sorts chronologically as a string
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// Options controls when a log file is rotated and how many rotated files are kept
+type Options struct {
+	// MaxSize rotates the file before a write would make it larger, in bytes (0 disables)
+	MaxSize int64
+	// MaxAge rotates the file once it has been written to for this long (0 disables)
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files kept (0 keeps all)
+	MaxBackups int
+}
+
+// Writer appends to a log file, rotating it by size and age
+// Rotated files are renamed to name-<timestamp>.ext next to the log file.
+// It is safe for concurrent use.
+type Writer struct {
+	mu       sync.Mutex
+	path     string
+	opts     Options
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// Open opens or creates the log file at path, appending to an existing one
+// An existing file counts its age from its last modification.
+func Open(path string, opts Options) (*Writer, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	w := &Writer{path: path, opts: opts, now: time.Now}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// open opens the log file for appending and records its size and age
+func (w *Writer) open() error {
+	file, err := os.OpenFile(w.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	w.file = file
+	w.size = info.Size()
+	w.openedAt = w.now()
+	if w.size > 0 {
+		w.openedAt = info.ModTime()
+	}
+	return nil
+}
+
+// Write appends p to the log file, rotating it first when it is due
+func (w *Writer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return 0, os.ErrClosed
+	}
+	if w.rotationDue(int64(len(p))) {
+		if err := w.rotate(); err != nil {
+			if w.file == nil {
+				return 0, err
+			}
+			// The log cannot record its own failures, so they go to stderr
+			fmt.Fprintf(os.Stderr, "logfile: %v\n", err)
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotationDue reports whether the file must be rotated before writing n more bytes
+// An empty file is never rotated, so a single oversized entry is still written.
+func (w *Writer) rotationDue(n int64) bool {
+	if w.size == 0 {
+		return false
+	}
+	if w.opts.MaxSize > 0 && w.size+n > w.opts.MaxSize {
+		return true
+	}
+	return w.opts.MaxAge > 0 && w.now().Sub(w.openedAt) >= w.opts.MaxAge
+}
+
+// rotate renames the current file to a timestamped backup, starts a new one and
+// removes the backups beyond MaxBackups
+// When the rename fails the current file is reopened, so logging carries on unrotated.
+// The file is only left closed when it cannot be reopened.
+func (w *Writer) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	w.file = nil
+
+	if err := os.Rename(w.path, w.backupName(w.now())); err != nil {
+		if openErr := w.open(); openErr != nil {
+			return openErr
+		}
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+	if err := w.open(); err != nil {
+		return err
+	}
+	if err := w.removeOldBackups(); err != nil {
+		fmt.Fprintf(os.Stderr, "logfile: %v\n", err)
+	}
+	return nil
+}
+
+// backupName returns the name a file rotated at t is renamed to
+func (w *Writer) backupName(t time.Time) string {
+	ext := filepath.Ext(w.path)
+	return strings.TrimSuffix(w.path, ext) + "-" + t.Format(backupTimeFormat) + ext
+}
+
+// backups returns the rotated files of the log, oldest first
+func (w *Writer) backups() ([]string, error) {
+	ext := filepath.Ext(w.path)
+	prefix := strings.TrimSuffix(w.path, ext) + "-"
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, prefix), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// removeOldBackups deletes the oldest rotated files beyond MaxBackups
+func (w *Writer) removeOldBackups() error {
+	if w.opts.MaxBackups <= 0 {
+		return nil
+	}
+	backups, err := w.backups()
+	if err != nil {
+		return fmt.Errorf("failed to list log backups: %w", err)
+	}
+	for len(backups) > w.opts.MaxBackups {
+		if err := os.Remove(backups[0]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove log backup: %w", err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
+
+// Close closes the log file; later writes fail
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
@@ -0,0 +1,137 @@
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// readFile returns the contents of a file or fails the test
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
+
+func TestWriter_RotatesBySize(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	w, err := Open(path, Options{MaxSize: 10})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer w.Close()
+
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return clock }
+
+	w.Write([]byte("first\n"))
+	w.Write([]byte("second\n"))
+
+	if got := readFile(t, path); got != "second\n" {
+		t.Errorf("log file = %q, want %q", got, "second\n")
+	}
+	backups, _ := w.backups()
+	if len(backups) != 1 || readFile(t, backups[0]) != "first\n" {
+		t.Fatalf("backups = %v, want one holding the first entry", backups)
+	}
+	if want := filepath.Join(filepath.Dir(path), "bot-2024-01-01T12-00-00.000.log"); backups[0] != want {
+		t.Errorf("backup name = %s, want %s", backups[0], want)
+	}
+}
+
+func TestWriter_RotatesByAge(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	w, err := Open(path, Options{MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer w.Close()
+	w.now = func() time.Time { return clock }
+	w.openedAt = clock
+
+	w.Write([]byte("morning\n"))
+	clock = clock.Add(30 * time.Minute)
+	w.Write([]byte("noon\n"))
+	if backups, _ := w.backups(); len(backups) != 0 {
+		t.Fatalf("rotated before MaxAge: %v", backups)
+	}
+
+	clock = clock.Add(time.Hour)
+	w.Write([]byte("evening\n"))
+	if got := readFile(t, path); got != "evening\n" {
+		t.Errorf("log file = %q, want %q", got, "evening\n")
+	}
+	if backups, _ := w.backups(); len(backups) != 1 {
+		t.Errorf("backups = %v, want 1", backups)
+	}
+}
+
+func TestWriter_KeepsMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bot.log")
+	w, err := Open(path, Options{MaxSize: 1, MaxBackups: 2})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer w.Close()
+
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time {
+		clock = clock.Add(time.Second)
+		return clock
+	}
+	for _, entry := range []string{"a", "b", "c", "d", "e"} {
+		w.Write([]byte(entry))
+	}
+
+	backups, _ := w.backups()
+	if len(backups) != 2 {
+		t.Fatalf("backups = %v, want 2", backups)
+	}
+	if readFile(t, backups[0]) != "c" || readFile(t, backups[1]) != "d" {
+		t.Errorf("kept backups %q and %q, want the newest c and d", readFile(t, backups[0]), readFile(t, backups[1]))
+	}
+
+	// Files that only look like backups are left alone
+	other := filepath.Join(dir, "bot-notes.log")
+	os.WriteFile(other, nil, 0o644)
+	w.Write([]byte("f"))
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("unrelated file was removed: %v", err)
+	}
+}
+
+func TestWriter_KeepsWritingWhenRotationFails(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.log")
+	w, err := Open(path, Options{MaxSize: 10})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer w.Close()
+
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return clock }
+
+	// A non-empty directory at the backup name makes the rename fail
+	blocked := w.backupName(clock)
+	if err := os.MkdirAll(filepath.Join(blocked, "keep"), 0o755); err != nil {
+		t.Fatalf("failed to block backup name: %v", err)
+	}
+
+	w.Write([]byte("first\n"))
+	if _, err := w.Write([]byte("second\n")); err != nil {
+		t.Fatalf("Write() after failed rotation error = %v", err)
+	}
+	if _, err := w.Write([]byte("third\n")); err != nil {
+		t.Fatalf("later Write() error = %v", err)
+	}
+
+	if got, want := readFile(t, path), "first\nsecond\nthird\n"; got != want {
+		t.Errorf("log file = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// ActressAlias is an alternative spelling of an actress name used for search,
+// e.g. generated pinyin or romaji, or a manually added English name
+type ActressAlias struct {
+	ID         uint   `gorm:"primaryKey"`
+	Name       string `gorm:"size:100;not null;uniqueIndex:idx_alias_name_normalized"`
+	Alias      string `gorm:"size:200;not null"`
+	Normalized string `gorm:"size:200;not null;uniqueIndex:idx_alias_name_normalized;index"`
+	Kind       string `gorm:"size:20;not null"`
+	CreatedAt  time.Time
+}
+
+// TableName returns the table name for ActressAlias
+func (ActressAlias) TableName() string {
+	return "actress_aliases"
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// BannedCode keeps a release deleted by an admin from being saved by later crawls
+// Code is canonical, so every re-listing and variant of the release is banned.
+type BannedCode struct {
+	Code string `gorm:"primaryKey;size:50"`
+	// BannedBy is the Telegram user ID of the admin who deleted the release
+	BannedBy  int64 `gorm:"not null;default:0"`
+	CreatedAt time.Time
+}
+
+// TableName returns the table name for BannedCode
+func (BannedCode) TableName() string {
+	return "banned_codes"
+}
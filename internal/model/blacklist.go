@@ -0,0 +1,44 @@
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+// BlacklistType defines what a blacklist entry matches against
+type BlacklistType string
+
+const (
+	BlacklistTag     BlacklistType = "TAG"
+	BlacklistActress BlacklistType = "ACTRESS"
+)
+
+// BlacklistEntry excludes videos with a tag or actress from a chat's pushes
+// Entries apply regardless of the chat's subscriptions.
+type BlacklistEntry struct {
+	ID        uint          `gorm:"primaryKey;autoIncrement"`
+	ChatID    int64         `gorm:"uniqueIndex:idx_blacklist_entry;not null"`
+	Type      BlacklistType `gorm:"uniqueIndex:idx_blacklist_entry;size:20;not null"`
+	Keyword   string        `gorm:"uniqueIndex:idx_blacklist_entry;size:100;not null"`
+	CreatedAt time.Time
+}
+
+// TableName returns the table name for BlacklistEntry
+func (BlacklistEntry) TableName() string {
+	return "chat_blacklists"
+}
+
+// Matches reports whether the video has the entry's tag or actress (case-insensitive),
+// using the same substring match as subscriptions
+func (e *BlacklistEntry) Matches(video *Video) bool {
+	var field string
+	switch e.Type {
+	case BlacklistTag:
+		field = video.Tags
+	case BlacklistActress:
+		field = video.Actresses
+	default:
+		return false
+	}
+	return e.Keyword != "" && strings.Contains(strings.ToLower(field), strings.ToLower(e.Keyword))
+}
@@ -0,0 +1,18 @@
+package model
+
+import (
+	"time"
+)
+
+// BotState is the polling progress of a Telegram bot, kept across restarts
+type BotState struct {
+	BotID int64 `gorm:"primaryKey;autoIncrement:false"`
+	// UpdateOffset is the ID of the next Telegram update to process
+	UpdateOffset int `gorm:"not null;default:0"`
+	UpdatedAt    time.Time
+}
+
+// TableName returns the table name for BotState
+func (BotState) TableName() string {
+	return "bot_states"
+}
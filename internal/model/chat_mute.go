@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+// ChatMute suppresses pushes of a release to a chat
+// Code is canonical, so every record and re-listing of the release is muted.
+type ChatMute struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	ChatID    int64  `gorm:"uniqueIndex:idx_chat_mute;not null"`
+	Code      string `gorm:"uniqueIndex:idx_chat_mute;size:50;not null"`
+	CreatedAt time.Time
+}
+
+// TableName returns the table name for ChatMute
+func (ChatMute) TableName() string {
+	return "chat_mutes"
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"time"
+)
+
+// ChatSettings holds per-chat bot preferences
+// Chats without a row use the zero value defaults.
+type ChatSettings struct {
+	ChatID int64 `gorm:"primaryKey;autoIncrement:false"`
+	// AdminOnly restricts subscription management in groups to Telegram group admins
+	AdminOnly bool `gorm:"not null;default:false"`
+	// PushMode chooses between one message per video and combined list messages
+	PushMode PushMode `gorm:"size:20;not null;default:''"`
+	// ParseMode chooses the markup of pushed messages; empty uses PUSH_PARSE_MODE
+	ParseMode ParseMode `gorm:"size:20;not null;default:''"`
+	// DMResults sends /search and /latest results asked for in a group to the requester privately
+	DMResults bool `gorm:"not null;default:false"`
+	// AutoDeleteSeconds deletes the bot's /search, /latest and error replies in groups after this long; 0 keeps them
+	AutoDeleteSeconds int `gorm:"not null;default:0"`
+	// SearchLimit and LatestPageSize override BOT_SEARCH_LIMIT and BOT_LATEST_PAGE_SIZE; 0 uses them
+	SearchLimit    int `gorm:"not null;default:0"`
+	LatestPageSize int `gorm:"not null;default:0"`
+	// SkipVR leaves VR releases out of pushes to the chat
+	SkipVR bool `gorm:"not null;default:false"`
+	// MinDuration leaves videos shorter than this many minutes out of pushes; 0 pushes all.
+	// Videos of unknown duration are always pushed.
+	MinDuration int `gorm:"not null;default:0"`
+	// WeeklyRecap opts the chat into a weekly summary of the videos pushed to it
+	WeeklyRecap bool `gorm:"not null;default:false;index"`
+	// RecapSentAt is when the last weekly recap was sent, or the recap enabled
+	RecapSentAt *time.Time
+	UpdatedAt   time.Time
+}
+
+// ExcludesFormat reports whether the chat leaves a video out of pushes for its
+// format: a VR release with SkipVR, or a video shorter than MinDuration
+func (c *ChatSettings) ExcludesFormat(video *Video) bool {
+	if c.SkipVR && video.VR {
+		return true
+	}
+	return c.MinDuration > 0 && video.Duration > 0 && video.Duration < c.MinDuration
+}
+
+// PushMode defines how new videos are delivered to a chat
+type PushMode string
+
+const (
+	// PushModeAuto sends one message per video, combining them when a delivery brings many
+	PushModeAuto PushMode = ""
+	// PushModeSingle always sends one message per video
+	PushModeSingle PushMode = "single"
+	// PushModeBatch combines every delivery of several videos into list messages
+	PushModeBatch PushMode = "batch"
+)
+
+// ParseMode defines the markup Telegram messages are formatted with
+type ParseMode string
+
+const (
+	// ParseModeDefault uses the configured default format
+	ParseModeDefault ParseMode = ""
+	// ParseModeMarkdown formats messages as MarkdownV2
+	ParseModeMarkdown ParseMode = "markdown"
+	// ParseModeHTML formats messages as HTML, which needs far less escaping
+	ParseModeHTML ParseMode = "html"
+)
+
+// TableName returns the table name for ChatSettings
+func (ChatSettings) TableName() string {
+	return "chat_settings"
+}
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// CodeWatch asks for a chat to be notified once a release not yet in the database appears
+// Code is canonical; the watch is removed after the release is pushed to the chat.
+type CodeWatch struct {
+	ID        uint   `gorm:"primaryKey;autoIncrement"`
+	ChatID    int64  `gorm:"uniqueIndex:idx_code_watch;not null"`
+	Code      string `gorm:"uniqueIndex:idx_code_watch;size:50;not null;index"`
+	UserID    int64  // user who added the watch, 0 when unknown
+	CreatedAt time.Time
+}
+
+// TableName returns the table name for CodeWatch
+func (CodeWatch) TableName() string {
+	return "code_watches"
+}
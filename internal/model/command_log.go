@@ -0,0 +1,31 @@
+package model
+
+import (
+	"time"
+)
+
+// CommandOutcome describes how a bot command invocation ended
+type CommandOutcome string
+
+const (
+	CommandOutcomeOK     CommandOutcome = "OK"
+	CommandOutcomeError  CommandOutcome = "ERROR"
+	CommandOutcomeDenied CommandOutcome = "DENIED"
+	// CommandOutcomeThrottled is only exported as a metric; throttled commands are not logged
+	CommandOutcomeThrottled CommandOutcome = "THROTTLED"
+)
+
+// CommandLog records a single bot command invocation
+type CommandLog struct {
+	ID        uint           `gorm:"primaryKey"`
+	ChatID    int64          `gorm:"index;not null"`
+	Command   string         `gorm:"size:50;index;not null"`
+	Outcome   CommandOutcome `gorm:"size:20;not null"`
+	LatencyMs int64
+	CreatedAt time.Time `gorm:"index"`
+}
+
+// TableName returns the table name for CommandLog
+func (CommandLog) TableName() string {
+	return "command_logs"
+}
@@ -0,0 +1,40 @@
+package model
+
+import (
+	"time"
+)
+
+// CrawlTrigger defines what started a crawl run
+type CrawlTrigger string
+
+const (
+	CrawlTriggerScheduled CrawlTrigger = "SCHEDULED"
+	CrawlTriggerManual    CrawlTrigger = "MANUAL"
+	CrawlTriggerCommand   CrawlTrigger = "COMMAND"
+)
+
+// CrawlRun represents the outcome of a single crawl execution
+type CrawlRun struct {
+	ID         uint         `gorm:"primaryKey" json:"id"`
+	Trigger    CrawlTrigger `gorm:"size:20;not null;index" json:"trigger"`
+	Target     string       `gorm:"size:200" json:"target,omitempty"`
+	Pages      int          `json:"pages"`
+	Found      int          `json:"found"`
+	Saved      int          `json:"saved"`
+	Duplicates int          `json:"duplicates"`
+	DurationMs int64        `json:"durationMs"`
+	Error      string       `gorm:"size:500" json:"error,omitempty"`
+	StartedAt  time.Time    `gorm:"index" json:"startedAt"`
+	CreatedAt  time.Time    `json:"createdAt"`
+
+	// Fetch statistics reported by the crawler
+	PagesFetched   int `json:"pagesFetched"`
+	HTTPFetches    int `json:"httpFetches"`
+	BrowserFetches int `json:"browserFetches"`
+	ParseFailures  int `json:"parseFailures"`
+}
+
+// TableName returns the table name for CrawlRun
+func (CrawlRun) TableName() string {
+	return "crawl_runs"
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+)
+
+// DuplicateStatus is the review state of a duplicate candidate
+type DuplicateStatus string
+
+const (
+	DuplicatePending   DuplicateStatus = "PENDING"
+	DuplicateMerged    DuplicateStatus = "MERGED"
+	DuplicateDismissed DuplicateStatus = "DISMISSED"
+)
+
+// DuplicateCandidate flags two videos with different codes that look like the same release
+// While pending, the newer video is held back from pushes until an admin reviews it.
+type DuplicateCandidate struct {
+	ID uint `gorm:"primaryKey"`
+	// VideoID is the older video, kept when the pair is merged
+	VideoID   uint   `gorm:"uniqueIndex:idx_duplicate_pair;not null"`
+	VideoCode string `gorm:"size:50"`
+	// DuplicateID is the newer listing of the same release
+	DuplicateID   uint            `gorm:"uniqueIndex:idx_duplicate_pair;not null;index"`
+	DuplicateCode string          `gorm:"size:50"`
+	Similarity    float64         `gorm:"not null"`
+	Status        DuplicateStatus `gorm:"size:20;not null;default:PENDING;index"`
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// TableName returns the table name for DuplicateCandidate
+func (DuplicateCandidate) TableName() string {
+	return "duplicate_candidates"
+}
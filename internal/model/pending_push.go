@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+)
+
+// PendingPush represents a queued delivery of a video to a chat (the push outbox)
+// Rows are created when a video is matched and removed once delivery completes
+type PendingPush struct {
+	ID        uint   `gorm:"primaryKey"`
+	VideoID   uint   `gorm:"uniqueIndex:idx_pending_video_chat;not null"`
+	ChatID    int64  `gorm:"uniqueIndex:idx_pending_video_chat;not null"`
+	Attempts  int    `gorm:"default:0"`
+	Platform  string `gorm:"size:20"`            // Empty means Telegram
+	Target    string `gorm:"size:500"`           // Platform address for non-Telegram chats
+	BotID     int64  `gorm:"not null;default:0"` // Telegram bot delivering the push; 0 means the primary bot
+	Priority  int    `gorm:"not null;default:0"` // Higher priorities are delivered first
+	Mentions  string `gorm:"size:500"`           // Comma-separated IDs of users to mention with the push
+	Video     *Video `gorm:"foreignKey:VideoID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Delivery priorities of queued pushes
+const (
+	// PushPriorityGeneral is the priority of pushes matched by ALL subscriptions or the default chat
+	PushPriorityGeneral = 0
+	// PushPrioritySpecific is the priority of pushes matched by ACTRESS or TAG subscriptions,
+	// delivered before the fan-out to ALL subscribers
+	PushPrioritySpecific = 1
+)
+
+// TableName returns the table name for PendingPush
+func (PendingPush) TableName() string {
+	return "pending_pushes"
+}
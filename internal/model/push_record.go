@@ -10,18 +10,40 @@ type PushStatus string
 const (
 	PushStatusSuccess PushStatus = "SUCCESS"
 	PushStatusFailed  PushStatus = "FAILED"
+	// PushStatusCapped marks a match held back by the chat's daily push cap
+	PushStatusCapped PushStatus = "CAPPED"
+	// PushStatusCappedReported marks a capped match already counted in a summary message
+	PushStatusCappedReported PushStatus = "CAPPED_REPORTED"
+)
+
+// MessageType describes the Telegram message a push was delivered as
+type MessageType string
+
+const (
+	// MessageTypeText is a plain MarkdownV2 text message
+	MessageTypeText MessageType = "text"
+	// MessageTypeMedia is a photo, video or album whose first item carries the caption
+	MessageTypeMedia MessageType = "media"
+	// MessageTypeList is a combined message listing several videos
+	MessageTypeList MessageType = "list"
 )
 
 // PushRecord represents a record of a video push to a chat
 type PushRecord struct {
-	ID         uint       `gorm:"primaryKey"`
-	VideoID    uint       `gorm:"index;not null"`
-	ChatID     int64      `gorm:"index;not null"`
-	Status     PushStatus `gorm:"size:20;not null"`
-	FailReason string     `gorm:"size:500"`
-	MessageID  int
-	PushedAt   time.Time
-	CreatedAt  time.Time
+	ID          uint       `gorm:"primaryKey"`
+	VideoID     uint       `gorm:"not null;index:idx_push_records_video_chat_status,priority:1"`
+	Code        string     `gorm:"size:50;index"` // Canonical video code
+	ChatID      int64      `gorm:"index;not null;index:idx_push_records_video_chat_status,priority:2"`
+	Status      PushStatus `gorm:"size:20;not null;index:idx_push_records_status_pushed_at;index:idx_push_records_video_chat_status,priority:3"`
+	FailReason  string     `gorm:"size:500"`
+	MessageID   int
+	MessageType MessageType `gorm:"size:10"`
+	// MessageCount is the number of messages sent, more than one for albums
+	// whose items have consecutive IDs starting at MessageID
+	MessageCount int
+	BotID        int64     `gorm:"not null;default:0"` // Telegram bot that sent the message
+	PushedAt     time.Time `gorm:"index:idx_push_records_status_pushed_at"`
+	CreatedAt    time.Time `gorm:"index"`
 }
 
 // TableName returns the table name for PushRecord
@@ -13,6 +13,24 @@ const (
 	SubTypeTag     SubscriptionType = "TAG"
 )
 
+// Notification platforms a subscription can deliver to
+const (
+	PlatformTelegram = "telegram"
+	PlatformDiscord  = "discord"
+)
+
+// PersonalMode defines how a personal subscription in a group reaches its subscriber
+type PersonalMode string
+
+const (
+	// PersonalNone subscribes the whole chat
+	PersonalNone PersonalMode = ""
+	// PersonalMention posts matches in the group and mentions the subscriber
+	PersonalMention PersonalMode = "mention"
+	// PersonalDM sends matches to the subscriber's private chat with the bot
+	PersonalDM PersonalMode = "dm"
+)
+
 // Subscription represents a user's subscription to video updates
 type Subscription struct {
 	ID        uint             `gorm:"primaryKey"`
@@ -21,10 +39,28 @@ type Subscription struct {
 	Type      SubscriptionType `gorm:"size:20;not null"`
 	Keyword   string           `gorm:"size:100"`
 	Enabled   bool             `gorm:"default:true"`
+	Platform  string           `gorm:"size:20"`                     // Notifier platform; empty means Telegram
+	Target    string           `gorm:"size:500"`                    // Platform address for non-Telegram chats, e.g. a Discord webhook URL
+	BotID     int64            `gorm:"not null;default:0"`          // Telegram bot serving the chat; 0 means the primary bot
+	UserID    int64            `gorm:"index;not null;default:0"`    // Subscriber of a personal subscription; 0 subscribes the whole chat
+	Personal  PersonalMode     `gorm:"size:10;not null;default:''"` // How a personal subscription is delivered
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
 
+// NotifyPlatform returns the platform the subscription delivers to
+func (s *Subscription) NotifyPlatform() string {
+	if s.Platform == "" {
+		return PlatformTelegram
+	}
+	return s.Platform
+}
+
+// IsPersonal reports whether the subscription belongs to one user of the chat
+func (s *Subscription) IsPersonal() bool {
+	return s.UserID != 0 && s.Personal != PersonalNone
+}
+
 // TableName returns the table name for Subscription
 func (Subscription) TableName() string {
 	return "subscriptions"
@@ -0,0 +1,20 @@
+package model
+
+import (
+	"time"
+)
+
+// User is a Telegram user the bot has seen sending messages
+// In private chats the user ID is also the chat ID.
+type User struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement:false"`
+	Username     string    `gorm:"size:100"`
+	LanguageCode string    `gorm:"size:20"`
+	FirstSeenAt  time.Time `gorm:"not null"`
+	LastActiveAt time.Time `gorm:"index;not null"`
+}
+
+// TableName returns the table name for User
+func (User) TableName() string {
+	return "users"
+}
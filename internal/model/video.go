@@ -1,13 +1,30 @@
 package model
 
 import (
+	"regexp"
+	"strings"
 	"time"
+
+	"gorm.io/gorm"
+)
+
+// canonicalCodePattern matches the base release code, e.g. ABC-123 in ABC-123-UNCENSORED-LEAK
+var canonicalCodePattern = regexp.MustCompile(`^([A-Z0-9]+-\d+)`)
+
+// vrTitlePattern matches a standalone VR marker in a title, e.g. 【VR】 or [8K VR], but not SIVR-123
+var vrTitlePattern = regexp.MustCompile(`(?i)(^|[^a-z])vr([^a-z]|$)`)
+
+// Sites videos are crawled from
+const (
+	SourceMissAV = "missav"
 )
 
 // Video represents a video entity with metadata
+// Codes are unique per source, so catalogs of different sites can coexist.
 type Video struct {
 	ID          uint       `gorm:"primaryKey"`
-	Code        string     `gorm:"uniqueIndex;size:50;not null"`
+	Source      string     `gorm:"uniqueIndex:idx_videos_source_code;size:20;not null;default:missav"`
+	Code        string     `gorm:"uniqueIndex:idx_videos_source_code;size:50;not null"`
 	Title       string     `gorm:"size:500"`
 	Actresses   string     `gorm:"size:500"`
 	Tags        string     `gorm:"size:500"`
@@ -16,12 +33,90 @@ type Video struct {
 	CoverURL    string     `gorm:"size:500"`
 	PreviewURL  string     `gorm:"size:500"`
 	DetailURL   string     `gorm:"size:500"`
-	Pushed      bool       `gorm:"default:false;index"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	Screenshots []string   `gorm:"serializer:json;type:json"`
+	// Provenance maps merged fields to the source they were taken from
+	Provenance map[string]string `gorm:"serializer:json;type:json"`
+	// Completeness is the number of metadata fields present, see CompletenessScore
+	Completeness int `gorm:"default:0;index"`
+	// EnrichedAt is when the detail page was last crawled to fill missing fields
+	EnrichedAt *time.Time
+	// DuplicateOf is the video this one was merged into as a re-listing of the same release
+	DuplicateOf *uint `gorm:"index"`
+	// VR and Clip flag VR releases and short clips, see DetectFormat
+	VR     bool `gorm:"default:false;index"`
+	Clip   bool `gorm:"default:false;index"`
+	Pushed bool `gorm:"default:false;index"`
+	// Hidden marks a video revoked by an admin; it is never pushed again
+	Hidden    bool `gorm:"default:false;index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// DeletedAt soft-deletes a video removed by an admin; gorm leaves it out of every query
+	DeletedAt gorm.DeletedAt `gorm:"index"`
 }
 
 // TableName returns the table name for Video
 func (Video) TableName() string {
 	return "videos"
 }
+
+// MaxCompleteness is the completeness score of a video with every scored field present
+const MaxCompleteness = 6
+
+// CompletenessScore counts the metadata fields present: title, actresses, tags,
+// cover, preview and release date
+func (v *Video) CompletenessScore() int {
+	score := 0
+	for _, present := range []bool{
+		v.Title != "",
+		v.Actresses != "",
+		v.Tags != "",
+		v.CoverURL != "",
+		v.PreviewURL != "",
+		v.ReleaseDate != nil,
+	} {
+		if present {
+			score++
+		}
+	}
+	return score
+}
+
+// ClipMaxDuration is the duration in minutes below which a video is flagged as a clip
+const ClipMaxDuration = 20
+
+// nonVRLabels are code labels that contain VR but are not VR releases
+var nonVRLabels = map[string]bool{"VRTM": true}
+
+// DetectFormat flags VR releases, recognized by a VR label in the code, a VR
+// marker in the title or a VR tag, and clips shorter than ClipMaxDuration
+// An unknown duration is not a clip.
+func (v *Video) DetectFormat() {
+	v.VR = isVRCode(v.Code) || vrTitlePattern.MatchString(v.Title) || hasVRTag(v.Tags)
+	v.Clip = v.Duration > 0 && v.Duration < ClipMaxDuration
+}
+
+// isVRCode reports whether a code's label marks VR releases, e.g. SIVR, KAVR or VRKM
+func isVRCode(code string) bool {
+	label, _, found := strings.Cut(CanonicalCode(code), "-")
+	return found && strings.Contains(label, "VR") && !nonVRLabels[label]
+}
+
+// hasVRTag reports whether comma-separated tags include a VR tag, e.g. VR or VR専用
+func hasVRTag(tags string) bool {
+	for _, tag := range strings.Split(tags, ",") {
+		if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(tag)), "VR") {
+			return true
+		}
+	}
+	return false
+}
+
+// CanonicalCode returns the canonical form of a video code: uppercase, without
+// mirror or variant suffixes, so the same release saved under different pages compares equal
+func CanonicalCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if matches := canonicalCodePattern.FindStringSubmatch(code); len(matches) > 1 {
+		return matches[1]
+	}
+	return code
+}
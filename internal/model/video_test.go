@@ -0,0 +1,107 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCanonicalCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"ABC-123", "ABC-123"},
+		{"abc-123", "ABC-123"},
+		{" ssis-001 ", "SSIS-001"},
+		{"ABC-123-UNCENSORED-LEAK", "ABC-123"},
+		{"abc-123-chinese-subtitle", "ABC-123"},
+		{"FC2-PPV-1234567", "FC2-PPV-1234567"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := CanonicalCode(tt.code); got != tt.want {
+			t.Errorf("CanonicalCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestCompletenessScore(t *testing.T) {
+	released := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		video Video
+		want  int
+	}{
+		{"empty", Video{Code: "ABC-123"}, 0},
+		{"listing card", Video{Code: "ABC-123", Title: "Title", CoverURL: "https://example.com/c.jpg"}, 2},
+		{"duration is not scored", Video{Title: "Title", Duration: 120}, 1},
+		{"complete", Video{
+			Title:       "Title",
+			Actresses:   "Actress",
+			Tags:        "Tag",
+			CoverURL:    "https://example.com/c.jpg",
+			PreviewURL:  "https://example.com/p.mp4",
+			ReleaseDate: &released,
+		}, MaxCompleteness},
+	}
+
+	for _, tt := range tests {
+		if got := tt.video.CompletenessScore(); got != tt.want {
+			t.Errorf("%s: CompletenessScore() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name  string
+		video Video
+		vr    bool
+		clip  bool
+	}{
+		{"regular", Video{Code: "ABC-123", Title: "Title", Duration: 120}, false, false},
+		{"VR label", Video{Code: "sivr-123", Duration: 120}, true, false},
+		{"VR label first", Video{Code: "VRKM-456"}, true, false},
+		{"VR lookalike label", Video{Code: "VRTM-123"}, false, false},
+		{"VR title marker", Video{Code: "ABC-123", Title: "【VR】長尺"}, true, false},
+		{"VR inside a title word", Video{Code: "ABC-123", Title: "DVR Overdrive"}, false, false},
+		{"VR tag", Video{Code: "ABC-123", Tags: "巨乳, VR専用"}, true, false},
+		{"clip", Video{Code: "ABC-123", Duration: ClipMaxDuration - 1}, false, true},
+		{"unknown duration", Video{Code: "ABC-123"}, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			video := tt.video
+			video.DetectFormat()
+			if video.VR != tt.vr || video.Clip != tt.clip {
+				t.Errorf("DetectFormat() = VR %v, Clip %v; want %v, %v", video.VR, video.Clip, tt.vr, tt.clip)
+			}
+		})
+	}
+}
+
+func TestExcludesFormat(t *testing.T) {
+	settings := ChatSettings{SkipVR: true, MinDuration: 30}
+	tests := []struct {
+		name  string
+		video Video
+		want  bool
+	}{
+		{"VR", Video{VR: true, Duration: 120}, true},
+		{"short", Video{Duration: 29}, true},
+		{"long enough", Video{Duration: 30}, false},
+		{"unknown duration", Video{}, false},
+	}
+
+	for _, tt := range tests {
+		if got := settings.ExcludesFormat(&tt.video); got != tt.want {
+			t.Errorf("%s: ExcludesFormat() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	if (&ChatSettings{}).ExcludesFormat(&Video{VR: true, Duration: 5}) {
+		t.Error("default settings excluded a video")
+	}
+}
@@ -0,0 +1,144 @@
+package push
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+// defaultBatchSize is the number of videos listed per combined message when unset
+const defaultBatchSize = 10
+
+// planDeliveries splits jobs into delivery units of one job each, except that the
+// Telegram jobs of a chat that combines pushes become units of up to BatchSize jobs
+func (s *Service) planDeliveries(ctx context.Context, jobs []pushJob) [][]pushJob {
+	byChat := make(map[int64][]pushJob)
+	for _, job := range jobs {
+		if job.target.Platform == model.PlatformTelegram {
+			byChat[job.target.ChatID] = append(byChat[job.target.ChatID], job)
+		}
+	}
+
+	var units [][]pushJob
+	batched := make(map[int64]bool)
+	for _, job := range jobs {
+		chatJobs := byChat[job.target.ChatID]
+		if job.target.Platform != model.PlatformTelegram || !s.combinesPushes(ctx, job.target.ChatID, len(chatJobs)) {
+			units = append(units, []pushJob{job})
+			continue
+		}
+		if batched[job.target.ChatID] {
+			continue
+		}
+		batched[job.target.ChatID] = true
+		for start := 0; start < len(chatJobs); start += s.config.BatchSize {
+			end := min(start+s.config.BatchSize, len(chatJobs))
+			units = append(units, chatJobs[start:end])
+		}
+	}
+	return units
+}
+
+// combinesPushes reports whether n videos delivered to a chat at once are combined
+// into list messages, following the chat's push mode
+func (s *Service) combinesPushes(ctx context.Context, chatID int64, n int) bool {
+	if n < 2 {
+		return false
+	}
+
+	settings, err := s.store.GetChatSettings(ctx, chatID)
+	if err != nil {
+		logctx.From(ctx).Warn().Err(err).Int64("chatID", chatID).Msg("Failed to get chat push mode, using default")
+		settings = &model.ChatSettings{ChatID: chatID}
+	}
+
+	switch settings.PushMode {
+	case model.PushModeSingle:
+		return false
+	case model.PushModeBatch:
+		return true
+	default:
+		return s.config.BatchThreshold > 0 && n >= s.config.BatchThreshold
+	}
+}
+
+// pushBatch delivers the videos of several jobs for one Telegram chat as a single list message
+// Videos the chat must not receive are left out and the daily cap applies per video.
+func (s *Service) pushBatch(ctx context.Context, jobs []pushJob) error {
+	target := jobs[0].target
+	chatID := target.ChatID
+	for _, job := range jobs[1:] {
+		target.Mentions = mergeMentions(target.Mentions, job.target.Mentions)
+	}
+
+	var videos []*model.Video
+	for _, job := range jobs {
+		skip, err := s.shouldSkip(ctx, job.video, chatID)
+		if err != nil {
+			return err
+		}
+		if !skip {
+			videos = append(videos, job.video)
+		}
+	}
+	if len(videos) == 0 {
+		return nil
+	}
+	if len(videos) == 1 {
+		return s.pushVideo(ctx, videos[0], target)
+	}
+
+	// Wait for per-chat rate limiter (Requirement 5.10)
+	if err := s.chatLimiter(chatID).Wait(ctx); err != nil {
+		return fmt.Errorf("chat rate limiter error: %w", err)
+	}
+
+	remaining, limited, err := s.dailyCapRemaining(ctx, target)
+	if err != nil {
+		return fmt.Errorf("failed to check daily push cap: %w", err)
+	}
+	if limited && len(videos) > remaining {
+		keep := max(remaining, 0)
+		for _, video := range videos[keep:] {
+			if err := s.recordCapped(ctx, video, chatID); err != nil {
+				return err
+			}
+		}
+		videos = videos[:keep]
+	}
+	if len(videos) == 0 {
+		return nil
+	}
+
+	// Wait for the global rate limiter of the delivering bot (Requirement 5.9)
+	bot := s.telegram.bot(target.BotID)
+	if err := bot.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limiter error: %w", err)
+	}
+
+	target.ParseMode = s.ParseMode(ctx, chatID)
+	message, parseMode := FormatBatch(videos, target.ParseMode)
+	id, sendErr := bot.client.SendText(chatID, message, parseMode)
+	sent := sentMessage{id: id, kind: model.MessageTypeList, count: 1}
+
+	// Record the videos of one message together, so a failure cannot leave part of the
+	// list unrecorded and pushed to the chat a second time
+	err = s.store.WithTx(ctx, func(tx store.Store) error {
+		for _, video := range videos {
+			if err := tx.RecordPush(ctx, newPushRecord(ctx, video, target, sent, sendErr)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Int64("chatID", chatID).Msg("Failed to record batch push")
+	}
+	if sendErr == nil {
+		s.sendMentions(ctx, target, videos)
+	}
+	return sendErr
+}
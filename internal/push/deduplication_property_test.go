@@ -2,6 +2,8 @@ package push
 
 import (
 	"context"
+	"database/sql"
+	"sort"
 	"sync"
 	"testing"
 	"time"
@@ -19,6 +21,11 @@ type MockStore struct {
 	videos        map[uint]*model.Video
 	subscriptions []*model.Subscription
 	pushRecords   []*model.PushRecord
+	pending       []*model.PendingPush
+	nextPendingID uint
+	mutes         map[int64]map[string]bool
+	blacklist     []*model.BlacklistEntry
+	settings      map[int64]*model.ChatSettings
 }
 
 func NewMockStore() *MockStore {
@@ -56,6 +63,308 @@ func (m *MockStore) GetVideoByCode(ctx context.Context, code string) (*model.Vid
 	return nil, nil
 }
 
+func (m *MockStore) UpdateVideoDetails(ctx context.Context, video *model.Video) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.videos[video.ID] = video
+	return nil
+}
+
+func (m *MockStore) GetIncompleteVideos(ctx context.Context, since time.Time, limit int) ([]*model.Video, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CountVideosByCompleteness(ctx context.Context) (map[int]int64, error) {
+	return map[int]int64{}, nil
+}
+
+func (m *MockStore) GetVideosSince(ctx context.Context, since time.Time) ([]*model.Video, error) {
+	return nil, nil
+}
+
+func (m *MockStore) SaveDuplicateCandidates(ctx context.Context, candidates []*model.DuplicateCandidate) (int, error) {
+	return len(candidates), nil
+}
+
+func (m *MockStore) GetDuplicateCandidates(ctx context.Context, status model.DuplicateStatus, limit int) ([]*model.DuplicateCandidate, error) {
+	return nil, nil
+}
+
+func (m *MockStore) ResolveDuplicateCandidate(ctx context.Context, id uint, status model.DuplicateStatus) (*model.DuplicateCandidate, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetPushHistory(ctx context.Context, chatID int64, limit int) ([]*store.PushHistoryEntry, error) {
+	return nil, nil
+}
+
+func (m *MockStore) MuteCode(ctx context.Context, chatID int64, code string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mutes == nil {
+		m.mutes = make(map[int64]map[string]bool)
+	}
+	if m.mutes[chatID] == nil {
+		m.mutes[chatID] = make(map[string]bool)
+	}
+	m.mutes[chatID][code] = true
+	return nil
+}
+
+func (m *MockStore) UnmuteCode(ctx context.Context, chatID int64, code string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	muted := m.mutes[chatID][code]
+	delete(m.mutes[chatID], code)
+	return muted, nil
+}
+
+func (m *MockStore) IsCodeMuted(ctx context.Context, code string, chatID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mutes[chatID][code], nil
+}
+
+func (m *MockStore) GetMutedCodes(ctx context.Context, chatID int64) ([]*model.ChatMute, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var mutes []*model.ChatMute
+	for code := range m.mutes[chatID] {
+		mutes = append(mutes, &model.ChatMute{ChatID: chatID, Code: code})
+	}
+	return mutes, nil
+}
+
+func (m *MockStore) AddCodeWatch(ctx context.Context, watch *model.CodeWatch) (bool, error) {
+	return true, nil
+}
+
+func (m *MockStore) RemoveCodeWatch(ctx context.Context, chatID int64, code string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockStore) GetCodeWatches(ctx context.Context, chatID int64) ([]*model.CodeWatch, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetAllCodeWatches(ctx context.Context) ([]*model.CodeWatch, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetPushHistorySince(ctx context.Context, chatID int64, since time.Time, limit int) ([]*store.PushHistoryEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var history []*store.PushHistoryEntry
+	for i := len(m.pushRecords) - 1; i >= 0 && len(history) < limit; i-- {
+		r := m.pushRecords[i]
+		if r.ChatID == chatID && r.Status == model.PushStatusSuccess && !r.PushedAt.Before(since) {
+			if video := m.videos[r.VideoID]; video != nil {
+				history = append(history, &store.PushHistoryEntry{Video: video, PushedAt: r.PushedAt})
+			}
+		}
+	}
+	return history, nil
+}
+
+func (m *MockStore) CountCappedPushesSince(ctx context.Context, chatID int64, since time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int64
+	for _, r := range m.pushRecords {
+		if r.ChatID == chatID && (r.Status == model.PushStatusCapped || r.Status == model.PushStatusCappedReported) && !r.PushedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockStore) GetRecapChats(ctx context.Context) ([]*model.ChatSettings, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var chats []*model.ChatSettings
+	for _, settings := range m.settings {
+		if settings.WeeklyRecap {
+			chats = append(chats, settings)
+		}
+	}
+	return chats, nil
+}
+
+func (m *MockStore) GetRelatedVideos(ctx context.Context, video *model.Video, limit int) ([]*model.Video, error) {
+	return nil, nil
+}
+
+func (m *MockStore) AddBlacklistEntry(ctx context.Context, entry *model.BlacklistEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.blacklist = append(m.blacklist, entry)
+	return nil
+}
+
+func (m *MockStore) DeleteBlacklistEntry(ctx context.Context, chatID int64, entryType model.BlacklistType, keyword string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, e := range m.blacklist {
+		if e.ChatID == chatID && e.Type == entryType && e.Keyword == keyword {
+			m.blacklist = append(m.blacklist[:i], m.blacklist[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockStore) GetBlacklist(ctx context.Context, chatID int64) ([]*model.BlacklistEntry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var entries []*model.BlacklistEntry
+	for _, e := range m.blacklist {
+		if e.ChatID == chatID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (m *MockStore) TouchUser(ctx context.Context, user *model.User) error {
+	return nil
+}
+
+func (m *MockStore) GetUser(ctx context.Context, userID int64) (*model.User, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetUpdateOffset(ctx context.Context, botID int64) (int, error) {
+	return 0, nil
+}
+
+func (m *MockStore) SaveUpdateOffset(ctx context.Context, botID int64, offset int) error {
+	return nil
+}
+
+func (m *MockStore) CountPushesSince(ctx context.Context, chatID int64, since time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int64
+	for _, r := range m.pushRecords {
+		if r.ChatID == chatID && r.Status == model.PushStatusSuccess && !r.PushedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockStore) ReportCappedPushes(ctx context.Context, before time.Time) (map[int64]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := make(map[int64]int64)
+	for _, r := range m.pushRecords {
+		if r.Status == model.PushStatusCapped && r.PushedAt.Before(before) {
+			r.Status = model.PushStatusCappedReported
+			counts[r.ChatID]++
+		}
+	}
+	return counts, nil
+}
+
+func (m *MockStore) GetEditablePushes(ctx context.Context, videoID uint) ([]*model.PushRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var records []*model.PushRecord
+	for _, r := range m.pushRecords {
+		if r.VideoID == videoID && r.Status == model.PushStatusSuccess && r.MessageID > 0 &&
+			(r.MessageType == model.MessageTypeText || r.MessageType == model.MessageTypeMedia) {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+func (m *MockStore) GetPushedMessages(ctx context.Context, code string) ([]*model.PushRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var records []*model.PushRecord
+	for _, r := range m.pushRecords {
+		if r.Code == code && r.Status == model.PushStatusSuccess && r.MessageID > 0 &&
+			(r.MessageType == model.MessageTypeText || r.MessageType == model.MessageTypeMedia) {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+func (m *MockStore) ClearPushMessage(ctx context.Context, recordID uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.pushRecords {
+		if r.ID == recordID {
+			r.MessageID = 0
+			r.MessageCount = 0
+		}
+	}
+	return nil
+}
+
+func (m *MockStore) HideVideos(ctx context.Context, code string) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var hidden int64
+	for _, v := range m.videos {
+		if model.CanonicalCode(v.Code) == code {
+			v.Hidden = true
+			hidden++
+		}
+	}
+	return hidden, nil
+}
+
+func (m *MockStore) DeleteVideos(ctx context.Context, code string, bannedBy int64) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) CountUnpushedVideos(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int64
+	for _, v := range m.videos {
+		if !v.Pushed && !v.Hidden {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockStore) GetPushStatsByDay(ctx context.Context, since time.Time) ([]*store.PushDayStat, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetPushStatsByChat(ctx context.Context, since time.Time, limit int) ([]*store.PushChatStat, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CountSubscriptionsByType(ctx context.Context) (map[model.SubscriptionType]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	counts := make(map[model.SubscriptionType]int64)
+	for _, sub := range m.subscriptions {
+		if sub.Enabled {
+			counts[sub.Type]++
+		}
+	}
+	return counts, nil
+}
+
+func (m *MockStore) CountSubscriptionsByChatType(ctx context.Context) ([]*store.SubscriptionCount, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CountSubscribedChats(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) WithTx(ctx context.Context, fn func(store.Store) error) error {
+	return fn(m)
+}
+
 func (m *MockStore) GetUnpushedVideos(ctx context.Context) ([]*model.Video, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -77,10 +386,76 @@ func (m *MockStore) MarkAsPushed(ctx context.Context, videoID uint) error {
 	return nil
 }
 
+func (m *MockStore) MarkAsPushedBulk(ctx context.Context, videoIDs []uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range videoIDs {
+		if v, ok := m.videos[id]; ok {
+			v.Pushed = true
+		}
+	}
+	return nil
+}
+
 func (m *MockStore) SearchVideos(ctx context.Context, keyword string, limit int) ([]*model.Video, error) {
 	return nil, nil
 }
 
+func (m *MockStore) CountVideosMatching(ctx context.Context, filter *store.VideoFilter) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) FindVideos(ctx context.Context, filter *store.VideoFilter) ([]*model.Video, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetActressNames(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetTagNames(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetPopularTags(ctx context.Context, limit int) ([]*store.TagCount, error) {
+	return nil, nil
+}
+
+func (m *MockStore) AddActressAlias(ctx context.Context, name string, alias string) error {
+	return nil
+}
+
+func (m *MockStore) GetActressAliases(ctx context.Context, name string) ([]*model.ActressAlias, error) {
+	return nil, nil
+}
+
+func (m *MockStore) RecordCommand(ctx context.Context, entry *model.CommandLog) error {
+	return nil
+}
+
+func (m *MockStore) GetCommandStats(ctx context.Context, chatID int64, since time.Time) ([]*store.CommandStat, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetChatSettings(ctx context.Context, chatID int64) (*model.ChatSettings, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if settings, ok := m.settings[chatID]; ok {
+		return settings, nil
+	}
+	return &model.ChatSettings{ChatID: chatID}, nil
+}
+
+func (m *MockStore) SaveChatSettings(ctx context.Context, settings *model.ChatSettings) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.settings == nil {
+		m.settings = make(map[int64]*model.ChatSettings)
+	}
+	m.settings[settings.ChatID] = settings
+	return nil
+}
+
 func (m *MockStore) GetLatestVideos(ctx context.Context, limit, offset int) ([]*model.Video, error) {
 	return nil, nil
 }
@@ -91,6 +466,10 @@ func (m *MockStore) CountVideos(ctx context.Context) (int64, error) {
 	return int64(len(m.videos)), nil
 }
 
+func (m *MockStore) RefreshVideoCount(ctx context.Context) (int64, error) {
+	return m.CountVideos(ctx)
+}
+
 func (m *MockStore) ExistsByCode(ctx context.Context, code string) (bool, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -109,12 +488,12 @@ func (m *MockStore) CreateSubscription(ctx context.Context, sub *model.Subscript
 	return nil
 }
 
-func (m *MockStore) DeleteSubscription(ctx context.Context, chatID int64, subType string, keyword string) error {
+func (m *MockStore) DeleteSubscription(ctx context.Context, chatID int64, userID int64, subType string, keyword string) error {
 	return nil
 }
 
-func (m *MockStore) DeleteAllSubscriptions(ctx context.Context, chatID int64) error {
-	return nil
+func (m *MockStore) DeleteAllSubscriptions(ctx context.Context, chatID int64) (int64, error) {
+	return 0, nil
 }
 
 func (m *MockStore) GetSubscriptions(ctx context.Context, chatID int64) ([]*model.Subscription, error) {
@@ -128,12 +507,23 @@ func (m *MockStore) GetAllSubscriptions(ctx context.Context) ([]*model.Subscript
 }
 
 func (m *MockStore) GetMatchingSubscriptions(ctx context.Context, video *model.Video) ([]*model.Subscription, error) {
-	return nil, nil
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var matching []*model.Subscription
+	for _, sub := range m.subscriptions {
+		if MatchesSubscription(video, sub) {
+			matching = append(matching, sub)
+		}
+	}
+	return matching, nil
 }
 
 func (m *MockStore) RecordPush(ctx context.Context, record *model.PushRecord) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	if record.ID == 0 {
+		record.ID = uint(len(m.pushRecords) + 1)
+	}
 	m.pushRecords = append(m.pushRecords, record)
 	return nil
 }
@@ -149,10 +539,139 @@ func (m *MockStore) HasPushed(ctx context.Context, videoID uint, chatID int64) (
 	return false, nil
 }
 
+func (m *MockStore) EnqueueVideoPushes(ctx context.Context, videoID uint, pushes []*model.PendingPush) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if v, ok := m.videos[videoID]; ok {
+		v.Pushed = true
+	}
+	for _, p := range pushes {
+		duplicate := false
+		for _, existing := range m.pending {
+			if existing.VideoID == p.VideoID && existing.ChatID == p.ChatID {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			m.nextPendingID++
+			p.ID = m.nextPendingID
+			m.pending = append(m.pending, p)
+		}
+	}
+	return nil
+}
+
+func (m *MockStore) GetPendingPushes(ctx context.Context, maxAttempts int, limit int) ([]*model.PendingPush, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var result []*model.PendingPush
+	for _, p := range m.pending {
+		if p.Attempts < maxAttempts {
+			result = append(result, &model.PendingPush{
+				ID:       p.ID,
+				VideoID:  p.VideoID,
+				ChatID:   p.ChatID,
+				Attempts: p.Attempts,
+				Priority: p.Priority,
+				Video:    m.videos[p.VideoID],
+			})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Priority > result[j].Priority
+	})
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result, nil
+}
+
+func (m *MockStore) CompletePendingPush(ctx context.Context, id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, p := range m.pending {
+		if p.ID == id {
+			m.pending = append(m.pending[:i], m.pending[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *MockStore) FailPendingPush(ctx context.Context, id uint, maxAttempts int) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, p := range m.pending {
+		if p.ID == id {
+			p.Attempts++
+			if p.Attempts >= maxAttempts {
+				m.pending = append(m.pending[:i], m.pending[i+1:]...)
+				return true, nil
+			}
+			break
+		}
+	}
+	return false, nil
+}
+
+func (m *MockStore) CountPendingPushes(ctx context.Context, maxAttempts int) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var count int64
+	for _, p := range m.pending {
+		if p.Attempts < maxAttempts {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockStore) RecordCrawlRun(ctx context.Context, run *model.CrawlRun) error {
+	return nil
+}
+
+func (m *MockStore) GetRecentCrawlRuns(ctx context.Context, limit int) ([]*model.CrawlRun, error) {
+	return nil, nil
+}
+
+func (m *MockStore) HasPushedCode(ctx context.Context, code string, chatID int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, r := range m.pushRecords {
+		if r.Code == code && r.ChatID == chatID && r.Status == model.PushStatusSuccess {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockStore) FilterUnpushedChats(ctx context.Context, videoID uint, code string, chatIDs []int64) ([]int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pushed := make(map[int64]bool)
+	for _, r := range m.pushRecords {
+		if (r.VideoID == videoID || r.Code == code) && r.Status == model.PushStatusSuccess {
+			pushed[r.ChatID] = true
+		}
+	}
+	var unpushed []int64
+	for _, id := range chatIDs {
+		if !pushed[id] {
+			unpushed = append(unpushed, id)
+		}
+	}
+	return unpushed, nil
+}
+
 func (m *MockStore) Ping(ctx context.Context) error {
 	return nil
 }
 
+func (m *MockStore) DBStats() sql.DBStats {
+	return sql.DBStats{}
+}
+
 func (m *MockStore) Close() error {
 	return nil
 }
@@ -173,7 +692,10 @@ func (m *MockStore) CountSuccessPushes(videoID uint, chatID int64) int {
 // MockTelegramClient implements TelegramClient for testing
 type MockTelegramClient struct {
 	mu       sync.Mutex
-	messages []string
+	messages   []string
+	parseModes []string
+	edits      []string
+	deleted    []int
 }
 
 func NewMockTelegramClient() *MockTelegramClient {
@@ -189,24 +711,56 @@ func (m *MockTelegramClient) SendMessage(chatID int64, text string) error {
 	return nil
 }
 
-func (m *MockTelegramClient) SendMarkdown(chatID int64, text string) error {
+func (m *MockTelegramClient) SendText(chatID int64, text string, parseMode string) (int, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.messages = append(m.messages, text)
-	return nil
+	m.parseModes = append(m.parseModes, parseMode)
+	return len(m.messages), nil
 }
 
-func (m *MockTelegramClient) SendPhoto(chatID int64, photoURL string, caption string) error {
+func (m *MockTelegramClient) SendPhoto(chatID int64, photoURL string, caption string, parseMode string) (int, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.messages = append(m.messages, caption)
-	return nil
+	m.parseModes = append(m.parseModes, parseMode)
+	return len(m.messages), nil
 }
 
-func (m *MockTelegramClient) SendVideo(chatID int64, videoURL string, thumbURL string, caption string) error {
+func (m *MockTelegramClient) SendVideo(chatID int64, videoURL string, thumbURL string, caption string, parseMode string) (int, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.messages = append(m.messages, caption)
+	m.parseModes = append(m.parseModes, parseMode)
+	return len(m.messages), nil
+}
+
+func (m *MockTelegramClient) SendMediaGroup(chatID int64, videoURL string, photoURLs []string, caption string, parseMode string) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.messages = append(m.messages, caption)
+	m.parseModes = append(m.parseModes, parseMode)
+	return len(m.messages), nil
+}
+
+func (m *MockTelegramClient) EditText(chatID int64, messageID int, text string, parseMode string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.edits = append(m.edits, text)
+	return nil
+}
+
+func (m *MockTelegramClient) EditCaption(chatID int64, messageID int, caption string, parseMode string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.edits = append(m.edits, caption)
+	return nil
+}
+
+func (m *MockTelegramClient) DeleteMessage(chatID int64, messageID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deleted = append(m.deleted, messageID)
 	return nil
 }
 
@@ -391,6 +945,40 @@ func TestProperty_PushDeduplication(t *testing.T) {
 	properties.TestingRun(t)
 }
 
+// Property 10b: Canonical Code Deduplication
+// *For any* two video records of the same release (different IDs, same canonical code),
+// a chat SHALL receive at most one SUCCESS push for that release.
+func TestProperty_CanonicalCodeDeduplication(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+	properties := gopter.NewProperties(parameters)
+
+	suffixGen := gen.OneConstOf("", "-UNCENSORED-LEAK", "-CHINESE-SUBTITLE")
+
+	properties.Property("mirrors of one release are pushed once per chat", prop.ForAll(
+		func(chatID int64, suffix string) bool {
+			mockStore := NewMockStore()
+			service := NewService(mockStore, NewMockTelegramClient())
+			ctx := context.Background()
+
+			original := &model.Video{ID: 1, Code: "MIRR-001", DetailURL: "https://example.com/mirr-001"}
+			mirror := &model.Video{ID: 2, Code: "mirr-001" + suffix, DetailURL: "https://example.com/mirr-001" + suffix}
+			mockStore.SaveVideo(ctx, original)
+			mockStore.SaveVideo(ctx, mirror)
+
+			_ = service.PushVideoToChat(ctx, original, chatID)
+			_ = service.PushVideoToChat(ctx, mirror, chatID)
+
+			total := mockStore.CountSuccessPushes(original.ID, chatID) + mockStore.CountSuccessPushes(mirror.ID, chatID)
+			return total == 1
+		},
+		gen.Int64Range(1, 1000000),
+		suffixGen,
+	))
+
+	properties.TestingRun(t)
+}
+
 // Ensure MockStore implements the store.Store interface
 var _ store.Store = (*MockStore)(nil)
 
@@ -0,0 +1,139 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+const (
+	// discordTimeout bounds each Discord webhook request
+	discordTimeout = 10 * time.Second
+	// discordEmbedColor is the accent color of video embeds
+	discordEmbedColor = 0xE91E63
+)
+
+// DiscordWebhookID validates a Discord webhook URL and returns its numeric ID
+// The ID serves as the chat ID of Discord subscriptions, so deduplication and
+// the push outbox work the same as for Telegram chats.
+func DiscordWebhookID(webhookURL string) (int64, error) {
+	u, err := url.Parse(webhookURL)
+	if err != nil || u.Scheme != "https" {
+		return 0, fmt.Errorf("invalid Discord webhook URL")
+	}
+	host := strings.TrimPrefix(u.Host, "www.")
+	if host != "discord.com" && host != "discordapp.com" {
+		return 0, fmt.Errorf("invalid Discord webhook host %q", u.Host)
+	}
+
+	parts := strings.Split(strings.Trim(u.Path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "api" || parts[1] != "webhooks" || parts[3] == "" {
+		return 0, fmt.Errorf("invalid Discord webhook path")
+	}
+	id, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid Discord webhook ID %q", parts[2])
+	}
+	return id, nil
+}
+
+// discordMessage is the body of a Discord webhook execution
+type discordMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	URL         string              `json:"url,omitempty"`
+	Description string              `json:"description,omitempty"`
+	Color       int                 `json:"color"`
+	Fields      []discordEmbedField `json:"fields,omitempty"`
+	Image       *discordEmbedImage  `json:"image,omitempty"`
+}
+
+type discordEmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordEmbedImage struct {
+	URL string `json:"url"`
+}
+
+// formatDiscordMessage renders a video as a Discord embed
+func formatDiscordMessage(video *model.Video) *discordMessage {
+	embed := discordEmbed{
+		Title:       video.Code,
+		URL:         video.DetailURL,
+		Description: video.Title,
+		Color:       discordEmbedColor,
+	}
+	if video.Actresses != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "演员", Value: video.Actresses, Inline: true})
+	}
+	if video.Tags != "" {
+		embed.Fields = append(embed.Fields, discordEmbedField{Name: "标签", Value: video.Tags})
+	}
+	if video.Duration > 0 {
+		embed.Fields = append(embed.Fields, discordEmbedField{
+			Name:   "时长",
+			Value:  fmt.Sprintf("%d:%02d", video.Duration/60, video.Duration%60),
+			Inline: true,
+		})
+	}
+	if video.CoverURL != "" {
+		embed.Image = &discordEmbedImage{URL: video.CoverURL}
+	}
+	return &discordMessage{Embeds: []discordEmbed{embed}}
+}
+
+// DiscordNotifier delivers notifications through Discord webhooks
+// The target address is the webhook URL of the subscribed channel.
+type DiscordNotifier struct {
+	client *http.Client
+}
+
+// NewDiscordNotifier creates a Discord webhook notifier
+func NewDiscordNotifier() *DiscordNotifier {
+	return &DiscordNotifier{client: &http.Client{Timeout: discordTimeout}}
+}
+
+// NotifyVideo posts the video as an embed to the target webhook
+func (n *DiscordNotifier) NotifyVideo(ctx context.Context, target Target, video *model.Video) error {
+	if target.Address == "" {
+		return fmt.Errorf("discord target %d has no webhook URL", target.ChatID)
+	}
+
+	body, err := json.Marshal(formatDiscordMessage(video))
+	if err != nil {
+		return fmt.Errorf("failed to encode discord message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.Address, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create discord request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("discord request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("discord returned status %d", resp.StatusCode)
+	}
+	return nil
+}
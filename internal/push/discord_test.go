@@ -0,0 +1,100 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestDiscordWebhookID(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    int64
+		wantErr bool
+	}{
+		{"https://discord.com/api/webhooks/123456789012345678/token", 123456789012345678, false},
+		{"https://discordapp.com/api/webhooks/42/abc-DEF_1", 42, false},
+		{"http://discord.com/api/webhooks/42/abc", 0, true},
+		{"https://example.com/api/webhooks/42/abc", 0, true},
+		{"https://discord.com/api/webhooks/42", 0, true},
+		{"https://discord.com/api/webhooks/abc/token", 0, true},
+		{"not a url", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := DiscordWebhookID(tt.url)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("DiscordWebhookID(%q) = %d, %v; want %d, error %v", tt.url, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestDiscordNotifier_PostsEmbed(t *testing.T) {
+	var received discordMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	video := &model.Video{Code: "ABC-123", Title: "Title", Actresses: "A", CoverURL: "https://example.com/c.jpg", Duration: 125}
+	err := NewDiscordNotifier().NotifyVideo(context.Background(), Target{ChatID: 1, Platform: model.PlatformDiscord, Address: server.URL}, video)
+	if err != nil {
+		t.Fatalf("NotifyVideo() error = %v", err)
+	}
+
+	if len(received.Embeds) != 1 {
+		t.Fatalf("embeds = %d, want 1", len(received.Embeds))
+	}
+	embed := received.Embeds[0]
+	if embed.Title != "ABC-123" || embed.Image == nil || len(embed.Fields) != 2 {
+		t.Errorf("unexpected embed: %+v", embed)
+	}
+}
+
+// recordingNotifier records the targets it was asked to deliver to
+type recordingNotifier struct {
+	targets []Target
+}
+
+func (n *recordingNotifier) NotifyVideo(ctx context.Context, target Target, video *model.Video) error {
+	n.targets = append(n.targets, target)
+	return nil
+}
+
+func TestService_RoutesSubscriptionsByPlatform(t *testing.T) {
+	mockStore := NewMockStore()
+	mockTelegram := NewMockTelegramClient()
+	service := NewService(mockStore, mockTelegram)
+	discord := &recordingNotifier{}
+	service.RegisterNotifier(model.PlatformDiscord, discord)
+
+	ctx := context.Background()
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 1, Type: model.SubTypeAll, Enabled: true})
+	mockStore.CreateSubscription(ctx, &model.Subscription{
+		ChatID:   2,
+		Type:     model.SubTypeAll,
+		Enabled:  true,
+		Platform: model.PlatformDiscord,
+		Target:   "https://discord.com/api/webhooks/2/token",
+	})
+
+	video := &model.Video{ID: 1, Code: "TEST-300", DetailURL: "https://example.com/test"}
+	mockStore.SaveVideo(ctx, video)
+	if err := service.PushVideoToSubscribers(ctx, video); err != nil {
+		t.Fatalf("PushVideoToSubscribers() error = %v", err)
+	}
+
+	if len(mockTelegram.messages) != 1 {
+		t.Errorf("telegram messages = %d, want 1", len(mockTelegram.messages))
+	}
+	if len(discord.targets) != 1 || discord.targets[0].ChatID != 2 || discord.targets[0].Address == "" {
+		t.Errorf("discord targets = %+v, want chat 2 with webhook address", discord.targets)
+	}
+}
@@ -0,0 +1,81 @@
+package push
+
+import (
+	"context"
+	"sync"
+
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// drainState tracks the push runs in flight so shutdown can wait for them
+type drainState struct {
+	mu       sync.Mutex
+	draining bool
+	active   sync.WaitGroup
+}
+
+// begin registers a push run, returning false once the service is draining
+// Every successful begin must be paired with end.
+func (s *Service) begin() bool {
+	s.drain.mu.Lock()
+	defer s.drain.mu.Unlock()
+	if s.drain.draining {
+		return false
+	}
+	s.drain.active.Add(1)
+	return true
+}
+
+// end marks a push run registered with begin as finished
+func (s *Service) end() {
+	s.drain.active.Done()
+}
+
+// isDraining reports whether Drain was called
+func (s *Service) isDraining() bool {
+	s.drain.mu.Lock()
+	defer s.drain.mu.Unlock()
+	return s.drain.draining
+}
+
+// Drain stops the service from starting new deliveries and waits for the ones in flight
+// Work not started stays in the store: unmatched videos remain unpushed and queued
+// deliveries remain in the outbox, both picked up again after a restart.
+// Returns ctx's error if it is done before the in-flight deliveries finish.
+func (s *Service) Drain(ctx context.Context) error {
+	s.drain.mu.Lock()
+	s.drain.draining = true
+	s.drain.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.drain.active.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// requeue persists the jobs of a delivery unit skipped by Drain as pending pushes
+// Jobs already in the outbox stay there untouched.
+func (s *Service) requeue(ctx context.Context, unit []pushJob) {
+	for _, job := range unit {
+		if job.pendingID != 0 {
+			continue
+		}
+		pending := []*model.PendingPush{newPendingPush(job)}
+		if err := s.store.EnqueueVideoPushes(ctx, job.video.ID, pending); err != nil {
+			logctx.From(ctx).Error().
+				Err(err).
+				Str("code", job.video.Code).
+				Int64("chatID", job.target.ChatID).
+				Msg("Failed to queue push skipped by shutdown")
+		}
+	}
+}
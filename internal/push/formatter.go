@@ -2,27 +2,97 @@ package push
 
 import (
 	"fmt"
+	"html"
 	"strings"
 
 	"github.com/user/missav-bot-go/internal/model"
 )
 
+// markdownEscaper escapes the characters that need escaping in MarkdownV2:
+// _ * [ ] ( ) ~ ` > # + - = | { } . !
+var markdownEscaper = newEscaper("_*[]()~`>#+-=|{}.!")
+
+// markdownURLEscaper escapes the characters that end or break an inline link URL
+var markdownURLEscaper = strings.NewReplacer("\\", "\\\\", ")", "\\)")
+
+// newEscaper returns a replacer prefixing each of chars with a backslash in one pass
+func newEscaper(chars string) *strings.Replacer {
+	pairs := make([]string, 0, 2*len(chars))
+	for _, char := range chars {
+		pairs = append(pairs, string(char), "\\"+string(char))
+	}
+	return strings.NewReplacer(pairs...)
+}
+
 // EscapeMarkdown escapes special characters for Telegram MarkdownV2 format
 func EscapeMarkdown(text string) string {
-	// Characters that need to be escaped in MarkdownV2:
-	// _ * [ ] ( ) ~ ` > # + - = | { } . !
-	specialChars := []string{"_", "*", "[", "]", "(", ")", "~", "`", ">", "#", "+", "-", "=", "|", "{", "}", ".", "!"}
-	result := text
-	for _, char := range specialChars {
-		result = strings.ReplaceAll(result, char, "\\"+char)
+	return markdownEscaper.Replace(text)
+}
+
+// Telegram parse_mode values of the supported message formats
+const (
+	telegramMarkdownV2 = "MarkdownV2"
+	telegramHTML       = "HTML"
+)
+
+// markup writes formatted text in one Telegram parse mode
+type markup struct {
+	parseMode string
+	escape    func(text string) string
+	bold      func(text string) string
+	link      func(text, url string) string
+}
+
+// markdownMarkup formats MarkdownV2 text
+var markdownMarkup = markup{
+	parseMode: telegramMarkdownV2,
+	escape:    EscapeMarkdown,
+	bold:      func(text string) string { return "*" + EscapeMarkdown(text) + "*" },
+	link: func(text, url string) string {
+		return fmt.Sprintf("[%s](%s)", EscapeMarkdown(text), EscapeMarkdownURL(url))
+	},
+}
+
+// htmlMarkup formats HTML text, which only needs <, > and & escaped
+var htmlMarkup = markup{
+	parseMode: telegramHTML,
+	escape:    html.EscapeString,
+	bold:      func(text string) string { return "<b>" + html.EscapeString(text) + "</b>" },
+	link: func(text, url string) string {
+		return fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), html.EscapeString(text))
+	},
+}
+
+// markupFor returns the markup of a parse mode, MarkdownV2 unless HTML is chosen
+func markupFor(mode model.ParseMode) markup {
+	if mode == model.ParseModeHTML {
+		return htmlMarkup
 	}
-	return result
+	return markdownMarkup
 }
 
-// FormatVideoMessage formats a video into a Telegram message string
+// FormatVideo formats a video text message in a parse mode, within Telegram's message length
+// It returns the text and the Telegram parse_mode value to send it with.
+// Use FormatVideoCaption for the shorter captions of photos and videos.
+func FormatVideo(video *model.Video, mode model.ParseMode) (text string, parseMode string) {
+	return FormatVideoWithin(video, mode, maxMessageLength)
+}
+
+// FormatVideoMessage formats a video into a Telegram MarkdownV2 message string
 // The message includes: video code, actresses (if present), tags (if present),
 // duration (if present), and detail URL
 func FormatVideoMessage(video *model.Video) string {
+	return formatVideo(video, markdownMarkup)
+}
+
+// FormatVideoMessageHTML formats a video into a Telegram HTML message string
+// It carries the same fields as FormatVideoMessage.
+func FormatVideoMessageHTML(video *model.Video) string {
+	return formatVideo(video, htmlMarkup)
+}
+
+// formatVideo formats a video message with the given markup
+func formatVideo(video *model.Video, m markup) string {
 	if video == nil {
 		return ""
 	}
@@ -30,21 +100,21 @@ func FormatVideoMessage(video *model.Video) string {
 	var parts []string
 
 	// Video code is always included (required field)
-	parts = append(parts, fmt.Sprintf("🎬 *%s*", EscapeMarkdown(video.Code)))
+	parts = append(parts, "🎬 "+m.bold(video.Code))
 
 	// Title if present
 	if video.Title != "" {
-		parts = append(parts, fmt.Sprintf("📝 %s", EscapeMarkdown(video.Title)))
+		parts = append(parts, fmt.Sprintf("📝 %s", m.escape(video.Title)))
 	}
 
 	// Actresses if present
 	if video.Actresses != "" {
-		parts = append(parts, fmt.Sprintf("👩 %s", EscapeMarkdown(video.Actresses)))
+		parts = append(parts, fmt.Sprintf("👩 %s", m.escape(video.Actresses)))
 	}
 
 	// Tags if present
 	if video.Tags != "" {
-		parts = append(parts, fmt.Sprintf("🏷 %s", EscapeMarkdown(video.Tags)))
+		parts = append(parts, fmt.Sprintf("🏷 %s", m.escape(video.Tags)))
 	}
 
 	// Duration if present (greater than 0)
@@ -56,8 +126,74 @@ func FormatVideoMessage(video *model.Video) string {
 
 	// Detail URL is always included (required field)
 	if video.DetailURL != "" {
-		parts = append(parts, fmt.Sprintf("🔗 %s", EscapeMarkdown(video.DetailURL)))
+		parts = append(parts, fmt.Sprintf("🔗 %s", m.escape(video.DetailURL)))
 	}
 
 	return strings.Join(parts, "\n")
 }
+
+// batchTitleLength and batchActressesLength are the number of title and actress
+// characters shown per video in a combined message
+const (
+	batchTitleLength     = 40
+	batchActressesLength = 60
+)
+
+// FormatBatch formats several videos into one list message in a parse mode
+// It returns the text and the Telegram parse_mode value to send it with.
+// Videos beyond Telegram's message length are left out and counted in a closing line.
+func FormatBatch(videos []*model.Video, mode model.ParseMode) (text string, parseMode string) {
+	m := markupFor(mode)
+	return formatBatchWithin(videos, m, maxMessageLength), m.parseMode
+}
+
+// FormatBatchMessage formats several videos into one Telegram MarkdownV2 list message
+// Each entry links the video code to its detail page and shows the title and actresses.
+func FormatBatchMessage(videos []*model.Video) string {
+	return formatBatch(videos, markdownMarkup)
+}
+
+// FormatBatchMessageHTML formats several videos into one Telegram HTML list message
+func FormatBatchMessageHTML(videos []*model.Video) string {
+	return formatBatch(videos, htmlMarkup)
+}
+
+// formatBatch formats a list message with the given markup
+func formatBatch(videos []*model.Video, m markup) string {
+	lines := append([]string{batchHeader(len(videos), m)}, batchEntries(videos, m)...)
+	return strings.Join(lines, "\n")
+}
+
+// batchHeader is the first line of a list message of count videos, followed by a blank line
+func batchHeader(count int, m markup) string {
+	return fmt.Sprintf("🆕 %s\n", m.bold(fmt.Sprintf("%d 部新视频", count)))
+}
+
+// batchEntries formats the list entry of each video
+func batchEntries(videos []*model.Video, m markup) []string {
+	entries := make([]string, 0, len(videos))
+	for i, video := range videos {
+		code := m.escape(video.Code)
+		if video.DetailURL != "" {
+			code = m.link(video.Code, video.DetailURL)
+		}
+		entry := fmt.Sprintf("%s %s", m.escape(fmt.Sprintf("%d.", i+1)), code)
+		if video.Title != "" {
+			title := []rune(video.Title)
+			if len(title) > batchTitleLength {
+				title = append(title[:batchTitleLength-3], []rune("...")...)
+			}
+			entry += " " + m.escape(string(title))
+		}
+		if video.Actresses != "" {
+			entry += "\n   👩 " + m.escape(truncateRunes(video.Actresses, batchActressesLength))
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// EscapeMarkdownURL escapes a URL for use inside a MarkdownV2 inline link
+func EscapeMarkdownURL(url string) string {
+	return markdownURLEscaper.Replace(url)
+}
@@ -188,3 +188,75 @@ func TestProperty_EscapeMarkdown(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+// Property: Telegram Length Limits
+// *For any* video, however long its fields, the formatted caption SHALL fit in 1024 characters
+// and the formatted message in 4096, in both parse modes, and SHALL still name the video code.
+func TestProperty_MessageLengthLimits(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+	properties := gopter.NewProperties(parameters)
+
+	codeGen := gen.RegexMatch(`[A-Z]{2,5}-[0-9]{3,5}`)
+	// Long fields full of characters that need escaping, and CJK text
+	longGen := gen.IntRange(0, 3000).Map(func(n int) string {
+		return strings.Repeat("タグ_(x)&<", n/8)
+	})
+	modeGen := gen.OneConstOf(model.ParseModeMarkdown, model.ParseModeHTML)
+
+	properties.Property("captions and messages fit Telegram limits", prop.ForAll(
+		func(code string, title string, actresses string, tags string, mode model.ParseMode) bool {
+			video := &model.Video{
+				Code:      code,
+				Title:     title,
+				Actresses: actresses,
+				Tags:      tags,
+				DetailURL: "https://example.com/video/" + strings.ToLower(code),
+			}
+			caption, _ := FormatVideoCaption(video, mode)
+			message, _ := FormatVideo(video, mode)
+			escapedCode := markupFor(mode).escape(code)
+			return textLength(caption) <= maxCaptionLength &&
+				textLength(message) <= maxMessageLength &&
+				strings.Contains(caption, escapedCode) &&
+				strings.Contains(message, escapedCode)
+		},
+		codeGen,
+		longGen,
+		longGen,
+		longGen,
+		modeGen,
+	))
+
+	properties.Property("short messages are not shortened", prop.ForAll(
+		func(code string, title string, mode model.ParseMode) bool {
+			video := &model.Video{Code: code, Title: title, DetailURL: "https://example.com/video/1"}
+			caption, _ := FormatVideoCaption(video, mode)
+			return caption == formatVideo(video, markupFor(mode))
+		},
+		codeGen,
+		gen.AlphaString().Map(func(s string) string { return truncateRunes(s, 200) }),
+		modeGen,
+	))
+
+	properties.Property("list messages fit Telegram limits", prop.ForAll(
+		func(count int, actresses string, mode model.ParseMode) bool {
+			videos := make([]*model.Video, count)
+			for i := range videos {
+				videos[i] = &model.Video{
+					Code:      "ABC-123",
+					Title:     strings.Repeat("長", 50),
+					Actresses: actresses,
+					DetailURL: "https://example.com/video/abc-123",
+				}
+			}
+			message, _ := FormatBatch(videos, mode)
+			return textLength(message) <= maxMessageLength
+		},
+		gen.IntRange(1, 200),
+		longGen,
+		modeGen,
+	))
+
+	properties.TestingRun(t)
+}
@@ -0,0 +1,132 @@
+package push
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	got := EscapeMarkdown(`a_b*c[d](e)~f` + "`" + `g>h#i+j-k=l|m{n}o.p!q\r`)
+	want := `a\_b\*c\[d\]\(e\)\~f\` + "`" + `g\>h\#i\+j\-k\=l\|m\{n\}o\.p\!q\r`
+	if got != want {
+		t.Errorf("EscapeMarkdown() = %q, want %q", got, want)
+	}
+}
+
+func BenchmarkEscapeMarkdown(b *testing.B) {
+	title := "【4K】SSIS-001 新人NO.1 STYLE (Debut) - 三上悠亜 #tag_one! [Uncensored]"
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		EscapeMarkdown(title)
+	}
+}
+
+func TestEscapeMarkdownURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://missav.ai/abc-123", "https://missav.ai/abc-123"},
+		{"https://example.com/a_(b)", `https://example.com/a_(b\)`},
+		{`https://example.com/a\b`, `https://example.com/a\\b`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeMarkdownURL(tt.url); got != tt.want {
+			t.Errorf("EscapeMarkdownURL(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBatchMessage(t *testing.T) {
+	videos := []*model.Video{
+		{Code: "ABC-123", Title: strings.Repeat("長", 50), Actresses: "Actress One", DetailURL: "https://missav.ai/abc-123"},
+		{Code: "DEF-456"},
+	}
+
+	message := FormatBatchMessage(videos)
+	for _, want := range []string{
+		"*2 部新视频*",
+		`1\. [ABC\-123](https://missav.ai/abc-123) ` + strings.Repeat("長", 37) + `\.\.\.`,
+		"👩 Actress One",
+		`2\. DEF\-456`,
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("FormatBatchMessage() = %q, missing %q", message, want)
+		}
+	}
+}
+
+func TestFormatVideoMessageHTML(t *testing.T) {
+	video := &model.Video{
+		Code:      "ABC-123",
+		Title:     "<Special> Title & (more) [stuff]!",
+		Actresses: "Actress_One",
+		DetailURL: "https://missav.ai/abc-123?a=1&b=2",
+	}
+
+	message := FormatVideoMessageHTML(video)
+	for _, want := range []string{
+		"<b>ABC-123</b>",
+		"&lt;Special&gt; Title &amp; (more) [stuff]!",
+		"👩 Actress_One",
+		"https://missav.ai/abc-123?a=1&amp;b=2",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("FormatVideoMessageHTML() = %q, missing %q", message, want)
+		}
+	}
+
+	text, parseMode := FormatVideo(video, model.ParseModeHTML)
+	if text != message || parseMode != "HTML" {
+		t.Errorf("FormatVideo(html) parse mode = %q", parseMode)
+	}
+	if _, parseMode := FormatVideo(video, model.ParseModeDefault); parseMode != "MarkdownV2" {
+		t.Errorf("FormatVideo(default) parse mode = %q, want MarkdownV2", parseMode)
+	}
+}
+
+func TestFormatBatchMessageHTML(t *testing.T) {
+	videos := []*model.Video{
+		{Code: "ABC-123", Title: "A & B", DetailURL: "https://missav.ai/abc-123"},
+		{Code: "DEF-456"},
+	}
+
+	message := FormatBatchMessageHTML(videos)
+	for _, want := range []string{
+		"<b>2 部新视频</b>",
+		`1. <a href="https://missav.ai/abc-123">ABC-123</a> A &amp; B`,
+		"2. DEF-456",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("FormatBatchMessageHTML() = %q, missing %q", message, want)
+		}
+	}
+}
+
+func TestFormatVideoCaption_ShortensTagsFirst(t *testing.T) {
+	video := &model.Video{
+		Code:      "ABC-123",
+		Title:     "Full Title",
+		Actresses: "Actress One",
+		Tags:      strings.Repeat("tag, ", 400),
+		DetailURL: "https://missav.ai/abc-123",
+	}
+
+	caption, _ := FormatVideoCaption(video, model.ParseModeHTML)
+	if textLength(caption) > maxCaptionLength {
+		t.Fatalf("caption length = %d, want at most %d", textLength(caption), maxCaptionLength)
+	}
+	for _, want := range []string{"Full Title", "Actress One", "https://missav.ai/abc-123", ellipsis} {
+		if !strings.Contains(caption, want) {
+			t.Errorf("caption = %q, missing %q", caption, want)
+		}
+	}
+
+	message, _ := FormatVideo(video, model.ParseModeHTML)
+	if message != FormatVideoMessageHTML(video) {
+		t.Errorf("message within the message limit was shortened")
+	}
+}
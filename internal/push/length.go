@@ -0,0 +1,100 @@
+package push
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// Telegram length limits, counted in UTF-16 code units of the text after entity parsing
+const (
+	maxCaptionLength = 1024
+	maxMessageLength = 4096
+)
+
+// textLength returns the length of text as Telegram counts it
+// Markup and escapes are counted as well, which keeps the check on the safe side.
+func textLength(text string) int {
+	return len(utf16.Encode([]rune(text)))
+}
+
+// ellipsis marks a shortened field
+const ellipsis = "…"
+
+// truncateRunes shortens text to at most n runes, ending it with an ellipsis
+func truncateRunes(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	if n <= 0 {
+		return ""
+	}
+	return string(runes[:n-1]) + ellipsis
+}
+
+// FormatVideoWithin formats a video message in a parse mode that fits in limit characters
+// The least important fields are shortened first: tags, then actresses, then the title.
+// Fields are cut before escaping, so the markup stays valid.
+func FormatVideoWithin(video *model.Video, mode model.ParseMode, limit int) (text string, parseMode string) {
+	m := markupFor(mode)
+	return formatVideoWithin(video, m, limit), m.parseMode
+}
+
+// FormatVideoCaption formats a video message in a parse mode that fits in a media caption
+func FormatVideoCaption(video *model.Video, mode model.ParseMode) (text string, parseMode string) {
+	return FormatVideoWithin(video, mode, maxCaptionLength)
+}
+
+// formatVideoWithin formats a video message with the given markup within limit characters
+func formatVideoWithin(video *model.Video, m markup, limit int) string {
+	text := formatVideo(video, m)
+	if video == nil || textLength(text) <= limit {
+		return text
+	}
+
+	shortened := *video
+	// The detail URL is cut only as a last resort, when a huge code leaves no room for it
+	for _, field := range []*string{&shortened.Tags, &shortened.Actresses, &shortened.Title, &shortened.DetailURL} {
+		for *field != "" {
+			excess := textLength(text) - limit
+			if excess <= 0 {
+				return text
+			}
+			// Escaping may double a character, so cut the excess at least once over
+			*field = truncateRunes(*field, len([]rune(*field))-excess)
+			text = formatVideo(&shortened, m)
+		}
+	}
+	if textLength(text) <= limit {
+		return text
+	}
+	// Only the code is left; it is at most 50 characters in the database
+	return formatVideo(&model.Video{Code: truncateRunes(video.Code, limit/4)}, m)
+}
+
+// batchOverflowReserve keeps room in a list message for the line counting omitted videos
+const batchOverflowReserve = 64
+
+// formatBatchWithin formats a list message that fits in limit characters
+// Videos that do not fit are left out and counted in a closing line.
+func formatBatchWithin(videos []*model.Video, m markup, limit int) string {
+	text := formatBatch(videos, m)
+	if textLength(text) <= limit {
+		return text
+	}
+
+	lines := []string{batchHeader(len(videos), m)}
+	length := textLength(lines[0])
+	for i, entry := range batchEntries(videos, m) {
+		if length+1+textLength(entry) > limit-batchOverflowReserve {
+			lines = append(lines, m.escape(fmt.Sprintf("… 另有 %d 部", len(videos)-i)))
+			break
+		}
+		lines = append(lines, entry)
+		length += 1 + textLength(entry)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,111 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// mentionFallbackName labels mentioned users whose username is unknown
+const mentionFallbackName = "订阅者"
+
+// mergeMentions returns the users of a followed by those of b not already in a
+// The result never shares a's backing array.
+func mergeMentions(a, b []int64) []int64 {
+	if len(b) == 0 {
+		return a
+	}
+	merged := append([]int64(nil), a...)
+	for _, id := range b {
+		found := false
+		for _, existing := range merged {
+			if existing == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged = append(merged, id)
+		}
+	}
+	return merged
+}
+
+// formatMentionIDs stores mentioned user IDs as a comma-separated list
+func formatMentionIDs(ids []int64) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseMentionIDs reads a list written by formatMentionIDs, skipping malformed entries
+func parseMentionIDs(s string) []int64 {
+	var ids []int64
+	for _, part := range strings.Split(s, ",") {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err == nil && id != 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// formatMentions formats the message telling mentioned users that videos they subscribed to were posted
+// names holds the display name of each user in ids.
+func formatMentions(ids []int64, names []string, videos []*model.Video, m markup) string {
+	links := make([]string, 0, len(ids))
+	for i, id := range ids {
+		links = append(links, m.link(names[i], fmt.Sprintf("tg://user?id=%d", id)))
+	}
+	codes := make([]string, 0, len(videos))
+	for _, video := range videos {
+		codes = append(codes, video.Code)
+	}
+	return "🔔 " + strings.Join(links, " ") + " " + m.escape(fmt.Sprintf("你订阅的 %s 已推送", strings.Join(codes, ", ")))
+}
+
+// mentionNames returns the display names of users: their @username, or a generic label
+func (s *Service) mentionNames(ctx context.Context, ids []int64) []string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		name := mentionFallbackName
+		user, err := s.store.GetUser(ctx, id)
+		if err != nil {
+			logctx.From(ctx).Warn().Err(err).Int64("userID", id).Msg("Failed to get mentioned user")
+		} else if user != nil && user.Username != "" {
+			name = "@" + user.Username
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// sendMentions mentions the personal subscribers of a Telegram group after their videos were posted
+// Failures are only logged, since the videos themselves were delivered.
+func (s *Service) sendMentions(ctx context.Context, target Target, videos []*model.Video) {
+	if len(target.Mentions) == 0 || target.Platform != model.PlatformTelegram {
+		return
+	}
+
+	if err := s.chatLimiter(target.ChatID).Wait(ctx); err != nil {
+		logctx.From(ctx).Warn().Err(err).Int64("chatID", target.ChatID).Msg("Failed to wait to send mentions")
+		return
+	}
+	bot := s.telegram.bot(target.BotID)
+	if err := bot.limiter.Wait(ctx); err != nil {
+		logctx.From(ctx).Warn().Err(err).Int64("chatID", target.ChatID).Msg("Failed to wait to send mentions")
+		return
+	}
+
+	m := markupFor(target.ParseMode)
+	text := formatMentions(target.Mentions, s.mentionNames(ctx, target.Mentions), videos, m)
+	if _, err := bot.client.SendText(target.ChatID, text, m.parseMode); err != nil {
+		logctx.From(ctx).Warn().Err(err).Int64("chatID", target.ChatID).Msg("Failed to send subscriber mentions")
+	}
+}
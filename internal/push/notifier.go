@@ -0,0 +1,230 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+	"golang.org/x/time/rate"
+)
+
+// Target identifies where a notification is delivered
+type Target struct {
+	// ChatID keys deduplication and the push outbox on every platform
+	ChatID int64
+	// Platform selects the notifier, e.g. model.PlatformTelegram
+	Platform string
+	// Address is the platform-specific destination, e.g. a Discord webhook URL
+	Address string
+	// BotID selects the Telegram bot delivering to the chat; 0 is the primary bot
+	BotID int64
+	// ParseMode formats Telegram messages; resolved from the chat settings when delivering
+	ParseMode model.ParseMode
+	// Mentions are the users of a Telegram group to mention after the push
+	Mentions []int64
+}
+
+// subscriptionTarget returns the delivery target of a subscription
+// Personal subscriptions go to the subscriber's private chat or mention them in the group.
+func subscriptionTarget(sub *model.Subscription) Target {
+	target := Target{ChatID: sub.ChatID, Platform: sub.NotifyPlatform(), Address: sub.Target, BotID: sub.BotID}
+	if !sub.IsPersonal() {
+		return target
+	}
+	switch sub.Personal {
+	case model.PersonalDM:
+		target.ChatID = sub.UserID
+	case model.PersonalMention:
+		target.Mentions = []int64{sub.UserID}
+	}
+	return target
+}
+
+// pendingTarget returns the delivery target of a queued push
+func pendingTarget(p *model.PendingPush) Target {
+	platform := p.Platform
+	if platform == "" {
+		platform = model.PlatformTelegram
+	}
+	return Target{ChatID: p.ChatID, Platform: platform, Address: p.Target, BotID: p.BotID, Mentions: parseMentionIDs(p.Mentions)}
+}
+
+// Notifier delivers video notifications on one messaging platform
+type Notifier interface {
+	NotifyVideo(ctx context.Context, target Target, video *model.Video) error
+}
+
+// maxMediaGroupSize is the largest album the Telegram Bot API accepts
+const maxMediaGroupSize = 10
+
+// telegramBot is one Telegram bot pushes are delivered through
+type telegramBot struct {
+	client  TelegramClient
+	limiter *rate.Limiter // Telegram rate limit: max 30 msg/sec per bot (Requirement 5.9)
+}
+
+// newTelegramBot wraps a client with its own global rate limiter
+func newTelegramBot(client TelegramClient) *telegramBot {
+	return &telegramBot{client: client, limiter: rate.NewLimiter(rate.Limit(30), 1)}
+}
+
+// telegramNotifier delivers notifications through the Telegram Bot API
+// Each chat is served by the bot its subscription was created with, since a bot
+// can only message chats that talk to it; unknown bots fall back to the primary one.
+type telegramNotifier struct {
+	primary *telegramBot
+	mu      sync.RWMutex
+	bots    map[int64]*telegramBot
+}
+
+// newTelegramNotifier creates a notifier delivering through the primary bot
+func newTelegramNotifier(primary TelegramClient) *telegramNotifier {
+	return &telegramNotifier{
+		primary: newTelegramBot(primary),
+		bots:    make(map[int64]*telegramBot),
+	}
+}
+
+// register adds a bot by its Telegram user ID
+// Registering the primary client shares its rate limiter instead of creating a second one.
+func (n *telegramNotifier) register(botID int64, client TelegramClient) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if client == n.primary.client {
+		n.bots[botID] = n.primary
+		return
+	}
+	n.bots[botID] = newTelegramBot(client)
+}
+
+// bot returns the bot registered under botID, or the primary bot
+func (n *telegramNotifier) bot(botID int64) *telegramBot {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	if bot, ok := n.bots[botID]; ok {
+		return bot
+	}
+	return n.primary
+}
+
+// sentMessage identifies a delivered Telegram message so it can be edited later
+type sentMessage struct {
+	id    int
+	kind  model.MessageType
+	count int // messages sent; album items have consecutive IDs starting at id
+}
+
+// editableNotifier is implemented by notifiers whose messages can be updated after sending
+type editableNotifier interface {
+	sendVideo(ctx context.Context, target Target, video *model.Video) (sentMessage, error)
+	editVideo(ctx context.Context, target Target, sent sentMessage, video *model.Video) error
+	deleteMessages(ctx context.Context, target Target, sent sentMessage) error
+}
+
+// NotifyVideo sends the video preview, falling back to the cover photo and then to text
+func (n *telegramNotifier) NotifyVideo(ctx context.Context, target Target, video *model.Video) error {
+	_, err := n.sendVideo(ctx, target, video)
+	return err
+}
+
+// sendVideo sends the video preview, falling back to the cover photo and then to text
+// Videos with a preview gallery are sent as an album of the preview or cover and the screenshots.
+func (n *telegramNotifier) sendVideo(ctx context.Context, target Target, video *model.Video) (sentMessage, error) {
+	chatID := target.ChatID
+	telegram := n.bot(target.BotID).client
+	caption, parseMode := FormatVideoCaption(video, target.ParseMode)
+
+	if len(video.Screenshots) > 0 {
+		videoURL, photos := MediaGroup(video)
+		id, err := telegram.SendMediaGroup(chatID, videoURL, photos, caption, parseMode)
+		if err == nil {
+			count := len(photos)
+			if videoURL != "" {
+				count++
+			}
+			return sentMessage{id: id, kind: model.MessageTypeMedia, count: count}, nil
+		}
+		logctx.From(ctx).Warn().Err(err).Int64("chatID", chatID).Str("code", video.Code).Msg("Failed to send media group, falling back to single media")
+	}
+
+	var sendErr error
+	var id int
+
+	// Try video first if preview URL exists (Requirement 5.8)
+	if video.PreviewURL != "" {
+		id, sendErr = telegram.SendVideo(chatID, video.PreviewURL, video.CoverURL, caption, parseMode)
+		if sendErr == nil {
+			return sentMessage{id: id, kind: model.MessageTypeMedia, count: 1}, nil
+		}
+	}
+
+	// Fallback to photo if video fails or no preview URL (Requirement 5.7)
+	if video.CoverURL != "" {
+		id, sendErr = telegram.SendPhoto(chatID, video.CoverURL, caption, parseMode)
+		if sendErr != nil {
+			return sentMessage{}, sendErr
+		}
+		return sentMessage{id: id, kind: model.MessageTypeMedia, count: 1}, nil
+	}
+
+	// No media, send text only
+	message, _ := FormatVideo(video, target.ParseMode)
+	id, sendErr = telegram.SendText(chatID, message, parseMode)
+	if sendErr != nil {
+		return sentMessage{}, sendErr
+	}
+	return sentMessage{id: id, kind: model.MessageTypeText, count: 1}, nil
+}
+
+// editVideo rewrites a sent message with the current video details
+// Text posts stay text: a message cannot gain media after it was sent.
+func (n *telegramNotifier) editVideo(ctx context.Context, target Target, sent sentMessage, video *model.Video) error {
+	telegram := n.bot(target.BotID).client
+
+	switch sent.kind {
+	case model.MessageTypeText:
+		message, parseMode := FormatVideo(video, target.ParseMode)
+		return telegram.EditText(target.ChatID, sent.id, message, parseMode)
+	case model.MessageTypeMedia:
+		caption, parseMode := FormatVideoCaption(video, target.ParseMode)
+		return telegram.EditCaption(target.ChatID, sent.id, caption, parseMode)
+	default:
+		return fmt.Errorf("message type %q cannot be edited", sent.kind)
+	}
+}
+
+// deleteMessages deletes a sent message, or every item of a sent album
+// Telegram only lets bots delete messages younger than 48 hours, and in groups
+// messages of others only with the delete permission.
+func (n *telegramNotifier) deleteMessages(ctx context.Context, target Target, sent sentMessage) error {
+	telegram := n.bot(target.BotID).client
+	count := max(sent.count, 1)
+	for i := 0; i < count; i++ {
+		if err := telegram.DeleteMessage(target.ChatID, sent.id+i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MediaGroup returns the album of a video: the preview clip, or else the cover,
+// followed by as many screenshots as fit in one media group
+func MediaGroup(video *model.Video) (videoURL string, photos []string) {
+	if video.PreviewURL != "" {
+		videoURL = video.PreviewURL
+	} else if video.CoverURL != "" {
+		photos = append(photos, video.CoverURL)
+	}
+
+	remaining := maxMediaGroupSize - len(photos)
+	if videoURL != "" {
+		remaining--
+	}
+	screenshots := video.Screenshots
+	if len(screenshots) > remaining {
+		screenshots = screenshots[:remaining]
+	}
+	return videoURL, append(photos, screenshots...)
+}
@@ -0,0 +1,58 @@
+package push
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestMediaGroup(t *testing.T) {
+	var screenshots []string
+	for i := 1; i <= 12; i++ {
+		screenshots = append(screenshots, fmt.Sprintf("https://example.com/shot-%d.jpg", i))
+	}
+
+	tests := []struct {
+		name       string
+		video      *model.Video
+		wantVideo  string
+		wantPhotos int
+		wantFirst  string
+	}{
+		{
+			name:       "preview clip leads the album",
+			video:      &model.Video{PreviewURL: "https://example.com/preview.mp4", CoverURL: "https://example.com/cover.jpg", Screenshots: screenshots},
+			wantVideo:  "https://example.com/preview.mp4",
+			wantPhotos: maxMediaGroupSize - 1,
+			wantFirst:  "https://example.com/shot-1.jpg",
+		},
+		{
+			name:       "cover leads without preview",
+			video:      &model.Video{CoverURL: "https://example.com/cover.jpg", Screenshots: screenshots[:3]},
+			wantPhotos: 4,
+			wantFirst:  "https://example.com/cover.jpg",
+		},
+		{
+			name:       "screenshots only",
+			video:      &model.Video{Screenshots: screenshots},
+			wantPhotos: maxMediaGroupSize,
+			wantFirst:  "https://example.com/shot-1.jpg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			videoURL, photos := MediaGroup(tt.video)
+			if videoURL != tt.wantVideo {
+				t.Errorf("video = %q, want %q", videoURL, tt.wantVideo)
+			}
+			if len(photos) != tt.wantPhotos {
+				t.Fatalf("got %d photos, want %d", len(photos), tt.wantPhotos)
+			}
+			if photos[0] != tt.wantFirst {
+				t.Errorf("first photo = %q, want %q", photos[0], tt.wantFirst)
+			}
+		})
+	}
+}
@@ -0,0 +1,820 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// Property: Push Pipeline Fan-out
+// *For any* set of subscribed chats, pushing a video SHALL deliver exactly one message per
+// distinct chat, without serializing deliveries to different chats.
+func TestProperty_PushPipelineFanOut(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 20
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("one delivery per distinct chat", prop.ForAll(
+		func(numChats int, duplicates int, workers int) bool {
+			mockStore := NewMockStore()
+			mockTelegram := NewMockTelegramClient()
+			service := NewServiceWithConfig(mockStore, mockTelegram, &ServiceConfig{Workers: workers})
+
+			ctx := context.Background()
+			for chat := 1; chat <= numChats; chat++ {
+				// Several matching subscriptions for the same chat must collapse into one push
+				for i := 0; i <= duplicates; i++ {
+					mockStore.CreateSubscription(ctx, &model.Subscription{
+						ChatID:  int64(chat),
+						Type:    model.SubTypeAll,
+						Enabled: true,
+					})
+				}
+			}
+
+			video := &model.Video{ID: 1, Code: "TEST-100", DetailURL: "https://example.com/test"}
+			mockStore.SaveVideo(ctx, video)
+
+			start := time.Now()
+			if err := service.PushVideoToSubscribers(ctx, video); err != nil {
+				return false
+			}
+
+			// Deliveries to different chats are not held back by the per-chat limiter
+			if time.Since(start) > time.Second {
+				return false
+			}
+
+			for chat := 1; chat <= numChats; chat++ {
+				if mockStore.CountSuccessPushes(video.ID, int64(chat)) != 1 {
+					return false
+				}
+			}
+			return len(mockTelegram.messages) == numChats
+		},
+		gen.IntRange(1, 10),
+		gen.IntRange(0, 2),
+		gen.IntRange(1, 8),
+	))
+
+	properties.TestingRun(t)
+}
+
+// TestPushVideoToSubscribers_DefaultChat checks that the default chat receives every
+// video once, whether or not it subscribes
+func TestPushVideoToSubscribers_DefaultChat(t *testing.T) {
+	mockStore := NewMockStore()
+	mockTelegram := NewMockTelegramClient()
+	cfg := DefaultServiceConfig()
+	cfg.DefaultChatID = -100
+	service := NewServiceWithConfig(mockStore, mockTelegram, cfg)
+	ctx := context.Background()
+
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 1, Type: model.SubTypeTag, Keyword: "VR", Enabled: true})
+
+	video := &model.Video{ID: 1, Code: "TEST-300", Tags: "Drama", DetailURL: "https://example.com/test"}
+	mockStore.SaveVideo(ctx, video)
+	for i := 0; i < 2; i++ {
+		if err := service.PushVideoToSubscribers(ctx, video); err != nil {
+			t.Fatalf("PushVideoToSubscribers() error = %v", err)
+		}
+	}
+
+	if got := mockStore.CountSuccessPushes(video.ID, -100); got != 1 {
+		t.Errorf("default chat pushes = %d, want 1", got)
+	}
+	if got := mockStore.CountSuccessPushes(video.ID, 1); got != 0 {
+		t.Errorf("non-matching subscriber pushes = %d, want 0", got)
+	}
+	if len(mockTelegram.messages) != 1 {
+		t.Errorf("sent %d messages, want 1", len(mockTelegram.messages))
+	}
+}
+
+// TestPushUnpushedVideos_SkipsPushedChats checks that chats which already received a
+// release get no outbox entry, and videos left with no chats are still marked pushed
+func TestPushUnpushedVideos_SkipsPushedChats(t *testing.T) {
+	mockStore := NewMockStore()
+	mockTelegram := NewMockTelegramClient()
+	service := NewService(mockStore, mockTelegram)
+	ctx := context.Background()
+
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 1, Type: model.SubTypeTag, Keyword: "VR", Enabled: true})
+	// The release was pushed to chat 1 under an earlier record
+	mockStore.RecordPush(ctx, &model.PushRecord{VideoID: 1, Code: "ABC-100", ChatID: 1, Status: model.PushStatusSuccess})
+
+	mirror := &model.Video{ID: 2, Code: "ABC-100", Tags: "VR", DetailURL: "https://example.com/abc-100"}
+	unmatched := &model.Video{ID: 3, Code: "XYZ-200", Tags: "Drama", DetailURL: "https://example.com/xyz-200"}
+	mockStore.SaveVideo(ctx, mirror)
+	mockStore.SaveVideo(ctx, unmatched)
+
+	if err := service.PushUnpushedVideos(ctx); err != nil {
+		t.Fatalf("PushUnpushedVideos() error = %v", err)
+	}
+
+	if len(mockStore.pending) != 0 {
+		t.Errorf("queued %d pushes, want none", len(mockStore.pending))
+	}
+	if len(mockTelegram.messages) != 0 {
+		t.Errorf("sent %d messages, want none", len(mockTelegram.messages))
+	}
+	if !mirror.Pushed || !unmatched.Pushed {
+		t.Errorf("pushed = %v, %v, want both videos marked pushed", mirror.Pushed, unmatched.Pushed)
+	}
+}
+
+// TestPushVideoToSubscribers_RoutesByBot checks that each chat is pushed through the
+// bot its subscription was created with, and unknown bots fall back to the primary one
+func TestPushVideoToSubscribers_RoutesByBot(t *testing.T) {
+	mockStore := NewMockStore()
+	primary := NewMockTelegramClient()
+	secondary := NewMockTelegramClient()
+	service := NewService(mockStore, primary)
+	service.RegisterTelegramBot(100, primary)
+	service.RegisterTelegramBot(200, secondary)
+	ctx := context.Background()
+
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 1, Type: model.SubTypeAll, Enabled: true, BotID: 100})
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 2, Type: model.SubTypeAll, Enabled: true, BotID: 200})
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 3, Type: model.SubTypeAll, Enabled: true, BotID: 300})
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 4, Type: model.SubTypeAll, Enabled: true})
+
+	video := &model.Video{ID: 1, Code: "TEST-400", DetailURL: "https://example.com/test"}
+	mockStore.SaveVideo(ctx, video)
+	if err := service.PushVideoToSubscribers(ctx, video); err != nil {
+		t.Fatalf("PushVideoToSubscribers() error = %v", err)
+	}
+
+	if len(primary.messages) != 3 {
+		t.Errorf("primary bot sent %d messages, want 3", len(primary.messages))
+	}
+	if len(secondary.messages) != 1 {
+		t.Errorf("secondary bot sent %d messages, want 1", len(secondary.messages))
+	}
+}
+
+// TestPushVideoToSubscribers_PersonalSubscriptions checks that personal subscriptions in a
+// group mention their subscribers after the group post, or go to the subscriber's private chat
+func TestPushVideoToSubscribers_PersonalSubscriptions(t *testing.T) {
+	mockStore := NewMockStore()
+	telegram := NewMockTelegramClient()
+	service := NewService(mockStore, telegram)
+	ctx := context.Background()
+
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: -10, Type: model.SubTypeAll, Enabled: true, UserID: 1, Personal: model.PersonalMention})
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: -10, Type: model.SubTypeAll, Enabled: true, UserID: 2, Personal: model.PersonalMention})
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: -10, Type: model.SubTypeAll, Enabled: true, UserID: 3, Personal: model.PersonalDM})
+
+	video := &model.Video{ID: 1, Code: "TEST-500", DetailURL: "https://example.com/test"}
+	mockStore.SaveVideo(ctx, video)
+	if err := service.PushVideoToSubscribers(ctx, video); err != nil {
+		t.Fatalf("PushVideoToSubscribers() error = %v", err)
+	}
+
+	for _, chatID := range []int64{-10, 3} {
+		if pushed, _ := mockStore.HasPushed(ctx, video.ID, chatID); !pushed {
+			t.Errorf("video not pushed to chat %d", chatID)
+		}
+	}
+	if pushed, _ := mockStore.HasPushed(ctx, video.ID, 1); pushed {
+		t.Error("mentioned subscriber got a private push")
+	}
+
+	mentions := 0
+	for _, message := range telegram.messages {
+		if strings.Contains(message, "tg://user?id=1") && strings.Contains(message, "tg://user?id=2") {
+			mentions++
+		}
+	}
+	if mentions != 1 {
+		t.Errorf("sent %d messages mentioning both subscribers, want 1", mentions)
+	}
+}
+
+// TestPushVideoToChat_DailyCap checks that pushes over a chat's daily cap are held
+// back and announced once in a summary message
+func TestPushVideoToChat_DailyCap(t *testing.T) {
+	mockStore := NewMockStore()
+	mockTelegram := NewMockTelegramClient()
+	cfg := DefaultServiceConfig()
+	cfg.DailyCap = 1
+	service := NewServiceWithConfig(mockStore, mockTelegram, cfg)
+	ctx := context.Background()
+
+	for i := uint(1); i <= 3; i++ {
+		video := &model.Video{ID: i, Code: fmt.Sprintf("TEST-50%d", i), DetailURL: "https://example.com/test"}
+		if err := service.PushVideoToChat(ctx, video, 42); err != nil {
+			t.Fatalf("PushVideoToChat() error = %v", err)
+		}
+	}
+	if len(mockTelegram.messages) != 1 {
+		t.Fatalf("sent %d messages, want 1 under the cap", len(mockTelegram.messages))
+	}
+
+	// Capped matches of the current day are not summarized yet
+	service.sendCapSummaries(ctx, dayStart(time.Now()))
+	if len(mockTelegram.messages) != 1 {
+		t.Fatalf("summary sent before the day ended")
+	}
+
+	tomorrow := dayStart(time.Now()).AddDate(0, 0, 1)
+	service.sendCapSummaries(ctx, tomorrow)
+	service.sendCapSummaries(ctx, tomorrow)
+	if len(mockTelegram.messages) != 2 || !strings.Contains(mockTelegram.messages[1], "2 个") {
+		t.Errorf("messages = %q, want one summary of 2 held-back videos", mockTelegram.messages)
+	}
+}
+
+// TestDeliverPending_DailyCapWithWorkers checks that parallel workers never push a chat
+// past its daily cap, since one chat's deliveries are handled by one worker at a time
+func TestDeliverPending_DailyCapWithWorkers(t *testing.T) {
+	mockStore := NewMockStore()
+	mockTelegram := NewMockTelegramClient()
+	cfg := DefaultServiceConfig()
+	cfg.Workers = 4
+	cfg.DailyCap = 2
+	cfg.BatchThreshold = 0
+	cfg.ChatDelay = 0
+	service := NewServiceWithConfig(mockStore, mockTelegram, cfg)
+	ctx := context.Background()
+
+	for i := uint(1); i <= 6; i++ {
+		video := &model.Video{ID: i, Code: fmt.Sprintf("TEST-80%d", i), DetailURL: "https://example.com/test"}
+		mockStore.SaveVideo(ctx, video)
+		pending := []*model.PendingPush{{VideoID: i, ChatID: 42}}
+		if i <= 3 {
+			pending = append(pending, &model.PendingPush{VideoID: i, ChatID: int64(i)})
+		}
+		mockStore.EnqueueVideoPushes(ctx, i, pending)
+	}
+
+	if err := service.DeliverPending(ctx); err != nil {
+		t.Fatalf("DeliverPending() error = %v", err)
+	}
+
+	pushed := 0
+	for i := uint(1); i <= 6; i++ {
+		pushed += mockStore.CountSuccessPushes(i, 42)
+	}
+	if pushed != 2 {
+		t.Errorf("pushed %d videos to the capped chat, want 2", pushed)
+	}
+	if len(mockTelegram.messages) != 2+3 {
+		t.Errorf("sent %d messages, want 5", len(mockTelegram.messages))
+	}
+}
+
+func TestDeliveryQueue(t *testing.T) {
+	unit := func(chatID int64, code string) []pushJob {
+		return []pushJob{{video: &model.Video{Code: code}, target: Target{ChatID: chatID, Platform: model.PlatformTelegram}}}
+	}
+	queue := newDeliveryQueue([][]pushJob{unit(1, "A"), unit(1, "B"), unit(2, "C"), unit(3, "D")})
+	ctx := context.Background()
+
+	// While chat 1 is being delivered to, its next unit waits and other chats go ahead
+	first, _ := queue.next(ctx)
+	second, _ := queue.next(ctx)
+	if first[0].video.Code != "A" || second[0].video.Code != "C" {
+		t.Fatalf("next() = %s, %s, want A, C", first[0].video.Code, second[0].video.Code)
+	}
+
+	queue.done(first)
+	var got []string
+	for {
+		next, ok := queue.next(ctx)
+		if !ok {
+			break
+		}
+		got = append(got, next[0].video.Code)
+		queue.done(next)
+	}
+	if want := []string{"B", "D"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("remaining units = %v, want %v", got, want)
+	}
+}
+
+// TestDeliverPending_BatchesPerChat checks that a delivery bringing a chat many videos
+// is combined into list messages, following each chat's push mode
+func TestDeliverPending_BatchesPerChat(t *testing.T) {
+	mockStore := NewMockStore()
+	mockTelegram := NewMockTelegramClient()
+	cfg := DefaultServiceConfig()
+	cfg.BatchSize = 3
+	cfg.BatchThreshold = 4
+	service := NewServiceWithConfig(mockStore, mockTelegram, cfg)
+	ctx := context.Background()
+
+	mockStore.SaveChatSettings(ctx, &model.ChatSettings{ChatID: 2, PushMode: model.PushModeSingle})
+	mockStore.SaveChatSettings(ctx, &model.ChatSettings{ChatID: 3, PushMode: model.PushModeBatch})
+
+	// Chats 1 and 2 get four videos, over the threshold; chat 3 gets two
+	for i := uint(1); i <= 4; i++ {
+		video := &model.Video{ID: i, Code: fmt.Sprintf("TEST-60%d", i), DetailURL: "https://example.com/test"}
+		mockStore.SaveVideo(ctx, video)
+		pending := []*model.PendingPush{{VideoID: i, ChatID: 1}, {VideoID: i, ChatID: 2}}
+		if i <= 2 {
+			pending = append(pending, &model.PendingPush{VideoID: i, ChatID: 3})
+		}
+		mockStore.EnqueueVideoPushes(ctx, i, pending)
+	}
+
+	if err := service.DeliverPending(ctx); err != nil {
+		t.Fatalf("DeliverPending() error = %v", err)
+	}
+
+	// Chat 1: lists of 3 and 1 videos; chat 2: 4 single messages; chat 3: one list
+	if len(mockTelegram.messages) != 2+4+1 {
+		t.Errorf("sent %d messages, want 7", len(mockTelegram.messages))
+	}
+	for i := uint(1); i <= 4; i++ {
+		for _, chat := range []int64{1, 2} {
+			if mockStore.CountSuccessPushes(i, chat) != 1 {
+				t.Errorf("video %d not recorded as pushed to chat %d", i, chat)
+			}
+		}
+	}
+	if remaining, _ := mockStore.GetPendingPushes(ctx, MaxPushAttempts, outboxBatchSize); len(remaining) != 0 {
+		t.Errorf("%d pushes left in the outbox", len(remaining))
+	}
+}
+
+// TestPushUnpushedVideos_SpecificMatchesFirst checks that pushes matched by ACTRESS or
+// TAG subscriptions are delivered before the fan-out to ALL subscribers
+func TestPushUnpushedVideos_SpecificMatchesFirst(t *testing.T) {
+	mockStore := NewMockStore()
+	mockTelegram := NewMockTelegramClient()
+	cfg := DefaultServiceConfig()
+	cfg.Workers = 1
+	service := NewServiceWithConfig(mockStore, mockTelegram, cfg)
+	ctx := context.Background()
+
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 1, Type: model.SubTypeAll, Enabled: true})
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 2, Type: model.SubTypeAll, Enabled: true})
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 1, Type: model.SubTypeTag, Keyword: "VR", Enabled: true})
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 3, Type: model.SubTypeTag, Keyword: "VR", Enabled: true})
+
+	mockStore.SaveVideo(ctx, &model.Video{ID: 1, Code: "TEST-701", Tags: "Drama", DetailURL: "https://example.com/test-701"})
+	mockStore.SaveVideo(ctx, &model.Video{ID: 2, Code: "TEST-702", Tags: "VR", DetailURL: "https://example.com/test-702"})
+
+	if err := service.PushUnpushedVideos(ctx); err != nil {
+		t.Fatalf("PushUnpushedVideos() error = %v", err)
+	}
+
+	if len(mockStore.pushRecords) != 5 {
+		t.Fatalf("recorded %d pushes, want 5", len(mockStore.pushRecords))
+	}
+	for i, want := range []int64{1, 3} {
+		record := mockStore.pushRecords[i]
+		if record.VideoID != 2 || record.ChatID != want {
+			t.Errorf("push %d = video %d to chat %d, want the VR match to chat %d", i, record.VideoID, record.ChatID, want)
+		}
+	}
+}
+
+// TestPushVideoToChat_PerChatRateLimit checks that consecutive deliveries to the
+// same chat are spaced by the per-chat limit
+func TestPushVideoToChat_PerChatRateLimit(t *testing.T) {
+	mockStore := NewMockStore()
+	service := NewService(mockStore, NewMockTelegramClient())
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := uint(1); i <= 2; i++ {
+		video := &model.Video{ID: i, Code: fmt.Sprintf("TEST-20%d", i), DetailURL: "https://example.com/test"}
+		if err := service.PushVideoToChat(ctx, video, 42); err != nil {
+			t.Fatalf("PushVideoToChat() error = %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("two pushes to the same chat took %v, want at least ~1s", elapsed)
+	}
+}
+
+// TestPushVideoToChat_NoChatDelay checks that a zero ChatDelay does not space pushes
+func TestPushVideoToChat_NoChatDelay(t *testing.T) {
+	mockStore := NewMockStore()
+	cfg := DefaultServiceConfig()
+	cfg.ChatDelay = 0
+	service := NewServiceWithConfig(mockStore, NewMockTelegramClient(), cfg)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := uint(1); i <= 3; i++ {
+		video := &model.Video{ID: i, Code: fmt.Sprintf("TEST-21%d", i), DetailURL: "https://example.com/test"}
+		if err := service.PushVideoToChat(ctx, video, 42); err != nil {
+			t.Fatalf("PushVideoToChat() error = %v", err)
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("three pushes to the same chat took %v, want no spacing", elapsed)
+	}
+}
+
+func TestPushVideoToChat_SkipsMutedCode(t *testing.T) {
+	mockStore := NewMockStore()
+	telegram := NewMockTelegramClient()
+	service := NewService(mockStore, telegram)
+	ctx := context.Background()
+
+	if err := mockStore.MuteCode(ctx, 42, "ABC-123"); err != nil {
+		t.Fatalf("MuteCode() error = %v", err)
+	}
+
+	video := &model.Video{ID: 1, Code: "abc-123-uncensored-leak", DetailURL: "https://example.com/abc-123"}
+	if err := service.PushVideoToChat(ctx, video, 42); err != nil {
+		t.Fatalf("PushVideoToChat() error = %v", err)
+	}
+	if len(telegram.messages) != 0 {
+		t.Errorf("muted code was pushed: %v", telegram.messages)
+	}
+
+	if err := service.PushVideoToChat(ctx, video, 7); err != nil {
+		t.Fatalf("PushVideoToChat() error = %v", err)
+	}
+	if len(telegram.messages) != 1 {
+		t.Errorf("code muted in another chat was not pushed, messages = %d", len(telegram.messages))
+	}
+}
+
+func TestPushVideoToChat_ChatParseMode(t *testing.T) {
+	mockStore := NewMockStore()
+	telegram := NewMockTelegramClient()
+	service := NewService(mockStore, telegram)
+	ctx := context.Background()
+
+	if err := mockStore.SaveChatSettings(ctx, &model.ChatSettings{ChatID: 42, ParseMode: model.ParseModeHTML}); err != nil {
+		t.Fatalf("SaveChatSettings() error = %v", err)
+	}
+
+	for _, chatID := range []int64{42, 7} {
+		video := &model.Video{ID: 1, Code: "ABC-123", Title: "A & B", DetailURL: "https://example.com/abc-123"}
+		if err := service.PushVideoToChat(ctx, video, chatID); err != nil {
+			t.Fatalf("PushVideoToChat() error = %v", err)
+		}
+	}
+
+	if len(telegram.parseModes) != 2 || telegram.parseModes[0] != "HTML" || telegram.parseModes[1] != "MarkdownV2" {
+		t.Errorf("parse modes = %v, want HTML for the chat choosing it and MarkdownV2 otherwise", telegram.parseModes)
+	}
+	if !strings.Contains(telegram.messages[0], "A &amp; B") {
+		t.Errorf("HTML message = %q, want escaped title", telegram.messages[0])
+	}
+}
+
+func TestRefreshPushedVideo_EditsOriginalMessage(t *testing.T) {
+	mockStore := NewMockStore()
+	telegram := NewMockTelegramClient()
+	service := NewService(mockStore, telegram)
+	ctx := context.Background()
+
+	video := &model.Video{ID: 1, Code: "ABC-123", Title: "Bare", DetailURL: "https://example.com/abc-123"}
+	if err := service.PushVideoToChat(ctx, video, 42); err != nil {
+		t.Fatalf("PushVideoToChat() error = %v", err)
+	}
+	record := mockStore.pushRecords[0]
+	if record.MessageID == 0 || record.MessageType != model.MessageTypeText {
+		t.Fatalf("push record message = %d/%q, want a text message ID", record.MessageID, record.MessageType)
+	}
+
+	video.Actresses = "Enriched Actress"
+	if err := service.RefreshPushedVideo(ctx, video); err != nil {
+		t.Fatalf("RefreshPushedVideo() error = %v", err)
+	}
+	if len(telegram.edits) != 1 || !strings.Contains(telegram.edits[0], "Enriched Actress") {
+		t.Errorf("edits = %v, want the message rewritten with the actress", telegram.edits)
+	}
+}
+
+func TestRevokeVideo_DeletesAlbumAndHidesVideo(t *testing.T) {
+	mockStore := NewMockStore()
+	telegram := NewMockTelegramClient()
+	service := NewService(mockStore, telegram)
+	ctx := context.Background()
+
+	video := &model.Video{
+		ID:          1,
+		Code:        "ABC-123",
+		CoverURL:    "https://example.com/cover.jpg",
+		Screenshots: []string{"https://example.com/1.jpg", "https://example.com/2.jpg"},
+		DetailURL:   "https://example.com/abc-123",
+	}
+	if err := mockStore.SaveVideo(ctx, video); err != nil {
+		t.Fatalf("SaveVideo() error = %v", err)
+	}
+	if err := service.PushVideoToChat(ctx, video, 42); err != nil {
+		t.Fatalf("PushVideoToChat() error = %v", err)
+	}
+
+	result, err := service.RevokeVideo(ctx, "abc-123")
+	if err != nil {
+		t.Fatalf("RevokeVideo() error = %v", err)
+	}
+	if result.Hidden != 1 || result.Deleted != 1 || result.Failed != 0 {
+		t.Errorf("RevokeVideo() = %+v, want 1 hidden and 1 deleted", result)
+	}
+	// The album of cover and two screenshots is three messages
+	if len(telegram.deleted) != 3 {
+		t.Errorf("deleted messages = %v, want the three album items", telegram.deleted)
+	}
+
+	if err := service.PushVideoToChat(ctx, video, 7); err != nil {
+		t.Fatalf("PushVideoToChat() error = %v", err)
+	}
+	if len(telegram.messages) != 1 {
+		t.Errorf("revoked video was pushed again, messages = %d", len(telegram.messages))
+	}
+
+	result, err = service.RevokeVideo(ctx, "ABC-123")
+	if err != nil {
+		t.Fatalf("RevokeVideo() error = %v", err)
+	}
+	if result.Deleted != 0 || len(telegram.deleted) != 3 {
+		t.Errorf("second revoke deleted messages again: %+v", result)
+	}
+}
+
+func TestPushVideoToChat_SkipsBlacklistedVideo(t *testing.T) {
+	mockStore := NewMockStore()
+	telegram := NewMockTelegramClient()
+	service := NewService(mockStore, telegram)
+	ctx := context.Background()
+
+	if err := mockStore.AddBlacklistEntry(ctx, &model.BlacklistEntry{ChatID: 42, Type: model.BlacklistTag, Keyword: "vr"}); err != nil {
+		t.Fatalf("AddBlacklistEntry() error = %v", err)
+	}
+
+	blacklisted := &model.Video{ID: 1, Code: "ABC-123", Tags: "Drama, VR", DetailURL: "https://example.com/abc-123"}
+	allowed := &model.Video{ID: 2, Code: "DEF-456", Tags: "Drama", DetailURL: "https://example.com/def-456"}
+	for _, video := range []*model.Video{blacklisted, allowed} {
+		if err := service.PushVideoToChat(ctx, video, 42); err != nil {
+			t.Fatalf("PushVideoToChat() error = %v", err)
+		}
+	}
+	if len(telegram.messages) != 1 {
+		t.Errorf("pushed %d messages, want only the video without the blacklisted tag", len(telegram.messages))
+	}
+}
+
+func TestPushVideoToChat_SkipsExcludedFormats(t *testing.T) {
+	mockStore := NewMockStore()
+	telegram := NewMockTelegramClient()
+	service := NewService(mockStore, telegram)
+	ctx := context.Background()
+
+	if err := mockStore.SaveChatSettings(ctx, &model.ChatSettings{ChatID: 42, SkipVR: true, MinDuration: 30}); err != nil {
+		t.Fatalf("SaveChatSettings() error = %v", err)
+	}
+
+	vr := &model.Video{ID: 1, Code: "SIVR-123", VR: true, Duration: 120, DetailURL: "https://example.com/sivr-123"}
+	clip := &model.Video{ID: 2, Code: "ABC-123", Duration: 12, Clip: true, DetailURL: "https://example.com/abc-123"}
+	full := &model.Video{ID: 3, Code: "DEF-456", Duration: 120, DetailURL: "https://example.com/def-456"}
+	unknown := &model.Video{ID: 4, Code: "GHI-789", DetailURL: "https://example.com/ghi-789"}
+	for _, video := range []*model.Video{vr, clip, full, unknown} {
+		if err := service.PushVideoToChat(ctx, video, 42); err != nil {
+			t.Fatalf("PushVideoToChat() error = %v", err)
+		}
+	}
+	if len(telegram.messages) != 2 {
+		t.Errorf("pushed %d messages, want only the full-length and unknown-length videos", len(telegram.messages))
+	}
+
+	if err := service.PushVideoToChat(ctx, vr, 7); err != nil {
+		t.Fatalf("PushVideoToChat() error = %v", err)
+	}
+	if len(telegram.messages) != 3 {
+		t.Errorf("VR video was not pushed to a chat without skipvr, messages = %d", len(telegram.messages))
+	}
+}
+
+// FailingTelegramClient fails every send, simulating Telegram outages
+type FailingTelegramClient struct{}
+
+func (f *FailingTelegramClient) SendMessage(chatID int64, text string) error {
+	return errors.New("telegram unavailable")
+}
+
+func (f *FailingTelegramClient) SendText(chatID int64, text string, parseMode string) (int, error) {
+	return 0, errors.New("telegram unavailable")
+}
+
+func (f *FailingTelegramClient) SendPhoto(chatID int64, photoURL string, caption string, parseMode string) (int, error) {
+	return 0, errors.New("telegram unavailable")
+}
+
+func (f *FailingTelegramClient) SendVideo(chatID int64, videoURL string, thumbURL string, caption string, parseMode string) (int, error) {
+	return 0, errors.New("telegram unavailable")
+}
+
+func (f *FailingTelegramClient) SendMediaGroup(chatID int64, videoURL string, photoURLs []string, caption string, parseMode string) (int, error) {
+	return 0, errors.New("telegram unavailable")
+}
+
+func (f *FailingTelegramClient) EditText(chatID int64, messageID int, text string, parseMode string) error {
+	return errors.New("telegram unavailable")
+}
+
+func (f *FailingTelegramClient) EditCaption(chatID int64, messageID int, caption string, parseMode string) error {
+	return errors.New("telegram unavailable")
+}
+
+func (f *FailingTelegramClient) DeleteMessage(chatID int64, messageID int) error {
+	return errors.New("telegram unavailable")
+}
+
+// Property: Durable Push Outbox
+// *For any* deliveries left queued by an interrupted fan-out, the next delivery pass SHALL
+// deliver each of them exactly once and leave the outbox empty.
+func TestProperty_PushOutboxResume(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 20
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("queued deliveries resume after restart", prop.ForAll(
+		func(numChats int, alreadyDelivered int) bool {
+			if alreadyDelivered > numChats {
+				alreadyDelivered = numChats
+			}
+
+			mockStore := NewMockStore()
+			ctx := context.Background()
+
+			video := &model.Video{ID: 7, Code: "TEST-300", DetailURL: "https://example.com/test", Pushed: true}
+			mockStore.SaveVideo(ctx, video)
+
+			var pending []*model.PendingPush
+			for chat := 1; chat <= numChats; chat++ {
+				pending = append(pending, &model.PendingPush{VideoID: video.ID, ChatID: int64(chat)})
+			}
+			mockStore.EnqueueVideoPushes(ctx, video.ID, pending)
+
+			// Some chats were reached before the crash, but their outbox rows survived
+			for chat := 1; chat <= alreadyDelivered; chat++ {
+				mockStore.RecordPush(ctx, &model.PushRecord{VideoID: video.ID, ChatID: int64(chat), Status: model.PushStatusSuccess})
+			}
+
+			// A fresh service after restart drains the outbox
+			service := NewService(mockStore, NewMockTelegramClient())
+			if err := service.DeliverPending(ctx); err != nil {
+				return false
+			}
+
+			for chat := 1; chat <= numChats; chat++ {
+				if mockStore.CountSuccessPushes(video.ID, int64(chat)) != 1 {
+					return false
+				}
+			}
+			remaining, _ := mockStore.GetPendingPushes(ctx, MaxPushAttempts, outboxBatchSize)
+			return len(remaining) == 0
+		},
+		gen.IntRange(1, 10),
+		gen.IntRange(0, 10),
+	))
+
+	properties.TestingRun(t)
+}
+
+func TestDeliverPending_FailedDeliveryStaysQueued(t *testing.T) {
+	mockStore := NewMockStore()
+	ctx := context.Background()
+
+	video := &model.Video{ID: 8, Code: "TEST-400", DetailURL: "https://example.com/test"}
+	mockStore.SaveVideo(ctx, video)
+	mockStore.EnqueueVideoPushes(ctx, video.ID, []*model.PendingPush{{VideoID: video.ID, ChatID: 99}})
+
+	service := NewService(mockStore, &FailingTelegramClient{})
+	if err := service.DeliverPending(ctx); err != nil {
+		t.Fatalf("DeliverPending() error = %v", err)
+	}
+
+	remaining, _ := mockStore.GetPendingPushes(ctx, MaxPushAttempts, outboxBatchSize)
+	if len(remaining) != 1 || remaining[0].Attempts != 1 {
+		t.Fatalf("pending after failure = %+v, want one row with 1 attempt", remaining)
+	}
+}
+
+func TestDeliverPending_AbandonsAfterMaxAttempts(t *testing.T) {
+	mockStore := NewMockStore()
+	ctx := context.Background()
+
+	video := &model.Video{ID: 9, Code: "TEST-401", DetailURL: "https://example.com/test"}
+	mockStore.SaveVideo(ctx, video)
+	mockStore.EnqueueVideoPushes(ctx, video.ID, []*model.PendingPush{{VideoID: video.ID, ChatID: 99}})
+
+	service := NewService(mockStore, &FailingTelegramClient{})
+	for i := 0; i < MaxPushAttempts; i++ {
+		if err := service.DeliverPending(ctx); err != nil {
+			t.Fatalf("DeliverPending() error = %v", err)
+		}
+	}
+
+	if remaining, _ := mockStore.GetPendingPushes(ctx, MaxPushAttempts, outboxBatchSize); len(remaining) != 0 {
+		t.Fatalf("pending after %d failures = %+v, want none", MaxPushAttempts, remaining)
+	}
+	if count, _ := mockStore.CountPendingPushes(ctx, MaxPushAttempts); count != 0 {
+		t.Fatalf("CountPendingPushes() = %d, want 0", count)
+	}
+	mockStore.mu.Lock()
+	defer mockStore.mu.Unlock()
+	if len(mockStore.pending) != 0 {
+		t.Fatalf("outbox still holds %d abandoned rows", len(mockStore.pending))
+	}
+}
+
+func TestPushVideoToChat_LogsPushID(t *testing.T) {
+	var buf bytes.Buffer
+	original := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = original }()
+
+	mockStore := NewMockStore()
+	ctx := logctx.With(context.Background(), logctx.RunID, "run1")
+	video := &model.Video{ID: 10, Code: "TEST-402", DetailURL: "https://example.com/test"}
+	mockStore.SaveVideo(ctx, video)
+
+	service := NewService(mockStore, NewMockTelegramClient())
+	if err := service.PushVideoToChat(ctx, video, 99); err != nil {
+		t.Fatalf("PushVideoToChat() error = %v", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if strings.Contains(line, "Successfully pushed video") {
+			if !strings.Contains(line, `"runID":"run1"`) || !strings.Contains(line, `"pushID":`) {
+				t.Errorf("delivery log entry %s lacks correlation IDs", line)
+			}
+			return
+		}
+	}
+	t.Errorf("no delivery log entry in %q", buf.String())
+}
+
+// TestDrain_LeavesWorkPending checks that a draining service starts no deliveries and
+// leaves unmatched videos unpushed and queued deliveries in the outbox
+func TestDrain_LeavesWorkPending(t *testing.T) {
+	mockStore := NewMockStore()
+	mockTelegram := NewMockTelegramClient()
+	service := NewService(mockStore, mockTelegram)
+	ctx := context.Background()
+
+	mockStore.CreateSubscription(ctx, &model.Subscription{ChatID: 1, Type: model.SubTypeAll, Enabled: true})
+	queued := &model.Video{ID: 1, Code: "TEST-900", DetailURL: "https://example.com/test-900"}
+	unpushed := &model.Video{ID: 2, Code: "TEST-901", DetailURL: "https://example.com/test-901"}
+	direct := &model.Video{ID: 3, Code: "TEST-902", DetailURL: "https://example.com/test-902"}
+	mockStore.SaveVideo(ctx, queued)
+	mockStore.SaveVideo(ctx, unpushed)
+	mockStore.SaveVideo(ctx, direct)
+	mockStore.EnqueueVideoPushes(ctx, queued.ID, []*model.PendingPush{{VideoID: queued.ID, ChatID: 1}})
+
+	if err := service.Drain(ctx); err != nil {
+		t.Fatalf("Drain() error = %v", err)
+	}
+	if err := service.PushUnpushedVideos(ctx); err != nil {
+		t.Fatalf("PushUnpushedVideos() error = %v", err)
+	}
+	if err := service.PushVideoToSubscribers(ctx, direct); err != nil {
+		t.Fatalf("PushVideoToSubscribers() error = %v", err)
+	}
+
+	if len(mockTelegram.messages) != 0 {
+		t.Errorf("sent %d messages while draining, want none", len(mockTelegram.messages))
+	}
+	if unpushed.Pushed {
+		t.Error("video matched while draining, want it left unpushed")
+	}
+	// The queued delivery stays and the direct one is persisted for the next start
+	if len(mockStore.pending) != 2 {
+		t.Errorf("outbox has %d pushes, want 2", len(mockStore.pending))
+	}
+}
+
+// TestDrain_TimesOut checks that Drain gives up when in-flight pushes outlast ctx
+func TestDrain_TimesOut(t *testing.T) {
+	service := NewService(NewMockStore(), NewMockTelegramClient())
+	if !service.begin() {
+		t.Fatal("begin() = false before draining")
+	}
+	defer service.end()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := service.Drain(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Drain() error = %v, want deadline exceeded", err)
+	}
+	if service.begin() {
+		t.Error("begin() = true while draining")
+	}
+}
@@ -0,0 +1,33 @@
+package push
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// previewNote precedes a subscription preview, so it is not mistaken for a new release
+const previewNote = "👀 推送预览：以下是最近一条匹配的视频，并非新发布。之后的新视频会以相同格式推送。"
+
+// PreviewSubscription sends a stored video to a new Telegram subscription exactly as
+// a push would look, after a note marking it as a preview
+// Nothing is recorded, so the video can still be pushed to the chat later.
+// Subscriptions on other platforms get no preview.
+func (s *Service) PreviewSubscription(ctx context.Context, sub *model.Subscription, video *model.Video) error {
+	target := subscriptionTarget(sub)
+	if target.Platform != model.PlatformTelegram {
+		return nil
+	}
+	target.Mentions = nil
+	target.ParseMode = s.ParseMode(ctx, target.ChatID)
+
+	bot := s.telegram.bot(target.BotID)
+	if err := bot.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limiter error: %w", err)
+	}
+	if err := bot.client.SendMessage(target.ChatID, previewNote); err != nil {
+		return fmt.Errorf("failed to send preview note: %w", err)
+	}
+	return s.telegram.NotifyVideo(ctx, target, video)
+}
@@ -0,0 +1,42 @@
+package push
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestPreviewSubscription(t *testing.T) {
+	mockStore := NewMockStore()
+	telegram := NewMockTelegramClient()
+	service := NewService(mockStore, telegram)
+
+	video := &model.Video{ID: 1, Code: "SSIS-001", Title: "Preview Title", CoverURL: "https://example.com/cover.jpg"}
+	sub := &model.Subscription{ChatID: 100, Type: model.SubTypeAll, Enabled: true}
+	if err := service.PreviewSubscription(context.Background(), sub, video); err != nil {
+		t.Fatalf("PreviewSubscription() error = %v", err)
+	}
+
+	if len(telegram.messages) != 2 {
+		t.Fatalf("sent %d messages, want the note and the video", len(telegram.messages))
+	}
+	if telegram.messages[0] != previewNote {
+		t.Errorf("first message = %q, want the preview note", telegram.messages[0])
+	}
+	if !strings.Contains(telegram.messages[1], "SSIS") {
+		t.Errorf("second message = %q, want the video caption", telegram.messages[1])
+	}
+	if len(mockStore.pushRecords) != 0 {
+		t.Errorf("recorded %d pushes, want none", len(mockStore.pushRecords))
+	}
+
+	discord := &model.Subscription{ChatID: 100, Type: model.SubTypeAll, Platform: model.PlatformDiscord, Target: "https://discord.invalid/hook"}
+	if err := service.PreviewSubscription(context.Background(), discord, video); err != nil {
+		t.Fatalf("PreviewSubscription() error = %v", err)
+	}
+	if len(telegram.messages) != 2 {
+		t.Errorf("Discord subscription sent %d more messages, want none", len(telegram.messages)-2)
+	}
+}
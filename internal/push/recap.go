@@ -0,0 +1,177 @@
+package push
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+const (
+	// RecapInterval is how often a chat that opted in gets a recap
+	RecapInterval = 7 * 24 * time.Hour
+	// recapHistoryLimit caps the pushes a recap summarizes
+	recapHistoryLimit = 500
+	// recapTopCount is the number of actresses and tags listed in a recap
+	recapTopCount = 5
+	// recapTitles is the number of titles listed in a recap
+	recapTitles = 5
+	// recapTitleRunes shortens the titles listed in a recap
+	recapTitleRunes = 40
+)
+
+// recapCount is an actress or tag with the number of pushed videos featuring it
+type recapCount struct {
+	name  string
+	count int
+}
+
+// weeklyRecap summarizes what a chat received and missed over a recap interval
+type weeklyRecap struct {
+	received  int
+	actresses []recapCount
+	tags      []recapCount
+	latest    []*model.Video
+	capped    int64
+}
+
+// buildRecap summarizes the pushes to a chat, newest first, and the matches its daily cap held back
+func buildRecap(history []*store.PushHistoryEntry, capped int64) *weeklyRecap {
+	recap := &weeklyRecap{received: len(history), capped: capped}
+	actresses := make(map[string]int)
+	tags := make(map[string]int)
+	for _, entry := range history {
+		for _, actress := range splitList(entry.Video.Actresses) {
+			actresses[actress]++
+		}
+		for _, tag := range splitList(entry.Video.Tags) {
+			tags[tag]++
+		}
+		if len(recap.latest) < recapTitles {
+			recap.latest = append(recap.latest, entry.Video)
+		}
+	}
+	recap.actresses = topRecapCounts(actresses)
+	recap.tags = topRecapCounts(tags)
+	return recap
+}
+
+// topRecapCounts returns the recapTopCount most frequent names, ties by name
+func topRecapCounts(counts map[string]int) []recapCount {
+	top := make([]recapCount, 0, len(counts))
+	for name, count := range counts {
+		top = append(top, recapCount{name: name, count: count})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].count != top[j].count {
+			return top[i].count > top[j].count
+		}
+		return top[i].name < top[j].name
+	})
+	if len(top) > recapTopCount {
+		top = top[:recapTopCount]
+	}
+	return top
+}
+
+// formatRecap renders a weekly recap as plain text
+func formatRecap(recap *weeklyRecap, since time.Time, now time.Time) string {
+	lines := []string{
+		fmt.Sprintf("📅 每周回顾 (%s - %s)", since.Format("01-02"), now.Format("01-02")),
+		fmt.Sprintf("📬 本周共推送 %d 个视频", recap.received),
+	}
+	if recap.capped > 0 {
+		lines = append(lines, fmt.Sprintf("📦 另有 %d 个匹配的视频因每日推送上限未推送", recap.capped))
+	}
+	if len(recap.actresses) > 0 {
+		lines = append(lines, "", "👩 演员:")
+		for _, actress := range recap.actresses {
+			lines = append(lines, fmt.Sprintf("  %s × %d", actress.name, actress.count))
+		}
+	}
+	if len(recap.tags) > 0 {
+		lines = append(lines, "", "🏷 标签:")
+		for _, tag := range recap.tags {
+			lines = append(lines, fmt.Sprintf("  %s × %d", tag.name, tag.count))
+		}
+	}
+	if len(recap.latest) > 0 {
+		lines = append(lines, "", "🎬 最新推送:")
+		for _, video := range recap.latest {
+			title := []rune(strings.TrimSpace(strings.TrimPrefix(video.Title, video.Code)))
+			if len(title) > recapTitleRunes {
+				title = append(title[:recapTitleRunes], '…')
+			}
+			lines = append(lines, strings.TrimSpace(fmt.Sprintf("  %s %s", video.Code, string(title))))
+		}
+	}
+	if recap.received == 0 && recap.capped == 0 {
+		lines = append(lines, "", "本周没有匹配订阅的新视频。使用 /latest 查看最新视频。")
+	}
+	lines = append(lines, "", "使用 /settings recap off 关闭每周回顾。")
+	return strings.Join(lines, "\n")
+}
+
+// SendWeeklyRecaps sends a recap to each chat that opted in and has not had one for
+// RecapInterval, summarizing the videos pushed to it and those its daily cap held back
+func (s *Service) SendWeeklyRecaps(ctx context.Context, now time.Time) {
+	chats, err := s.store.GetRecapChats(ctx)
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Failed to get weekly recap chats")
+		return
+	}
+
+	for _, settings := range chats {
+		if ctx.Err() != nil {
+			return
+		}
+		since := now.Add(-RecapInterval)
+		if settings.RecapSentAt != nil {
+			if now.Sub(*settings.RecapSentAt) < RecapInterval {
+				continue
+			}
+			since = *settings.RecapSentAt
+		}
+		if err := s.sendRecap(ctx, settings, since, now); err != nil {
+			logctx.From(ctx).Error().Err(err).Int64("chatID", settings.ChatID).Msg("Failed to send weekly recap")
+		}
+	}
+}
+
+// sendRecap sends one chat its recap of the pushes since a time and records it as sent
+func (s *Service) sendRecap(ctx context.Context, settings *model.ChatSettings, since time.Time, now time.Time) error {
+	chatID := settings.ChatID
+	history, err := s.store.GetPushHistorySince(ctx, chatID, since, recapHistoryLimit)
+	if err != nil {
+		return err
+	}
+	capped, err := s.store.CountCappedPushesSince(ctx, chatID, since)
+	if err != nil {
+		return err
+	}
+
+	recap := buildRecap(history, capped)
+	bot := s.telegram.bot(s.chatBotID(ctx, chatID))
+	if err := bot.limiter.Wait(ctx); err != nil {
+		return fmt.Errorf("rate limiter error: %w", err)
+	}
+	if err := bot.client.SendMessage(chatID, formatRecap(recap, since, now)); err != nil {
+		return fmt.Errorf("failed to send recap: %w", err)
+	}
+
+	settings.RecapSentAt = &now
+	if err := s.store.SaveChatSettings(ctx, settings); err != nil {
+		return err
+	}
+	logctx.From(ctx).Info().
+		Int64("chatID", chatID).
+		Int("received", recap.received).
+		Int64("capped", capped).
+		Msg("Sent weekly recap")
+	return nil
+}
@@ -0,0 +1,86 @@
+package push
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+func TestBuildRecap(t *testing.T) {
+	history := []*store.PushHistoryEntry{
+		{Video: &model.Video{Code: "ABC-003", Title: "ABC-003 Third", Actresses: "Alice, Bob", Tags: "Drama"}},
+		{Video: &model.Video{Code: "ABC-002", Title: "ABC-002 Second", Actresses: "Alice", Tags: "Drama, Comedy"}},
+		{Video: &model.Video{Code: "ABC-001", Title: "ABC-001 First", Actresses: "Carol"}},
+	}
+	recap := buildRecap(history, 4)
+
+	if recap.received != 3 || recap.capped != 4 {
+		t.Errorf("received %d, capped %d; want 3, 4", recap.received, recap.capped)
+	}
+	if len(recap.actresses) != 3 || recap.actresses[0] != (recapCount{"Alice", 2}) || recap.actresses[1].name != "Bob" {
+		t.Errorf("actresses = %v, want Alice first, then ties by name", recap.actresses)
+	}
+	if len(recap.tags) != 2 || recap.tags[0] != (recapCount{"Drama", 2}) {
+		t.Errorf("tags = %v, want Drama first", recap.tags)
+	}
+
+	since := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	text := formatRecap(recap, since, since.Add(RecapInterval))
+	for _, want := range []string{"02-01 - 02-08", "共推送 3 个视频", "4 个匹配的视频因每日推送上限未推送", "Alice × 2", "Drama × 2", "ABC-003 Third", "/settings recap off"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("recap missing %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestSendWeeklyRecaps(t *testing.T) {
+	mockStore := NewMockStore()
+	telegram := NewMockTelegramClient()
+	service := NewService(mockStore, telegram)
+	ctx := context.Background()
+	now := time.Now()
+
+	lastWeek := now.Add(-RecapInterval - time.Hour)
+	yesterday := now.Add(-24 * time.Hour)
+	for _, settings := range []*model.ChatSettings{
+		{ChatID: 42, WeeklyRecap: true, RecapSentAt: &lastWeek},
+		{ChatID: 7, WeeklyRecap: true, RecapSentAt: &yesterday},
+		{ChatID: 9},
+	} {
+		if err := mockStore.SaveChatSettings(ctx, settings); err != nil {
+			t.Fatalf("SaveChatSettings() error = %v", err)
+		}
+	}
+	if err := mockStore.SaveVideo(ctx, &model.Video{ID: 1, Code: "ABC-123", Actresses: "Alice"}); err != nil {
+		t.Fatalf("SaveVideo() error = %v", err)
+	}
+	for _, record := range []*model.PushRecord{
+		{VideoID: 1, ChatID: 42, Status: model.PushStatusSuccess, PushedAt: now.Add(-time.Hour)},
+		{VideoID: 1, ChatID: 42, Status: model.PushStatusCappedReported, PushedAt: now.Add(-2 * time.Hour)},
+	} {
+		if err := mockStore.RecordPush(ctx, record); err != nil {
+			t.Fatalf("RecordPush() error = %v", err)
+		}
+	}
+
+	service.SendWeeklyRecaps(ctx, now)
+	if len(telegram.messages) != 1 {
+		t.Fatalf("sent %d recaps, want one to the chat that is due", len(telegram.messages))
+	}
+	if text := telegram.messages[0]; !strings.Contains(text, "共推送 1 个视频") || !strings.Contains(text, "1 个匹配的视频") {
+		t.Errorf("recap = %q, want one pushed and one capped video", text)
+	}
+
+	settings, _ := mockStore.GetChatSettings(ctx, 42)
+	if settings.RecapSentAt == nil || !settings.RecapSentAt.Equal(now) {
+		t.Errorf("RecapSentAt = %v, want the send time", settings.RecapSentAt)
+	}
+	service.SendWeeklyRecaps(ctx, now.Add(time.Hour))
+	if len(telegram.messages) != 1 {
+		t.Errorf("recap was sent again within the week")
+	}
+}
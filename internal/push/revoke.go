@@ -0,0 +1,86 @@
+package push
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// RevokeResult summarizes the revocation of a video
+type RevokeResult struct {
+	// Code is the canonical code that was revoked
+	Code string `json:"code"`
+	// Hidden is the number of video records marked hidden
+	Hidden int64 `json:"hidden"`
+	// Deleted is the number of pushes whose messages were deleted
+	Deleted int `json:"deleted"`
+	// Failed is the number of pushes whose messages could not be deleted
+	Failed int `json:"failed"`
+}
+
+// RevokeVideo hides every video of a code so it is not pushed again and deletes the
+// Telegram messages it was pushed as. Messages the bot may not delete are counted
+// as failed and kept on record, so a later revoke retries them.
+func (s *Service) RevokeVideo(ctx context.Context, code string) (*RevokeResult, error) {
+	result := &RevokeResult{Code: model.CanonicalCode(code)}
+
+	hidden, err := s.store.HideVideos(ctx, result.Code)
+	if err != nil {
+		return nil, err
+	}
+	result.Hidden = hidden
+
+	result.Deleted, result.Failed, err = s.DeletePushedMessages(ctx, result.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	logctx.From(ctx).Info().
+		Str("code", result.Code).
+		Int64("hidden", result.Hidden).
+		Int("deleted", result.Deleted).
+		Int("failed", result.Failed).
+		Msg("Revoked video")
+	return result, nil
+}
+
+// DeletePushedMessages deletes the Telegram messages a canonical code was pushed as
+// and returns how many pushes were deleted and how many failed. Messages the bot may
+// not delete are kept on record, so a later call retries them.
+func (s *Service) DeletePushedMessages(ctx context.Context, code string) (deleted int, failed int, err error) {
+	deleter, ok := s.notifiers[model.PlatformTelegram].(editableNotifier)
+	if !ok {
+		return 0, 0, nil
+	}
+
+	records, err := s.store.GetPushedMessages(ctx, code)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, record := range records {
+		target := Target{ChatID: record.ChatID, Platform: model.PlatformTelegram, BotID: record.BotID}
+		if err := s.telegram.bot(target.BotID).limiter.Wait(ctx); err != nil {
+			return deleted, failed, fmt.Errorf("rate limiter error: %w", err)
+		}
+
+		sent := sentMessage{id: record.MessageID, kind: record.MessageType, count: record.MessageCount}
+		if err := deleter.deleteMessages(ctx, target, sent); err != nil {
+			logctx.From(ctx).Warn().
+				Err(err).
+				Str("code", code).
+				Int64("chatID", target.ChatID).
+				Int("messageID", record.MessageID).
+				Msg("Failed to delete pushed message")
+			failed++
+			continue
+		}
+		if err := s.store.ClearPushMessage(ctx, record.ID); err != nil {
+			logctx.From(ctx).Error().Err(err).Uint("recordID", record.ID).Msg("Failed to clear deleted push message")
+		}
+		deleted++
+	}
+	return deleted, failed, nil
+}
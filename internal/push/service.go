@@ -3,10 +3,12 @@ package push
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/logctx"
 	"github.com/user/missav-bot-go/internal/model"
 	"github.com/user/missav-bot-go/internal/store"
 	"golang.org/x/time/rate"
@@ -15,26 +17,117 @@ import (
 // TelegramClient defines the interface for sending Telegram messages
 type TelegramClient interface {
 	SendMessage(chatID int64, text string) error
-	SendMarkdown(chatID int64, text string) error
-	SendPhoto(chatID int64, photoURL string, caption string) error
-	SendVideo(chatID int64, videoURL string, thumbURL string, caption string) error
+	// The methods below format text in parseMode, a Telegram parse_mode value such as "HTML",
+	// and return the ID of the sent message
+	SendText(chatID int64, text string, parseMode string) (int, error)
+	SendPhoto(chatID int64, photoURL string, caption string, parseMode string) (int, error)
+	SendVideo(chatID int64, videoURL string, thumbURL string, caption string, parseMode string) (int, error)
+	SendMediaGroup(chatID int64, videoURL string, photoURLs []string, caption string, parseMode string) (int, error)
+	EditText(chatID int64, messageID int, text string, parseMode string) error
+	EditCaption(chatID int64, messageID int, caption string, parseMode string) error
+	DeleteMessage(chatID int64, messageID int) error
 }
 
+const (
+	// MaxPushAttempts is the number of delivery attempts before a queued push is abandoned
+	MaxPushAttempts = 3
+	// outboxBatchSize is the maximum number of queued pushes delivered per cycle
+	outboxBatchSize = 1000
+)
+
 // Service handles pushing video notifications to subscribers
 type Service struct {
-	store    store.Store
-	telegram TelegramClient
-	limiter  *rate.Limiter // Telegram rate limit: max 30 msg/sec globally
+	store        store.Store
+	notifiers    map[string]Notifier // keyed by model.Platform*
+	config       *ServiceConfig
+	telegram     *telegramNotifier // Telegram bots and their global rate limits
+	chatLimiters map[int64]*rate.Limiter
+	chatMu       sync.Mutex
+	webhooks     *WebhookNotifier // nil when no webhook URLs are configured
+	drain        drainState
+}
+
+// ServiceConfig holds configuration for the push service
+type ServiceConfig struct {
+	// Workers is the number of concurrent push workers
+	Workers int
+	// WebhookURLs receive a signed JSON POST for each new matched video
+	WebhookURLs []string
+	// WebhookSecret is the HMAC-SHA256 key used to sign webhook bodies (unsigned if empty)
+	WebhookSecret string
+	// WebhookTimeout bounds each webhook request
+	WebhookTimeout time.Duration
+	// DefaultChatID receives every new video regardless of subscriptions (0 disables)
+	DefaultChatID int64
+	// DailyCap limits the videos pushed to a Telegram chat per day (0 disables)
+	DailyCap int
+	// BatchSize is the maximum number of videos listed in one combined message
+	BatchSize int
+	// BatchThreshold combines deliveries bringing a chat this many videos (0 disables)
+	// Chats choose single or combined messages regardless with ChatSettings.PushMode.
+	BatchThreshold int
+	// ParseMode is the markup of Telegram messages; chats may choose their own
+	ParseMode model.ParseMode
+	// ChatDelay is the minimum spacing between messages to one chat (0 disables)
+	ChatDelay time.Duration
 }
 
-// NewService creates a new push service
+// DefaultServiceConfig returns default push service configuration
+func DefaultServiceConfig() *ServiceConfig {
+	return &ServiceConfig{
+		Workers:        4,
+		WebhookTimeout: 10 * time.Second,
+		BatchSize:      defaultBatchSize,
+		BatchThreshold: 5,
+		ParseMode:      model.ParseModeMarkdown,
+		ChatDelay:      time.Second,
+	}
+}
+
+// NewService creates a new push service with default configuration
 func NewService(store store.Store, telegram TelegramClient) *Service {
-	return &Service{
-		store:    store,
-		telegram: telegram,
-		// Telegram rate limit: 30 messages per second globally
-		limiter: rate.NewLimiter(rate.Limit(30), 1),
+	return NewServiceWithConfig(store, telegram, DefaultServiceConfig())
+}
+
+// NewServiceWithConfig creates a new push service with custom configuration
+func NewServiceWithConfig(store store.Store, telegram TelegramClient, cfg *ServiceConfig) *Service {
+	if cfg == nil {
+		cfg = DefaultServiceConfig()
+	}
+	if cfg.Workers < 1 {
+		cfg.Workers = 1
 	}
+	if cfg.BatchSize < 2 {
+		cfg.BatchSize = defaultBatchSize
+	}
+
+	telegramBots := newTelegramNotifier(telegram)
+	s := &Service{
+		store: store,
+		notifiers: map[string]Notifier{
+			model.PlatformTelegram: telegramBots,
+			model.PlatformDiscord:  NewDiscordNotifier(),
+		},
+		config:       cfg,
+		telegram:     telegramBots,
+		chatLimiters: make(map[int64]*rate.Limiter),
+	}
+	if len(cfg.WebhookURLs) > 0 {
+		s.webhooks = NewWebhookNotifier(cfg.WebhookURLs, cfg.WebhookSecret, cfg.WebhookTimeout)
+	}
+	return s
+}
+
+// RegisterNotifier sets the notifier used for a platform, replacing any existing one
+func (s *Service) RegisterNotifier(platform string, notifier Notifier) {
+	s.notifiers[platform] = notifier
+}
+
+// RegisterTelegramBot adds a further bot that pushes to the chats subscribed through it
+// botID is the bot's Telegram user ID, as stored in Subscription.BotID.
+// Each bot has its own global rate limit.
+func (s *Service) RegisterTelegramBot(botID int64, client TelegramClient) {
+	s.telegram.register(botID, client)
 }
 
 // MatchesSubscription checks if a video matches a subscription
@@ -65,127 +158,673 @@ func MatchesSubscription(video *model.Video, sub *model.Subscription) bool {
 	}
 }
 
-// PushUnpushedVideos fetches all unpushed videos and pushes them to matching subscribers
+// PushUnpushedVideos matches all unpushed videos against subscribers, queues the
+// deliveries in the push outbox, then delivers everything pending in the outbox
+// Matched videos are announced to the configured webhooks after Telegram delivery
 func (s *Service) PushUnpushedVideos(ctx context.Context) error {
+	if !s.begin() {
+		logctx.From(ctx).Info().Msg("Push service draining, skipping push of unpushed videos")
+		return nil
+	}
+	defer s.end()
+
 	videos, err := s.store.GetUnpushedVideos(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to get unpushed videos: %w", err)
 	}
 
-	log.Info().Int("count", len(videos)).Msg("Found unpushed videos")
+	logctx.From(ctx).Info().Int("count", len(videos)).Msg("Found unpushed videos")
+
+	// Track canonical codes in this batch so mirrors of one release are pushed once
+	seenCodes := make(map[string]bool)
+	var matched []*WebhookPayload
+	// Videos with nothing to deliver are marked pushed together after matching
+	var unmatched []uint
 
 	for _, video := range videos {
-		if err := s.PushVideoToSubscribers(ctx, video); err != nil {
-			log.Error().Err(err).Str("code", video.Code).Msg("Failed to push video to subscribers")
+		if s.isDraining() {
+			// The rest stay unpushed until the next start
+			logctx.From(ctx).Info().Msg("Push service draining, leaving remaining videos unpushed")
+			break
+		}
+		code := model.CanonicalCode(video.Code)
+		if seenCodes[code] {
+			logctx.From(ctx).Info().Str("code", video.Code).Msg("Duplicate release in batch, skipping push")
+			unmatched = append(unmatched, video.ID)
 			continue
 		}
+		seenCodes[code] = true
 
-		// Mark video as pushed after successful push to all subscribers
-		if err := s.store.MarkAsPushed(ctx, video.ID); err != nil {
-			log.Error().Err(err).Str("code", video.Code).Msg("Failed to mark video as pushed")
+		jobs, err := s.buildJobs(ctx, video)
+		if err != nil {
+			logctx.From(ctx).Error().Err(err).Str("code", video.Code).Msg("Failed to match video to subscribers")
+			continue
+		}
+		if len(jobs) == 0 {
+			unmatched = append(unmatched, video.ID)
+			continue
+		}
+
+		pending := make([]*model.PendingPush, 0, len(jobs))
+		for _, job := range jobs {
+			pending = append(pending, newPendingPush(job))
+		}
+		// Queue deliveries and mark the video as pushed atomically
+		if err := s.store.EnqueueVideoPushes(ctx, video.ID, pending); err != nil {
+			logctx.From(ctx).Error().Err(err).Str("code", video.Code).Msg("Failed to enqueue video pushes")
+			continue
 		}
+		if s.webhooks != nil {
+			matched = append(matched, NewWebhookPayload(video, len(jobs)))
+		}
+	}
+
+	if err := s.store.MarkAsPushedBulk(ctx, unmatched); err != nil {
+		logctx.From(ctx).Error().Err(err).Int("count", len(unmatched)).Msg("Failed to mark videos as pushed")
+	}
+
+	err = s.DeliverPending(ctx)
+	for _, payload := range matched {
+		s.webhooks.Notify(ctx, payload)
 	}
+	s.sendCapSummaries(ctx, dayStart(time.Now()))
+	return err
+}
 
+// newPendingPush creates the outbox row of a delivery
+func newPendingPush(job pushJob) *model.PendingPush {
+	return &model.PendingPush{
+		VideoID:  job.video.ID,
+		ChatID:   job.target.ChatID,
+		Platform: job.target.Platform,
+		Target:   job.target.Address,
+		BotID:    job.target.BotID,
+		Priority: job.priority,
+		Mentions: formatMentionIDs(job.target.Mentions),
+	}
+}
+
+// dayStart returns midnight of t's day in t's location; daily caps reset then
+func dayStart(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// dailyCapRemaining returns how many more videos a chat may receive today
+// limited is false when no cap applies to the target.
+func (s *Service) dailyCapRemaining(ctx context.Context, target Target) (remaining int, limited bool, err error) {
+	if s.config.DailyCap <= 0 || target.Platform != model.PlatformTelegram {
+		return 0, false, nil
+	}
+	count, err := s.store.CountPushesSince(ctx, target.ChatID, dayStart(time.Now()))
+	if err != nil {
+		return 0, false, err
+	}
+	return s.config.DailyCap - int(count), true, nil
+}
+
+// sendCapSummaries tells each chat how many matches its daily cap held back before a time
+// Each chat gets one "and N more" message per day instead of the videos themselves.
+func (s *Service) sendCapSummaries(ctx context.Context, before time.Time) {
+	if s.config.DailyCap <= 0 {
+		return
+	}
+
+	counts, err := s.store.ReportCappedPushes(ctx, before)
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Failed to collect capped pushes")
+		return
+	}
+
+	for chatID, count := range counts {
+		text := fmt.Sprintf("📦 还有 %d 个匹配的新视频因每日推送上限 (%d 条) 未推送。\n使用 /latest 查看最新视频。", count, s.config.DailyCap)
+		if err := s.telegram.bot(s.chatBotID(ctx, chatID)).client.SendMessage(chatID, text); err != nil {
+			logctx.From(ctx).Error().Err(err).Int64("chatID", chatID).Msg("Failed to send daily cap summary")
+			continue
+		}
+		logctx.From(ctx).Info().Int64("chatID", chatID).Int64("capped", count).Msg("Sent daily cap summary")
+	}
+}
+
+// chatBotID returns the bot serving a chat's Telegram subscriptions, 0 for the primary bot
+func (s *Service) chatBotID(ctx context.Context, chatID int64) int64 {
+	subs, err := s.store.GetSubscriptions(ctx, chatID)
+	if err != nil {
+		logctx.From(ctx).Warn().Err(err).Int64("chatID", chatID).Msg("Failed to look up chat bot, using primary bot")
+		return 0
+	}
+	for _, sub := range subs {
+		if sub.NotifyPlatform() == model.PlatformTelegram {
+			return sub.BotID
+		}
+	}
+	return 0
+}
+
+// DeliverPending delivers queued pushes from the outbox through the worker pool
+// Deliveries interrupted by a crash or restart are picked up again here
+func (s *Service) DeliverPending(ctx context.Context) error {
+	if !s.begin() {
+		return nil
+	}
+	defer s.end()
+
+	pending, err := s.store.GetPendingPushes(ctx, MaxPushAttempts, outboxBatchSize)
+	if err != nil {
+		return fmt.Errorf("failed to get pending pushes: %w", err)
+	}
+
+	if len(pending) > 0 {
+		logctx.From(ctx).Info().Int("count", len(pending)).Msg("Delivering pending pushes")
+	}
+
+	jobs := make([]pushJob, 0, len(pending))
+	for _, p := range pending {
+		if p.Video == nil {
+			// Video no longer exists, drop the delivery
+			if err := s.store.CompletePendingPush(ctx, p.ID); err != nil {
+				logctx.From(ctx).Error().Err(err).Uint("pendingID", p.ID).Msg("Failed to drop orphaned pending push")
+			}
+			continue
+		}
+		jobs = append(jobs, pushJob{video: p.Video, target: pendingTarget(p), pendingID: p.ID, priority: p.Priority})
+	}
+
+	s.deliver(ctx, jobs)
 	return nil
 }
 
 // PushVideoToSubscribers pushes a video to all matching subscribers
 func (s *Service) PushVideoToSubscribers(ctx context.Context, video *model.Video) error {
+	jobs, err := s.buildJobs(ctx, video)
+	if err != nil {
+		return err
+	}
+
+	if !s.begin() {
+		s.requeue(ctx, jobs)
+		return nil
+	}
+	defer s.end()
+	s.deliver(ctx, jobs)
+	return nil
+}
+
+// pushJob is a single delivery of a video to a chat
+type pushJob struct {
+	video     *model.Video
+	target    Target
+	pendingID uint // Outbox row to settle after delivery (0 if not queued)
+	priority  int  // model.PushPriority* of the best subscription matching the chat
+}
+
+// buildJobs finds the matching subscribers of a video and creates one job per chat
+// The default chat, when configured, gets a job whether or not it subscribes.
+// Personal subscriptions in a group add their subscriber to the group job's mentions.
+// Chats matched by an ACTRESS or TAG subscription come before the ALL fan-out.
+func (s *Service) buildJobs(ctx context.Context, video *model.Video) ([]pushJob, error) {
 	subs, err := s.store.GetMatchingSubscriptions(ctx, video)
 	if err != nil {
-		return fmt.Errorf("failed to get matching subscriptions: %w", err)
+		return nil, fmt.Errorf("failed to get matching subscriptions: %w", err)
 	}
 
-	log.Info().
+	logctx.From(ctx).Info().
 		Str("code", video.Code).
 		Int("subscribers", len(subs)).
-		Msg("Pushing video to subscribers")
+		Msg("Matched video to subscribers")
 
-	// Track which chats we've already pushed to (for deduplication)
-	pushedChats := make(map[int64]bool)
+	// Track which chats already have a job (for deduplication)
+	seenChats := make(map[int64]int)
 
+	var jobs []pushJob
 	for _, sub := range subs {
-		// Skip if we've already pushed to this chat
-		if pushedChats[sub.ChatID] {
+		target := subscriptionTarget(sub)
+		if i, ok := seenChats[target.ChatID]; ok {
+			jobs[i].priority = max(jobs[i].priority, matchPriority(sub))
+			jobs[i].target.Mentions = mergeMentions(jobs[i].target.Mentions, target.Mentions)
 			continue
 		}
+		seenChats[target.ChatID] = len(jobs)
+		jobs = append(jobs, pushJob{video: video, target: target, priority: matchPriority(sub)})
+	}
 
-		if err := s.PushVideoToChat(ctx, video, sub.ChatID); err != nil {
-			log.Error().
-				Err(err).
-				Str("code", video.Code).
-				Int64("chatID", sub.ChatID).
-				Msg("Failed to push video to chat")
-			continue
+	// The default chat is fed every video; push records dedup it like any other chat
+	if chatID := s.config.DefaultChatID; chatID != 0 {
+		if _, ok := seenChats[chatID]; !ok {
+			jobs = append(jobs, pushJob{video: video, target: Target{ChatID: chatID, Platform: model.PlatformTelegram}})
 		}
+	}
+
+	sort.SliceStable(jobs, func(i, j int) bool {
+		return jobs[i].priority > jobs[j].priority
+	})
+	return s.dropPushedJobs(ctx, video, jobs)
+}
 
-		pushedChats[sub.ChatID] = true
+// matchPriority returns the delivery priority of a push matched by a subscription
+func matchPriority(sub *model.Subscription) int {
+	if sub.Type == model.SubTypeAll {
+		return model.PushPriorityGeneral
+	}
+	return model.PushPrioritySpecific
+}
 
-		// Add 1 second delay between messages to same chat (Requirement 5.10)
-		time.Sleep(1 * time.Second)
+// dropPushedJobs leaves out the jobs of chats that already received the video or its release
+// The push history of all chats is checked in one query; pushVideo checks again on delivery.
+func (s *Service) dropPushedJobs(ctx context.Context, video *model.Video, jobs []pushJob) ([]pushJob, error) {
+	if len(jobs) == 0 {
+		return jobs, nil
 	}
 
-	return nil
+	chatIDs := make([]int64, 0, len(jobs))
+	for _, job := range jobs {
+		chatIDs = append(chatIDs, job.target.ChatID)
+	}
+	unpushed, err := s.store.FilterUnpushedChats(ctx, video.ID, model.CanonicalCode(video.Code), chatIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check push history: %w", err)
+	}
+
+	keep := make(map[int64]bool, len(unpushed))
+	for _, chatID := range unpushed {
+		keep[chatID] = true
+	}
+	kept := jobs[:0]
+	for _, job := range jobs {
+		if keep[job.target.ChatID] {
+			kept = append(kept, job)
+		}
+	}
+	return kept, nil
+}
+
+// deliver sends jobs through a bounded pool of workers and waits for them to finish
+// Jobs planned into a batch are delivered together as one combined message.
+// Different chats are served in parallel, but a chat's units go out one at a time so
+// its per-chat checks, such as the daily cap, never race.
+// Global and per-chat rate limits are enforced in pushVideo
+// Once the service drains, units not yet started are left for the next start.
+func (s *Service) deliver(ctx context.Context, jobs []pushJob) {
+	if len(jobs) == 0 {
+		return
+	}
+
+	queue := newDeliveryQueue(s.planDeliveries(ctx, jobs))
+	workers := s.config.Workers
+	if workers > len(queue.units) {
+		workers = len(queue.units)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				unit, ok := queue.next(ctx)
+				if !ok {
+					return
+				}
+				if s.isDraining() {
+					s.requeue(ctx, unit)
+					queue.done(unit)
+					continue
+				}
+				s.deliverUnit(ctx, unit)
+				queue.done(unit)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// deliverUnit sends one planned unit, a single video or a combined batch, and settles its jobs
+// Each delivery gets a push ID in its log entries.
+func (s *Service) deliverUnit(ctx context.Context, unit []pushJob) {
+	ctx = logctx.With(ctx, logctx.PushID, logctx.NewID())
+	var err error
+	if len(unit) == 1 {
+		err = s.pushVideo(ctx, unit[0].video, unit[0].target)
+	} else {
+		err = s.pushBatch(ctx, unit)
+	}
+	if err != nil {
+		logctx.From(ctx).Error().
+			Err(err).
+			Str("code", unit[0].video.Code).
+			Int("videos", len(unit)).
+			Int64("chatID", unit[0].target.ChatID).
+			Msg("Failed to push video to chat")
+	}
+	for _, job := range unit {
+		s.settle(ctx, job, err)
+	}
+}
+
+// destination identifies where a delivery unit goes
+type destination struct {
+	platform string
+	chatID   int64
+	address  string
+}
+
+// unitDestination returns the destination of a delivery unit
+func unitDestination(unit []pushJob) destination {
+	target := unit[0].target
+	return destination{platform: target.Platform, chatID: target.ChatID, address: target.Address}
+}
+
+// deliveryQueue hands out delivery units in order, skipping units whose destination is
+// being delivered to by another worker until that delivery is done
+type deliveryQueue struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	units [][]pushJob
+	busy  map[destination]bool
+}
+
+// newDeliveryQueue creates a queue of planned delivery units
+func newDeliveryQueue(units [][]pushJob) *deliveryQueue {
+	q := &deliveryQueue{units: units, busy: make(map[destination]bool)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// next returns the first unit whose destination is idle, waiting while every remaining
+// unit's destination is busy. It returns false once the queue is empty or ctx is done.
+func (q *deliveryQueue) next(ctx context.Context) ([]pushJob, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for {
+		if len(q.units) == 0 || ctx.Err() != nil {
+			return nil, false
+		}
+		for i, unit := range q.units {
+			dest := unitDestination(unit)
+			if q.busy[dest] {
+				continue
+			}
+			q.busy[dest] = true
+			q.units = append(q.units[:i], q.units[i+1:]...)
+			return unit, true
+		}
+		q.cond.Wait()
+	}
+}
+
+// done marks the destination of a unit returned by next idle again
+func (q *deliveryQueue) done(unit []pushJob) {
+	q.mu.Lock()
+	delete(q.busy, unitDestination(unit))
+	q.mu.Unlock()
+	q.cond.Broadcast()
 }
 
+// settle updates the outbox row of a job after a delivery attempt
+// Failed deliveries stay queued for retry until MaxPushAttempts is reached;
+// interrupted ones are left untouched
+func (s *Service) settle(ctx context.Context, job pushJob, sendErr error) {
+	if job.pendingID == 0 || ctx.Err() != nil {
+		return
+	}
+
+	if sendErr == nil {
+		if err := s.store.CompletePendingPush(ctx, job.pendingID); err != nil {
+			logctx.From(ctx).Error().Err(err).Uint("pendingID", job.pendingID).Msg("Failed to settle pending push")
+		}
+		return
+	}
 
-// PushVideoToChat pushes a video to a specific chat
+	abandoned, err := s.store.FailPendingPush(ctx, job.pendingID, MaxPushAttempts)
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Uint("pendingID", job.pendingID).Msg("Failed to settle pending push")
+		return
+	}
+	if abandoned {
+		logctx.From(ctx).Error().Err(sendErr).
+			Uint("pendingID", job.pendingID).
+			Str("code", job.video.Code).
+			Int64("chatID", job.target.ChatID).
+			Int("attempts", MaxPushAttempts).
+			Msg("Giving up on pending push")
+	}
+}
+
+// chatLimiter returns the rate limiter for a chat, creating it if necessary
+// Telegram allows about one message per second to the same chat (Requirement 5.10);
+// messages are spaced by ChatDelay, and not at all when it is 0.
+func (s *Service) chatLimiter(chatID int64) *rate.Limiter {
+	s.chatMu.Lock()
+	defer s.chatMu.Unlock()
+
+	limiter, ok := s.chatLimiters[chatID]
+	if !ok {
+		limit := rate.Inf
+		if s.config.ChatDelay > 0 {
+			limit = rate.Every(s.config.ChatDelay)
+		}
+		limiter = rate.NewLimiter(limit, 1)
+		s.chatLimiters[chatID] = limiter
+	}
+	return limiter
+}
+
+
+// PushVideoToChat pushes a video to a specific Telegram chat
 // It checks for duplicates before pushing and records the push result
 func (s *Service) PushVideoToChat(ctx context.Context, video *model.Video, chatID int64) error {
+	ctx = logctx.With(ctx, logctx.PushID, logctx.NewID())
+	return s.pushVideo(ctx, video, Target{ChatID: chatID, Platform: model.PlatformTelegram})
+}
+
+// pushVideo delivers a video to a target through its platform's notifier
+// It checks for duplicates before pushing and records the push result
+func (s *Service) pushVideo(ctx context.Context, video *model.Video, target Target) error {
+	chatID := target.ChatID
+	notifier, ok := s.notifiers[target.Platform]
+	if !ok {
+		return fmt.Errorf("no notifier for platform %q", target.Platform)
+	}
+
+	skip, err := s.shouldSkip(ctx, video, chatID)
+	if err != nil || skip {
+		return err
+	}
+
+	// Wait for per-chat rate limiter (Requirement 5.10)
+	if err := s.chatLimiter(chatID).Wait(ctx); err != nil {
+		return fmt.Errorf("chat rate limiter error: %w", err)
+	}
+
+	// Hold back matches over the chat's daily cap; they are summarized the next day.
+	// Checked after the per-chat limiter so the chat's previous push is already recorded.
+	remaining, limited, err := s.dailyCapRemaining(ctx, target)
+	if err != nil {
+		return fmt.Errorf("failed to check daily push cap: %w", err)
+	}
+
+	if limited && remaining <= 0 {
+		return s.recordCapped(ctx, video, chatID)
+	}
+
+	// Wait for the global rate limiter of the delivering bot (Requirement 5.9)
+	if target.Platform == model.PlatformTelegram {
+		if err := s.telegram.bot(target.BotID).limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limiter error: %w", err)
+		}
+	}
+
+	if target.Platform == model.PlatformTelegram {
+		target.ParseMode = s.ParseMode(ctx, chatID)
+	}
+
+	// Keep the message ID where possible so the post can be edited after enrichment
+	var sent sentMessage
+	var sendErr error
+	if editor, ok := notifier.(editableNotifier); ok {
+		sent, sendErr = editor.sendVideo(ctx, target, video)
+	} else {
+		sendErr = notifier.NotifyVideo(ctx, target, video)
+	}
+	s.recordResult(ctx, video, target, sent, sendErr)
+	if sendErr == nil {
+		s.sendMentions(ctx, target, []*model.Video{video})
+	}
+	return sendErr
+}
+
+// ParseMode returns the markup of Telegram messages to a chat: the chat's own
+// choice, or else the configured default
+func (s *Service) ParseMode(ctx context.Context, chatID int64) model.ParseMode {
+	settings, err := s.store.GetChatSettings(ctx, chatID)
+	if err != nil {
+		logctx.From(ctx).Warn().Err(err).Int64("chatID", chatID).Msg("Failed to get chat parse mode, using default")
+		return s.config.ParseMode
+	}
+	if settings.ParseMode != model.ParseModeDefault {
+		return settings.ParseMode
+	}
+	return s.config.ParseMode
+}
+
+// shouldSkip reports whether a video must not be pushed to a chat: it was pushed
+// already, possibly under another record, it was revoked, or the chat muted, blacklisted
+// or excluded its format
+func (s *Service) shouldSkip(ctx context.Context, video *model.Video, chatID int64) (bool, error) {
+	// Revoked videos are never pushed again
+	if video.Hidden {
+		logctx.From(ctx).Debug().
+			Str("code", video.Code).
+			Int64("chatID", chatID).
+			Msg("Video revoked, skipping")
+		return true, nil
+	}
+
 	// Check if already pushed (Requirement 5.3)
 	hasPushed, err := s.store.HasPushed(ctx, video.ID, chatID)
 	if err != nil {
-		return fmt.Errorf("failed to check push history: %w", err)
+		return false, fmt.Errorf("failed to check push history: %w", err)
 	}
 
 	if hasPushed {
-		log.Debug().
+		logctx.From(ctx).Debug().
 			Str("code", video.Code).
 			Int64("chatID", chatID).
 			Msg("Video already pushed to chat, skipping")
-		return nil
+		return true, nil
 	}
 
-	// Wait for rate limiter (Requirement 5.9)
-	if err := s.limiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limiter error: %w", err)
+	// Check if the same release was pushed under another video record
+	code := model.CanonicalCode(video.Code)
+	hasPushed, err = s.store.HasPushedCode(ctx, code, chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check push history by code: %w", err)
 	}
 
-	// Format the message
-	message := FormatVideoMessage(video)
+	if hasPushed {
+		logctx.From(ctx).Debug().
+			Str("code", video.Code).
+			Int64("chatID", chatID).
+			Msg("Release already pushed to chat under another record, skipping")
+		return true, nil
+	}
 
-	// Try to send with media
-	var sendErr error
-	var messageID int
+	// Respect codes the chat muted with /mute
+	muted, err := s.store.IsCodeMuted(ctx, code, chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check muted codes: %w", err)
+	}
 
-	// Try video first if preview URL exists (Requirement 5.8)
-	if video.PreviewURL != "" {
-		sendErr = s.telegram.SendVideo(chatID, video.PreviewURL, video.CoverURL, message)
+	if muted {
+		logctx.From(ctx).Debug().
+			Str("code", video.Code).
+			Int64("chatID", chatID).
+			Msg("Code muted in chat, skipping")
+		return true, nil
+	}
+
+	// Respect the chat's tag and actress blacklist
+	blacklist, err := s.store.GetBlacklist(ctx, chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get blacklist: %w", err)
 	}
 
-	// Fallback to photo if video fails or no preview URL (Requirement 5.7)
-	if sendErr != nil || video.PreviewURL == "" {
-		if video.CoverURL != "" {
-			sendErr = s.telegram.SendPhoto(chatID, video.CoverURL, message)
-		} else {
-			// No media, send text only
-			sendErr = s.telegram.SendMarkdown(chatID, message)
+	for _, entry := range blacklist {
+		if entry.Matches(video) {
+			logctx.From(ctx).Debug().
+				Str("code", video.Code).
+				Int64("chatID", chatID).
+				Str("type", string(entry.Type)).
+				Str("keyword", entry.Keyword).
+				Msg("Video blacklisted in chat, skipping")
+			return true, nil
 		}
 	}
 
-	// Record the push result
+	// Respect the chat's /settings skipvr and minduration
+	settings, err := s.store.GetChatSettings(ctx, chatID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get chat settings: %w", err)
+	}
+
+	if settings.ExcludesFormat(video) {
+		logctx.From(ctx).Debug().
+			Str("code", video.Code).
+			Int64("chatID", chatID).
+			Bool("vr", video.VR).
+			Int("duration", video.Duration).
+			Msg("Video format excluded in chat, skipping")
+		return true, nil
+	}
+	return false, nil
+}
+
+// recordCapped records a video held back by the chat's daily cap
+func (s *Service) recordCapped(ctx context.Context, video *model.Video, chatID int64) error {
+	record := &model.PushRecord{
+		VideoID:  video.ID,
+		Code:     model.CanonicalCode(video.Code),
+		ChatID:   chatID,
+		Status:   model.PushStatusCapped,
+		PushedAt: time.Now(),
+	}
+	if err := s.store.RecordPush(ctx, record); err != nil {
+		return fmt.Errorf("failed to record capped push: %w", err)
+	}
+	logctx.From(ctx).Debug().
+		Str("code", video.Code).
+		Int64("chatID", chatID).
+		Msg("Daily push cap reached for chat, holding back video")
+	return nil
+}
+
+// recordResult records the outcome of sending a video to a chat
+func (s *Service) recordResult(ctx context.Context, video *model.Video, target Target, sent sentMessage, sendErr error) {
+	if err := s.store.RecordPush(ctx, newPushRecord(ctx, video, target, sent, sendErr)); err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Failed to record push")
+	}
+}
+
+// newPushRecord builds and logs the record of sending a video to a chat
+func newPushRecord(ctx context.Context, video *model.Video, target Target, sent sentMessage, sendErr error) *model.PushRecord {
+	chatID := target.ChatID
 	record := &model.PushRecord{
-		VideoID:   video.ID,
-		ChatID:    chatID,
-		PushedAt:  time.Now(),
-		MessageID: messageID,
+		VideoID:      video.ID,
+		Code:         model.CanonicalCode(video.Code),
+		ChatID:       chatID,
+		PushedAt:     time.Now(),
+		MessageID:    sent.id,
+		MessageType:  sent.kind,
+		MessageCount: sent.count,
+		BotID:        target.BotID,
 	}
 
 	if sendErr != nil {
 		// Record failed push (Requirement 5.5)
 		record.Status = model.PushStatusFailed
 		record.FailReason = sendErr.Error()
-		log.Error().
+		logctx.From(ctx).Error().
 			Err(sendErr).
 			Str("code", video.Code).
 			Int64("chatID", chatID).
@@ -193,17 +832,59 @@ func (s *Service) PushVideoToChat(ctx context.Context, video *model.Video, chatI
 	} else {
 		// Record successful push (Requirement 5.4)
 		record.Status = model.PushStatusSuccess
-		log.Info().
+		logctx.From(ctx).Info().
 			Str("code", video.Code).
 			Int64("chatID", chatID).
 			Msg("Successfully pushed video")
 	}
+	return record
+}
 
-	if err := s.store.RecordPush(ctx, record); err != nil {
-		log.Error().Err(err).Msg("Failed to record push")
+// RefreshPushedVideo edits the Telegram messages a video was pushed as, so that details
+// filled in after the push (actresses, tags, cover) show up in the original post.
+// Combined list messages are left alone; edit failures are logged and skipped.
+func (s *Service) RefreshPushedVideo(ctx context.Context, video *model.Video) error {
+	editor, ok := s.notifiers[model.PlatformTelegram].(editableNotifier)
+	if !ok {
+		return nil
 	}
 
-	return sendErr
+	records, err := s.store.GetEditablePushes(ctx, video.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get pushed messages: %w", err)
+	}
+
+	edited := 0
+	for _, record := range records {
+		target := Target{ChatID: record.ChatID, Platform: model.PlatformTelegram, BotID: record.BotID}
+		target.ParseMode = s.ParseMode(ctx, target.ChatID)
+		if err := s.chatLimiter(target.ChatID).Wait(ctx); err != nil {
+			return fmt.Errorf("chat rate limiter error: %w", err)
+		}
+		if err := s.telegram.bot(target.BotID).limiter.Wait(ctx); err != nil {
+			return fmt.Errorf("rate limiter error: %w", err)
+		}
+
+		sent := sentMessage{id: record.MessageID, kind: record.MessageType}
+		if err := editor.editVideo(ctx, target, sent, video); err != nil {
+			logctx.From(ctx).Warn().
+				Err(err).
+				Str("code", video.Code).
+				Int64("chatID", target.ChatID).
+				Int("messageID", record.MessageID).
+				Msg("Failed to edit pushed message")
+			continue
+		}
+		edited++
+	}
+
+	if edited > 0 {
+		logctx.From(ctx).Info().
+			Str("code", video.Code).
+			Int("messages", edited).
+			Msg("Updated pushed messages with enriched details")
+	}
+	return nil
 }
 
 // FindMatchingSubscriptions finds all subscriptions that match a video
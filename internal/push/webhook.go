@@ -0,0 +1,206 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+const (
+	// webhookEventNewVideo is the event name of new matched video notifications
+	webhookEventNewVideo = "video.new"
+	// WebhookSignatureHeader carries the hex HMAC-SHA256 of the request body, prefixed with "sha256="
+	WebhookSignatureHeader = "X-MissAV-Signature"
+	// WebhookEventHeader carries the event name
+	WebhookEventHeader = "X-MissAV-Event"
+
+	// maxWebhookAttempts is the number of delivery attempts per URL and event
+	maxWebhookAttempts = 3
+	// webhookRetryDelay is the wait before the first retry; it doubles on each attempt
+	webhookRetryDelay = 2 * time.Second
+)
+
+var webhookDeliveriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "missav_bot_webhook_deliveries_total",
+	Help: "Total number of outbound webhook deliveries by status",
+}, []string{"status"})
+
+func init() {
+	prometheus.MustRegister(webhookDeliveriesTotal)
+}
+
+// WebhookPayload is the JSON body POSTed to webhook URLs
+type WebhookPayload struct {
+	Event       string       `json:"event"`
+	Timestamp   time.Time    `json:"timestamp"`
+	Video       WebhookVideo `json:"video"`
+	Subscribers int          `json:"subscribers"`
+}
+
+// WebhookVideo describes a video in a webhook payload
+type WebhookVideo struct {
+	Code        string     `json:"code"`
+	Title       string     `json:"title"`
+	Actresses   []string   `json:"actresses"`
+	Tags        []string   `json:"tags"`
+	Duration    int        `json:"duration"`
+	ReleaseDate *time.Time `json:"releaseDate,omitempty"`
+	CoverURL    string     `json:"coverUrl,omitempty"`
+	PreviewURL  string     `json:"previewUrl,omitempty"`
+	DetailURL   string     `json:"detailUrl,omitempty"`
+}
+
+// NewWebhookPayload builds the new video event for a video matched by the given number of chats
+func NewWebhookPayload(video *model.Video, subscribers int) *WebhookPayload {
+	return &WebhookPayload{
+		Event:     webhookEventNewVideo,
+		Timestamp: time.Now().UTC(),
+		Video: WebhookVideo{
+			Code:        video.Code,
+			Title:       video.Title,
+			Actresses:   splitList(video.Actresses),
+			Tags:        splitList(video.Tags),
+			Duration:    video.Duration,
+			ReleaseDate: video.ReleaseDate,
+			CoverURL:    video.CoverURL,
+			PreviewURL:  video.PreviewURL,
+			DetailURL:   video.DetailURL,
+		},
+		Subscribers: subscribers,
+	}
+}
+
+// splitList splits a comma-separated video field into trimmed, non-empty values
+func splitList(value string) []string {
+	values := []string{}
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// SignWebhookBody returns the signature header value of a webhook body
+func SignWebhookBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookNotifier POSTs new video events to operator-configured URLs
+// Each URL is retried with exponential backoff on network errors, 429 and 5xx responses.
+type WebhookNotifier struct {
+	urls       []string
+	secret     string
+	client     *http.Client
+	retryDelay time.Duration
+}
+
+// NewWebhookNotifier creates a notifier for the given URLs
+// Requests are signed when secret is non-empty.
+func NewWebhookNotifier(urls []string, secret string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		urls:       urls,
+		secret:     secret,
+		client:     &http.Client{Timeout: timeout},
+		retryDelay: webhookRetryDelay,
+	}
+}
+
+// Notify delivers the payload to every URL concurrently and waits for all deliveries
+func (n *WebhookNotifier) Notify(ctx context.Context, payload *WebhookPayload) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Str("code", payload.Video.Code).Msg("Failed to encode webhook payload")
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, url := range n.urls {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			if err := n.deliver(ctx, url, payload.Event, body); err != nil {
+				webhookDeliveriesTotal.WithLabelValues("failed").Inc()
+				logctx.From(ctx).Error().
+					Err(err).
+					Str("url", url).
+					Str("code", payload.Video.Code).
+					Msg("Failed to deliver webhook")
+				return
+			}
+			webhookDeliveriesTotal.WithLabelValues("success").Inc()
+		}(url)
+	}
+	wg.Wait()
+}
+
+// deliver POSTs a body to one URL, retrying transient failures
+func (n *WebhookNotifier) deliver(ctx context.Context, url string, event string, body []byte) error {
+	delay := n.retryDelay
+	var lastErr error
+	for attempt := 1; attempt <= maxWebhookAttempts; attempt++ {
+		retry, err := n.post(ctx, url, event, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || attempt == maxWebhookAttempts {
+			break
+		}
+
+		logctx.From(ctx).Warn().
+			Err(err).
+			Str("url", url).
+			Int("attempt", attempt).
+			Msg("Webhook delivery failed, retrying")
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+	}
+	return lastErr
+}
+
+// post sends a single webhook request and reports whether a failure is worth retrying
+func (n *WebhookNotifier) post(ctx context.Context, url string, event string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, event)
+	if n.secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookBody(n.secret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+}
@@ -0,0 +1,72 @@
+package push
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestWebhookNotifier_SignsAndRetries(t *testing.T) {
+	var attempts atomic.Int32
+	received := make(chan *http.Request, 1)
+	var receivedBody []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		receivedBody, _ = io.ReadAll(r.Body)
+		received <- r
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier([]string{server.URL}, "secret", time.Second)
+	notifier.retryDelay = time.Millisecond
+
+	video := &model.Video{Code: "ABC-123", Title: "Title", Actresses: "A, B", Tags: "t1"}
+	notifier.Notify(context.Background(), NewWebhookPayload(video, 2))
+
+	if got := attempts.Load(); got != 2 {
+		t.Fatalf("attempts = %d, want 2", got)
+	}
+	req := <-received
+	if got, want := req.Header.Get(WebhookSignatureHeader), SignWebhookBody("secret", receivedBody); got != want {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+	if got := req.Header.Get(WebhookEventHeader); got != webhookEventNewVideo {
+		t.Errorf("event header = %q, want %q", got, webhookEventNewVideo)
+	}
+
+	var payload WebhookPayload
+	if err := json.Unmarshal(receivedBody, &payload); err != nil {
+		t.Fatalf("invalid payload: %v", err)
+	}
+	if payload.Video.Code != "ABC-123" || len(payload.Video.Actresses) != 2 || payload.Subscribers != 2 {
+		t.Errorf("unexpected payload: %+v", payload)
+	}
+}
+
+func TestWebhookNotifier_NoRetryOnClientError(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier([]string{server.URL}, "", time.Second)
+	notifier.retryDelay = time.Millisecond
+	notifier.Notify(context.Background(), NewWebhookPayload(&model.Video{Code: "ABC-123"}, 1))
+
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("attempts = %d, want 1", got)
+	}
+}
@@ -0,0 +1,156 @@
+package scheduler
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// duplicateScanState remembers where the last duplicate scan stopped
+// Only videos created since then are compared against the window.
+type duplicateScanState struct {
+	lastScan time.Time
+}
+
+// flagDuplicates flags recent videos whose title and actresses nearly match
+// another video with a different code, e.g. a release re-listed under a new code.
+// Flagged videos are held back from pushes until an admin merges or dismisses them.
+func (s *Scheduler) flagDuplicates(ctx context.Context) {
+	if s.config.DuplicateThreshold <= 0 {
+		return
+	}
+
+	now := time.Now()
+	videos, err := s.store.GetVideosSince(ctx, now.Add(-s.config.DuplicateWindow))
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Failed to load videos for duplicate scan")
+		return
+	}
+
+	candidates := findDuplicates(videos, s.duplicates.lastScan, s.config.DuplicateThreshold)
+	saved, err := s.store.SaveDuplicateCandidates(ctx, candidates)
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Failed to save duplicate candidates")
+		return
+	}
+	s.duplicates.lastScan = now
+
+	if saved > 0 {
+		logctx.From(ctx).Info().Int("candidates", saved).Msg("Flagged potential duplicate videos")
+	}
+}
+
+// findDuplicates compares the videos created after since with all older videos
+// videos must be ordered oldest first; the older video of a pair is the one kept.
+func findDuplicates(videos []*model.Video, since time.Time, threshold float64) []*model.DuplicateCandidate {
+	titles := make([]map[string]int, len(videos))
+	for i, video := range videos {
+		titles[i] = titleBigrams(video)
+	}
+
+	var candidates []*model.DuplicateCandidate
+	for j, newer := range videos {
+		if !newer.CreatedAt.After(since) || newer.DuplicateOf != nil {
+			continue
+		}
+		for i := 0; i < j; i++ {
+			older := videos[i]
+			if older.DuplicateOf != nil || model.CanonicalCode(older.Code) == model.CanonicalCode(newer.Code) {
+				continue
+			}
+			if !actressesOverlap(older.Actresses, newer.Actresses) {
+				continue
+			}
+			similarity := diceCoefficient(titles[i], titles[j])
+			if similarity < threshold {
+				continue
+			}
+			candidates = append(candidates, &model.DuplicateCandidate{
+				VideoID:       older.ID,
+				VideoCode:     older.Code,
+				DuplicateID:   newer.ID,
+				DuplicateCode: newer.Code,
+				Similarity:    similarity,
+				Status:        model.DuplicatePending,
+			})
+			break
+		}
+	}
+	return candidates
+}
+
+// normalizeTitle lowercases a title and strips the video code, punctuation and spaces
+func normalizeTitle(video *model.Video) string {
+	title := strings.ToLower(video.Title)
+	if code := strings.ToLower(model.CanonicalCode(video.Code)); code != "" {
+		title = strings.ReplaceAll(title, code, "")
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return r
+		}
+		return -1
+	}, title)
+}
+
+// titleBigrams returns the character bigram counts of a normalized title
+// Character bigrams work for Japanese titles, which have no word separators.
+func titleBigrams(video *model.Video) map[string]int {
+	runes := []rune(normalizeTitle(video))
+	bigrams := make(map[string]int)
+	for i := 0; i+1 < len(runes); i++ {
+		bigrams[string(runes[i:i+2])]++
+	}
+	return bigrams
+}
+
+// diceCoefficient returns the Sørensen–Dice similarity of two bigram multisets
+func diceCoefficient(a, b map[string]int) float64 {
+	total := 0
+	for _, n := range a {
+		total += n
+	}
+	for _, n := range b {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+
+	shared := 0
+	for bigram, n := range a {
+		shared += min(n, b[bigram])
+	}
+	return 2 * float64(shared) / float64(total)
+}
+
+// actressesOverlap reports whether two actress lists share a name
+// Videos without actresses can't be told apart by cast, so they only match each other.
+func actressesOverlap(a, b string) bool {
+	namesA := splitNames(a)
+	namesB := splitNames(b)
+	if len(namesA) == 0 || len(namesB) == 0 {
+		return len(namesA) == len(namesB)
+	}
+	for name := range namesA {
+		if namesB[name] {
+			return true
+		}
+	}
+	return false
+}
+
+// splitNames splits a comma-separated name list into a set of lowercased names
+func splitNames(list string) map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			names[name] = true
+		}
+	}
+	return names
+}
@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestNormalizeTitle(t *testing.T) {
+	video := &model.Video{Code: "ABC-123", Title: "ABC-123 新人デビュー！ Fresh Face 【4K】"}
+	if got := normalizeTitle(video); got != "新人デビューfreshface4k" {
+		t.Errorf("normalizeTitle() = %q", got)
+	}
+}
+
+func TestDiceCoefficient(t *testing.T) {
+	a := titleBigrams(&model.Video{Title: "新人デビュー 爆乳"})
+	if got := diceCoefficient(a, a); got != 1 {
+		t.Errorf("identical titles similarity = %v, want 1", got)
+	}
+	b := titleBigrams(&model.Video{Title: "温泉旅行 記念"})
+	if got := diceCoefficient(a, b); got != 0 {
+		t.Errorf("unrelated titles similarity = %v, want 0", got)
+	}
+	if got := diceCoefficient(map[string]int{}, map[string]int{}); got != 0 {
+		t.Errorf("empty titles similarity = %v, want 0", got)
+	}
+}
+
+func TestActressesOverlap(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"三上悠亜, 河北彩花", "河北彩花", true},
+		{"Yua Mikami", "yua mikami", true},
+		{"三上悠亜", "河北彩花", false},
+		{"", "", true},
+		{"三上悠亜", "", false},
+	}
+	for _, tt := range tests {
+		if got := actressesOverlap(tt.a, tt.b); got != tt.want {
+			t.Errorf("actressesOverlap(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	videos := []*model.Video{
+		{ID: 1, Code: "ABC-123", Title: "ABC-123 新人デビュー 完全版", Actresses: "三上悠亜", CreatedAt: base},
+		{ID: 2, Code: "ABC-123-UNCENSORED-LEAK", Title: "ABC-123 新人デビュー 完全版", Actresses: "三上悠亜", CreatedAt: base.Add(time.Hour)},
+		{ID: 3, Code: "XYZ-999", Title: "XYZ-999 新人デビュー 完全版", Actresses: "三上悠亜", CreatedAt: base.Add(2 * time.Hour)},
+		{ID: 4, Code: "DEF-456", Title: "DEF-456 新人デビュー 完全版", Actresses: "河北彩花", CreatedAt: base.Add(3 * time.Hour)},
+	}
+
+	candidates := findDuplicates(videos, base, 0.9)
+	if len(candidates) != 1 {
+		t.Fatalf("findDuplicates() = %d candidates, want 1", len(candidates))
+	}
+	c := candidates[0]
+	if c.VideoID != 1 || c.DuplicateID != 3 || c.Similarity != 1 || c.Status != model.DuplicatePending {
+		t.Errorf("candidate = %+v, want XYZ-999 flagged against ABC-123", c)
+	}
+
+	if got := findDuplicates(videos, base.Add(2*time.Hour), 0.9); len(got) != 0 {
+		t.Errorf("findDuplicates() rescanned videos from before the last scan: %+v", got)
+	}
+}
@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+var videosByCompleteness = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "missav_bot_videos_by_completeness",
+	Help: "Number of stored videos per completeness score (metadata fields present)",
+}, []string{"score"})
+
+func init() {
+	prometheus.MustRegister(videosByCompleteness)
+}
+
+// enrichIncompleteVideos crawls the detail pages of the lowest-scoring recent videos
+// Listing pages only carry a title and cover; the detail page fills in the rest.
+// Each video is enriched once, so pages that lack the fields aren't crawled again.
+// Messages already pushed for an enriched video are edited to show the new details.
+func (s *Scheduler) enrichIncompleteVideos(ctx context.Context) {
+	if s.config.EnrichPerRun <= 0 {
+		return
+	}
+
+	videos, err := s.store.GetIncompleteVideos(ctx, time.Now().Add(-s.config.EnrichWindow), s.config.EnrichPerRun)
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Failed to load incomplete videos")
+		return
+	}
+
+	enriched := 0
+	for _, video := range videos {
+		if ctx.Err() != nil || crawler.BudgetExhausted(s.crawler) {
+			break
+		}
+
+		detail, err := s.crawler.CrawlVideoDetail(ctx, video.DetailURL)
+		if err != nil {
+			logctx.From(ctx).Warn().Err(err).Str("code", video.Code).Msg("Failed to enrich video")
+			if errors.Is(err, crawler.ErrBudgetExhausted) {
+				break
+			}
+			continue
+		}
+
+		before := video.Completeness
+		fillMissingFields(video, detail)
+		now := time.Now()
+		video.EnrichedAt = &now
+		if err := s.store.UpdateVideoDetails(ctx, video); err != nil {
+			logctx.From(ctx).Error().Err(err).Str("code", video.Code).Msg("Failed to save enriched video")
+			continue
+		}
+		enriched++
+		logctx.From(ctx).Debug().
+			Str("code", video.Code).
+			Int("before", before).
+			Int("after", video.Completeness).
+			Msg("Enriched video")
+
+		// Videos pushed with bare listing data get their original messages updated
+		if video.Pushed && video.Completeness > before && s.pushService != nil {
+			if err := s.pushService.RefreshPushedVideo(ctx, video); err != nil {
+				logctx.From(ctx).Warn().Err(err).Str("code", video.Code).Msg("Failed to refresh pushed messages")
+			}
+		}
+	}
+
+	if enriched > 0 {
+		logctx.From(ctx).Info().Int("videos", enriched).Msg("Enriched incomplete videos")
+	}
+}
+
+// fillMissingFields copies the metadata video lacks from its crawled detail page
+func fillMissingFields(video, detail *model.Video) {
+	if video.Title == "" {
+		video.Title = detail.Title
+	}
+	if video.Actresses == "" {
+		video.Actresses = detail.Actresses
+	}
+	if video.Tags == "" {
+		video.Tags = detail.Tags
+	}
+	if video.Duration == 0 {
+		video.Duration = detail.Duration
+	}
+	if video.ReleaseDate == nil {
+		video.ReleaseDate = detail.ReleaseDate
+	}
+	if video.CoverURL == "" {
+		video.CoverURL = detail.CoverURL
+	}
+	if video.PreviewURL == "" {
+		video.PreviewURL = detail.PreviewURL
+	}
+	if len(video.Screenshots) == 0 {
+		video.Screenshots = detail.Screenshots
+	}
+	video.Completeness = video.CompletenessScore()
+}
+
+// updateCompletenessMetrics publishes the number of videos per completeness score
+func (s *Scheduler) updateCompletenessMetrics(ctx context.Context) {
+	counts, err := s.store.CountVideosByCompleteness(ctx)
+	if err != nil {
+		logctx.From(ctx).Warn().Err(err).Msg("Failed to count videos by completeness")
+		return
+	}
+	for score := 0; score <= model.MaxCompleteness; score++ {
+		videosByCompleteness.WithLabelValues(strconv.Itoa(score)).Set(float64(counts[score]))
+	}
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestFillMissingFields(t *testing.T) {
+	video := &model.Video{
+		Code:     "ABC-123",
+		Title:    "Listing Title",
+		CoverURL: "https://example.com/listing.jpg",
+	}
+	detail := &model.Video{
+		Title:       "Detail Title",
+		Actresses:   "Actress One",
+		Tags:        "Drama",
+		Duration:    120,
+		CoverURL:    "https://example.com/detail.jpg",
+		PreviewURL:  "https://example.com/preview.mp4",
+		Screenshots: []string{"https://example.com/shot-1.jpg"},
+	}
+
+	fillMissingFields(video, detail)
+
+	if video.Title != "Listing Title" || video.CoverURL != "https://example.com/listing.jpg" {
+		t.Errorf("fillMissingFields() overwrote present fields: %+v", video)
+	}
+	if video.Actresses != "Actress One" || video.Tags != "Drama" || video.Duration != 120 ||
+		video.PreviewURL != detail.PreviewURL || len(video.Screenshots) != 1 {
+		t.Errorf("fillMissingFields() left fields missing: %+v", video)
+	}
+	if video.Completeness != 5 {
+		t.Errorf("Completeness = %d, want 5", video.Completeness)
+	}
+}
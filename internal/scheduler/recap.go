@@ -0,0 +1,32 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/logctx"
+)
+
+// recapCheckInterval is how often the scheduler looks for chats due a weekly recap
+const recapCheckInterval = time.Hour
+
+// runRecaps sends the weekly recaps of the chats that opted in with /settings recap,
+// checking every recapCheckInterval which are due
+func (s *Scheduler) runRecaps(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(recapCheckInterval)
+	defer ticker.Stop()
+	logctx.From(ctx).Info().Dur("interval", recapCheckInterval).Msg("Weekly recap job started")
+
+	for {
+		select {
+		case <-ticker.C:
+			s.pushService.SendWeeklyRecaps(ctx, time.Now())
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
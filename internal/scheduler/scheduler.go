@@ -2,6 +2,8 @@ package scheduler
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -9,6 +11,8 @@ import (
 	"github.com/rs/zerolog/log"
 	"github.com/user/missav-bot-go/internal/config"
 	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
 	"github.com/user/missav-bot-go/internal/push"
 	"github.com/user/missav-bot-go/internal/store"
 )
@@ -20,11 +24,21 @@ type Scheduler struct {
 	pushService *push.Service
 	config      *config.CrawlerConfig
 	running     atomic.Bool
+	paused      atomic.Bool // Scheduled crawls are skipped while set; manual runs still proceed
 	mu          sync.Mutex // Mutex to prevent concurrent crawl tasks (Requirement 6.3)
+	locker      store.Locker // Optional cross-instance lock (nil when disabled)
 	stopCh      chan struct{}
 	wg          sync.WaitGroup
+	tagCursor   atomic.Uint64 // Rotation offset into the subscribed tags for targeted crawls
+	actorCursor atomic.Uint64 // Rotation offset into the subscribed actresses for targeted crawls
+	watchCursor atomic.Uint64 // Rotation offset into the missing /watch codes probed each cycle
+	nextRun     atomic.Int64  // Unix nanoseconds of the next scheduled crawl (0 when not scheduled)
+	duplicates  duplicateScanState
 }
 
+// crawlLockName is the name of the distributed lock guarding crawl cycles
+const crawlLockName = "missav_bot_crawl"
+
 // NewScheduler creates a new scheduler instance
 func NewScheduler(
 	crawler crawler.Crawler,
@@ -32,13 +46,24 @@ func NewScheduler(
 	pushService *push.Service,
 	cfg *config.CrawlerConfig,
 ) *Scheduler {
-	return &Scheduler{
+	s := &Scheduler{
 		crawler:     crawler,
 		store:       store,
 		pushService: pushService,
 		config:      cfg,
 		stopCh:      make(chan struct{}),
 	}
+
+	// Use the store's shared lock when running multiple replicas
+	if cfg.DistributedLock {
+		if locker, ok := storeLocker(store); ok {
+			s.locker = locker
+		} else {
+			log.Warn().Msg("Distributed lock enabled but store does not support locking")
+		}
+	}
+
+	return s
 }
 
 // Start begins the scheduler with initial delay and periodic execution
@@ -46,50 +71,64 @@ func NewScheduler(
 // Requirement 6.2: Execute periodic crawl at configurable interval (default 15 minutes)
 func (s *Scheduler) Start(ctx context.Context) {
 	if !s.config.Enabled {
-		log.Info().Msg("Scheduler is disabled")
+		logctx.From(ctx).Info().Msg("Scheduler is disabled")
 		return
 	}
 
 	s.wg.Add(1)
 	go s.run(ctx)
+
+	// Keep the site session warm between crawls
+	if warmer, ok := s.crawler.(crawler.SessionWarmer); ok && s.config.SessionRefreshInterval > 0 {
+		s.wg.Add(1)
+		go s.runSessionWarmer(ctx, warmer)
+	}
+
+	// Send weekly recaps to the chats that opted in
+	s.wg.Add(1)
+	go s.runRecaps(ctx)
 }
 
 
 // run is the main scheduler loop
 func (s *Scheduler) run(ctx context.Context) {
 	defer s.wg.Done()
+	defer s.nextRun.Store(0)
 
 	// Initial delay before first crawl (Requirement 6.1)
 	initialDelay := 5 * time.Second
-	log.Info().Dur("delay", initialDelay).Msg("Scheduler starting with initial delay")
+	logctx.From(ctx).Info().Dur("delay", initialDelay).Msg("Scheduler starting with initial delay")
+	s.setNextRun(time.Now().Add(initialDelay))
 
 	select {
 	case <-time.After(initialDelay):
 		// Execute initial crawl
 		s.executeCrawl(ctx)
 	case <-s.stopCh:
-		log.Info().Msg("Scheduler stopped during initial delay")
+		logctx.From(ctx).Info().Msg("Scheduler stopped during initial delay")
 		return
 	case <-ctx.Done():
-		log.Info().Msg("Scheduler context cancelled during initial delay")
+		logctx.From(ctx).Info().Msg("Scheduler context cancelled during initial delay")
 		return
 	}
 
 	// Periodic execution (Requirement 6.2)
 	ticker := time.NewTicker(s.config.Interval)
 	defer ticker.Stop()
+	s.setNextRun(time.Now().Add(s.config.Interval))
 
-	log.Info().Dur("interval", s.config.Interval).Msg("Scheduler started periodic execution")
+	logctx.From(ctx).Info().Dur("interval", s.config.Interval).Msg("Scheduler started periodic execution")
 
 	for {
 		select {
-		case <-ticker.C:
+		case tick := <-ticker.C:
+			s.setNextRun(tick.Add(s.config.Interval))
 			s.executeCrawl(ctx)
 		case <-s.stopCh:
-			log.Info().Msg("Scheduler stopped")
+			logctx.From(ctx).Info().Msg("Scheduler stopped")
 			return
 		case <-ctx.Done():
-			log.Info().Msg("Scheduler context cancelled")
+			logctx.From(ctx).Info().Msg("Scheduler context cancelled")
 			return
 		}
 	}
@@ -100,25 +139,45 @@ func (s *Scheduler) run(ctx context.Context) {
 func (s *Scheduler) executeCrawl(ctx context.Context) {
 	// Try to acquire the mutex without blocking
 	if !s.mu.TryLock() {
-		log.Warn().Msg("Crawl task already running, skipping this trigger")
+		logctx.From(ctx).Warn().Msg("Crawl task already running, skipping this trigger")
 		return
 	}
 	defer s.mu.Unlock()
 
+	// Skip if another instance holds the distributed lock
+	unlock, ok := s.acquireDistributedLock(ctx)
+	if !ok {
+		logctx.From(ctx).Warn().Msg("Crawl task running on another instance, skipping this trigger")
+		return
+	}
+	defer unlock()
+
+	if s.paused.Load() {
+		logctx.From(ctx).Info().Msg("Scheduler paused, skipping scheduled crawl")
+		return
+	}
+
+	// Leave the rest of the daily budget to admin crawls
+	if crawler.BudgetExhausted(s.crawler) {
+		logctx.From(ctx).Warn().Msg("Daily crawl budget exhausted, skipping scheduled crawl")
+		return
+	}
+
 	s.running.Store(true)
 	defer s.running.Store(false)
 
+	ctx = logctx.With(ctx, logctx.RunID, logctx.NewID())
 	startTime := time.Now()
-	log.Info().Int("pages", s.config.InitialPages).Msg("Starting scheduled crawl")
+	logctx.From(ctx).Info().Int("pages", s.config.InitialPages).Msg("Starting scheduled crawl")
 
 	// Execute the crawl
-	if err := s.RunOnce(ctx, s.config.InitialPages); err != nil {
-		log.Error().Err(err).Msg("Scheduled crawl failed")
+	if err := s.runAndRecord(ctx, model.CrawlTriggerScheduled, s.config.InitialPages); err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Scheduled crawl failed")
 	}
 
 	// Log execution time (Requirement 6.5)
 	duration := time.Since(startTime)
-	log.Info().
+	logctx.From(ctx).Info().
 		Dur("duration", duration).
 		Msg("Scheduled crawl completed")
 }
@@ -126,41 +185,254 @@ func (s *Scheduler) executeCrawl(ctx context.Context) {
 // RunOnce executes a single crawl and push cycle
 // Requirement 6.4: Trigger push for all unpushed videos after crawl completes
 func (s *Scheduler) RunOnce(ctx context.Context, pages int) error {
+	_, err := s.runOnce(logctx.With(ctx, logctx.RunID, logctx.NewID()), pages)
+	return err
+}
+
+// runOnce executes a single crawl and push cycle and reports the crawl counts
+func (s *Scheduler) runOnce(ctx context.Context, pages int) (*model.CrawlRun, error) {
+	run := &model.CrawlRun{Pages: pages}
+
 	// Crawl new videos
-	videos, err := s.crawler.CrawlNewVideos(ctx, pages)
+	result, err := s.crawler.CrawlNewVideos(ctx, pages)
 	if err != nil {
-		return err
+		result.Fill(run)
+		return run, err
 	}
 
-	log.Info().Int("count", len(videos)).Msg("Crawled videos")
+	s.crawlSubscribed(ctx, result)
+	result.Fill(run)
+
+	videos := result.Videos
+	logctx.From(ctx).Info().
+		Int("count", len(videos)).
+		Int("pagesFetched", result.PagesFetched).
+		Int("httpFetches", result.HTTPFetches).
+		Int("browserFetches", result.BrowserFetches).
+		Int("parseFailures", result.ParseFailures).
+		Msg("Crawled videos")
+	if len(videos) == 0 {
+		// The listing pages always hold videos, so an empty crawl means the parser no
+		// longer matches the site
+		logctx.From(ctx).Error().
+			Int("pagesFetched", result.PagesFetched).
+			Int("parseFailures", result.ParseFailures).
+			Msg("Crawl found no videos, the site layout may have changed")
+	}
 
 	// Save videos to store
 	if len(videos) > 0 {
 		saved, duplicates, err := s.store.SaveVideos(ctx, videos)
 		if err != nil {
-			log.Error().Err(err).Msg("Failed to save videos")
+			logctx.From(ctx).Error().Err(err).Msg("Failed to save videos")
 		} else {
-			log.Info().
+			run.Saved = saved
+			run.Duplicates = duplicates
+			logctx.From(ctx).Info().
 				Int("saved", saved).
 				Int("duplicates", duplicates).
 				Msg("Videos saved to database")
 		}
 	}
 
+	// Fill in missing metadata before pushing so notifications are complete
+	s.enrichIncompleteVideos(ctx)
+	s.updateCompletenessMetrics(ctx)
+	s.flagDuplicates(ctx)
+	s.checkWatches(ctx)
+
 	// Push unpushed videos to subscribers (Requirement 6.4)
 	if err := s.pushService.PushUnpushedVideos(ctx); err != nil {
-		log.Error().Err(err).Msg("Failed to push videos")
+		logctx.From(ctx).Error().Err(err).Msg("Failed to push videos")
+	}
+
+	return run, nil
+}
+
+// crawlSubscribed adds the listings of subscribed tags and actresses, which rarely
+// reach the /new pages, to result. Subscriptions are loaded once for both passes.
+func (s *Scheduler) crawlSubscribed(ctx context.Context, result *crawler.CrawlResult) {
+	tagsEnabled := s.config.TagCrawlLimit > 0 && s.config.TagCrawlsPerRun > 0
+	actressesEnabled := s.config.ActressCrawlLimit > 0 && s.config.ActressCrawlsPerRun > 0
+	if !tagsEnabled && !actressesEnabled {
+		return
+	}
+
+	subs, err := s.store.GetAllSubscriptions(ctx)
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Failed to load subscriptions for targeted crawls")
+		return
+	}
+	if tagsEnabled {
+		s.crawlSubscribedTags(ctx, subs, result)
+	}
+	if actressesEnabled {
+		s.crawlSubscribedActresses(ctx, subs, result)
+	}
+}
+
+// crawlSubscribedTags adds the listings of subscribed tags to result
+// At most TagCrawlsPerRun tags are crawled per cycle, rotating through all subscribed tags.
+func (s *Scheduler) crawlSubscribedTags(ctx context.Context, subs []*model.Subscription, result *crawler.CrawlResult) {
+	tags := subscribedKeywords(subs, model.SubTypeTag)
+	if len(tags) == 0 {
+		return
+	}
+
+	offset := s.tagCursor.Add(uint64(s.config.TagCrawlsPerRun)) - uint64(s.config.TagCrawlsPerRun)
+	for _, tag := range rotateKeywords(tags, offset, s.config.TagCrawlsPerRun) {
+		if ctx.Err() != nil {
+			return
+		}
+		tagResult, err := s.crawler.CrawlByTag(ctx, tag, s.config.TagCrawlLimit)
+		result.Merge(tagResult)
+		if err != nil {
+			logctx.From(ctx).Warn().Err(err).Str("tag", tag).Msg("Targeted tag crawl failed")
+			continue
+		}
+		logctx.From(ctx).Info().Str("tag", tag).Int("count", len(tagResult.Videos)).Msg("Crawled subscribed tag")
+	}
+}
+
+// crawlSubscribedActresses adds the listings of subscribed actresses to result
+// At most ActressCrawlsPerRun actresses are crawled per cycle, rotating through all of them.
+func (s *Scheduler) crawlSubscribedActresses(ctx context.Context, subs []*model.Subscription, result *crawler.CrawlResult) {
+	actresses := subscribedKeywords(subs, model.SubTypeActress)
+	if len(actresses) == 0 {
+		return
+	}
+
+	offset := s.actorCursor.Add(uint64(s.config.ActressCrawlsPerRun)) - uint64(s.config.ActressCrawlsPerRun)
+	for _, actress := range rotateKeywords(actresses, offset, s.config.ActressCrawlsPerRun) {
+		if ctx.Err() != nil {
+			return
+		}
+		actressResult, err := s.crawler.CrawlByActor(ctx, actress, s.config.ActressCrawlLimit)
+		result.Merge(actressResult)
+		if err != nil {
+			logctx.From(ctx).Warn().Err(err).Str("actress", actress).Msg("Targeted actress crawl failed")
+			continue
+		}
+		logctx.From(ctx).Info().Str("actress", actress).Int("count", len(actressResult.Videos)).Msg("Crawled subscribed actress")
 	}
+}
+
+// subscribedKeywords returns the distinct keywords of enabled subscriptions of a type in sorted order
+func subscribedKeywords(subs []*model.Subscription, subType model.SubscriptionType) []string {
+	seen := make(map[string]bool)
+	var keywords []string
+	for _, sub := range subs {
+		if !sub.Enabled || sub.Type != subType || sub.Keyword == "" {
+			continue
+		}
+		key := strings.ToLower(sub.Keyword)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		keywords = append(keywords, sub.Keyword)
+	}
+	sort.Strings(keywords)
+	return keywords
+}
 
-	return nil
+// rotateKeywords returns up to n keywords starting at offset, wrapping around the list
+func rotateKeywords(keywords []string, offset uint64, n int) []string {
+	if n > len(keywords) {
+		n = len(keywords)
+	}
+	start := int(offset % uint64(len(keywords)))
+	picked := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		picked = append(picked, keywords[(start+i)%len(keywords)])
+	}
+	return picked
 }
 
-// Stop gracefully stops the scheduler
+// runAndRecord executes a crawl cycle and persists its outcome to the crawl history
+func (s *Scheduler) runAndRecord(ctx context.Context, trigger model.CrawlTrigger, pages int) error {
+	startTime := time.Now()
+	run, err := s.runOnce(ctx, pages)
+
+	run.Trigger = trigger
+	run.StartedAt = startTime
+	run.DurationMs = time.Since(startTime).Milliseconds()
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	if recordErr := s.store.RecordCrawlRun(ctx, run); recordErr != nil {
+		logctx.From(ctx).Error().Err(recordErr).Msg("Failed to record crawl run")
+	}
+
+	return err
+}
+
+// storeLocker returns the store's Locker implementation, if it has one
+func storeLocker(st store.Store) (store.Locker, bool) {
+	locker, ok := st.(store.Locker)
+	return locker, ok
+}
+
+// acquireDistributedLock acquires the cross-instance crawl lock if one is configured
+// Returns a release function and true when the crawl may proceed
+func (s *Scheduler) acquireDistributedLock(ctx context.Context) (func(), bool) {
+	if s.locker == nil {
+		return func() {}, true
+	}
+
+	unlock, acquired, err := s.locker.TryLock(ctx, crawlLockName)
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Failed to acquire distributed crawl lock")
+		return nil, false
+	}
+	if !acquired {
+		return nil, false
+	}
+	return unlock, true
+}
+
+// Stop gracefully stops the scheduler, waiting for the running crawl to finish
 func (s *Scheduler) Stop() {
-	log.Info().Msg("Stopping scheduler...")
+	_ = s.Shutdown(context.Background())
+}
+
+// Shutdown stops the scheduler from triggering new crawls and waits for the running
+// crawl to finish. It returns ctx's error if ctx is done first; the crawl then keeps
+// running until its own context is cancelled.
+func (s *Scheduler) Shutdown(ctx context.Context) error {
+	logctx.From(ctx).Info().Msg("Stopping scheduler...")
 	close(s.stopCh)
-	s.wg.Wait()
-	log.Info().Msg("Scheduler stopped")
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logctx.From(ctx).Info().Msg("Scheduler stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// setNextRun records when the next scheduled crawl is due
+func (s *Scheduler) setNextRun(t time.Time) {
+	s.nextRun.Store(t.UnixNano())
+}
+
+// NextRun returns when the next scheduled crawl is due, or the zero time when the
+// scheduler is disabled or stopped. A time in the past means the crawl is overdue:
+// the current cycle ran past the interval or the scheduler loop is stuck.
+func (s *Scheduler) NextRun() time.Time {
+	next := s.nextRun.Load()
+	if next == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, next)
 }
 
 // IsRunning returns true if a crawl task is currently running
@@ -168,26 +440,105 @@ func (s *Scheduler) IsRunning() bool {
 	return s.running.Load()
 }
 
+// Pause stops scheduled crawls until Resume; the ticker keeps running so NextRun stays current
+// The pause is not persisted and applies to this instance only.
+// Returns false if the scheduler was already paused.
+func (s *Scheduler) Pause() bool {
+	if !s.paused.CompareAndSwap(false, true) {
+		return false
+	}
+	log.Info().Msg("Scheduler paused")
+	return true
+}
+
+// Resume lets scheduled crawls run again after Pause
+// Returns false if the scheduler was not paused.
+func (s *Scheduler) Resume() bool {
+	if !s.paused.CompareAndSwap(true, false) {
+		return false
+	}
+	log.Info().Msg("Scheduler resumed")
+	return true
+}
+
+// IsPaused returns true if scheduled crawls are paused
+func (s *Scheduler) IsPaused() bool {
+	return s.paused.Load()
+}
+
+// tryAcquire takes the local and distributed crawl locks without waiting
+// Returns a function releasing both and true when a crawl may start
+func (s *Scheduler) tryAcquire(ctx context.Context) (func(), bool) {
+	if !s.mu.TryLock() {
+		return nil, false
+	}
+
+	unlock, ok := s.acquireDistributedLock(ctx)
+	if !ok {
+		s.mu.Unlock()
+		return nil, false
+	}
+	return func() {
+		unlock()
+		s.mu.Unlock()
+	}, true
+}
+
 // TryRun attempts to run a crawl task immediately
 // Returns false if a task is already running
 func (s *Scheduler) TryRun(ctx context.Context, pages int) bool {
-	if !s.mu.TryLock() {
+	release, ok := s.tryAcquire(ctx)
+	if !ok {
+		return false
+	}
+	defer release()
+
+	s.manualCrawl(ctx, pages)
+	return true
+}
+
+// RunNow starts a crawl of the scheduled size in the background, even while paused
+// The crawl outlives ctx, e.g. the HTTP request that triggered it, and is cancelled
+// when the scheduler stops. Returns false if a task is already running.
+func (s *Scheduler) RunNow(ctx context.Context) bool {
+	release, ok := s.tryAcquire(ctx)
+	if !ok {
 		return false
 	}
-	defer s.mu.Unlock()
 
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer release()
+		defer cancel()
+
+		go func() {
+			select {
+			case <-s.stopCh:
+				cancel()
+			case <-runCtx.Done():
+			}
+		}()
+		s.manualCrawl(runCtx, s.config.InitialPages)
+	}()
+	return true
+}
+
+// manualCrawl runs and records a crawl requested outside the schedule
+// The caller holds the crawl locks.
+func (s *Scheduler) manualCrawl(ctx context.Context, pages int) {
 	s.running.Store(true)
 	defer s.running.Store(false)
 
+	ctx = logctx.With(ctx, logctx.RunID, logctx.NewID())
 	startTime := time.Now()
-	log.Info().Int("pages", pages).Msg("Starting manual crawl")
+	logctx.From(ctx).Info().Int("pages", pages).Msg("Starting manual crawl")
 
-	if err := s.RunOnce(ctx, pages); err != nil {
-		log.Error().Err(err).Msg("Manual crawl failed")
+	if err := s.runAndRecord(ctx, model.CrawlTriggerManual, pages); err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Manual crawl failed")
 	}
 
 	duration := time.Since(startTime)
-	log.Info().Dur("duration", duration).Msg("Manual crawl completed")
-
-	return true
+	logctx.From(ctx).Info().Dur("duration", duration).Msg("Manual crawl completed")
 }
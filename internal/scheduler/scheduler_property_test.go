@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"context"
+	"database/sql"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
 	"github.com/user/missav-bot-go/internal/config"
+	"github.com/user/missav-bot-go/internal/crawler"
 	"github.com/user/missav-bot-go/internal/model"
 	"github.com/user/missav-bot-go/internal/push"
 	"github.com/user/missav-bot-go/internal/store"
@@ -31,7 +33,7 @@ func NewMockCrawler(crawlDelay time.Duration) *MockCrawler {
 	}
 }
 
-func (m *MockCrawler) CrawlNewVideos(ctx context.Context, pages int) ([]*model.Video, error) {
+func (m *MockCrawler) CrawlNewVideos(ctx context.Context, pages int) (*crawler.CrawlResult, error) {
 	// Track concurrent executions
 	current := atomic.AddInt32(&m.concurrent, 1)
 	defer atomic.AddInt32(&m.concurrent, -1)
@@ -48,23 +50,27 @@ func (m *MockCrawler) CrawlNewVideos(ctx context.Context, pages int) ([]*model.V
 	// Simulate crawl work
 	time.Sleep(m.crawlDelay)
 
-	return []*model.Video{}, nil
+	return &crawler.CrawlResult{Videos: []*model.Video{}, PagesFetched: pages}, nil
 }
 
 func (m *MockCrawler) CrawlVideoDetail(ctx context.Context, detailURL string) (*model.Video, error) {
 	return nil, nil
 }
 
-func (m *MockCrawler) CrawlByActor(ctx context.Context, actorName string, limit int) ([]*model.Video, error) {
-	return nil, nil
+func (m *MockCrawler) CrawlByActor(ctx context.Context, actorName string, limit int) (*crawler.CrawlResult, error) {
+	return &crawler.CrawlResult{}, nil
 }
 
-func (m *MockCrawler) CrawlByCode(ctx context.Context, code string) (*model.Video, error) {
-	return nil, nil
+func (m *MockCrawler) CrawlByCode(ctx context.Context, code string) (*crawler.CrawlResult, error) {
+	return &crawler.CrawlResult{}, nil
 }
 
-func (m *MockCrawler) CrawlByKeyword(ctx context.Context, keyword string, limit int) ([]*model.Video, error) {
-	return nil, nil
+func (m *MockCrawler) CrawlByKeyword(ctx context.Context, keyword string, limit int) (*crawler.CrawlResult, error) {
+	return &crawler.CrawlResult{}, nil
+}
+
+func (m *MockCrawler) CrawlByTag(ctx context.Context, tag string, limit int) (*crawler.CrawlResult, error) {
+	return &crawler.CrawlResult{}, nil
 }
 
 func (m *MockCrawler) Close() error {
@@ -88,6 +94,7 @@ type MockStore struct {
 	videos        map[uint]*model.Video
 	subscriptions []*model.Subscription
 	pushRecords   []*model.PushRecord
+	crawlRuns     []*model.CrawlRun
 }
 
 func NewMockStore() *MockStore {
@@ -118,6 +125,173 @@ func (m *MockStore) GetVideoByCode(ctx context.Context, code string) (*model.Vid
 	return nil, nil
 }
 
+func (m *MockStore) UpdateVideoDetails(ctx context.Context, video *model.Video) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.videos[video.ID] = video
+	return nil
+}
+
+func (m *MockStore) GetIncompleteVideos(ctx context.Context, since time.Time, limit int) ([]*model.Video, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CountVideosByCompleteness(ctx context.Context) (map[int]int64, error) {
+	return map[int]int64{}, nil
+}
+
+func (m *MockStore) GetVideosSince(ctx context.Context, since time.Time) ([]*model.Video, error) {
+	return nil, nil
+}
+
+func (m *MockStore) SaveDuplicateCandidates(ctx context.Context, candidates []*model.DuplicateCandidate) (int, error) {
+	return len(candidates), nil
+}
+
+func (m *MockStore) GetDuplicateCandidates(ctx context.Context, status model.DuplicateStatus, limit int) ([]*model.DuplicateCandidate, error) {
+	return nil, nil
+}
+
+func (m *MockStore) ResolveDuplicateCandidate(ctx context.Context, id uint, status model.DuplicateStatus) (*model.DuplicateCandidate, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetPushHistory(ctx context.Context, chatID int64, limit int) ([]*store.PushHistoryEntry, error) {
+	return nil, nil
+}
+
+func (m *MockStore) MuteCode(ctx context.Context, chatID int64, code string) error {
+	return nil
+}
+
+func (m *MockStore) UnmuteCode(ctx context.Context, chatID int64, code string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockStore) IsCodeMuted(ctx context.Context, code string, chatID int64) (bool, error) {
+	return false, nil
+}
+
+func (m *MockStore) GetMutedCodes(ctx context.Context, chatID int64) ([]*model.ChatMute, error) {
+	return nil, nil
+}
+
+func (m *MockStore) AddCodeWatch(ctx context.Context, watch *model.CodeWatch) (bool, error) {
+	return true, nil
+}
+
+func (m *MockStore) RemoveCodeWatch(ctx context.Context, chatID int64, code string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockStore) GetCodeWatches(ctx context.Context, chatID int64) ([]*model.CodeWatch, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetAllCodeWatches(ctx context.Context) ([]*model.CodeWatch, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetPushHistorySince(ctx context.Context, chatID int64, since time.Time, limit int) ([]*store.PushHistoryEntry, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CountCappedPushesSince(ctx context.Context, chatID int64, since time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) GetRecapChats(ctx context.Context) ([]*model.ChatSettings, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetRelatedVideos(ctx context.Context, video *model.Video, limit int) ([]*model.Video, error) {
+	return nil, nil
+}
+
+func (m *MockStore) AddBlacklistEntry(ctx context.Context, entry *model.BlacklistEntry) error {
+	return nil
+}
+
+func (m *MockStore) DeleteBlacklistEntry(ctx context.Context, chatID int64, entryType model.BlacklistType, keyword string) (bool, error) {
+	return false, nil
+}
+
+func (m *MockStore) GetBlacklist(ctx context.Context, chatID int64) ([]*model.BlacklistEntry, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CountPushesSince(ctx context.Context, chatID int64, since time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) ReportCappedPushes(ctx context.Context, before time.Time) (map[int64]int64, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetEditablePushes(ctx context.Context, videoID uint) ([]*model.PushRecord, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetPushedMessages(ctx context.Context, code string) ([]*model.PushRecord, error) {
+	return nil, nil
+}
+
+func (m *MockStore) ClearPushMessage(ctx context.Context, recordID uint) error {
+	return nil
+}
+
+func (m *MockStore) HideVideos(ctx context.Context, code string) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) DeleteVideos(ctx context.Context, code string, bannedBy int64) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) CountUnpushedVideos(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) GetPushStatsByDay(ctx context.Context, since time.Time) ([]*store.PushDayStat, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetPushStatsByChat(ctx context.Context, since time.Time, limit int) ([]*store.PushChatStat, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CountSubscriptionsByType(ctx context.Context) (map[model.SubscriptionType]int64, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CountSubscriptionsByChatType(ctx context.Context) ([]*store.SubscriptionCount, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CountSubscribedChats(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) WithTx(ctx context.Context, fn func(store.Store) error) error {
+	return fn(m)
+}
+
+func (m *MockStore) TouchUser(ctx context.Context, user *model.User) error {
+	return nil
+}
+
+func (m *MockStore) GetUser(ctx context.Context, userID int64) (*model.User, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetUpdateOffset(ctx context.Context, botID int64) (int, error) {
+	return 0, nil
+}
+
+func (m *MockStore) SaveUpdateOffset(ctx context.Context, botID int64, offset int) error {
+	return nil
+}
+
 func (m *MockStore) GetUnpushedVideos(ctx context.Context) ([]*model.Video, error) {
 	return []*model.Video{}, nil
 }
@@ -126,10 +300,58 @@ func (m *MockStore) MarkAsPushed(ctx context.Context, videoID uint) error {
 	return nil
 }
 
+func (m *MockStore) MarkAsPushedBulk(ctx context.Context, videoIDs []uint) error {
+	return nil
+}
+
 func (m *MockStore) SearchVideos(ctx context.Context, keyword string, limit int) ([]*model.Video, error) {
 	return nil, nil
 }
 
+func (m *MockStore) CountVideosMatching(ctx context.Context, filter *store.VideoFilter) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) FindVideos(ctx context.Context, filter *store.VideoFilter) ([]*model.Video, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetActressNames(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetTagNames(ctx context.Context) ([]string, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetPopularTags(ctx context.Context, limit int) ([]*store.TagCount, error) {
+	return nil, nil
+}
+
+func (m *MockStore) AddActressAlias(ctx context.Context, name string, alias string) error {
+	return nil
+}
+
+func (m *MockStore) GetActressAliases(ctx context.Context, name string) ([]*model.ActressAlias, error) {
+	return nil, nil
+}
+
+func (m *MockStore) RecordCommand(ctx context.Context, entry *model.CommandLog) error {
+	return nil
+}
+
+func (m *MockStore) GetCommandStats(ctx context.Context, chatID int64, since time.Time) ([]*store.CommandStat, error) {
+	return nil, nil
+}
+
+func (m *MockStore) GetChatSettings(ctx context.Context, chatID int64) (*model.ChatSettings, error) {
+	return &model.ChatSettings{ChatID: chatID}, nil
+}
+
+func (m *MockStore) SaveChatSettings(ctx context.Context, settings *model.ChatSettings) error {
+	return nil
+}
+
 func (m *MockStore) GetLatestVideos(ctx context.Context, limit, offset int) ([]*model.Video, error) {
 	return nil, nil
 }
@@ -138,6 +360,10 @@ func (m *MockStore) CountVideos(ctx context.Context) (int64, error) {
 	return 0, nil
 }
 
+func (m *MockStore) RefreshVideoCount(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
 func (m *MockStore) ExistsByCode(ctx context.Context, code string) (bool, error) {
 	return false, nil
 }
@@ -146,12 +372,12 @@ func (m *MockStore) CreateSubscription(ctx context.Context, sub *model.Subscript
 	return nil
 }
 
-func (m *MockStore) DeleteSubscription(ctx context.Context, chatID int64, subType string, keyword string) error {
+func (m *MockStore) DeleteSubscription(ctx context.Context, chatID int64, userID int64, subType string, keyword string) error {
 	return nil
 }
 
-func (m *MockStore) DeleteAllSubscriptions(ctx context.Context, chatID int64) error {
-	return nil
+func (m *MockStore) DeleteAllSubscriptions(ctx context.Context, chatID int64) (int64, error) {
+	return 0, nil
 }
 
 func (m *MockStore) GetSubscriptions(ctx context.Context, chatID int64) ([]*model.Subscription, error) {
@@ -174,10 +400,55 @@ func (m *MockStore) HasPushed(ctx context.Context, videoID uint, chatID int64) (
 	return false, nil
 }
 
+func (m *MockStore) HasPushedCode(ctx context.Context, code string, chatID int64) (bool, error) {
+	return false, nil
+}
+
+func (m *MockStore) FilterUnpushedChats(ctx context.Context, videoID uint, code string, chatIDs []int64) ([]int64, error) {
+	return chatIDs, nil
+}
+
+func (m *MockStore) EnqueueVideoPushes(ctx context.Context, videoID uint, pushes []*model.PendingPush) error {
+	return nil
+}
+
+func (m *MockStore) GetPendingPushes(ctx context.Context, maxAttempts int, limit int) ([]*model.PendingPush, error) {
+	return nil, nil
+}
+
+func (m *MockStore) CompletePendingPush(ctx context.Context, id uint) error {
+	return nil
+}
+
+func (m *MockStore) FailPendingPush(ctx context.Context, id uint, maxAttempts int) (bool, error) {
+	return false, nil
+}
+
+func (m *MockStore) CountPendingPushes(ctx context.Context, maxAttempts int) (int64, error) {
+	return 0, nil
+}
+
+func (m *MockStore) RecordCrawlRun(ctx context.Context, run *model.CrawlRun) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.crawlRuns = append(m.crawlRuns, run)
+	return nil
+}
+
+func (m *MockStore) GetRecentCrawlRuns(ctx context.Context, limit int) ([]*model.CrawlRun, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.crawlRuns, nil
+}
+
 func (m *MockStore) Ping(ctx context.Context) error {
 	return nil
 }
 
+func (m *MockStore) DBStats() sql.DBStats {
+	return sql.DBStats{}
+}
+
 func (m *MockStore) Close() error {
 	return nil
 }
@@ -189,15 +460,31 @@ func (m *MockTelegramClient) SendMessage(chatID int64, text string) error {
 	return nil
 }
 
-func (m *MockTelegramClient) SendMarkdown(chatID int64, text string) error {
+func (m *MockTelegramClient) SendText(chatID int64, text string, parseMode string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockTelegramClient) SendPhoto(chatID int64, photoURL string, caption string, parseMode string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockTelegramClient) SendVideo(chatID int64, videoURL string, thumbURL string, caption string, parseMode string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockTelegramClient) SendMediaGroup(chatID int64, videoURL string, photoURLs []string, caption string, parseMode string) (int, error) {
+	return 0, nil
+}
+
+func (m *MockTelegramClient) EditText(chatID int64, messageID int, text string, parseMode string) error {
 	return nil
 }
 
-func (m *MockTelegramClient) SendPhoto(chatID int64, photoURL string, caption string) error {
+func (m *MockTelegramClient) EditCaption(chatID int64, messageID int, caption string, parseMode string) error {
 	return nil
 }
 
-func (m *MockTelegramClient) SendVideo(chatID int64, videoURL string, thumbURL string, caption string) error {
+func (m *MockTelegramClient) DeleteMessage(chatID int64, messageID int) error {
 	return nil
 }
 
@@ -442,3 +729,98 @@ func TestProperty_SchedulerMutualExclusion(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+// Property: Crawl History Recording
+// *For any* number of sequential manual runs, each run SHALL record exactly one crawl run
+// with the MANUAL trigger and the requested page count.
+func TestProperty_CrawlRunRecording(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("each manual run records one crawl run", prop.ForAll(
+		func(numRuns int, pages int) bool {
+			mockCrawler := NewMockCrawler(0)
+			mockStore := NewMockStore()
+			pushService := push.NewService(mockStore, &MockTelegramClient{})
+
+			cfg := &config.CrawlerConfig{
+				Enabled:      true,
+				Interval:     time.Hour,
+				InitialPages: 1,
+			}
+
+			scheduler := NewScheduler(mockCrawler, mockStore, pushService, cfg)
+
+			ctx := context.Background()
+			for i := 0; i < numRuns; i++ {
+				scheduler.TryRun(ctx, pages)
+			}
+
+			runs, _ := mockStore.GetRecentCrawlRuns(ctx, numRuns)
+			if len(runs) != numRuns {
+				return false
+			}
+			for _, run := range runs {
+				if run.Trigger != model.CrawlTriggerManual || run.Pages != pages || run.StartedAt.IsZero() {
+					return false
+				}
+			}
+			return true
+		},
+		gen.IntRange(1, 5),
+		gen.IntRange(1, 3),
+	))
+
+	properties.TestingRun(t)
+}
+
+// MockLockingStore wraps MockStore with a shared lock, simulating another instance holding it
+type MockLockingStore struct {
+	*MockStore
+	heldElsewhere bool
+	locks         int32
+}
+
+func (m *MockLockingStore) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	if m.heldElsewhere {
+		return nil, false, nil
+	}
+	atomic.AddInt32(&m.locks, 1)
+	return func() { atomic.AddInt32(&m.locks, -1) }, true, nil
+}
+
+// Property: Distributed Lock
+// *For any* manual trigger, when the distributed lock is held by another instance the
+// crawl SHALL be skipped, and when it is free the lock SHALL be released after the run.
+func TestProperty_DistributedLock(t *testing.T) {
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("crawl runs only while holding the distributed lock", prop.ForAll(
+		func(heldElsewhere bool) bool {
+			mockCrawler := NewMockCrawler(0)
+			lockingStore := &MockLockingStore{MockStore: NewMockStore(), heldElsewhere: heldElsewhere}
+			pushService := push.NewService(lockingStore, &MockTelegramClient{})
+
+			cfg := &config.CrawlerConfig{
+				Enabled:         true,
+				Interval:        time.Hour,
+				InitialPages:    1,
+				DistributedLock: true,
+			}
+
+			scheduler := NewScheduler(mockCrawler, lockingStore, pushService, cfg)
+			ran := scheduler.TryRun(context.Background(), 1)
+
+			if heldElsewhere {
+				return !ran && mockCrawler.GetCrawlCount() == 0
+			}
+			return ran && mockCrawler.GetCrawlCount() == 1 && atomic.LoadInt32(&lockingStore.locks) == 0
+		},
+		gen.Bool(),
+	))
+
+	properties.TestingRun(t)
+}
@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/config"
+	"github.com/user/missav-bot-go/internal/push"
+)
+
+func TestScheduler_NextRun(t *testing.T) {
+	mockStore := NewMockStore()
+	pushService := push.NewService(mockStore, &MockTelegramClient{})
+	cfg := &config.CrawlerConfig{Enabled: true, Interval: time.Hour, InitialPages: 1}
+	s := NewScheduler(NewMockCrawler(0), mockStore, pushService, cfg)
+
+	if next := s.NextRun(); !next.IsZero() {
+		t.Fatalf("NextRun() before Start = %v, want zero", next)
+	}
+
+	start := time.Now()
+	s.Start(context.Background())
+	deadline := time.Now().Add(time.Second)
+	for s.NextRun().IsZero() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	// The first crawl follows the initial delay
+	if next := s.NextRun(); next.Before(start) || next.After(start.Add(6*time.Second)) {
+		t.Errorf("NextRun() after Start = %v, want within the initial delay of %v", next, start)
+	}
+
+	s.Stop()
+	if next := s.NextRun(); !next.IsZero() {
+		t.Errorf("NextRun() after Stop = %v, want zero", next)
+	}
+}
+
+func TestScheduler_PauseSkipsScheduledCrawls(t *testing.T) {
+	mockStore := NewMockStore()
+	mockCrawler := NewMockCrawler(0)
+	pushService := push.NewService(mockStore, &MockTelegramClient{})
+	cfg := &config.CrawlerConfig{Enabled: true, Interval: time.Hour, InitialPages: 1}
+	s := NewScheduler(mockCrawler, mockStore, pushService, cfg)
+	ctx := context.Background()
+
+	if !s.Pause() || s.Pause() {
+		t.Fatal("Pause() should succeed once")
+	}
+	s.executeCrawl(ctx)
+	if got := mockCrawler.GetCrawlCount(); got != 0 {
+		t.Fatalf("crawls while paused = %d, want 0", got)
+	}
+
+	// Manual runs proceed while paused
+	if !s.RunNow(ctx) {
+		t.Fatal("RunNow() = false, want a crawl started")
+	}
+	s.wg.Wait()
+	if got := mockCrawler.GetCrawlCount(); got != 1 {
+		t.Fatalf("crawls after RunNow = %d, want 1", got)
+	}
+
+	if !s.Resume() || s.Resume() {
+		t.Fatal("Resume() should succeed once")
+	}
+	s.executeCrawl(ctx)
+	if got := mockCrawler.GetCrawlCount(); got != 2 {
+		t.Errorf("crawls after resume = %d, want 2", got)
+	}
+}
@@ -0,0 +1,44 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/logctx"
+)
+
+// runSessionWarmer refreshes the crawler's site session every SessionRefreshInterval,
+// so the first crawl after a quiet spell does not pay for the warm-up requests
+func (s *Scheduler) runSessionWarmer(ctx context.Context, warmer crawler.SessionWarmer) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.config.SessionRefreshInterval)
+	defer ticker.Stop()
+	logctx.From(ctx).Info().Dur("interval", s.config.SessionRefreshInterval).Msg("Session warmer started")
+
+	for {
+		select {
+		case <-ticker.C:
+			s.warmSession(ctx, warmer)
+		case <-s.stopCh:
+			return
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// warmSession refreshes the session if it would expire before the next tick
+// Nothing is fetched while the scheduler is paused, the daily budget is used up
+// or the site asked us to back off.
+func (s *Scheduler) warmSession(ctx context.Context, warmer crawler.SessionWarmer) {
+	if s.paused.Load() || crawler.BudgetExhausted(s.crawler) {
+		return
+	}
+	if reporter, ok := s.crawler.(crawler.RateLimitReporter); ok && time.Now().Before(reporter.RateLimitedUntil()) {
+		logctx.From(ctx).Debug().Msg("Site is rate limiting us, skipping session refresh")
+		return
+	}
+	warmer.WarmSession(ctx, s.config.SessionRefreshInterval)
+}
@@ -0,0 +1,49 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/config"
+)
+
+// warmingCrawler records session refreshes and can report a site back-off
+type warmingCrawler struct {
+	*MockCrawler
+	warmed       []time.Duration
+	limitedUntil time.Time
+}
+
+func (c *warmingCrawler) WarmSession(ctx context.Context, within time.Duration) {
+	c.warmed = append(c.warmed, within)
+}
+
+func (c *warmingCrawler) RateLimitedUntil() time.Time {
+	return c.limitedUntil
+}
+
+func TestWarmSession(t *testing.T) {
+	c := &warmingCrawler{MockCrawler: NewMockCrawler(0)}
+	cfg := &config.CrawlerConfig{SessionRefreshInterval: 2 * time.Minute}
+	s := NewScheduler(c, NewMockStore(), nil, cfg)
+	ctx := context.Background()
+
+	s.warmSession(ctx, c)
+	if len(c.warmed) != 1 || c.warmed[0] != 2*time.Minute {
+		t.Fatalf("warmed = %v, want one refresh within 2m", c.warmed)
+	}
+
+	s.Pause()
+	s.warmSession(ctx, c)
+	s.Resume()
+	if len(c.warmed) != 1 {
+		t.Errorf("refreshed while paused")
+	}
+
+	c.limitedUntil = time.Now().Add(time.Minute)
+	s.warmSession(ctx, c)
+	if len(c.warmed) != 1 {
+		t.Errorf("refreshed while rate limited")
+	}
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestSubscribedKeywords(t *testing.T) {
+	subs := []*model.Subscription{
+		{Type: model.SubTypeTag, Keyword: "巨乳", Enabled: true},
+		{Type: model.SubTypeTag, Keyword: "VR", Enabled: true},
+		{Type: model.SubTypeTag, Keyword: "vr", Enabled: true},
+		{Type: model.SubTypeTag, Keyword: "disabled", Enabled: false},
+		{Type: model.SubTypeActress, Keyword: "三上悠亜", Enabled: true},
+		{Type: model.SubTypeAll, Enabled: true},
+	}
+
+	want := []string{"VR", "巨乳"}
+	if got := subscribedKeywords(subs, model.SubTypeTag); !reflect.DeepEqual(got, want) {
+		t.Errorf("subscribedKeywords(TAG) = %v, want %v", got, want)
+	}
+
+	want = []string{"三上悠亜"}
+	if got := subscribedKeywords(subs, model.SubTypeActress); !reflect.DeepEqual(got, want) {
+		t.Errorf("subscribedKeywords(ACTRESS) = %v, want %v", got, want)
+	}
+}
+
+func TestRotateKeywords(t *testing.T) {
+	tags := []string{"a", "b", "c"}
+
+	tests := []struct {
+		offset uint64
+		n      int
+		want   []string
+	}{
+		{0, 2, []string{"a", "b"}},
+		{2, 2, []string{"c", "a"}},
+		{4, 2, []string{"b", "c"}},
+		{0, 5, []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		if got := rotateKeywords(tags, tt.offset, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("rotateKeywords(%d, %d) = %v, want %v", tt.offset, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// checkWatches notifies chats whose /watch codes have appeared and removes those watches
+// Codes are looked up in the store first. Up to WatchProbesPerRun of the codes still
+// missing have their detail page crawled, rotating through them across cycles.
+func (s *Scheduler) checkWatches(ctx context.Context) {
+	if s.pushService == nil {
+		return
+	}
+
+	watches, err := s.store.GetAllCodeWatches(ctx)
+	if err != nil {
+		logctx.From(ctx).Error().Err(err).Msg("Failed to load code watches")
+		return
+	}
+	codes, chats := groupWatches(watches)
+
+	var missing []string
+	for _, code := range codes {
+		if ctx.Err() != nil {
+			return
+		}
+		video, err := s.store.GetVideoByCode(ctx, code)
+		if err != nil {
+			logctx.From(ctx).Warn().Err(err).Str("code", code).Msg("Failed to look up watched code")
+			continue
+		}
+		if video == nil {
+			missing = append(missing, code)
+			continue
+		}
+		s.notifyWatchers(ctx, video, chats[code])
+	}
+
+	probes := s.config.WatchProbesPerRun
+	if probes <= 0 || len(missing) == 0 {
+		return
+	}
+	offset := s.watchCursor.Add(uint64(probes)) - uint64(probes)
+	for _, code := range rotateKeywords(missing, offset, probes) {
+		if ctx.Err() != nil || crawler.BudgetExhausted(s.crawler) {
+			return
+		}
+		result, err := s.crawler.CrawlByCode(ctx, code)
+		if err != nil {
+			logctx.From(ctx).Debug().Err(err).Str("code", code).Msg("Watched code not available yet")
+			if errors.Is(err, crawler.ErrBudgetExhausted) {
+				return
+			}
+			continue
+		}
+		if len(result.Videos) == 0 {
+			continue
+		}
+		if _, _, err := s.store.SaveVideos(ctx, result.Videos); err != nil {
+			logctx.From(ctx).Error().Err(err).Str("code", code).Msg("Failed to save watched code")
+			continue
+		}
+		video, err := s.store.GetVideoByCode(ctx, code)
+		if err != nil || video == nil {
+			logctx.From(ctx).Warn().Err(err).Str("code", code).Msg("Failed to load saved watched code")
+			continue
+		}
+		s.notifyWatchers(ctx, video, chats[code])
+	}
+}
+
+// notifyWatchers pushes a watched video to the chats watching it and removes their watches
+// A chat the push fails for keeps its watch, so it is notified on a later cycle.
+func (s *Scheduler) notifyWatchers(ctx context.Context, video *model.Video, chatIDs []int64) {
+	code := model.CanonicalCode(video.Code)
+	for _, chatID := range chatIDs {
+		if err := s.pushService.PushVideoToChat(ctx, video, chatID); err != nil {
+			logctx.From(ctx).Warn().Err(err).Str("code", code).Int64("chatID", chatID).Msg("Failed to push watched code")
+			continue
+		}
+		if _, err := s.store.RemoveCodeWatch(ctx, chatID, code); err != nil {
+			logctx.From(ctx).Error().Err(err).Str("code", code).Int64("chatID", chatID).Msg("Failed to remove code watch")
+			continue
+		}
+		logctx.From(ctx).Info().Str("code", code).Int64("chatID", chatID).Msg("Watched code appeared, notified chat")
+	}
+}
+
+// groupWatches returns the distinct watched codes in the order they were first watched
+// and the chats watching each
+func groupWatches(watches []*model.CodeWatch) ([]string, map[string][]int64) {
+	var codes []string
+	chats := make(map[string][]int64)
+	for _, watch := range watches {
+		if _, ok := chats[watch.Code]; !ok {
+			codes = append(codes, watch.Code)
+		}
+		chats[watch.Code] = append(chats[watch.Code], watch.ChatID)
+	}
+	return codes, chats
+}
@@ -0,0 +1,27 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+func TestGroupWatches(t *testing.T) {
+	codes, chats := groupWatches([]*model.CodeWatch{
+		{ChatID: 1, Code: "SSIS-001"},
+		{ChatID: 2, Code: "IPX-100"},
+		{ChatID: 2, Code: "SSIS-001"},
+		{ChatID: 3, Code: "SSIS-001"},
+	})
+
+	if want := []string{"SSIS-001", "IPX-100"}; !reflect.DeepEqual(codes, want) {
+		t.Errorf("codes = %v, want %v", codes, want)
+	}
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(chats["SSIS-001"], want) {
+		t.Errorf("chats[SSIS-001] = %v, want %v", chats["SSIS-001"], want)
+	}
+	if want := []int64{2}; !reflect.DeepEqual(chats["IPX-100"], want) {
+		t.Errorf("chats[IPX-100] = %v, want %v", chats["IPX-100"], want)
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+// storeCollectTimeout bounds the store queries of a single scrape
+const storeCollectTimeout = 5 * time.Second
+
+var (
+	unpushedVideosDesc = prometheus.NewDesc(
+		"missav_bot_unpushed_videos",
+		"Number of videos waiting to be pushed",
+		nil, nil,
+	)
+	subscriptionsDesc = prometheus.NewDesc(
+		"missav_bot_subscriptions",
+		"Number of enabled subscriptions by type and chat type",
+		[]string{"type", "chat_type"}, nil,
+	)
+	disabledSubscriptionsDesc = prometheus.NewDesc(
+		"missav_bot_subscriptions_disabled",
+		"Number of disabled subscriptions by type and chat type",
+		[]string{"type", "chat_type"}, nil,
+	)
+	subscribedChatsDesc = prometheus.NewDesc(
+		"missav_bot_subscribed_chats",
+		"Number of distinct chats with at least one enabled subscription",
+		nil, nil,
+	)
+	pushes24hDesc = prometheus.NewDesc(
+		"missav_bot_pushes_24h",
+		"Number of pushes in the last 24 hours by status",
+		[]string{"status"}, nil,
+	)
+)
+
+// storeCollector reports aggregated statistics queried from the store on every scrape
+type storeCollector struct {
+	store store.Store
+}
+
+// Describe implements prometheus.Collector
+func (c *storeCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- unpushedVideosDesc
+	ch <- pushes24hDesc
+}
+
+// Collect implements prometheus.Collector
+// A failed query leaves its metric out of the scrape instead of reporting zero.
+func (c *storeCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeCollectTimeout)
+	defer cancel()
+
+	if backlog, err := c.store.CountUnpushedVideos(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to collect unpushed videos")
+	} else {
+		ch <- prometheus.MustNewConstMetric(unpushedVideosDesc, prometheus.GaugeValue, float64(backlog))
+	}
+
+	if days, err := c.store.GetPushStatsByDay(ctx, time.Now().Add(-24*time.Hour)); err != nil {
+		log.Error().Err(err).Msg("Failed to collect push stats")
+	} else {
+		var success, failed int64
+		for _, stat := range days {
+			success += stat.Success
+			failed += stat.Failed
+		}
+		ch <- prometheus.MustNewConstMetric(pushes24hDesc, prometheus.GaugeValue, float64(success), "success")
+		ch <- prometheus.MustNewConstMetric(pushes24hDesc, prometheus.GaugeValue, float64(failed), "failed")
+	}
+}
+
+// subscriptionCollector reports subscription counts queried from the store on every scrape
+type subscriptionCollector struct {
+	store store.Store
+}
+
+// Describe implements prometheus.Collector
+func (c *subscriptionCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- subscriptionsDesc
+	ch <- disabledSubscriptionsDesc
+	ch <- subscribedChatsDesc
+}
+
+// Collect implements prometheus.Collector
+// Only combinations of type and chat type that have subscriptions are reported.
+func (c *subscriptionCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), storeCollectTimeout)
+	defer cancel()
+
+	if counts, err := c.store.CountSubscriptionsByChatType(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to collect subscriptions")
+	} else {
+		for _, count := range counts {
+			desc := subscriptionsDesc
+			if !count.Enabled {
+				desc = disabledSubscriptionsDesc
+			}
+			ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(count.Count),
+				strings.ToLower(string(count.Type)), subscriptionChatType(count.ChatType))
+		}
+	}
+
+	if chats, err := c.store.CountSubscribedChats(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to collect subscribed chats")
+	} else {
+		ch <- prometheus.MustNewConstMetric(subscribedChatsDesc, prometheus.GaugeValue, float64(chats))
+	}
+}
+
+// subscriptionChatType labels the chat type of a subscription
+// Subscriptions of other platforms, such as Discord, have none.
+func subscriptionChatType(chatType string) string {
+	if chatType == "" {
+		return "unknown"
+	}
+	return chatType
+}
+
+// RegisterStoreMetrics exposes the store's push and subscription statistics on /metrics
+// It registers with the default registry and is called once at startup.
+func (s *Server) RegisterStoreMetrics() error {
+	if err := prometheus.Register(&storeCollector{store: s.store}); err != nil {
+		return err
+	}
+	return prometheus.Register(&subscriptionCollector{store: s.store})
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+// statsStore serves fixed statistics
+type statsStore struct {
+	store.Store
+}
+
+func (s *statsStore) CountUnpushedVideos(ctx context.Context) (int64, error) {
+	return 7, nil
+}
+
+func (s *statsStore) GetPushStatsByDay(ctx context.Context, since time.Time) ([]*store.PushDayStat, error) {
+	return []*store.PushDayStat{{Success: 4, Failed: 1}, {Success: 5}}, nil
+}
+
+func TestStoreCollector(t *testing.T) {
+	expected := `
+# HELP missav_bot_pushes_24h Number of pushes in the last 24 hours by status
+# TYPE missav_bot_pushes_24h gauge
+missav_bot_pushes_24h{status="failed"} 1
+missav_bot_pushes_24h{status="success"} 9
+# HELP missav_bot_unpushed_videos Number of videos waiting to be pushed
+# TYPE missav_bot_unpushed_videos gauge
+missav_bot_unpushed_videos 7
+`
+	collector := &storeCollector{store: &statsStore{}}
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
+
+func (s *statsStore) CountSubscriptionsByChatType(ctx context.Context) ([]*store.SubscriptionCount, error) {
+	return []*store.SubscriptionCount{
+		{Type: model.SubTypeAll, ChatType: "private", Enabled: true, Count: 2},
+		{Type: model.SubTypeTag, ChatType: "supergroup", Enabled: true, Count: 3},
+		{Type: model.SubTypeTag, ChatType: "supergroup", Enabled: false, Count: 1},
+		{Type: model.SubTypeActress, Enabled: true, Count: 4},
+	}, nil
+}
+
+func (s *statsStore) CountSubscribedChats(ctx context.Context) (int64, error) {
+	return 5, nil
+}
+
+func TestSubscriptionCollector(t *testing.T) {
+	expected := `
+# HELP missav_bot_subscribed_chats Number of distinct chats with at least one enabled subscription
+# TYPE missav_bot_subscribed_chats gauge
+missav_bot_subscribed_chats 5
+# HELP missav_bot_subscriptions Number of enabled subscriptions by type and chat type
+# TYPE missav_bot_subscriptions gauge
+missav_bot_subscriptions{chat_type="private",type="all"} 2
+missav_bot_subscriptions{chat_type="supergroup",type="tag"} 3
+missav_bot_subscriptions{chat_type="unknown",type="actress"} 4
+# HELP missav_bot_subscriptions_disabled Number of disabled subscriptions by type and chat type
+# TYPE missav_bot_subscriptions_disabled gauge
+missav_bot_subscriptions_disabled{chat_type="supergroup",type="tag"} 1
+`
+	collector := &subscriptionCollector{store: &statsStore{}}
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(expected)); err != nil {
+		t.Error(err)
+	}
+}
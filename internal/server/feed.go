@@ -0,0 +1,222 @@
+package server
+
+import (
+	"encoding/xml"
+	"fmt"
+	"html"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+const (
+	// defaultFeedLimit is the number of videos in a feed unless ?limit= is given
+	defaultFeedLimit = 50
+	// maxFeedLimit caps the ?limit= query parameter
+	maxFeedLimit = 200
+	// feedTitle is the title of the unfiltered feed
+	feedTitle = "MissAV 最新视频"
+)
+
+// feedQuery describes which videos a feed contains
+type feedQuery struct {
+	Title  string
+	Filter *store.VideoFilter
+}
+
+// parseFeedQuery builds the feed query of a request
+// kind and name come from the /feed/{kind}/{name} path and are empty for the main feed;
+// the limit comes from the optional "limit" query parameter.
+func parseFeedQuery(r *http.Request, kind string, name string) (*feedQuery, error) {
+	filter := &store.VideoFilter{Sort: store.SortNewest, Limit: defaultFeedLimit}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxFeedLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxFeedLimit)
+		}
+		filter.Limit = n
+	}
+
+	query := &feedQuery{Title: feedTitle, Filter: filter}
+	switch kind {
+	case "":
+	case "actress":
+		filter.Actress = name
+		query.Title = fmt.Sprintf("%s - %s", feedTitle, name)
+	case "tag":
+		filter.Tag = name
+		query.Title = fmt.Sprintf("%s - #%s", feedTitle, name)
+	default:
+		return nil, fmt.Errorf("unknown feed type %q", kind)
+	}
+	if kind != "" && strings.TrimSpace(name) == "" {
+		return nil, fmt.Errorf("feed name is required")
+	}
+	return query, nil
+}
+
+// requestURL reconstructs the absolute URL of a request, honouring reverse proxy headers
+func requestURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host + r.URL.RequestURI()
+}
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	GUID        rssGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Description string        `xml:"description"`
+	Categories  []string      `xml:"category"`
+	Enclosure   *rssEnclosure `xml:"enclosure,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// buildRSSFeed renders videos as an RSS 2.0 feed
+func buildRSSFeed(title string, selfURL string, videos []*model.Video) *rssFeed {
+	feed := &rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         title,
+			Link:          selfURL,
+			Description:   title,
+			AtomLink:      rssLink{Href: selfURL, Rel: "self", Type: "application/rss+xml"},
+			LastBuildDate: time.Now().UTC().Format(time.RFC1123Z),
+			Items:         make([]rssItem, 0, len(videos)),
+		},
+	}
+
+	for _, video := range videos {
+		item := rssItem{
+			Title:       strings.TrimSpace(video.Code + " " + video.Title),
+			Link:        video.DetailURL,
+			GUID:        rssGUID{Value: video.Code},
+			PubDate:     video.CreatedAt.UTC().Format(time.RFC1123Z),
+			Description: videoSummaryHTML(video),
+			Categories:  splitVideoList(video.Tags),
+		}
+		if video.CoverURL != "" {
+			item.Enclosure = &rssEnclosure{URL: video.CoverURL, Type: "image/jpeg"}
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+	return feed
+}
+
+// videoSummaryHTML renders the cover and metadata of a video as an HTML fragment
+func videoSummaryHTML(video *model.Video) string {
+	var b strings.Builder
+	if video.CoverURL != "" {
+		fmt.Fprintf(&b, `<p><img src="%s" alt="%s"></p>`, html.EscapeString(video.CoverURL), html.EscapeString(video.Code))
+	}
+	if video.Actresses != "" {
+		fmt.Fprintf(&b, "<p>演员: %s</p>", html.EscapeString(video.Actresses))
+	}
+	if video.Tags != "" {
+		fmt.Fprintf(&b, "<p>标签: %s</p>", html.EscapeString(video.Tags))
+	}
+	if video.Duration > 0 {
+		fmt.Fprintf(&b, "<p>时长: %d 分钟</p>", video.Duration)
+	}
+	return b.String()
+}
+
+// splitVideoList splits a comma-separated video field into trimmed, non-empty values
+func splitVideoList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// handleFeed handles /feed.xml and /feed/{actress|tag}/{name}.xml
+// Returns the latest matching videos as an RSS 2.0 feed
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var kind, name string
+	if r.URL.Path != "/feed.xml" {
+		rest, ok := strings.CutPrefix(r.URL.Path, "/feed/")
+		if ok {
+			rest, ok = strings.CutSuffix(rest, ".xml")
+		}
+		if ok {
+			kind, name, ok = strings.Cut(rest, "/")
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	query, err := parseFeedQuery(r, kind, name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	videos, err := s.store.FindVideos(r.Context(), query.Filter)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get feed videos")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+	enc := xml.NewEncoder(w)
+	if err := enc.Encode(buildRSSFeed(query.Title, requestURL(r), videos)); err != nil {
+		log.Error().Err(err).Msg("Failed to encode feed")
+	}
+}
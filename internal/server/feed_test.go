@@ -0,0 +1,120 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/store"
+)
+
+// feedStore serves fixed videos and records the last filter it was queried with
+type feedStore struct {
+	store.Store
+	videos []*model.Video
+	filter *store.VideoFilter
+}
+
+func (s *feedStore) FindVideos(ctx context.Context, filter *store.VideoFilter) ([]*model.Video, error) {
+	s.filter = filter
+	return s.videos, nil
+}
+
+func TestHandleFeed(t *testing.T) {
+	fs := &feedStore{videos: []*model.Video{{
+		Code:      "ABC-123",
+		Title:     "Title",
+		Tags:      "tag1, tag2",
+		CoverURL:  "https://example.com/cover.jpg",
+		DetailURL: "https://example.com/abc-123",
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}}
+	s := NewServer(fs)
+
+	tests := []struct {
+		path    string
+		status  int
+		actress string
+		tag     string
+		limit   int
+	}{
+		{"/feed.xml", http.StatusOK, "", "", defaultFeedLimit},
+		{"/feed.xml?limit=5", http.StatusOK, "", "", 5},
+		{"/feed/actress/%E4%B8%89%E4%B8%8A%E6%82%A0%E4%BA%9C.xml", http.StatusOK, "三上悠亜", "", defaultFeedLimit},
+		{"/feed/tag/tag1.xml", http.StatusOK, "", "tag1", defaultFeedLimit},
+		{"/feed/studio/x.xml", http.StatusBadRequest, "", "", 0},
+		{"/feed/tag/tag1", http.StatusNotFound, "", "", 0},
+		{"/feed.xml?limit=0", http.StatusBadRequest, "", "", 0},
+	}
+
+	for _, tt := range tests {
+		fs.filter = nil
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+		if rec.Code != tt.status {
+			t.Errorf("%s: status = %d, want %d", tt.path, rec.Code, tt.status)
+			continue
+		}
+		if tt.status != http.StatusOK {
+			continue
+		}
+		if fs.filter.Actress != tt.actress || fs.filter.Tag != tt.tag || fs.filter.Limit != tt.limit {
+			t.Errorf("%s: filter = %+v", tt.path, fs.filter)
+		}
+
+		var feed rssFeed
+		if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+			t.Fatalf("%s: invalid RSS: %v", tt.path, err)
+		}
+		if len(feed.Channel.Items) != 1 || feed.Channel.Items[0].GUID.Value != "ABC-123" {
+			t.Errorf("%s: items = %+v", tt.path, feed.Channel.Items)
+		}
+		if got := feed.Channel.Items[0].Categories; len(got) != 2 {
+			t.Errorf("%s: categories = %v, want 2", tt.path, got)
+		}
+	}
+}
+
+func TestHandleJSONFeed(t *testing.T) {
+	fs := &feedStore{videos: []*model.Video{{
+		Code:      "ABC-123",
+		Title:     "Title",
+		Actresses: "A, B",
+		CoverURL:  "https://example.com/cover.jpg",
+		DetailURL: "https://example.com/abc-123",
+	}}}
+	s := NewServer(fs)
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/feed?tag=tag1&limit=3", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if fs.filter.Tag != "tag1" || fs.filter.Limit != 3 {
+		t.Errorf("filter = %+v", fs.filter)
+	}
+
+	var feed JSONFeed
+	if err := json.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("invalid JSON feed: %v", err)
+	}
+	if feed.Version != jsonFeedVersion || len(feed.Items) != 1 {
+		t.Fatalf("feed = %+v", feed)
+	}
+	item := feed.Items[0]
+	if item.ID != "ABC-123" || item.Image == "" || len(item.Authors) != 2 || item.Video.DetailURL == "" {
+		t.Errorf("item = %+v", item)
+	}
+
+	rec = httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/feed?tag=a&actress=b", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("combined filters: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,129 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// jsonFeedVersion identifies the JSON Feed specification version
+const jsonFeedVersion = "https://jsonfeed.org/version/1.1"
+
+// JSONFeed is a JSON Feed 1.1 document
+type JSONFeed struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	FeedURL string         `json:"feed_url"`
+	Items   []JSONFeedItem `json:"items"`
+}
+
+// JSONFeedItem is a single video in a JSON Feed
+type JSONFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url,omitempty"`
+	Title         string           `json:"title"`
+	ContentHTML   string           `json:"content_html"`
+	Image         string           `json:"image,omitempty"`
+	DatePublished string           `json:"date_published"`
+	Tags          []string         `json:"tags,omitempty"`
+	Authors       []JSONFeedAuthor `json:"authors,omitempty"`
+	// Video carries the raw video metadata as a JSON Feed extension
+	Video JSONFeedVideo `json:"_missav"`
+}
+
+// JSONFeedAuthor is a JSON Feed author; actresses are listed as authors
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+// JSONFeedVideo is the "_missav" extension of a JSON Feed item
+type JSONFeedVideo struct {
+	Code        string     `json:"code"`
+	Duration    int        `json:"duration"`
+	ReleaseDate *time.Time `json:"release_date,omitempty"`
+	CoverURL    string     `json:"cover_url,omitempty"`
+	PreviewURL  string     `json:"preview_url,omitempty"`
+	DetailURL   string     `json:"detail_url,omitempty"`
+	Screenshots []string   `json:"screenshots,omitempty"`
+}
+
+// buildJSONFeed renders videos as a JSON Feed
+func buildJSONFeed(title string, feedURL string, videos []*model.Video) *JSONFeed {
+	feed := &JSONFeed{
+		Version: jsonFeedVersion,
+		Title:   title,
+		FeedURL: feedURL,
+		Items:   make([]JSONFeedItem, 0, len(videos)),
+	}
+
+	for _, video := range videos {
+		item := JSONFeedItem{
+			ID:            video.Code,
+			URL:           video.DetailURL,
+			Title:         strings.TrimSpace(video.Code + " " + video.Title),
+			ContentHTML:   videoSummaryHTML(video),
+			Image:         video.CoverURL,
+			DatePublished: video.CreatedAt.UTC().Format(time.RFC3339),
+			Tags:          splitVideoList(video.Tags),
+			Video: JSONFeedVideo{
+				Code:        video.Code,
+				Duration:    video.Duration,
+				ReleaseDate: video.ReleaseDate,
+				CoverURL:    video.CoverURL,
+				PreviewURL:  video.PreviewURL,
+				DetailURL:   video.DetailURL,
+				Screenshots: video.Screenshots,
+			},
+		}
+		for _, name := range splitVideoList(video.Actresses) {
+			item.Authors = append(item.Authors, JSONFeedAuthor{Name: name})
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed
+}
+
+// handleJSONFeed handles the /api/feed endpoint
+// Returns the latest videos as a JSON Feed, filtered by the optional "actress"
+// or "tag" query parameter and limited by "limit", like the RSS feeds
+func (s *Server) handleJSONFeed(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	params := r.URL.Query()
+	actress, tag := params.Get("actress"), params.Get("tag")
+	var kind, name string
+	switch {
+	case actress != "" && tag != "":
+		http.Error(w, "actress and tag cannot be combined", http.StatusBadRequest)
+		return
+	case actress != "":
+		kind, name = "actress", actress
+	case tag != "":
+		kind, name = "tag", tag
+	}
+
+	query, err := parseFeedQuery(r, kind, name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	videos, err := s.store.FindVideos(r.Context(), query.Filter)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get feed videos")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/feed+json; charset=utf-8")
+	if err := json.NewEncoder(w).Encode(buildJSONFeed(query.Title, requestURL(r), videos)); err != nil {
+		log.Error().Err(err).Msg("Failed to encode JSON feed")
+	}
+}
@@ -2,14 +2,20 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/crawler"
+	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/push"
 	"github.com/user/missav-bot-go/internal/store"
 )
 
@@ -49,11 +55,39 @@ type HealthResponse struct {
 	Status   string `json:"status"`
 	Database string `json:"database"`
 	Uptime   string `json:"uptime"`
+	// Browser is reported once a headless browser has been launched
+	Browser *crawler.BrowserHealth `json:"browser,omitempty"`
+	// NextRun is when the next scheduled crawl is due; a past time means it is overdue
+	NextRun *time.Time `json:"nextRun,omitempty"`
+	// SchedulerPaused is set while scheduled crawls are paused by an admin
+	SchedulerPaused bool `json:"schedulerPaused,omitempty"`
+	// Bots are the Telegram bots authenticated at startup, primary first
+	Bots []BotIdentity `json:"bots,omitempty"`
+}
+
+// BotIdentity is a Telegram bot as reported by getMe
+type BotIdentity struct {
+	ID       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+// SchedulerStatus is the state of the crawl scheduler reported by /api/scheduler
+type SchedulerStatus struct {
+	Paused  bool       `json:"paused"`
+	Running bool       `json:"running"`
+	NextRun *time.Time `json:"nextRun,omitempty"`
+	// Started is set by a run request: false when a crawl was already running
+	Started *bool `json:"started,omitempty"`
 }
 
 // Server handles HTTP requests for health checks and metrics
 type Server struct {
 	store     store.Store
+	browser   crawler.BrowserHealthReporter // optional
+	revoker   VideoRevoker                  // optional
+	schedule  SchedulerControl              // optional
+	apiToken  string                        // bearer token of admin endpoints; empty disables them
+	bots      []BotIdentity
 	router    *http.ServeMux
 	server    *http.Server
 	startTime time.Time
@@ -72,6 +106,43 @@ func NewServer(store store.Store) *Server {
 }
 
 
+// SetBrowserHealth makes /health report the state of the crawler's headless browser
+func (s *Server) SetBrowserHealth(reporter crawler.BrowserHealthReporter) {
+	s.browser = reporter
+}
+
+// SetBots makes /health report the identity of the running Telegram bots
+func (s *Server) SetBots(bots []BotIdentity) {
+	s.bots = bots
+}
+
+// SchedulerControl reports on and controls the crawl scheduler
+type SchedulerControl interface {
+	NextRun() time.Time
+	IsRunning() bool
+	IsPaused() bool
+	Pause() bool
+	Resume() bool
+	RunNow(ctx context.Context) bool
+}
+
+// SetScheduler makes /health report the next scheduled crawl and enables
+// /api/scheduler, authorized by the bearer token given to SetRevoker
+func (s *Server) SetScheduler(scheduler SchedulerControl) {
+	s.schedule = scheduler
+}
+
+// VideoRevoker deletes the pushed messages of a video and stops it from being pushed
+type VideoRevoker interface {
+	RevokeVideo(ctx context.Context, code string) (*push.RevokeResult, error)
+}
+
+// SetRevoker enables POST /api/revoke, authorized by the bearer token apiToken
+func (s *Server) SetRevoker(revoker VideoRevoker, apiToken string) {
+	s.revoker = revoker
+	s.apiToken = apiToken
+}
+
 // setupRoutes configures the HTTP routes
 func (s *Server) setupRoutes() {
 	// Health check endpoint (Requirement 8.1)
@@ -79,6 +150,23 @@ func (s *Server) setupRoutes() {
 
 	// Metrics endpoint (Requirement 8.3)
 	s.router.Handle("/metrics", promhttp.Handler())
+
+	// Crawl history endpoint
+	s.router.HandleFunc("/api/crawls", s.handleCrawls)
+
+	// RSS feeds of the latest videos, optionally per actress or tag
+	s.router.HandleFunc("/feed.xml", s.handleFeed)
+	s.router.HandleFunc("/feed/", s.handleFeed)
+
+	// JSON Feed with the same filters as the RSS feeds
+	s.router.HandleFunc("/api/feed", s.handleJSONFeed)
+
+	// Admin endpoint revoking a video's pushed messages
+	s.router.HandleFunc("/api/revoke", s.handleRevoke)
+
+	// Admin endpoints reporting, pausing, resuming and triggering the crawl scheduler
+	s.router.HandleFunc("/api/scheduler", s.handleScheduler)
+	s.router.HandleFunc("/api/scheduler/", s.handleScheduler)
 }
 
 // Start begins listening on the specified port (Requirement 8.1)
@@ -129,6 +217,24 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 		Status:   status,
 		Database: dbStatus,
 		Uptime:   uptime,
+		Bots:     s.bots,
+	}
+
+	// A broken browser degrades crawling but the bot keeps serving
+	if s.browser != nil {
+		if browser := s.browser.BrowserHealth(); browser.Running {
+			response.Browser = &browser
+			if !browser.Healthy && status == "healthy" {
+				response.Status = "degraded"
+			}
+		}
+	}
+
+	if s.schedule != nil {
+		if next := s.schedule.NextRun(); !next.IsZero() {
+			response.NextRun = &next
+		}
+		response.SchedulerPaused = s.schedule.IsPaused()
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -142,11 +248,176 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// handleCrawls handles the /api/crawls endpoint
+// Returns the most recent crawl runs as JSON, limited by the optional "limit" query parameter
+func (s *Server) handleCrawls(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	limit := 20
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 200 {
+			http.Error(w, "limit must be between 1 and 200", http.StatusBadRequest)
+			return
+		}
+		limit = n
+	}
+
+	runs, err := s.store.GetRecentCrawlRuns(r.Context(), limit)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to get crawl runs")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+	if runs == nil {
+		runs = []*model.CrawlRun{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(runs); err != nil {
+		log.Error().Err(err).Msg("Failed to encode crawl runs response")
+	}
+}
+
+// handleRevoke handles the /api/revoke endpoint
+// POST with a "code" parameter deletes the video's pushed messages and hides it.
+// Requires "Authorization: Bearer <SERVER_API_TOKEN>".
+func (s *Server) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if s.revoker == nil || s.apiToken == "" {
+		http.Error(w, "Admin API disabled", http.StatusNotFound)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	code := model.CanonicalCode(r.FormValue("code"))
+	if code == "" {
+		http.Error(w, "code is required", http.StatusBadRequest)
+		return
+	}
+
+	result, err := s.revoker.RevokeVideo(r.Context(), code)
+	if err != nil {
+		log.Error().Err(err).Str("code", code).Msg("Failed to revoke video")
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		log.Error().Err(err).Msg("Failed to encode revoke response")
+	}
+}
+
+// handleScheduler handles the /api/scheduler endpoints
+// GET /api/scheduler reports the scheduler state; POST /api/scheduler/pause, /resume
+// and /run change it and report the new state.
+// Requires "Authorization: Bearer <SERVER_API_TOKEN>".
+func (s *Server) handleScheduler(w http.ResponseWriter, r *http.Request) {
+	if s.schedule == nil || s.apiToken == "" {
+		http.Error(w, "Admin API disabled", http.StatusNotFound)
+		return
+	}
+	if !s.authorized(r) {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var started *bool
+	action := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api/scheduler"), "/")
+	if action == "" {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+	} else {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		switch action {
+		case "pause":
+			s.schedule.Pause()
+		case "resume":
+			s.schedule.Resume()
+		case "run":
+			ok := s.schedule.RunNow(r.Context())
+			started = &ok
+		default:
+			http.NotFound(w, r)
+			return
+		}
+	}
+
+	status := SchedulerStatus{
+		Paused:  s.schedule.IsPaused(),
+		Running: s.schedule.IsRunning(),
+		Started: started,
+	}
+	if next := s.schedule.NextRun(); !next.IsZero() {
+		status.NextRun = &next
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if started != nil && !*started {
+		w.WriteHeader(http.StatusConflict)
+	}
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Error().Err(err).Msg("Failed to encode scheduler response")
+	}
+}
+
+// authorized reports whether a request carries the admin API bearer token
+func (s *Server) authorized(r *http.Request) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(s.apiToken)) == 1
+}
+
 // UpdateVideoCount updates the videos_total metric
 func UpdateVideoCount(count int64) {
 	videosTotal.Set(float64(count))
 }
 
+// videoCountRefreshInterval is how often the video count is reconciled with the database
+const videoCountRefreshInterval = time.Minute
+
+// RefreshVideoCount keeps the videos_total metric current until ctx is done
+// Each refresh recounts the videos table, which also corrects the store's cached count.
+func (s *Server) RefreshVideoCount(ctx context.Context) {
+	s.refreshVideoCount(ctx, videoCountRefreshInterval)
+}
+
+// refreshVideoCount refreshes the video count now and then every interval
+func (s *Server) refreshVideoCount(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := s.store.RefreshVideoCount(ctx); err != nil {
+			if ctx.Err() == nil {
+				log.Error().Err(err).Msg("Failed to refresh video count")
+			}
+		} else {
+			UpdateVideoCount(count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // RecordPush records a push operation metric
 func RecordPush(status string) {
 	pushesTotal.WithLabelValues(status).Inc()
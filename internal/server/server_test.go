@@ -0,0 +1,208 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/user/missav-bot-go/internal/push"
+)
+
+// fakeRevoker records the codes it was asked to revoke
+type fakeRevoker struct {
+	codes []string
+}
+
+func (f *fakeRevoker) RevokeVideo(ctx context.Context, code string) (*push.RevokeResult, error) {
+	f.codes = append(f.codes, code)
+	return &push.RevokeResult{Code: code, Hidden: 1, Deleted: 2}, nil
+}
+
+func TestHandleRevoke(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		method   string
+		auth     string
+		query    string
+		wantCode int
+	}{
+		{"revokes with token", "secret", http.MethodPost, "Bearer secret", "?code=abc-123-uncensored-leak", http.StatusOK},
+		{"disabled without token", "", http.MethodPost, "Bearer ", "?code=ABC-123", http.StatusNotFound},
+		{"wrong token", "secret", http.MethodPost, "Bearer nope", "?code=ABC-123", http.StatusUnauthorized},
+		{"missing auth", "secret", http.MethodPost, "", "?code=ABC-123", http.StatusUnauthorized},
+		{"missing code", "secret", http.MethodPost, "Bearer secret", "", http.StatusBadRequest},
+		{"get not allowed", "secret", http.MethodGet, "Bearer secret", "?code=ABC-123", http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			revoker := &fakeRevoker{}
+			s := NewServer(&feedStore{})
+			s.SetRevoker(revoker, tt.token)
+
+			req := httptest.NewRequest(tt.method, "/api/revoke"+tt.query, nil)
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			s.router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if tt.wantCode != http.StatusOK {
+				if len(revoker.codes) != 0 {
+					t.Errorf("rejected request revoked %v", revoker.codes)
+				}
+				return
+			}
+
+			var result push.RevokeResult
+			if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+			if result.Code != "ABC-123" || result.Deleted != 2 {
+				t.Errorf("response = %+v, want ABC-123 with 2 deleted", result)
+			}
+		})
+	}
+}
+
+// fakeScheduler tracks pause state and whether a run was started
+type fakeScheduler struct {
+	paused  bool
+	running bool
+	runs    int
+}
+
+func (f *fakeScheduler) NextRun() time.Time { return time.Time{} }
+func (f *fakeScheduler) IsRunning() bool    { return f.running }
+func (f *fakeScheduler) IsPaused() bool     { return f.paused }
+
+func (f *fakeScheduler) Pause() bool {
+	was := f.paused
+	f.paused = true
+	return !was
+}
+
+func (f *fakeScheduler) Resume() bool {
+	was := f.paused
+	f.paused = false
+	return was
+}
+
+func (f *fakeScheduler) RunNow(ctx context.Context) bool {
+	if f.running {
+		return false
+	}
+	f.runs++
+	return true
+}
+
+func TestHandleScheduler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		running    bool
+		wantCode   int
+		wantPaused bool
+		wantRuns   int
+	}{
+		{"status", http.MethodGet, "/api/scheduler", false, http.StatusOK, false, 0},
+		{"pause", http.MethodPost, "/api/scheduler/pause", false, http.StatusOK, true, 0},
+		{"run", http.MethodPost, "/api/scheduler/run", false, http.StatusOK, false, 1},
+		{"run while running", http.MethodPost, "/api/scheduler/run", true, http.StatusConflict, false, 0},
+		{"get action not allowed", http.MethodGet, "/api/scheduler/pause", false, http.StatusMethodNotAllowed, false, 0},
+		{"unknown action", http.MethodPost, "/api/scheduler/stop", false, http.StatusNotFound, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheduler := &fakeScheduler{running: tt.running}
+			s := NewServer(&feedStore{})
+			s.SetRevoker(&fakeRevoker{}, "secret")
+			s.SetScheduler(scheduler)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer secret")
+			rec := httptest.NewRecorder()
+			s.router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantCode)
+			}
+			if scheduler.paused != tt.wantPaused || scheduler.runs != tt.wantRuns {
+				t.Errorf("paused = %v, runs = %d, want %v, %d", scheduler.paused, scheduler.runs, tt.wantPaused, tt.wantRuns)
+			}
+		})
+	}
+
+	t.Run("unauthorized", func(t *testing.T) {
+		scheduler := &fakeScheduler{}
+		s := NewServer(&feedStore{})
+		s.SetRevoker(&fakeRevoker{}, "secret")
+		s.SetScheduler(scheduler)
+
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/scheduler/pause", nil))
+		if rec.Code != http.StatusUnauthorized || scheduler.paused {
+			t.Errorf("status = %d, paused = %v, want 401 and not paused", rec.Code, scheduler.paused)
+		}
+	})
+}
+
+// healthStore is a store whose database is always reachable
+type healthStore struct {
+	feedStore
+}
+
+func (s *healthStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+func TestHandleHealth_ReportsBots(t *testing.T) {
+	s := NewServer(&healthStore{})
+	s.SetBots([]BotIdentity{{ID: 123, Username: "MissavBot"}})
+
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+
+	var response HealthResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Bots) != 1 || response.Bots[0] != (BotIdentity{ID: 123, Username: "MissavBot"}) {
+		t.Errorf("bots = %+v, want the configured bot", response.Bots)
+	}
+}
+
+// countStore reports a fixed number of stored videos
+type countStore struct {
+	feedStore
+}
+
+func (s *countStore) RefreshVideoCount(ctx context.Context) (int64, error) {
+	return 42, nil
+}
+
+func TestRefreshVideoCount_UpdatesMetric(t *testing.T) {
+	s := NewServer(&countStore{})
+
+	// A done context still refreshes once before returning
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	s.refreshVideoCount(ctx, time.Hour)
+
+	if got := testutil.ToFloat64(videosTotal); got != 42 {
+		t.Errorf("videos_total = %v, want 42", got)
+	}
+}
@@ -0,0 +1,310 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/logctx"
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// cacheVersionKey holds the generation counter that namespaces all video cache keys.
+// Bumping it on writes invalidates every cached read at once; stale entries expire by TTL.
+const cacheVersionKey = "missav:videos:version"
+
+// Cache defines a minimal key-value cache used by CachedStore
+type Cache interface {
+	// Get returns the cached value and whether it was found
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set stores a value with the given time-to-live
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Incr atomically increments an integer key and returns the new value
+	Incr(ctx context.Context, key string) (int64, error)
+	// Close releases cache resources
+	Close() error
+}
+
+// CachedStore wraps a Store and caches hot video read paths
+// Cache errors are logged and fall through to the underlying store
+type CachedStore struct {
+	Store
+	cache Cache
+	ttl   time.Duration
+}
+
+// NewCachedStore creates a caching decorator around the given store
+func NewCachedStore(store Store, cache Cache, ttl time.Duration) *CachedStore {
+	return &CachedStore{
+		Store: store,
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+// SaveVideo saves a video and invalidates cached video reads
+func (s *CachedStore) SaveVideo(ctx context.Context, video *model.Video) error {
+	if err := s.Store.SaveVideo(ctx, video); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+// SaveVideos saves videos and invalidates cached video reads when anything new was stored
+func (s *CachedStore) SaveVideos(ctx context.Context, videos []*model.Video) (saved int, duplicates int, err error) {
+	saved, duplicates, err = s.Store.SaveVideos(ctx, videos)
+	if err != nil {
+		return saved, duplicates, err
+	}
+	if saved > 0 {
+		s.invalidate(ctx)
+	}
+	return saved, duplicates, nil
+}
+
+// UpdateVideoDetails stores enriched video metadata and invalidates cached video reads
+func (s *CachedStore) UpdateVideoDetails(ctx context.Context, video *model.Video) error {
+	if err := s.Store.UpdateVideoDetails(ctx, video); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+// ResolveDuplicateCandidate closes a duplicate candidate and invalidates cached video reads
+func (s *CachedStore) ResolveDuplicateCandidate(ctx context.Context, id uint, status model.DuplicateStatus) (*model.DuplicateCandidate, error) {
+	candidate, err := s.Store.ResolveDuplicateCandidate(ctx, id, status)
+	if err != nil {
+		return nil, err
+	}
+	if candidate != nil {
+		s.invalidate(ctx)
+	}
+	return candidate, nil
+}
+
+// MarkAsPushed marks a video as pushed and invalidates cached video reads
+func (s *CachedStore) MarkAsPushed(ctx context.Context, videoID uint) error {
+	if err := s.Store.MarkAsPushed(ctx, videoID); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+// MarkAsPushedBulk marks videos as pushed and invalidates cached video reads
+func (s *CachedStore) MarkAsPushedBulk(ctx context.Context, videoIDs []uint) error {
+	if err := s.Store.MarkAsPushedBulk(ctx, videoIDs); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+// HideVideos hides the videos of a code and invalidates cached video reads
+func (s *CachedStore) HideVideos(ctx context.Context, code string) (int64, error) {
+	hidden, err := s.Store.HideVideos(ctx, code)
+	if err != nil {
+		return 0, err
+	}
+	s.invalidate(ctx)
+	return hidden, nil
+}
+
+// DeleteVideos deletes and bans the videos of a code and invalidates cached video reads
+func (s *CachedStore) DeleteVideos(ctx context.Context, code string, bannedBy int64) (int64, error) {
+	deleted, err := s.Store.DeleteVideos(ctx, code, bannedBy)
+	if err != nil {
+		return 0, err
+	}
+	s.invalidate(ctx)
+	return deleted, nil
+}
+
+// EnqueueVideoPushes queues a video's deliveries and invalidates cached video reads
+func (s *CachedStore) EnqueueVideoPushes(ctx context.Context, videoID uint, pushes []*model.PendingPush) error {
+	if err := s.Store.EnqueueVideoPushes(ctx, videoID, pushes); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+// WithTx runs fn in a transaction of the underlying store and invalidates cached video
+// reads once it commits. Reads inside the transaction bypass the cache.
+func (s *CachedStore) WithTx(ctx context.Context, fn func(Store) error) error {
+	if err := s.Store.WithTx(ctx, fn); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+// AddActressAlias adds an actress alias and invalidates cached video reads,
+// since searches by that alias may now match more videos
+func (s *CachedStore) AddActressAlias(ctx context.Context, name string, alias string) error {
+	if err := s.Store.AddActressAlias(ctx, name, alias); err != nil {
+		return err
+	}
+	s.invalidate(ctx)
+	return nil
+}
+
+// GetVideoByCode retrieves a video by its code, served from cache when possible
+func (s *CachedStore) GetVideoByCode(ctx context.Context, code string) (*model.Video, error) {
+	key := s.key(ctx, "code", strings.ToUpper(code))
+
+	var video *model.Video
+	if s.load(ctx, key, &video) {
+		return video, nil
+	}
+
+	video, err := s.Store.GetVideoByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if video != nil {
+		s.save(ctx, key, video)
+	}
+	return video, nil
+}
+
+// SearchVideos searches videos by keyword, served from cache when possible
+func (s *CachedStore) SearchVideos(ctx context.Context, keyword string, limit int) ([]*model.Video, error) {
+	key := s.key(ctx, "search", fmt.Sprintf("%d:%s", limit, strings.ToLower(keyword)))
+
+	var videos []*model.Video
+	if s.load(ctx, key, &videos) {
+		return videos, nil
+	}
+
+	videos, err := s.Store.SearchVideos(ctx, keyword, limit)
+	if err != nil {
+		return nil, err
+	}
+	s.save(ctx, key, videos)
+	return videos, nil
+}
+
+// FindVideos retrieves videos matching a filter, served from cache when possible
+func (s *CachedStore) FindVideos(ctx context.Context, filter *VideoFilter) ([]*model.Video, error) {
+	encoded, err := json.Marshal(filter)
+	if err != nil {
+		return s.Store.FindVideos(ctx, filter)
+	}
+	key := s.key(ctx, "find", strings.ToLower(string(encoded)))
+
+	var videos []*model.Video
+	if s.load(ctx, key, &videos) {
+		return videos, nil
+	}
+
+	videos, err = s.Store.FindVideos(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	s.save(ctx, key, videos)
+	return videos, nil
+}
+
+// GetLatestVideos retrieves the latest videos, served from cache when possible
+func (s *CachedStore) GetLatestVideos(ctx context.Context, limit, offset int) ([]*model.Video, error) {
+	key := s.key(ctx, "latest", fmt.Sprintf("%d:%d", limit, offset))
+
+	var videos []*model.Video
+	if s.load(ctx, key, &videos) {
+		return videos, nil
+	}
+
+	videos, err := s.Store.GetLatestVideos(ctx, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	s.save(ctx, key, videos)
+	return videos, nil
+}
+
+// GetPopularTags retrieves the most common tags, served from cache when possible
+func (s *CachedStore) GetPopularTags(ctx context.Context, limit int) ([]*TagCount, error) {
+	key := s.key(ctx, "tags", fmt.Sprintf("%d", limit))
+
+	var tags []*TagCount
+	if s.load(ctx, key, &tags) {
+		return tags, nil
+	}
+
+	tags, err := s.Store.GetPopularTags(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	s.save(ctx, key, tags)
+	return tags, nil
+}
+
+// TryLock delegates to the underlying store's Locker implementation
+func (s *CachedStore) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	locker, ok := s.Store.(Locker)
+	if !ok {
+		return nil, false, fmt.Errorf("underlying store does not support locking")
+	}
+	return locker.TryLock(ctx, name)
+}
+
+// Close closes the cache and the underlying store
+func (s *CachedStore) Close() error {
+	if err := s.cache.Close(); err != nil {
+		log.Warn().Err(err).Msg("Failed to close cache")
+	}
+	return s.Store.Close()
+}
+
+// key builds a versioned cache key
+func (s *CachedStore) key(ctx context.Context, kind, id string) string {
+	version := "0"
+	if data, found, err := s.cache.Get(ctx, cacheVersionKey); err != nil {
+		logctx.From(ctx).Warn().Err(err).Msg("Failed to read cache version")
+	} else if found {
+		version = string(data)
+	}
+	return fmt.Sprintf("missav:videos:v%s:%s:%s", version, kind, id)
+}
+
+// load reads and decodes a cached value, returning false on miss or error
+func (s *CachedStore) load(ctx context.Context, key string, dest interface{}) bool {
+	data, found, err := s.cache.Get(ctx, key)
+	if err != nil {
+		logctx.From(ctx).Warn().Err(err).Str("key", key).Msg("Cache read failed")
+		return false
+	}
+	if !found {
+		return false
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		logctx.From(ctx).Warn().Err(err).Str("key", key).Msg("Failed to decode cached value")
+		return false
+	}
+	return true
+}
+
+// save encodes and stores a value in the cache
+func (s *CachedStore) save(ctx context.Context, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		logctx.From(ctx).Warn().Err(err).Str("key", key).Msg("Failed to encode cache value")
+		return
+	}
+	if err := s.cache.Set(ctx, key, data, s.ttl); err != nil {
+		logctx.From(ctx).Warn().Err(err).Str("key", key).Msg("Cache write failed")
+	}
+}
+
+// invalidate bumps the cache version so all cached video reads are bypassed
+func (s *CachedStore) invalidate(ctx context.Context) {
+	if _, err := s.cache.Incr(ctx, cacheVersionKey); err != nil {
+		logctx.From(ctx).Warn().Err(err).Msg("Failed to invalidate video cache")
+	}
+}
@@ -0,0 +1,153 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/user/missav-bot-go/internal/model"
+)
+
+// memoryCache is an in-memory Cache for testing (TTL is ignored)
+type memoryCache struct {
+	mu   sync.Mutex
+	data map[string][]byte
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{data: make(map[string][]byte)}
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	v, ok := c.data[key]
+	return v, ok, nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data[key] = value
+	return nil
+}
+
+func (c *memoryCache) Incr(ctx context.Context, key string) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n, _ := strconv.ParseInt(string(c.data[key]), 10, 64)
+	n++
+	c.data[key] = []byte(strconv.FormatInt(n, 10))
+	return n, nil
+}
+
+func (c *memoryCache) Close() error {
+	return nil
+}
+
+// countingStore counts calls to the cached read paths
+type countingStore struct {
+	Store
+	latestCalls int
+	searchCalls int
+	codeCalls   int
+	saveResult  int
+}
+
+func (s *countingStore) GetLatestVideos(ctx context.Context, limit, offset int) ([]*model.Video, error) {
+	s.latestCalls++
+	return []*model.Video{{ID: 1, Code: "ABC-123"}}, nil
+}
+
+func (s *countingStore) SearchVideos(ctx context.Context, keyword string, limit int) ([]*model.Video, error) {
+	s.searchCalls++
+	return []*model.Video{{ID: 1, Code: "ABC-123"}}, nil
+}
+
+func (s *countingStore) GetVideoByCode(ctx context.Context, code string) (*model.Video, error) {
+	s.codeCalls++
+	return &model.Video{ID: 1, Code: code}, nil
+}
+
+func (s *countingStore) SaveVideos(ctx context.Context, videos []*model.Video) (int, int, error) {
+	return s.saveResult, len(videos) - s.saveResult, nil
+}
+
+func (s *countingStore) WithTx(ctx context.Context, fn func(Store) error) error {
+	return fn(s)
+}
+
+func TestCachedStore_ServesRepeatedReadsFromCache(t *testing.T) {
+	ctx := context.Background()
+	backing := &countingStore{}
+	cached := NewCachedStore(backing, newMemoryCache(), time.Minute)
+
+	for i := 0; i < 3; i++ {
+		videos, err := cached.GetLatestVideos(ctx, 5, 0)
+		if err != nil || len(videos) != 1 || videos[0].Code != "ABC-123" {
+			t.Fatalf("GetLatestVideos() = %v, %v", videos, err)
+		}
+		if _, err := cached.SearchVideos(ctx, "abc", 10); err != nil {
+			t.Fatalf("SearchVideos() error = %v", err)
+		}
+		if _, err := cached.GetVideoByCode(ctx, "ABC-123"); err != nil {
+			t.Fatalf("GetVideoByCode() error = %v", err)
+		}
+	}
+
+	if backing.latestCalls != 1 || backing.searchCalls != 1 || backing.codeCalls != 1 {
+		t.Errorf("backing calls = latest %d, search %d, code %d; want 1 each",
+			backing.latestCalls, backing.searchCalls, backing.codeCalls)
+	}
+}
+
+func TestCachedStore_SaveVideosInvalidates(t *testing.T) {
+	ctx := context.Background()
+	backing := &countingStore{}
+	cached := NewCachedStore(backing, newMemoryCache(), time.Minute)
+
+	cached.GetLatestVideos(ctx, 5, 0)
+
+	// Only duplicates: cache stays valid
+	backing.saveResult = 0
+	cached.SaveVideos(ctx, []*model.Video{{Code: "ABC-123"}})
+	cached.GetLatestVideos(ctx, 5, 0)
+	if backing.latestCalls != 1 {
+		t.Errorf("latestCalls after duplicate save = %d, want 1", backing.latestCalls)
+	}
+
+	// New videos: cache is invalidated
+	backing.saveResult = 1
+	cached.SaveVideos(ctx, []*model.Video{{Code: "XYZ-999"}})
+	cached.GetLatestVideos(ctx, 5, 0)
+	if backing.latestCalls != 2 {
+		t.Errorf("latestCalls after new save = %d, want 2", backing.latestCalls)
+	}
+}
+
+func TestCachedStore_WithTxInvalidatesOnCommit(t *testing.T) {
+	ctx := context.Background()
+	backing := &countingStore{}
+	cached := NewCachedStore(backing, newMemoryCache(), time.Minute)
+
+	cached.GetLatestVideos(ctx, 5, 0)
+
+	// Rolled back: cache stays valid
+	cached.WithTx(ctx, func(tx Store) error { return errors.New("rollback") })
+	cached.GetLatestVideos(ctx, 5, 0)
+	if backing.latestCalls != 1 {
+		t.Errorf("latestCalls after rollback = %d, want 1", backing.latestCalls)
+	}
+
+	// Committed: cache is invalidated
+	if err := cached.WithTx(ctx, func(tx Store) error { return nil }); err != nil {
+		t.Fatalf("WithTx: %v", err)
+	}
+	cached.GetLatestVideos(ctx, 5, 0)
+	if backing.latestCalls != 2 {
+		t.Errorf("latestCalls after commit = %d, want 2", backing.latestCalls)
+	}
+}
@@ -0,0 +1,53 @@
+package store
+
+// VideoSort selects the ordering of filtered video queries
+type VideoSort string
+
+const (
+	// SortNewest orders by crawl time, newest first (default)
+	SortNewest VideoSort = "new"
+	// SortOldest orders by crawl time, oldest first
+	SortOldest VideoSort = "old"
+	// SortRelease orders by release date, newest first
+	SortRelease VideoSort = "release"
+	// SortLongest orders by duration, longest first
+	SortLongest VideoSort = "long"
+)
+
+// VideoFilter describes a structured video query
+// Empty fields are ignored; all set fields must match.
+type VideoFilter struct {
+	// Keyword matches code, title, actresses or tags
+	Keyword string `json:"keyword,omitempty"`
+	// Actress matches the actresses column
+	Actress string `json:"actress,omitempty"`
+	// Tag matches the tags column
+	Tag string `json:"tag,omitempty"`
+	// MinDuration and MaxDuration bound the duration in minutes; 0 means unbounded
+	MinDuration int `json:"min_duration,omitempty"`
+	MaxDuration int `json:"max_duration,omitempty"`
+	// Sort selects the result order, SortNewest when empty
+	Sort   VideoSort `json:"sort,omitempty"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset,omitempty"`
+}
+
+// IsEmpty reports whether the filter has no matching criteria
+func (f *VideoFilter) IsEmpty() bool {
+	return f.Keyword == "" && f.Actress == "" && f.Tag == "" &&
+		f.MinDuration == 0 && f.MaxDuration == 0
+}
+
+// orderClause returns the SQL ORDER BY expression for the filter's sort
+func (f *VideoFilter) orderClause() string {
+	switch f.Sort {
+	case SortOldest:
+		return "created_at ASC"
+	case SortRelease:
+		return "release_date DESC, created_at DESC"
+	case SortLongest:
+		return "duration DESC, created_at DESC"
+	default:
+		return "created_at DESC"
+	}
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/user/missav-bot-go/internal/logctx"
+	"gorm.io/gorm"
+)
+
+// Store operations labelled on query metrics
+// Queries without an explicit operation are labelled by their gorm callback kind.
+const (
+	opSave       = "save"
+	opSearch     = "search"
+	opMatch      = "match"
+	opRecordPush = "record_push"
+)
+
+const (
+	// queryOperationKey is the statement setting carrying the store operation name
+	queryOperationKey = "missav:operation"
+	// queryStartKey is the statement instance setting carrying the query start time
+	queryStartKey = "missav:query_start"
+)
+
+var queryDurationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "missav_bot_db_query_duration_seconds",
+	Help:    "Duration of database queries in seconds by store operation",
+	Buckets: prometheus.DefBuckets,
+}, []string{"operation"})
+
+func init() {
+	prometheus.MustRegister(queryDurationSeconds)
+}
+
+// queryMetrics is a gorm plugin that records query durations and logs slow queries
+type queryMetrics struct {
+	histogram     *prometheus.HistogramVec
+	slowThreshold time.Duration
+}
+
+// newQueryMetrics creates the query metrics plugin
+// A zero slowThreshold disables slow query logging.
+func newQueryMetrics(histogram *prometheus.HistogramVec, slowThreshold time.Duration) *queryMetrics {
+	return &queryMetrics{
+		histogram:     histogram,
+		slowThreshold: slowThreshold,
+	}
+}
+
+// Name implements gorm.Plugin
+func (p *queryMetrics) Name() string {
+	return "missav:query_metrics"
+}
+
+// Initialize implements gorm.Plugin by wrapping every callback chain with timing hooks
+func (p *queryMetrics) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	chains := []struct {
+		kind   string
+		before func(name string, fn func(*gorm.DB)) error
+		after  func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("*").Register, cb.Create().After("*").Register},
+		{"query", cb.Query().Before("*").Register, cb.Query().After("*").Register},
+		{"update", cb.Update().Before("*").Register, cb.Update().After("*").Register},
+		{"delete", cb.Delete().Before("*").Register, cb.Delete().After("*").Register},
+		{"row", cb.Row().Before("*").Register, cb.Row().After("*").Register},
+		{"raw", cb.Raw().Before("*").Register, cb.Raw().After("*").Register},
+	}
+
+	for _, chain := range chains {
+		if err := chain.before("missav:metrics_before_"+chain.kind, p.before); err != nil {
+			return err
+		}
+		if err := chain.after("missav:metrics_after_"+chain.kind, p.after(chain.kind)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before records the query start time
+func (p *queryMetrics) before(db *gorm.DB) {
+	db.InstanceSet(queryStartKey, time.Now())
+}
+
+// after observes the query duration and logs it when slower than the threshold
+func (p *queryMetrics) after(kind string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(queryStartKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(start)
+
+		operation := kind
+		if op, ok := db.Get(queryOperationKey); ok {
+			if name, ok := op.(string); ok && name != "" {
+				operation = name
+			}
+		}
+
+		p.histogram.WithLabelValues(operation).Observe(elapsed.Seconds())
+
+		if p.slowThreshold > 0 && elapsed >= p.slowThreshold {
+			logctx.From(db.Statement.Context).Warn().
+				Str("operation", operation).
+				Dur("duration", elapsed).
+				Int64("rows", db.RowsAffected).
+				Str("sql", db.Statement.SQL.String()).
+				Msg("Slow database query")
+		}
+	}
+}
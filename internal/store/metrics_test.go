@@ -0,0 +1,60 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/user/missav-bot-go/internal/model"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openDryRunDB opens a gorm DB that builds SQL without connecting to MySQL
+func openDryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "user:pass@tcp(127.0.0.1:1)/db",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("failed to open dry-run db: %v", err)
+	}
+	return db
+}
+
+func TestQueryMetrics_LabelsByOperation(t *testing.T) {
+	db := openDryRunDB(t)
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "test_query_duration_seconds",
+	}, []string{"operation"})
+	if err := db.Use(newQueryMetrics(histogram, 0)); err != nil {
+		t.Fatalf("Use() error = %v", err)
+	}
+
+	var videos []*model.Video
+	db.Set(queryOperationKey, opSearch).Where("code LIKE ?", "%ABC%").Find(&videos)
+	db.Set(queryOperationKey, opSearch).Where("title LIKE ?", "%ABC%").Find(&videos)
+	db.Create(&model.Video{Code: "ABC-123"})
+
+	if got := testutil.CollectAndCount(histogram); got != 2 {
+		t.Fatalf("series = %d, want 2 (search, create)", got)
+	}
+
+	expected := map[string]uint64{opSearch: 2, "create": 1}
+	for op, want := range expected {
+		metric := &dto.Metric{}
+		if err := histogram.WithLabelValues(op).(prometheus.Histogram).Write(metric); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		if got := metric.GetHistogram().GetSampleCount(); got != want {
+			t.Errorf("operation %q samples = %d, want %d", op, got, want)
+		}
+	}
+}
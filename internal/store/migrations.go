@@ -0,0 +1,814 @@
+package store
+
+import (
+	"fmt"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/user/missav-bot-go/internal/config"
+	"github.com/user/missav-bot-go/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// migrationsTable is the table recording applied migration IDs
+const migrationsTable = "schema_migrations"
+
+// migrations returns the ordered list of schema migrations
+// New migrations must be appended; applied IDs must never change.
+// Each step is idempotent so databases created by the old AutoMigrate startup adopt cleanly.
+func migrations() []*gormigrate.Migration {
+	return []*gormigrate.Migration{
+		{
+			ID: "202601010001_initial_schema",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Video{}, &model.Subscription{}, &model.PushRecord{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.PushRecord{}, &model.Subscription{}, &model.Video{})
+			},
+		},
+		{
+			ID: "202601020001_crawl_runs",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.CrawlRun{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.CrawlRun{})
+			},
+		},
+		{
+			ID: "202601030001_pending_pushes",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.PendingPush{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.PendingPush{})
+			},
+		},
+		{
+			ID: "202601040001_push_record_code",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasColumn(&model.PushRecord{}, "Code") {
+					if err := tx.Migrator().AddColumn(&model.PushRecord{}, "Code"); err != nil {
+						return err
+					}
+				}
+				if !tx.Migrator().HasIndex(&model.PushRecord{}, "Code") {
+					if err := tx.Migrator().CreateIndex(&model.PushRecord{}, "Code"); err != nil {
+						return err
+					}
+				}
+				return backfillPushRecordCodes(tx)
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&model.PushRecord{}, "Code")
+			},
+		},
+		{
+			ID: "202601050001_actress_aliases",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&model.ActressAlias{}); err != nil {
+					return err
+				}
+				return backfillActressAliases(tx)
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.ActressAlias{})
+			},
+		},
+		{
+			ID: "202601060001_command_logs",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.CommandLog{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.CommandLog{})
+			},
+		},
+		{
+			ID: "202601070001_chat_settings",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.ChatSettings{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.ChatSettings{})
+			},
+		},
+		{
+			ID: "202601080001_notifier_targets",
+			Migrate: func(tx *gorm.DB) error {
+				for _, table := range []interface{}{&model.Subscription{}, &model.PendingPush{}} {
+					for _, field := range []string{"Platform", "Target"} {
+						if tx.Migrator().HasColumn(table, field) {
+							continue
+						}
+						if err := tx.Migrator().AddColumn(table, field); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, table := range []interface{}{&model.Subscription{}, &model.PendingPush{}} {
+					for _, field := range []string{"Platform", "Target"} {
+						if err := tx.Migrator().DropColumn(table, field); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202601090001_crawl_run_stats",
+			Migrate: func(tx *gorm.DB) error {
+				for _, field := range crawlRunStatFields {
+					if tx.Migrator().HasColumn(&model.CrawlRun{}, field) {
+						continue
+					}
+					if err := tx.Migrator().AddColumn(&model.CrawlRun{}, field); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, field := range crawlRunStatFields {
+					if err := tx.Migrator().DropColumn(&model.CrawlRun{}, field); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202601100001_video_screenshots",
+			Migrate: func(tx *gorm.DB) error {
+				if tx.Migrator().HasColumn(&model.Video{}, "Screenshots") {
+					return nil
+				}
+				return tx.Migrator().AddColumn(&model.Video{}, "Screenshots")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&model.Video{}, "Screenshots")
+			},
+		},
+		{
+			ID: "202601110001_video_source",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasColumn(&model.Video{}, "Source") {
+					if err := tx.Migrator().AddColumn(&model.Video{}, "Source"); err != nil {
+						return err
+					}
+				}
+				// Codes become unique per source instead of globally
+				if tx.Migrator().HasIndex(&model.Video{}, legacyVideoCodeIndex) {
+					if err := tx.Migrator().DropIndex(&model.Video{}, legacyVideoCodeIndex); err != nil {
+						return err
+					}
+				}
+				if !tx.Migrator().HasIndex(&model.Video{}, videoSourceCodeIndex) {
+					return tx.Migrator().CreateIndex(&model.Video{}, videoSourceCodeIndex)
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropIndex(&model.Video{}, videoSourceCodeIndex); err != nil {
+					return err
+				}
+				if err := tx.Exec("CREATE UNIQUE INDEX " + legacyVideoCodeIndex + " ON videos (code)").Error; err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&model.Video{}, "Source")
+			},
+		},
+		{
+			ID: "202601120001_video_provenance",
+			Migrate: func(tx *gorm.DB) error {
+				if tx.Migrator().HasColumn(&model.Video{}, "Provenance") {
+					return nil
+				}
+				return tx.Migrator().AddColumn(&model.Video{}, "Provenance")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&model.Video{}, "Provenance")
+			},
+		},
+		{
+			ID: "202601130001_video_completeness",
+			Migrate: func(tx *gorm.DB) error {
+				for _, field := range []string{"Completeness", "EnrichedAt"} {
+					if tx.Migrator().HasColumn(&model.Video{}, field) {
+						continue
+					}
+					if err := tx.Migrator().AddColumn(&model.Video{}, field); err != nil {
+						return err
+					}
+				}
+				if !tx.Migrator().HasIndex(&model.Video{}, "Completeness") {
+					if err := tx.Migrator().CreateIndex(&model.Video{}, "Completeness"); err != nil {
+						return err
+					}
+				}
+				return backfillCompleteness(tx)
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, field := range []string{"Completeness", "EnrichedAt"} {
+					if err := tx.Migrator().DropColumn(&model.Video{}, field); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202601140001_duplicate_candidates",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasColumn(&model.Video{}, "DuplicateOf") {
+					if err := tx.Migrator().AddColumn(&model.Video{}, "DuplicateOf"); err != nil {
+						return err
+					}
+				}
+				if !tx.Migrator().HasIndex(&model.Video{}, "DuplicateOf") {
+					if err := tx.Migrator().CreateIndex(&model.Video{}, "DuplicateOf"); err != nil {
+						return err
+					}
+				}
+				return tx.AutoMigrate(&model.DuplicateCandidate{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&model.DuplicateCandidate{}); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&model.Video{}, "DuplicateOf")
+			},
+		},
+		{
+			ID: "202601150001_chat_mutes",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.ChatMute{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.ChatMute{})
+			},
+		},
+		{
+			ID: "202601160001_chat_blacklists",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.BlacklistEntry{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.BlacklistEntry{})
+			},
+		},
+		{
+			ID: "202601170001_bot_ids",
+			Migrate: func(tx *gorm.DB) error {
+				for _, table := range []interface{}{&model.Subscription{}, &model.PendingPush{}} {
+					if tx.Migrator().HasColumn(table, "BotID") {
+						continue
+					}
+					if err := tx.Migrator().AddColumn(table, "BotID"); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, table := range []interface{}{&model.Subscription{}, &model.PendingPush{}} {
+					if err := tx.Migrator().DropColumn(table, "BotID"); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202601180001_push_record_status_index",
+			Migrate: func(tx *gorm.DB) error {
+				if tx.Migrator().HasIndex(&model.PushRecord{}, pushRecordStatusIndex) {
+					return nil
+				}
+				return tx.Migrator().CreateIndex(&model.PushRecord{}, pushRecordStatusIndex)
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropIndex(&model.PushRecord{}, pushRecordStatusIndex)
+			},
+		},
+		{
+			ID: "202601190001_chat_push_mode",
+			Migrate: func(tx *gorm.DB) error {
+				if tx.Migrator().HasColumn(&model.ChatSettings{}, "PushMode") {
+					return nil
+				}
+				return tx.Migrator().AddColumn(&model.ChatSettings{}, "PushMode")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&model.ChatSettings{}, "PushMode")
+			},
+		},
+		{
+			ID: "202601200001_push_record_messages",
+			Migrate: func(tx *gorm.DB) error {
+				for _, field := range pushRecordMessageFields {
+					if tx.Migrator().HasColumn(&model.PushRecord{}, field) {
+						continue
+					}
+					if err := tx.Migrator().AddColumn(&model.PushRecord{}, field); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, field := range pushRecordMessageFields {
+					if err := tx.Migrator().DropColumn(&model.PushRecord{}, field); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202601210001_revoked_videos",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasColumn(&model.Video{}, "Hidden") {
+					if err := tx.Migrator().AddColumn(&model.Video{}, "Hidden"); err != nil {
+						return err
+					}
+				}
+				if !tx.Migrator().HasColumn(&model.PushRecord{}, "MessageCount") {
+					if err := tx.Migrator().AddColumn(&model.PushRecord{}, "MessageCount"); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropColumn(&model.PushRecord{}, "MessageCount"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&model.Video{}, "Hidden")
+			},
+		},
+		{
+			ID: "202601220001_chat_parse_mode",
+			Migrate: func(tx *gorm.DB) error {
+				if tx.Migrator().HasColumn(&model.ChatSettings{}, "ParseMode") {
+					return nil
+				}
+				return tx.Migrator().AddColumn(&model.ChatSettings{}, "ParseMode")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&model.ChatSettings{}, "ParseMode")
+			},
+		},
+		{
+			ID: "202601230001_pending_push_priority",
+			Migrate: func(tx *gorm.DB) error {
+				if tx.Migrator().HasColumn(&model.PendingPush{}, "Priority") {
+					return nil
+				}
+				return tx.Migrator().AddColumn(&model.PendingPush{}, "Priority")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&model.PendingPush{}, "Priority")
+			},
+		},
+		{
+			ID: "202601240001_users",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.User{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.User{})
+			},
+		},
+		{
+			ID: "202601250001_personal_subscriptions",
+			Migrate: func(tx *gorm.DB) error {
+				for _, field := range personalSubscriptionFields {
+					if tx.Migrator().HasColumn(&model.Subscription{}, field) {
+						continue
+					}
+					if err := tx.Migrator().AddColumn(&model.Subscription{}, field); err != nil {
+						return err
+					}
+				}
+				if !tx.Migrator().HasIndex(&model.Subscription{}, "UserID") {
+					if err := tx.Migrator().CreateIndex(&model.Subscription{}, "UserID"); err != nil {
+						return err
+					}
+				}
+				if tx.Migrator().HasColumn(&model.PendingPush{}, "Mentions") {
+					return nil
+				}
+				return tx.Migrator().AddColumn(&model.PendingPush{}, "Mentions")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropColumn(&model.PendingPush{}, "Mentions"); err != nil {
+					return err
+				}
+				for _, field := range personalSubscriptionFields {
+					if err := tx.Migrator().DropColumn(&model.Subscription{}, field); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202601260001_chat_dm_results",
+			Migrate: func(tx *gorm.DB) error {
+				if tx.Migrator().HasColumn(&model.ChatSettings{}, "DMResults") {
+					return nil
+				}
+				return tx.Migrator().AddColumn(&model.ChatSettings{}, "DMResults")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&model.ChatSettings{}, "DMResults")
+			},
+		},
+		{
+			ID: "202601270001_chat_auto_delete",
+			Migrate: func(tx *gorm.DB) error {
+				if tx.Migrator().HasColumn(&model.ChatSettings{}, "AutoDeleteSeconds") {
+					return nil
+				}
+				return tx.Migrator().AddColumn(&model.ChatSettings{}, "AutoDeleteSeconds")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&model.ChatSettings{}, "AutoDeleteSeconds")
+			},
+		},
+		{
+			ID: "202601280001_bot_states",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.BotState{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.BotState{})
+			},
+		},
+		{
+			ID: "202601290001_push_record_lookup_indexes",
+			Migrate: func(tx *gorm.DB) error {
+				for _, name := range []string{pushRecordLookupIndex, pushRecordCreatedAtIndex} {
+					if tx.Migrator().HasIndex(&model.PushRecord{}, name) {
+						continue
+					}
+					if err := tx.Migrator().CreateIndex(&model.PushRecord{}, name); err != nil {
+						return err
+					}
+				}
+				if tx.Migrator().HasIndex(&model.PushRecord{}, legacyPushRecordVideoIndex) {
+					return tx.Migrator().DropIndex(&model.PushRecord{}, legacyPushRecordVideoIndex)
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Exec("CREATE INDEX " + legacyPushRecordVideoIndex + " ON push_records (video_id)").Error; err != nil {
+					return err
+				}
+				for _, name := range []string{pushRecordLookupIndex, pushRecordCreatedAtIndex} {
+					if err := tx.Migrator().DropIndex(&model.PushRecord{}, name); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202601300001_chat_page_sizes",
+			Migrate: func(tx *gorm.DB) error {
+				for _, column := range []string{"SearchLimit", "LatestPageSize"} {
+					if tx.Migrator().HasColumn(&model.ChatSettings{}, column) {
+						continue
+					}
+					if err := tx.Migrator().AddColumn(&model.ChatSettings{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"SearchLimit", "LatestPageSize"} {
+					if err := tx.Migrator().DropColumn(&model.ChatSettings{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202601310001_video_deletion",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasColumn(&model.Video{}, "DeletedAt") {
+					if err := tx.Migrator().AddColumn(&model.Video{}, "DeletedAt"); err != nil {
+						return err
+					}
+				}
+				if !tx.Migrator().HasIndex(&model.Video{}, "DeletedAt") {
+					if err := tx.Migrator().CreateIndex(&model.Video{}, "DeletedAt"); err != nil {
+						return err
+					}
+				}
+				return tx.AutoMigrate(&model.BannedCode{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&model.BannedCode{}); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&model.Video{}, "DeletedAt")
+			},
+		},
+		{
+			ID: "202602010001_code_watches",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.CodeWatch{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.CodeWatch{})
+			},
+		},
+		{
+			ID: "202602020001_video_formats",
+			Migrate: func(tx *gorm.DB) error {
+				for _, column := range []string{"VR", "Clip"} {
+					if !tx.Migrator().HasColumn(&model.Video{}, column) {
+						if err := tx.Migrator().AddColumn(&model.Video{}, column); err != nil {
+							return err
+						}
+					}
+					if !tx.Migrator().HasIndex(&model.Video{}, column) {
+						if err := tx.Migrator().CreateIndex(&model.Video{}, column); err != nil {
+							return err
+						}
+					}
+				}
+				// Flag the clips and VR releases already stored
+				if err := tx.Model(&model.Video{}).
+					Where("duration > 0 AND duration < ?", model.ClipMaxDuration).
+					Update("clip", true).Error; err != nil {
+					return err
+				}
+				if err := backfillVR(tx); err != nil {
+					return err
+				}
+				for _, column := range []string{"SkipVR", "MinDuration"} {
+					if tx.Migrator().HasColumn(&model.ChatSettings{}, column) {
+						continue
+					}
+					if err := tx.Migrator().AddColumn(&model.ChatSettings{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"SkipVR", "MinDuration"} {
+					if err := tx.Migrator().DropColumn(&model.ChatSettings{}, column); err != nil {
+						return err
+					}
+				}
+				for _, column := range []string{"VR", "Clip"} {
+					if err := tx.Migrator().DropColumn(&model.Video{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			ID: "202602030001_chat_weekly_recap",
+			Migrate: func(tx *gorm.DB) error {
+				for _, column := range []string{"WeeklyRecap", "RecapSentAt"} {
+					if tx.Migrator().HasColumn(&model.ChatSettings{}, column) {
+						continue
+					}
+					if err := tx.Migrator().AddColumn(&model.ChatSettings{}, column); err != nil {
+						return err
+					}
+				}
+				if tx.Migrator().HasIndex(&model.ChatSettings{}, "WeeklyRecap") {
+					return nil
+				}
+				return tx.Migrator().CreateIndex(&model.ChatSettings{}, "WeeklyRecap")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				for _, column := range []string{"WeeklyRecap", "RecapSentAt"} {
+					if err := tx.Migrator().DropColumn(&model.ChatSettings{}, column); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	}
+}
+
+// Unique indexes of the videos table before and after codes were scoped by source
+const (
+	legacyVideoCodeIndex = "idx_videos_code"
+	videoSourceCodeIndex = "idx_videos_source_code"
+)
+
+// pushRecordStatusIndex serves the daily cap summary, which scans push records by status and time
+const pushRecordStatusIndex = "idx_push_records_status_pushed_at"
+
+// Indexes of the push history lookups made for every delivery during fan-out
+// The composite lookup index starts with video_id and replaces its single-column index.
+const (
+	legacyPushRecordVideoIndex = "idx_push_records_video_id"
+	pushRecordLookupIndex      = "idx_push_records_video_chat_status"
+	pushRecordCreatedAtIndex   = "idx_push_records_created_at"
+)
+
+// pushRecordMessageFields locate the Telegram message of a push for later edits
+var pushRecordMessageFields = []string{"MessageType", "BotID"}
+
+// personalSubscriptionFields are the subscription columns of personal subscriptions in groups
+var personalSubscriptionFields = []string{"UserID", "Personal"}
+
+// crawlRunStatFields are the crawler statistics columns of crawl runs
+var crawlRunStatFields = []string{"PagesFetched", "HTTPFetches", "BrowserFetches", "ParseFailures"}
+
+// backfillPushRecordCodes fills the canonical code of push records created before it was stored
+func backfillPushRecordCodes(tx *gorm.DB) error {
+	var videos []*model.Video
+	result := tx.Select("id", "code").
+		Where("id IN (?)", tx.Model(&model.PushRecord{}).
+			Select("video_id").
+			Where("code IS NULL OR code = ''")).
+		Find(&videos)
+	if result.Error != nil {
+		return fmt.Errorf("failed to load videos for backfill: %w", result.Error)
+	}
+
+	for _, video := range videos {
+		result := tx.Model(&model.PushRecord{}).
+			Where("video_id = ? AND (code IS NULL OR code = '')", video.ID).
+			Update("code", model.CanonicalCode(video.Code))
+		if result.Error != nil {
+			return fmt.Errorf("failed to backfill push record codes: %w", result.Error)
+		}
+	}
+
+	log.Info().Int("videos", len(videos)).Msg("Backfilled canonical codes on push records")
+	return nil
+}
+
+// completenessExpr computes model.Video.CompletenessScore in SQL
+const completenessExpr = "(COALESCE(title, '') <> '') + (COALESCE(actresses, '') <> '') + (COALESCE(tags, '') <> '') + " +
+	"(COALESCE(cover_url, '') <> '') + (COALESCE(preview_url, '') <> '') + (release_date IS NOT NULL)"
+
+// backfillCompleteness scores the videos stored before completeness was tracked
+func backfillCompleteness(tx *gorm.DB) error {
+	result := tx.Model(&model.Video{}).
+		Where("1 = 1").
+		Update("completeness", gorm.Expr(completenessExpr))
+	if result.Error != nil {
+		return fmt.Errorf("failed to backfill completeness: %w", result.Error)
+	}
+
+	log.Info().Int64("videos", result.RowsAffected).Msg("Backfilled video completeness")
+	return nil
+}
+
+// backfillVR flags the stored VR releases, which DetectFormat recognizes by code, title and tags
+func backfillVR(tx *gorm.DB) error {
+	var batch []*model.Video
+	var ids []uint
+	result := tx.Unscoped().
+		Select("id", "code", "title", "tags").
+		FindInBatches(&batch, 1000, func(*gorm.DB, int) error {
+			for _, video := range batch {
+				video.DetectFormat()
+				if video.VR {
+					ids = append(ids, video.ID)
+				}
+			}
+			return nil
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to load videos for VR backfill: %w", result.Error)
+	}
+
+	for start := 0; start < len(ids); start += 1000 {
+		chunk := ids[start:min(start+1000, len(ids))]
+		if err := tx.Unscoped().Model(&model.Video{}).Where("id IN ?", chunk).Update("vr", true).Error; err != nil {
+			return fmt.Errorf("failed to backfill VR flags: %w", err)
+		}
+	}
+
+	log.Info().Int("videos", len(ids)).Msg("Backfilled VR flags")
+	return nil
+}
+
+// backfillActressAliases generates transliterated aliases for actresses already in the catalog
+func backfillActressAliases(tx *gorm.DB) error {
+	var values []string
+	result := tx.Model(&model.Video{}).
+		Distinct().
+		Where("actresses <> ''").
+		Pluck("actresses", &values)
+	if result.Error != nil {
+		return fmt.Errorf("failed to load actresses for backfill: %w", result.Error)
+	}
+
+	aliases := generateAliases(splitCatalogNames(values))
+	if len(aliases) > 0 {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(aliases, 100)
+		if result.Error != nil {
+			return fmt.Errorf("failed to backfill actress aliases: %w", result.Error)
+		}
+	}
+
+	log.Info().Int("aliases", len(aliases)).Msg("Backfilled actress aliases")
+	return nil
+}
+
+// Migrator runs versioned schema migrations
+type Migrator struct {
+	db *gorm.DB
+	m  *gormigrate.Gormigrate
+}
+
+// NewMigrator connects to the database and prepares the migration runner
+func NewMigrator(cfg *config.DBConfig) (*Migrator, error) {
+	db, err := openMySQL(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(db), nil
+}
+
+// newMigrator creates a migration runner on an existing connection
+func newMigrator(db *gorm.DB) *Migrator {
+	opts := &gormigrate.Options{
+		TableName:    migrationsTable,
+		IDColumnName: "id",
+		IDColumnSize: 255,
+		// MySQL DDL statements commit implicitly, so transactions would not help
+		UseTransaction: false,
+	}
+	return &Migrator{
+		db: db,
+		m:  gormigrate.New(db, opts, migrations()),
+	}
+}
+
+// Up applies all pending migrations
+func (m *Migrator) Up() error {
+	if err := m.m.Migrate(); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return nil
+}
+
+// To applies pending migrations up to and including the given ID
+func (m *Migrator) To(id string) error {
+	if err := m.m.MigrateTo(id); err != nil {
+		return fmt.Errorf("failed to migrate to %s: %w", id, err)
+	}
+	return nil
+}
+
+// Down rolls back the most recently applied migration
+func (m *Migrator) Down() error {
+	if err := m.m.RollbackLast(); err != nil {
+		return fmt.Errorf("failed to roll back last migration: %w", err)
+	}
+	return nil
+}
+
+// RollbackTo rolls back migrations applied after the given ID
+func (m *Migrator) RollbackTo(id string) error {
+	if err := m.m.RollbackTo(id); err != nil {
+		return fmt.Errorf("failed to roll back to %s: %w", id, err)
+	}
+	return nil
+}
+
+// Applied returns the IDs of applied migrations in order
+func (m *Migrator) Applied() ([]string, error) {
+	var ids []string
+	if !m.db.Migrator().HasTable(migrationsTable) {
+		return ids, nil
+	}
+	if err := m.db.Table(migrationsTable).Order("id ASC").Pluck("id", &ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to list applied migrations: %w", err)
+	}
+	return ids, nil
+}
+
+// Close closes the migrator's database connection
+func (m *Migrator) Close() error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying db: %w", err)
+	}
+	return sqlDB.Close()
+}
@@ -0,0 +1,24 @@
+package store
+
+import "testing"
+
+func TestMigrations_IDsUniqueAndOrdered(t *testing.T) {
+	seen := make(map[string]bool)
+	prev := ""
+	for _, m := range migrations() {
+		if m.ID == "" {
+			t.Fatal("migration has empty ID")
+		}
+		if seen[m.ID] {
+			t.Errorf("duplicate migration ID %q", m.ID)
+		}
+		seen[m.ID] = true
+		if m.ID <= prev {
+			t.Errorf("migration %q is not ordered after %q", m.ID, prev)
+		}
+		prev = m.ID
+		if m.Migrate == nil || m.Rollback == nil {
+			t.Errorf("migration %q must define Migrate and Rollback", m.ID)
+		}
+	}
+}
@@ -2,34 +2,68 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/rs/zerolog/log"
 	"github.com/user/missav-bot-go/internal/config"
+	"github.com/user/missav-bot-go/internal/logctx"
 	"github.com/user/missav-bot-go/internal/model"
+	"github.com/user/missav-bot-go/internal/translit"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
 // MySQLStore implements Store interface using MySQL database
+// When a read replica is configured, plain reads are served by it; reads that must
+// observe this instance's own writes are pinned to the primary with dbresolver.Write.
 type MySQLStore struct {
-	db *gorm.DB
+	db      *gorm.DB
+	replica *sql.DB
+	videos  *videoCounter
 }
 
-// NewMySQLStore creates a new MySQL store instance
+// NewMySQLStore creates a new MySQL store instance and applies pending migrations
 func NewMySQLStore(cfg *config.DBConfig) (*MySQLStore, error) {
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Database)
+	db, err := openMySQL(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := newMigrator(db).Up(); err != nil {
+		return nil, err
+	}
+
+	if err := db.Use(newQueryMetrics(queryDurationSeconds, cfg.SlowQueryThreshold)); err != nil {
+		return nil, fmt.Errorf("failed to register query metrics: %w", err)
+	}
 
+	replica, err := useReadReplica(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MySQLStore{
+		db:      db,
+		replica: replica,
+		videos:  &videoCounter{},
+	}, nil
+}
+
+// openMySQL opens a pooled MySQL connection
+func openMySQL(cfg *config.DBConfig) (*gorm.DB, error) {
 	gormConfig := &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
 	}
 
-	db, err := gorm.Open(mysql.Open(dsn), gormConfig)
+	db, err := gorm.Open(mysql.Open(cfg.DSN()), gormConfig)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
@@ -44,12 +78,35 @@ func NewMySQLStore(cfg *config.DBConfig) (*MySQLStore, error) {
 	sqlDB.SetMaxIdleConns(cfg.MaxConns / 2)
 	sqlDB.SetConnMaxLifetime(time.Hour)
 
-	// Auto migrate tables
-	if err := db.AutoMigrate(&model.Video{}, &model.Subscription{}, &model.PushRecord{}); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	return db, nil
+}
+
+// useReadReplica routes reads to the replica at DB_READ_HOST, if configured
+// Returns the replica pool so it can be closed with the store, or nil without a replica
+func useReadReplica(db *gorm.DB, cfg *config.DBConfig) (*sql.DB, error) {
+	dsn := cfg.ReadDSN()
+	if dsn == "" {
+		return nil, nil
 	}
 
-	return &MySQLStore{db: db}, nil
+	replica, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open read replica: %w", err)
+	}
+	replica.SetMaxOpenConns(cfg.MaxConns)
+	replica.SetMaxIdleConns(cfg.MaxConns / 2)
+	replica.SetConnMaxLifetime(time.Hour)
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: []gorm.Dialector{mysql.New(mysql.Config{Conn: replica})},
+	})
+	if err := db.Use(resolver); err != nil {
+		replica.Close()
+		return nil, fmt.Errorf("failed to configure read replica: %w", err)
+	}
+
+	log.Info().Str("host", cfg.ReadHost).Msg("Read replica enabled")
+	return replica, nil
 }
 
 
@@ -58,21 +115,40 @@ func NewMySQLStore(cfg *config.DBConfig) (*MySQLStore, error) {
 func (s *MySQLStore) SaveVideo(ctx context.Context, video *model.Video) error {
 	// Ensure new videos have pushed=false
 	video.Pushed = false
+	if video.Source == "" {
+		video.Source = model.SourceMissAV
+	}
+	video.Completeness = video.CompletenessScore()
+	video.DetectFormat()
+
+	allowed, err := s.withoutBanned(ctx, []*model.Video{video})
+	if err != nil {
+		return err
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
 	
-	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "code"}},
+	result := s.db.WithContext(ctx).Set(queryOperationKey, opSave).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source"}, {Name: "code"}},
 		DoNothing: true,
 	}).Create(video)
 	
 	if result.Error != nil {
 		return fmt.Errorf("failed to save video: %w", result.Error)
 	}
+
+	if result.RowsAffected > 0 {
+		s.videos.add(result.RowsAffected)
+		s.saveGeneratedAliases(ctx, []*model.Video{video})
+	}
 	
 	return nil
 }
 
 // SaveVideos saves multiple videos in batch
 // Returns count of saved videos, duplicates, and any error
+// Videos of banned codes are skipped and counted as duplicates.
 func (s *MySQLStore) SaveVideos(ctx context.Context, videos []*model.Video) (saved int, duplicates int, err error) {
 	if len(videos) == 0 {
 		return 0, 0, nil
@@ -81,12 +157,25 @@ func (s *MySQLStore) SaveVideos(ctx context.Context, videos []*model.Video) (sav
 	// Ensure all new videos have pushed=false
 	for _, v := range videos {
 		v.Pushed = false
+		if v.Source == "" {
+			v.Source = model.SourceMissAV
+		}
+		v.Completeness = v.CompletenessScore()
+		v.DetectFormat()
 	}
 
-	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "code"}},
+	allowed, err := s.withoutBanned(ctx, videos)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(allowed) == 0 {
+		return 0, len(videos), nil
+	}
+
+	result := s.db.WithContext(ctx).Set(queryOperationKey, opSave).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "source"}, {Name: "code"}},
 		DoNothing: true,
-	}).CreateInBatches(videos, 100)
+	}).CreateInBatches(allowed, 100)
 
 	if result.Error != nil {
 		return 0, 0, fmt.Errorf("failed to save videos: %w", result.Error)
@@ -94,9 +183,45 @@ func (s *MySQLStore) SaveVideos(ctx context.Context, videos []*model.Video) (sav
 
 	saved = int(result.RowsAffected)
 	duplicates = len(videos) - saved
+	if saved > 0 {
+		s.videos.add(int64(saved))
+		s.saveGeneratedAliases(ctx, allowed)
+	}
 	return saved, duplicates, nil
 }
 
+// withoutBanned returns the videos whose canonical code is not banned
+func (s *MySQLStore) withoutBanned(ctx context.Context, videos []*model.Video) ([]*model.Video, error) {
+	codes := make([]string, 0, len(videos))
+	for _, v := range videos {
+		codes = append(codes, model.CanonicalCode(v.Code))
+	}
+
+	var banned []string
+	result := s.db.WithContext(ctx).
+		Model(&model.BannedCode{}).
+		Where("code IN ?", codes).
+		Pluck("code", &banned)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to check banned codes: %w", result.Error)
+	}
+	if len(banned) == 0 {
+		return videos, nil
+	}
+
+	isBanned := make(map[string]bool, len(banned))
+	for _, code := range banned {
+		isBanned[code] = true
+	}
+	allowed := make([]*model.Video, 0, len(videos))
+	for _, v := range videos {
+		if !isBanned[model.CanonicalCode(v.Code)] {
+			allowed = append(allowed, v)
+		}
+	}
+	return allowed, nil
+}
+
 // GetVideoByCode retrieves a video by its code
 func (s *MySQLStore) GetVideoByCode(ctx context.Context, code string) (*model.Video, error) {
 	var video model.Video
@@ -114,8 +239,7 @@ func (s *MySQLStore) GetVideoByCode(ctx context.Context, code string) (*model.Vi
 // Ordered by created_at DESC
 func (s *MySQLStore) GetUnpushedVideos(ctx context.Context) ([]*model.Video, error) {
 	var videos []*model.Video
-	result := s.db.WithContext(ctx).
-		Where("pushed = ?", false).
+	result := s.unpushedVideos(ctx).
 		Order("created_at DESC").
 		Find(&videos)
 	if result.Error != nil {
@@ -124,6 +248,27 @@ func (s *MySQLStore) GetUnpushedVideos(ctx context.Context) ([]*model.Video, err
 	return videos, nil
 }
 
+// unpushedVideos selects the videos waiting to be pushed
+func (s *MySQLStore) unpushedVideos(ctx context.Context) *gorm.DB {
+	return s.db.WithContext(ctx).
+		Clauses(dbresolver.Write).
+		Model(&model.Video{}).
+		Where("pushed = ? AND hidden = ?", false, false).
+		// Suspected re-listings wait for an admin to review them
+		Where("id NOT IN (?)", s.db.Model(&model.DuplicateCandidate{}).
+			Select("duplicate_id").
+			Where("status = ?", model.DuplicatePending))
+}
+
+// CountUnpushedVideos counts the videos waiting to be pushed
+func (s *MySQLStore) CountUnpushedVideos(ctx context.Context) (int64, error) {
+	var count int64
+	if err := s.unpushedVideos(ctx).Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count unpushed videos: %w", err)
+	}
+	return count, nil
+}
+
 // MarkAsPushed marks a video as pushed atomically
 func (s *MySQLStore) MarkAsPushed(ctx context.Context, videoID uint) error {
 	result := s.db.WithContext(ctx).
@@ -136,11 +281,65 @@ func (s *MySQLStore) MarkAsPushed(ctx context.Context, videoID uint) error {
 	return nil
 }
 
+// MarkAsPushedBulk marks several videos as pushed in a single statement
+func (s *MySQLStore) MarkAsPushedBulk(ctx context.Context, videoIDs []uint) error {
+	if len(videoIDs) == 0 {
+		return nil
+	}
+	result := s.db.WithContext(ctx).
+		Model(&model.Video{}).
+		Where("id IN ?", videoIDs).
+		Update("pushed", true)
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark videos as pushed: %w", result.Error)
+	}
+	return nil
+}
+
+// HideVideos marks every video of a canonical code hidden, including variants
+// such as ABC-123-UNCENSORED-LEAK, and returns how many were hidden
+func (s *MySQLStore) HideVideos(ctx context.Context, code string) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Model(&model.Video{}).
+		Where("code = ? OR code LIKE ?", code, escapeLike(code)+"-%").
+		Update("hidden", true)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to hide videos: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// DeleteVideos bans a canonical code and soft-deletes its videos, including variants
+// such as ABC-123-UNCENSORED-LEAK, returning how many were deleted
+// The ban keeps later crawls from saving the release again; its push records are kept.
+func (s *MySQLStore) DeleteVideos(ctx context.Context, code string, bannedBy int64) (int64, error) {
+	var deleted int64
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ban := &model.BannedCode{Code: code, BannedBy: bannedBy}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(ban).Error; err != nil {
+			return fmt.Errorf("failed to ban code: %w", err)
+		}
+
+		result := tx.Where("code = ? OR code LIKE ?", code, escapeLike(code)+"-%").Delete(&model.Video{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete videos: %w", result.Error)
+		}
+		deleted = result.RowsAffected
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	s.videos.add(-deleted)
+	return deleted, nil
+}
+
 // SearchVideos searches videos by keyword in code, title, actresses, or tags
 func (s *MySQLStore) SearchVideos(ctx context.Context, keyword string, limit int) ([]*model.Video, error) {
 	var videos []*model.Video
 	searchPattern := "%" + keyword + "%"
 	result := s.db.WithContext(ctx).
+		Set(queryOperationKey, opSearch).
 		Where("code LIKE ? OR title LIKE ? OR actresses LIKE ? OR tags LIKE ?",
 			searchPattern, searchPattern, searchPattern, searchPattern).
 		Order("created_at DESC").
@@ -152,10 +351,136 @@ func (s *MySQLStore) SearchVideos(ctx context.Context, keyword string, limit int
 	return videos, nil
 }
 
+// FindVideos retrieves videos matching a structured filter
+func (s *MySQLStore) FindVideos(ctx context.Context, filter *VideoFilter) ([]*model.Video, error) {
+	var videos []*model.Video
+	result := s.filterQuery(ctx, filter).
+		Order(filter.orderClause()).
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&videos)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to find videos: %w", result.Error)
+	}
+	return videos, nil
+}
+
+// CountVideosMatching counts the videos matching a structured filter, ignoring its limit and offset
+func (s *MySQLStore) CountVideosMatching(ctx context.Context, filter *VideoFilter) (int64, error) {
+	var count int64
+	result := s.filterQuery(ctx, filter).Model(&model.Video{}).Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count videos: %w", result.Error)
+	}
+	return count, nil
+}
+
+// filterQuery builds the conditions of a structured video filter
+func (s *MySQLStore) filterQuery(ctx context.Context, filter *VideoFilter) *gorm.DB {
+	// Revoked videos are left out of every listing
+	query := s.db.WithContext(ctx).Set(queryOperationKey, opSearch).Where("hidden = ?", false)
+
+	if filter.Keyword != "" {
+		// Keywords may also be a romaji or pinyin alias of an actress
+		pattern := "%" + filter.Keyword + "%"
+		conditions := []string{"code LIKE ?", "title LIKE ?", "actresses LIKE ?", "tags LIKE ?"}
+		args := []interface{}{pattern, pattern, pattern, pattern}
+		for _, name := range s.aliasedActressNames(ctx, filter.Keyword) {
+			conditions = append(conditions, "actresses LIKE ?")
+			args = append(args, "%"+name+"%")
+		}
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+	if filter.Actress != "" {
+		conditions := []string{"actresses LIKE ?"}
+		args := []interface{}{"%" + filter.Actress + "%"}
+		for _, name := range s.aliasedActressNames(ctx, filter.Actress) {
+			conditions = append(conditions, "actresses LIKE ?")
+			args = append(args, "%"+name+"%")
+		}
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+	if filter.Tag != "" {
+		query = query.Where("tags LIKE ?", "%"+filter.Tag+"%")
+	}
+	if filter.MinDuration > 0 {
+		query = query.Where("duration >= ?", filter.MinDuration)
+	}
+	if filter.MaxDuration > 0 {
+		query = query.Where("duration <= ?", filter.MaxDuration)
+	}
+	return query
+}
+
+// Weights of the shared actresses and tags that order related video candidates;
+// /similar ranks the candidates with the same weights
+const (
+	RelatedActressWeight = 2
+	RelatedTagWeight     = 1
+)
+
+// GetRelatedVideos retrieves up to limit videos sharing an actress or tag with a video,
+// as candidates for /similar
+// Candidates are ordered by a score summing RelatedActressWeight per shared actress and
+// RelatedTagWeight per shared tag, then newest first, so a common tag cannot crowd out
+// older videos of the same actress. Actresses and tags are matched as substrings of the
+// comma-separated columns, so callers compare the split values again. Revoked videos,
+// merged duplicates and other records of the same release are left out.
+func (s *MySQLStore) GetRelatedVideos(ctx context.Context, video *model.Video, limit int) ([]*model.Video, error) {
+	var conditions, scores []string
+	var args, scoreArgs []interface{}
+	for _, column := range []struct {
+		name   string
+		values string
+		weight int
+	}{{"actresses", video.Actresses, RelatedActressWeight}, {"tags", video.Tags, RelatedTagWeight}} {
+		for _, value := range strings.Split(column.values, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				pattern := "%" + escapeLike(value) + "%"
+				conditions = append(conditions, column.name+" LIKE ?")
+				args = append(args, pattern)
+				scores = append(scores, fmt.Sprintf("(CASE WHEN %s LIKE ? THEN %d ELSE 0 END)", column.name, column.weight))
+				scoreArgs = append(scoreArgs, pattern)
+			}
+		}
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	code := model.CanonicalCode(video.Code)
+	var videos []*model.Video
+	result := s.db.WithContext(ctx).
+		Set(queryOperationKey, opSearch).
+		Where("hidden = ? AND duplicate_of IS NULL AND id <> ?", false, video.ID).
+		Where("code <> ? AND code NOT LIKE ?", code, escapeLike(code)+"-%").
+		Where("("+strings.Join(conditions, " OR ")+")", args...).
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                strings.Join(scores, " + ") + " DESC, created_at DESC",
+			Vars:               scoreArgs,
+			WithoutParentheses: true,
+		}}).
+		Limit(limit).
+		Find(&videos)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get related videos: %w", result.Error)
+	}
+	return videos, nil
+}
+
+// likeEscaper escapes the LIKE wildcards, and the backslash escaping them, in a value
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike escapes a value so LIKE matches it literally
+func escapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
+
 // GetLatestVideos retrieves the latest videos with pagination
 func (s *MySQLStore) GetLatestVideos(ctx context.Context, limit, offset int) ([]*model.Video, error) {
 	var videos []*model.Video
 	result := s.db.WithContext(ctx).
+		Where("hidden = ?", false).
 		Order("created_at DESC").
 		Limit(limit).
 		Offset(offset).
@@ -166,13 +491,92 @@ func (s *MySQLStore) GetLatestVideos(ctx context.Context, limit, offset int) ([]
 	return videos, nil
 }
 
+// enrichedColumns are the columns written when a video's detail page fills missing fields
+var enrichedColumns = []string{
+	"title", "actresses", "tags", "duration", "release_date", "cover_url", "preview_url",
+	"screenshots", "completeness", "vr", "clip", "enriched_at",
+}
+
+// UpdateVideoDetails stores the enriched metadata of a video, rescores it and
+// flags its format again
+func (s *MySQLStore) UpdateVideoDetails(ctx context.Context, video *model.Video) error {
+	video.Completeness = video.CompletenessScore()
+	video.DetectFormat()
+	result := s.db.WithContext(ctx).Model(video).Select(enrichedColumns).Updates(video)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update video details: %w", result.Error)
+	}
+	return nil
+}
+
+// GetIncompleteVideos returns videos created since the given time that are missing
+// metadata and have not been enriched yet, lowest score first
+func (s *MySQLStore) GetIncompleteVideos(ctx context.Context, since time.Time, limit int) ([]*model.Video, error) {
+	var videos []*model.Video
+	result := s.db.WithContext(ctx).
+		Where("completeness < ? AND enriched_at IS NULL AND detail_url <> '' AND created_at >= ?", model.MaxCompleteness, since).
+		Order("completeness ASC, created_at DESC").
+		Limit(limit).
+		Find(&videos)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get incomplete videos: %w", result.Error)
+	}
+	return videos, nil
+}
+
+// CountVideosByCompleteness returns the number of videos per completeness score
+func (s *MySQLStore) CountVideosByCompleteness(ctx context.Context) (map[int]int64, error) {
+	var rows []struct {
+		Completeness int
+		Count        int64
+	}
+	result := s.db.WithContext(ctx).
+		Model(&model.Video{}).
+		Select("completeness, COUNT(*) AS count").
+		Group("completeness").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count videos by completeness: %w", result.Error)
+	}
+
+	counts := make(map[int]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Completeness] = row.Count
+	}
+	return counts, nil
+}
+
+// GetVideosSince retrieves the videos created since the given time, oldest first
+func (s *MySQLStore) GetVideosSince(ctx context.Context, since time.Time) ([]*model.Video, error) {
+	var videos []*model.Video
+	result := s.db.WithContext(ctx).
+		Where("created_at >= ?", since).
+		Order("created_at ASC, id ASC").
+		Find(&videos)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get videos since %s: %w", since, result.Error)
+	}
+	return videos, nil
+}
+
 // CountVideos returns the total count of videos
+// The count is loaded once and then kept up to date by saves and RefreshVideoCount.
 func (s *MySQLStore) CountVideos(ctx context.Context) (int64, error) {
+	if count, ok := s.videos.get(); ok {
+		return count, nil
+	}
+	return s.RefreshVideoCount(ctx)
+}
+
+// RefreshVideoCount counts the videos in the table and replaces the cached count
+// It corrects drift from rolled back saves and from videos saved by other instances.
+func (s *MySQLStore) RefreshVideoCount(ctx context.Context) (int64, error) {
 	var count int64
-	result := s.db.WithContext(ctx).Model(&model.Video{}).Count(&count)
+	result := s.db.WithContext(ctx).Clauses(dbresolver.Write).Model(&model.Video{}).Count(&count)
 	if result.Error != nil {
 		return 0, fmt.Errorf("failed to count videos: %w", result.Error)
 	}
+	s.videos.set(count)
 	return count, nil
 }
 
@@ -187,19 +591,206 @@ func (s *MySQLStore) ExistsByCode(ctx context.Context, code string) (bool, error
 }
 
 
+// GetActressNames returns the distinct actress names in the catalog, sorted
+func (s *MySQLStore) GetActressNames(ctx context.Context) ([]string, error) {
+	return s.catalogNames(ctx, "actresses")
+}
+
+// GetTagNames returns the distinct tag names in the catalog, sorted
+func (s *MySQLStore) GetTagNames(ctx context.Context) ([]string, error) {
+	return s.catalogNames(ctx, "tags")
+}
+
+// catalogNames collects the distinct comma-separated names stored in a video column
+func (s *MySQLStore) catalogNames(ctx context.Context, column string) ([]string, error) {
+	var values []string
+	result := s.db.WithContext(ctx).
+		Model(&model.Video{}).
+		Distinct().
+		Where(column+" <> ''").
+		Pluck(column, &values)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get %s catalog: %w", column, result.Error)
+	}
+	return splitCatalogNames(values), nil
+}
+
+// splitCatalogNames splits comma-separated column values into sorted, unique names
+func splitCatalogNames(values []string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetPopularTags returns the most common tags among stored videos with their video counts
+func (s *MySQLStore) GetPopularTags(ctx context.Context, limit int) ([]*TagCount, error) {
+	var values []string
+	result := s.db.WithContext(ctx).
+		Model(&model.Video{}).
+		Where("tags <> ''").
+		Pluck("tags", &values)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get popular tags: %w", result.Error)
+	}
+	return countCatalogNames(values, limit), nil
+}
+
+// countCatalogNames counts the names in comma-separated column values
+// Returns at most limit names by descending count, ties broken by name.
+func countCatalogNames(values []string, limit int) []*TagCount {
+	counts := make(map[string]int64)
+	for _, value := range values {
+		seen := make(map[string]bool)
+		for _, name := range strings.Split(value, ",") {
+			name = strings.TrimSpace(name)
+			if name == "" || seen[name] {
+				continue
+			}
+			seen[name] = true
+			counts[name]++
+		}
+	}
+
+	tags := make([]*TagCount, 0, len(counts))
+	for name, count := range counts {
+		tags = append(tags, &TagCount{Tag: name, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	if limit > 0 && len(tags) > limit {
+		tags = tags[:limit]
+	}
+	return tags
+}
+
+// AddActressAlias stores a manual alias for an actress name
+func (s *MySQLStore) AddActressAlias(ctx context.Context, name string, alias string) error {
+	normalized := translit.Normalize(alias)
+	if normalized == "" {
+		return fmt.Errorf("alias has no searchable characters")
+	}
+
+	record := &model.ActressAlias{
+		Name:       name,
+		Alias:      alias,
+		Normalized: normalized,
+		Kind:       translit.KindManual,
+	}
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(record)
+	if result.Error != nil {
+		return fmt.Errorf("failed to add actress alias: %w", result.Error)
+	}
+	return nil
+}
+
+// GetActressAliases retrieves all aliases of an actress name
+func (s *MySQLStore) GetActressAliases(ctx context.Context, name string) ([]*model.ActressAlias, error) {
+	var aliases []*model.ActressAlias
+	result := s.db.WithContext(ctx).
+		Where("name = ?", name).
+		Order("kind ASC, id ASC").
+		Find(&aliases)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get actress aliases: %w", result.Error)
+	}
+	return aliases, nil
+}
+
+// aliasedActressNames returns the actress names that query is an alias of
+// Lookup failures are logged and treated as no aliases so search still works
+func (s *MySQLStore) aliasedActressNames(ctx context.Context, query string) []string {
+	normalized := translit.Normalize(query)
+	if normalized == "" {
+		return nil
+	}
+
+	var names []string
+	result := s.db.WithContext(ctx).
+		Model(&model.ActressAlias{}).
+		Distinct().
+		Where("normalized = ?", normalized).
+		Pluck("name", &names)
+	if result.Error != nil {
+		logctx.From(ctx).Warn().Err(result.Error).Str("query", query).Msg("Failed to resolve actress aliases")
+		return nil
+	}
+	return names
+}
+
+// saveGeneratedAliases stores transliterated aliases for the actresses of saved videos
+// Failures are logged; aliases only improve search and must not fail a save
+func (s *MySQLStore) saveGeneratedAliases(ctx context.Context, videos []*model.Video) {
+	var values []string
+	for _, video := range videos {
+		values = append(values, video.Actresses)
+	}
+
+	aliases := generateAliases(splitCatalogNames(values))
+	if len(aliases) == 0 {
+		return
+	}
+
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).CreateInBatches(aliases, 100)
+	if result.Error != nil {
+		logctx.From(ctx).Warn().Err(result.Error).Msg("Failed to save generated actress aliases")
+	}
+}
+
+// generateAliases builds romaji and pinyin alias rows for actress names
+func generateAliases(names []string) []*model.ActressAlias {
+	var aliases []*model.ActressAlias
+	for _, name := range names {
+		for _, form := range translit.Forms(name) {
+			normalized := translit.Normalize(form.Value)
+			if normalized == "" || normalized == translit.Normalize(name) {
+				continue
+			}
+			aliases = append(aliases, &model.ActressAlias{
+				Name:       name,
+				Alias:      form.Value,
+				Normalized: normalized,
+				Kind:       form.Kind,
+			})
+		}
+	}
+	return aliases
+}
+
 // CreateSubscription creates a new subscription
 func (s *MySQLStore) CreateSubscription(ctx context.Context, sub *model.Subscription) error {
 	// Check if subscription already exists
 	var existing model.Subscription
 	result := s.db.WithContext(ctx).
-		Where("chat_id = ? AND type = ? AND keyword = ?", sub.ChatID, sub.Type, sub.Keyword).
+		Where("chat_id = ? AND user_id = ? AND type = ? AND keyword = ?", sub.ChatID, sub.UserID, sub.Type, sub.Keyword).
 		First(&existing)
 	
 	if result.Error == nil {
-		// Subscription already exists, update enabled status
-		return s.db.WithContext(ctx).
+		// Subscription already exists, re-enable it and serve it from the bot it came through
+		if err := s.db.WithContext(ctx).
 			Model(&existing).
-			Update("enabled", true).Error
+			Updates(map[string]interface{}{"enabled": true, "bot_id": sub.BotID, "personal": sub.Personal}).Error; err != nil {
+			return err
+		}
+		return nil
 	}
 	
 	if !errors.Is(result.Error, gorm.ErrRecordNotFound) {
@@ -214,9 +805,10 @@ func (s *MySQLStore) CreateSubscription(ctx context.Context, sub *model.Subscrip
 }
 
 // DeleteSubscription deletes a specific subscription
-func (s *MySQLStore) DeleteSubscription(ctx context.Context, chatID int64, subType string, keyword string) error {
+// userID selects a user's personal subscription; 0 deletes the chat-wide one.
+func (s *MySQLStore) DeleteSubscription(ctx context.Context, chatID int64, userID int64, subType string, keyword string) error {
 	result := s.db.WithContext(ctx).
-		Where("chat_id = ? AND type = ? AND keyword = ?", chatID, subType, keyword).
+		Where("chat_id = ? AND user_id = ? AND type = ? AND keyword = ?", chatID, userID, subType, keyword).
 		Delete(&model.Subscription{})
 	if result.Error != nil {
 		return fmt.Errorf("failed to delete subscription: %w", result.Error)
@@ -224,21 +816,22 @@ func (s *MySQLStore) DeleteSubscription(ctx context.Context, chatID int64, subTy
 	return nil
 }
 
-// DeleteAllSubscriptions deletes all subscriptions for a chat
-func (s *MySQLStore) DeleteAllSubscriptions(ctx context.Context, chatID int64) error {
+// DeleteAllSubscriptions deletes all subscriptions for a chat and returns how many were removed
+func (s *MySQLStore) DeleteAllSubscriptions(ctx context.Context, chatID int64) (int64, error) {
 	result := s.db.WithContext(ctx).
 		Where("chat_id = ?", chatID).
 		Delete(&model.Subscription{})
 	if result.Error != nil {
-		return fmt.Errorf("failed to delete all subscriptions: %w", result.Error)
+		return 0, fmt.Errorf("failed to delete all subscriptions: %w", result.Error)
 	}
-	return nil
+	return result.RowsAffected, nil
 }
 
 // GetSubscriptions retrieves all subscriptions for a chat
 func (s *MySQLStore) GetSubscriptions(ctx context.Context, chatID int64) ([]*model.Subscription, error) {
 	var subs []*model.Subscription
 	result := s.db.WithContext(ctx).
+		Clauses(dbresolver.Write).
 		Where("chat_id = ? AND enabled = ?", chatID, true).
 		Find(&subs)
 	if result.Error != nil {
@@ -259,20 +852,39 @@ func (s *MySQLStore) GetAllSubscriptions(ctx context.Context) ([]*model.Subscrip
 	return subs, nil
 }
 
+// matchSubscriptionsCondition selects the ALL subscriptions and the ACTRESS and TAG
+// subscriptions whose keyword occurs in the video's actresses or tags
+const matchSubscriptionsCondition = "enabled = ? AND (type = ? OR " +
+	"(type = ? AND LOCATE(LOWER(keyword), LOWER(?)) > 0) OR " +
+	"(type = ? AND LOCATE(LOWER(keyword), LOWER(?)) > 0))"
+
 // GetMatchingSubscriptions finds subscriptions that match a video
+// Matching runs in MySQL so only the matching subscriptions are loaded. MySQL collations
+// also fold accents and character widths, so keyword matches are confirmed in Go with
+// the same case-insensitive check push applies.
 func (s *MySQLStore) GetMatchingSubscriptions(ctx context.Context, video *model.Video) ([]*model.Subscription, error) {
-	allSubs, err := s.GetAllSubscriptions(ctx)
-	if err != nil {
-		return nil, err
+	var subs []*model.Subscription
+	result := s.db.WithContext(ctx).
+		Set(queryOperationKey, opMatch).
+		Where(matchSubscriptionsCondition, true, model.SubTypeAll,
+			model.SubTypeActress, video.Actresses,
+			model.SubTypeTag, video.Tags).
+		Find(&subs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get matching subscriptions: %w", result.Error)
 	}
+	return filterMatching(subs, video), nil
+}
 
+// filterMatching returns the subscriptions in subs that match the video
+func filterMatching(subs []*model.Subscription, video *model.Video) []*model.Subscription {
 	var matching []*model.Subscription
-	for _, sub := range allSubs {
+	for _, sub := range subs {
 		if matchesSubscription(video, sub) {
 			matching = append(matching, sub)
 		}
 	}
-	return matching, nil
+	return matching
 }
 
 // matchesSubscription checks if a video matches a subscription
@@ -298,36 +910,828 @@ func containsIgnoreCase(s, substr string) bool {
 // RecordPush records a push operation
 func (s *MySQLStore) RecordPush(ctx context.Context, record *model.PushRecord) error {
 	record.PushedAt = time.Now()
-	if err := s.db.WithContext(ctx).Create(record).Error; err != nil {
+	if err := s.db.WithContext(ctx).Set(queryOperationKey, opRecordPush).Create(record).Error; err != nil {
 		return fmt.Errorf("failed to record push: %w", err)
 	}
 	return nil
 }
 
 // HasPushed checks if a video has been successfully pushed to a chat
+// The lookup is answered from the (video_id, chat_id, status) index and stops at the first row.
 func (s *MySQLStore) HasPushed(ctx context.Context, videoID uint, chatID int64) (bool, error) {
-	var count int64
+	var found int
 	result := s.db.WithContext(ctx).
+		Clauses(dbresolver.Write).
 		Model(&model.PushRecord{}).
+		Select("1").
 		Where("video_id = ? AND chat_id = ? AND status = ?", videoID, chatID, model.PushStatusSuccess).
-		Count(&count)
+		Limit(1).
+		Find(&found)
 	if result.Error != nil {
 		return false, fmt.Errorf("failed to check push status: %w", result.Error)
 	}
-	return count > 0, nil
+	return result.RowsAffected > 0, nil
 }
 
-// Ping checks database connectivity
-func (s *MySQLStore) Ping(ctx context.Context) error {
-	sqlDB, err := s.db.DB()
-	if err != nil {
-		return fmt.Errorf("failed to get underlying db: %w", err)
+// HasPushedCode checks if any video with the given canonical code has been
+// successfully pushed to a chat
+func (s *MySQLStore) HasPushedCode(ctx context.Context, code string, chatID int64) (bool, error) {
+	var count int64
+	result := s.db.WithContext(ctx).
+		Clauses(dbresolver.Write).
+		Model(&model.PushRecord{}).
+		Where("code = ? AND chat_id = ? AND status = ?", code, chatID, model.PushStatusSuccess).
+		Count(&count)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to check push status by code: %w", result.Error)
 	}
-	return sqlDB.PingContext(ctx)
+	return count > 0, nil
 }
 
-// Close closes the database connection
-func (s *MySQLStore) Close() error {
+// FilterUnpushedChats returns the chats, in their given order, that have not been
+// successfully pushed the video or another video with its canonical code.
+// It answers HasPushed and HasPushedCode for many chats in one query.
+func (s *MySQLStore) FilterUnpushedChats(ctx context.Context, videoID uint, code string, chatIDs []int64) ([]int64, error) {
+	if len(chatIDs) == 0 {
+		return nil, nil
+	}
+
+	var pushedIDs []int64
+	result := s.db.WithContext(ctx).
+		Clauses(dbresolver.Write).
+		Model(&model.PushRecord{}).
+		Distinct("chat_id").
+		Where("chat_id IN ? AND status = ? AND (video_id = ? OR code = ?)",
+			chatIDs, model.PushStatusSuccess, videoID, code).
+		Pluck("chat_id", &pushedIDs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to filter pushed chats: %w", result.Error)
+	}
+
+	pushed := make(map[int64]bool, len(pushedIDs))
+	for _, id := range pushedIDs {
+		pushed[id] = true
+	}
+	unpushed := make([]int64, 0, len(chatIDs))
+	for _, id := range chatIDs {
+		if !pushed[id] {
+			unpushed = append(unpushed, id)
+		}
+	}
+	return unpushed, nil
+}
+
+// CountPushesSince counts the videos successfully pushed to a chat since a time
+func (s *MySQLStore) CountPushesSince(ctx context.Context, chatID int64, since time.Time) (int64, error) {
+	var count int64
+	result := s.db.WithContext(ctx).
+		Clauses(dbresolver.Write).
+		Model(&model.PushRecord{}).
+		Where("chat_id = ? AND status = ? AND pushed_at >= ?", chatID, model.PushStatusSuccess, since).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count pushes: %w", result.Error)
+	}
+	return count, nil
+}
+
+// GetEditablePushes returns the successful pushes of a video delivered as a single
+// Telegram message whose ID is known, so the message can be edited
+func (s *MySQLStore) GetEditablePushes(ctx context.Context, videoID uint) ([]*model.PushRecord, error) {
+	var records []*model.PushRecord
+	result := s.db.WithContext(ctx).
+		Where("video_id = ? AND status = ? AND message_id > 0 AND message_type IN ?",
+			videoID, model.PushStatusSuccess, []model.MessageType{model.MessageTypeText, model.MessageTypeMedia}).
+		Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get editable pushes: %w", result.Error)
+	}
+	return records, nil
+}
+
+// GetPushedMessages returns the successful pushes of a canonical code whose Telegram
+// messages are known and show only that video, so they can be deleted
+func (s *MySQLStore) GetPushedMessages(ctx context.Context, code string) ([]*model.PushRecord, error) {
+	var records []*model.PushRecord
+	result := s.db.WithContext(ctx).
+		Where("code = ? AND status = ? AND message_id > 0 AND message_type IN ?",
+			code, model.PushStatusSuccess, []model.MessageType{model.MessageTypeText, model.MessageTypeMedia}).
+		Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get pushed messages: %w", result.Error)
+	}
+	return records, nil
+}
+
+// ClearPushMessage forgets the Telegram message of a push once it was deleted
+// The record stays successful so the video is still deduplicated for the chat.
+func (s *MySQLStore) ClearPushMessage(ctx context.Context, recordID uint) error {
+	result := s.db.WithContext(ctx).
+		Model(&model.PushRecord{}).
+		Where("id = ?", recordID).
+		Updates(map[string]interface{}{"message_id": 0, "message_count": 0})
+	if result.Error != nil {
+		return fmt.Errorf("failed to clear push message: %w", result.Error)
+	}
+	return nil
+}
+
+// ReportCappedPushes counts the matches held back by daily caps before a time, per chat,
+// and marks them reported so each is summarized only once
+func (s *MySQLStore) ReportCappedPushes(ctx context.Context, before time.Time) (map[int64]int64, error) {
+	counts := make(map[int64]int64)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []struct {
+			ChatID int64
+			Count  int64
+		}
+		if err := tx.Model(&model.PushRecord{}).
+			Select("chat_id, COUNT(*) AS count").
+			Where("status = ? AND pushed_at < ?", model.PushStatusCapped, before).
+			Group("chat_id").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := tx.Model(&model.PushRecord{}).
+			Where("status = ? AND pushed_at < ?", model.PushStatusCapped, before).
+			Update("status", model.PushStatusCappedReported).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			counts[row.ChatID] = row.Count
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to report capped pushes: %w", err)
+	}
+	return counts, nil
+}
+
+// GetPushHistory retrieves the videos most recently pushed to a chat, newest first
+// Videos deleted since are left out.
+func (s *MySQLStore) GetPushHistory(ctx context.Context, chatID int64, limit int) ([]*PushHistoryEntry, error) {
+	var records []*model.PushRecord
+	result := s.db.WithContext(ctx).
+		Where("chat_id = ? AND status = ?", chatID, model.PushStatusSuccess).
+		Order("pushed_at DESC, id DESC").
+		Limit(limit).
+		Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get push history: %w", result.Error)
+	}
+	return s.loadPushHistory(ctx, records)
+}
+
+// GetPushHistorySince retrieves up to limit videos pushed to a chat since a time, newest first
+// Videos deleted since are left out.
+func (s *MySQLStore) GetPushHistorySince(ctx context.Context, chatID int64, since time.Time, limit int) ([]*PushHistoryEntry, error) {
+	var records []*model.PushRecord
+	result := s.db.WithContext(ctx).
+		Where("chat_id = ? AND status = ? AND pushed_at >= ?", chatID, model.PushStatusSuccess, since).
+		Order("pushed_at DESC, id DESC").
+		Limit(limit).
+		Find(&records)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get push history: %w", result.Error)
+	}
+	return s.loadPushHistory(ctx, records)
+}
+
+// loadPushHistory loads the videos of push records, keeping the records' order
+func (s *MySQLStore) loadPushHistory(ctx context.Context, records []*model.PushRecord) ([]*PushHistoryEntry, error) {
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]uint, len(records))
+	for i, record := range records {
+		ids[i] = record.VideoID
+	}
+	var videos []*model.Video
+	if err := s.db.WithContext(ctx).Where("id IN ?", ids).Find(&videos).Error; err != nil {
+		return nil, fmt.Errorf("failed to load pushed videos: %w", err)
+	}
+	byID := make(map[uint]*model.Video, len(videos))
+	for _, video := range videos {
+		byID[video.ID] = video
+	}
+
+	history := make([]*PushHistoryEntry, 0, len(records))
+	for _, record := range records {
+		if video := byID[record.VideoID]; video != nil {
+			history = append(history, &PushHistoryEntry{Video: video, PushedAt: record.PushedAt})
+		}
+	}
+	return history, nil
+}
+
+// CountCappedPushesSince counts the matches a chat's daily cap held back since a time,
+// whether or not they were summarized yet
+func (s *MySQLStore) CountCappedPushesSince(ctx context.Context, chatID int64, since time.Time) (int64, error) {
+	var count int64
+	result := s.db.WithContext(ctx).
+		Model(&model.PushRecord{}).
+		Where("chat_id = ? AND status IN ? AND pushed_at >= ?", chatID,
+			[]model.PushStatus{model.PushStatusCapped, model.PushStatusCappedReported}, since).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count capped pushes: %w", result.Error)
+	}
+	return count, nil
+}
+
+// EnqueueVideoPushes adds a video's deliveries to the push outbox and marks the video
+// as pushed in a single transaction, so a crash cannot leave it matched but unmarked
+// Deliveries already queued for the same video and chat are ignored
+func (s *MySQLStore) EnqueueVideoPushes(ctx context.Context, videoID uint, pushes []*model.PendingPush) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(pushes) > 0 {
+			result := tx.Clauses(clause.OnConflict{
+				DoNothing: true,
+			}).CreateInBatches(pushes, 100)
+			if result.Error != nil {
+				return fmt.Errorf("failed to enqueue pending pushes: %w", result.Error)
+			}
+		}
+
+		result := tx.Model(&model.Video{}).
+			Where("id = ?", videoID).
+			Update("pushed", true)
+		if result.Error != nil {
+			return fmt.Errorf("failed to mark video as pushed: %w", result.Error)
+		}
+		return nil
+	})
+}
+
+// GetPendingPushes retrieves queued deliveries with fewer than maxAttempts attempts,
+// highest priority first and oldest first within a priority
+// The associated video is preloaded
+func (s *MySQLStore) GetPendingPushes(ctx context.Context, maxAttempts int, limit int) ([]*model.PendingPush, error) {
+	var pushes []*model.PendingPush
+	result := s.db.WithContext(ctx).
+		Clauses(dbresolver.Write).
+		Preload("Video").
+		Where("attempts < ?", maxAttempts).
+		Order("priority DESC, id ASC").
+		Limit(limit).
+		Find(&pushes)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get pending pushes: %w", result.Error)
+	}
+	return pushes, nil
+}
+
+// CompletePendingPush removes a delivered push from the outbox
+func (s *MySQLStore) CompletePendingPush(ctx context.Context, id uint) error {
+	result := s.db.WithContext(ctx).Delete(&model.PendingPush{}, id)
+	if result.Error != nil {
+		return fmt.Errorf("failed to complete pending push: %w", result.Error)
+	}
+	return nil
+}
+
+// FailPendingPush records a failed delivery attempt so it is retried later
+// A push that has used up maxAttempts is removed from the outbox, and abandoned is true
+func (s *MySQLStore) FailPendingPush(ctx context.Context, id uint, maxAttempts int) (bool, error) {
+	var abandoned bool
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.PendingPush{}).
+			Where("id = ?", id).
+			Update("attempts", gorm.Expr("attempts + 1"))
+		if result.Error != nil {
+			return fmt.Errorf("failed to update pending push: %w", result.Error)
+		}
+
+		result = tx.Where("id = ? AND attempts >= ?", id, maxAttempts).
+			Delete(&model.PendingPush{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to abandon pending push: %w", result.Error)
+		}
+		abandoned = result.RowsAffected > 0
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return abandoned, nil
+}
+
+// CountPendingPushes counts the pushes waiting in the outbox with fewer than maxAttempts attempts
+func (s *MySQLStore) CountPendingPushes(ctx context.Context, maxAttempts int) (int64, error) {
+	var count int64
+	result := s.db.WithContext(ctx).
+		Clauses(dbresolver.Write).
+		Model(&model.PendingPush{}).
+		Where("attempts < ?", maxAttempts).
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count pending pushes: %w", result.Error)
+	}
+	return count, nil
+}
+
+// RecordCrawlRun persists the outcome of a crawl run
+func (s *MySQLStore) RecordCrawlRun(ctx context.Context, run *model.CrawlRun) error {
+	if err := s.db.WithContext(ctx).Create(run).Error; err != nil {
+		return fmt.Errorf("failed to record crawl run: %w", err)
+	}
+	return nil
+}
+
+// SaveDuplicateCandidates stores flagged duplicate pairs; pairs already flagged are ignored
+func (s *MySQLStore) SaveDuplicateCandidates(ctx context.Context, candidates []*model.DuplicateCandidate) (int, error) {
+	if len(candidates) == 0 {
+		return 0, nil
+	}
+	result := s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).CreateInBatches(candidates, 100)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to save duplicate candidates: %w", result.Error)
+	}
+	return int(result.RowsAffected), nil
+}
+
+// GetDuplicateCandidates retrieves duplicate candidates with the given status, most similar first
+func (s *MySQLStore) GetDuplicateCandidates(ctx context.Context, status model.DuplicateStatus, limit int) ([]*model.DuplicateCandidate, error) {
+	var candidates []*model.DuplicateCandidate
+	result := s.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("similarity DESC, id ASC").
+		Limit(limit).
+		Find(&candidates)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get duplicate candidates: %w", result.Error)
+	}
+	return candidates, nil
+}
+
+// ResolveDuplicateCandidate closes a pending duplicate candidate
+// Merging marks the newer video as a duplicate of the older one and as pushed, so it is never delivered.
+// Returns nil if no pending candidate has the given ID.
+func (s *MySQLStore) ResolveDuplicateCandidate(ctx context.Context, id uint, status model.DuplicateStatus) (*model.DuplicateCandidate, error) {
+	var candidate model.DuplicateCandidate
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND status = ?", id, model.DuplicatePending).First(&candidate)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		result = tx.Model(&candidate).Update("status", status)
+		if result.Error != nil {
+			return fmt.Errorf("failed to update duplicate candidate: %w", result.Error)
+		}
+		if status != model.DuplicateMerged {
+			return nil
+		}
+
+		result = tx.Model(&model.Video{}).
+			Where("id = ?", candidate.DuplicateID).
+			Updates(map[string]interface{}{"duplicate_of": candidate.VideoID, "pushed": true})
+		if result.Error != nil {
+			return fmt.Errorf("failed to mark video as duplicate: %w", result.Error)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &candidate, nil
+}
+
+// GetRecentCrawlRuns retrieves the most recent crawl runs, newest first
+func (s *MySQLStore) GetRecentCrawlRuns(ctx context.Context, limit int) ([]*model.CrawlRun, error) {
+	var runs []*model.CrawlRun
+	result := s.db.WithContext(ctx).
+		Order("started_at DESC").
+		Limit(limit).
+		Find(&runs)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get crawl runs: %w", result.Error)
+	}
+	return runs, nil
+}
+
+// RecordCommand stores a bot command invocation
+func (s *MySQLStore) RecordCommand(ctx context.Context, entry *model.CommandLog) error {
+	if err := s.db.WithContext(ctx).Create(entry).Error; err != nil {
+		return fmt.Errorf("failed to record command: %w", err)
+	}
+	return nil
+}
+
+// GetCommandStats aggregates command invocations since the given time, most used first
+// A chatID of 0 aggregates across all chats
+func (s *MySQLStore) GetCommandStats(ctx context.Context, chatID int64, since time.Time) ([]*CommandStat, error) {
+	query := s.db.WithContext(ctx).
+		Model(&model.CommandLog{}).
+		Select("command, COUNT(*) AS count, SUM(outcome <> ?) AS failures, AVG(latency_ms) AS avg_latency_ms",
+			model.CommandOutcomeOK).
+		Where("created_at >= ?", since)
+	if chatID != 0 {
+		query = query.Where("chat_id = ?", chatID)
+	}
+
+	var stats []*CommandStat
+	result := query.
+		Group("command").
+		Order("count DESC").
+		Scan(&stats)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get command stats: %w", result.Error)
+	}
+	return stats, nil
+}
+
+// GetPushStatsByDay counts successful and failed pushes per day since a time, oldest day first
+func (s *MySQLStore) GetPushStatsByDay(ctx context.Context, since time.Time) ([]*PushDayStat, error) {
+	var stats []*PushDayStat
+	result := s.db.WithContext(ctx).
+		Model(&model.PushRecord{}).
+		Select("DATE(pushed_at) AS day, SUM(status = ?) AS success, SUM(status = ?) AS failed",
+			model.PushStatusSuccess, model.PushStatusFailed).
+		Where("pushed_at >= ?", since).
+		Group("DATE(pushed_at)").
+		Order("day ASC").
+		Scan(&stats)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get push stats by day: %w", result.Error)
+	}
+	return stats, nil
+}
+
+// GetPushStatsByChat counts successful and failed pushes per chat since a time,
+// busiest chats first, limited to limit chats
+func (s *MySQLStore) GetPushStatsByChat(ctx context.Context, since time.Time, limit int) ([]*PushChatStat, error) {
+	var stats []*PushChatStat
+	result := s.db.WithContext(ctx).
+		Model(&model.PushRecord{}).
+		Select("chat_id, SUM(status = ?) AS success, SUM(status = ?) AS failed",
+			model.PushStatusSuccess, model.PushStatusFailed).
+		Where("pushed_at >= ?", since).
+		Group("chat_id").
+		Order("success DESC").
+		Limit(limit).
+		Scan(&stats)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get push stats by chat: %w", result.Error)
+	}
+	return stats, nil
+}
+
+// CountSubscriptionsByType counts the enabled subscriptions of each type
+func (s *MySQLStore) CountSubscriptionsByType(ctx context.Context) (map[model.SubscriptionType]int64, error) {
+	var rows []struct {
+		Type  model.SubscriptionType
+		Count int64
+	}
+	result := s.db.WithContext(ctx).
+		Model(&model.Subscription{}).
+		Select("type, COUNT(*) AS count").
+		Where("enabled = ?", true).
+		Group("type").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count subscriptions by type: %w", result.Error)
+	}
+
+	counts := make(map[model.SubscriptionType]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Type] = row.Count
+	}
+	return counts, nil
+}
+
+// CountSubscriptionsByChatType counts the subscriptions of each type and chat type,
+// enabled and disabled separately
+func (s *MySQLStore) CountSubscriptionsByChatType(ctx context.Context) ([]*SubscriptionCount, error) {
+	var counts []*SubscriptionCount
+	result := s.db.WithContext(ctx).
+		Model(&model.Subscription{}).
+		Select("type, chat_type, enabled, COUNT(*) AS count").
+		Group("type, chat_type, enabled").
+		Scan(&counts)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to count subscriptions by chat type: %w", result.Error)
+	}
+	return counts, nil
+}
+
+// CountSubscribedChats counts the distinct chats with at least one enabled subscription
+func (s *MySQLStore) CountSubscribedChats(ctx context.Context) (int64, error) {
+	var count int64
+	result := s.db.WithContext(ctx).
+		Model(&model.Subscription{}).
+		Where("enabled = ?", true).
+		Distinct("chat_id").
+		Count(&count)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to count subscribed chats: %w", result.Error)
+	}
+	return count, nil
+}
+
+// GetChatSettings returns the settings of a chat, or the defaults when none were saved
+func (s *MySQLStore) GetChatSettings(ctx context.Context, chatID int64) (*model.ChatSettings, error) {
+	var settings model.ChatSettings
+	result := s.db.WithContext(ctx).Where("chat_id = ?", chatID).Limit(1).Find(&settings)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get chat settings: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return &model.ChatSettings{ChatID: chatID}, nil
+	}
+	return &settings, nil
+}
+
+// SaveChatSettings creates or replaces the settings of a chat
+func (s *MySQLStore) SaveChatSettings(ctx context.Context, settings *model.ChatSettings) error {
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{UpdateAll: true}).
+		Create(settings)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save chat settings: %w", result.Error)
+	}
+	return nil
+}
+
+// GetRecapChats returns the settings of the chats that opted into the weekly recap
+func (s *MySQLStore) GetRecapChats(ctx context.Context) ([]*model.ChatSettings, error) {
+	var settings []*model.ChatSettings
+	result := s.db.WithContext(ctx).Where("weekly_recap = ?", true).Find(&settings)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get recap chats: %w", result.Error)
+	}
+	return settings, nil
+}
+
+// MuteCode stops pushes of a canonical code to a chat; muting twice is a no-op
+func (s *MySQLStore) MuteCode(ctx context.Context, chatID int64, code string) error {
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&model.ChatMute{ChatID: chatID, Code: code})
+	if result.Error != nil {
+		return fmt.Errorf("failed to mute code: %w", result.Error)
+	}
+	return nil
+}
+
+// UnmuteCode resumes pushes of a canonical code to a chat
+// It reports whether the code was muted.
+func (s *MySQLStore) UnmuteCode(ctx context.Context, chatID int64, code string) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Where("chat_id = ? AND code = ?", chatID, code).
+		Delete(&model.ChatMute{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to unmute code: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// IsCodeMuted checks if a canonical code is muted in a chat
+func (s *MySQLStore) IsCodeMuted(ctx context.Context, code string, chatID int64) (bool, error) {
+	var count int64
+	result := s.db.WithContext(ctx).
+		Model(&model.ChatMute{}).
+		Where("chat_id = ? AND code = ?", chatID, code).
+		Count(&count)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to check muted code: %w", result.Error)
+	}
+	return count > 0, nil
+}
+
+// GetMutedCodes retrieves the codes muted in a chat, most recent first
+func (s *MySQLStore) GetMutedCodes(ctx context.Context, chatID int64) ([]*model.ChatMute, error) {
+	var mutes []*model.ChatMute
+	result := s.db.WithContext(ctx).
+		Where("chat_id = ?", chatID).
+		Order("created_at DESC").
+		Find(&mutes)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get muted codes: %w", result.Error)
+	}
+	return mutes, nil
+}
+
+// AddCodeWatch watches a canonical code for a chat
+// It reports whether the watch was added, false when the chat already watches the code.
+func (s *MySQLStore) AddCodeWatch(ctx context.Context, watch *model.CodeWatch) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(watch)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to add code watch: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RemoveCodeWatch stops watching a canonical code for a chat
+// It reports whether the code was watched.
+func (s *MySQLStore) RemoveCodeWatch(ctx context.Context, chatID int64, code string) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Where("chat_id = ? AND code = ?", chatID, code).
+		Delete(&model.CodeWatch{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to remove code watch: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetCodeWatches retrieves the codes watched in a chat, oldest first
+func (s *MySQLStore) GetCodeWatches(ctx context.Context, chatID int64) ([]*model.CodeWatch, error) {
+	var watches []*model.CodeWatch
+	result := s.db.WithContext(ctx).
+		Where("chat_id = ?", chatID).
+		Order("created_at ASC").
+		Find(&watches)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get code watches: %w", result.Error)
+	}
+	return watches, nil
+}
+
+// GetAllCodeWatches retrieves the watches of every chat, oldest first
+func (s *MySQLStore) GetAllCodeWatches(ctx context.Context) ([]*model.CodeWatch, error) {
+	var watches []*model.CodeWatch
+	result := s.db.WithContext(ctx).
+		Order("created_at ASC").
+		Find(&watches)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get code watches: %w", result.Error)
+	}
+	return watches, nil
+}
+
+// AddBlacklistEntry adds a tag or actress to a chat's blacklist; adding it twice is a no-op
+func (s *MySQLStore) AddBlacklistEntry(ctx context.Context, entry *model.BlacklistEntry) error {
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(entry)
+	if result.Error != nil {
+		return fmt.Errorf("failed to add blacklist entry: %w", result.Error)
+	}
+	return nil
+}
+
+// DeleteBlacklistEntry removes a tag or actress from a chat's blacklist
+// It reports whether the entry existed.
+func (s *MySQLStore) DeleteBlacklistEntry(ctx context.Context, chatID int64, entryType model.BlacklistType, keyword string) (bool, error) {
+	result := s.db.WithContext(ctx).
+		Where("chat_id = ? AND type = ? AND keyword = ?", chatID, entryType, keyword).
+		Delete(&model.BlacklistEntry{})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to delete blacklist entry: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// GetBlacklist retrieves a chat's blacklist entries, ordered by type then keyword
+func (s *MySQLStore) GetBlacklist(ctx context.Context, chatID int64) ([]*model.BlacklistEntry, error) {
+	var entries []*model.BlacklistEntry
+	result := s.db.WithContext(ctx).
+		Where("chat_id = ?", chatID).
+		Order("type ASC, keyword ASC").
+		Find(&entries)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get blacklist: %w", result.Error)
+	}
+	return entries, nil
+}
+
+// TouchUser records that a user was active at user.LastActiveAt
+// New users are created with it as their first seen time; known users get their
+// username and language refreshed.
+func (s *MySQLStore) TouchUser(ctx context.Context, user *model.User) error {
+	if user.FirstSeenAt.IsZero() {
+		user.FirstSeenAt = user.LastActiveAt
+	}
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"username", "language_code", "last_active_at"}),
+		}).
+		Create(user)
+	if result.Error != nil {
+		return fmt.Errorf("failed to touch user: %w", result.Error)
+	}
+	return nil
+}
+
+// GetUser retrieves a user by Telegram user ID, or nil when the user was never seen
+func (s *MySQLStore) GetUser(ctx context.Context, userID int64) (*model.User, error) {
+	var user model.User
+	result := s.db.WithContext(ctx).Where("id = ?", userID).Limit(1).Find(&user)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to get user: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &user, nil
+}
+
+// GetUpdateOffset returns the next Telegram update a bot should process, 0 when none was saved
+func (s *MySQLStore) GetUpdateOffset(ctx context.Context, botID int64) (int, error) {
+	var state model.BotState
+	result := s.db.WithContext(ctx).
+		Clauses(dbresolver.Write).
+		Where("bot_id = ?", botID).
+		Limit(1).
+		Find(&state)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to get update offset: %w", result.Error)
+	}
+	return state.UpdateOffset, nil
+}
+
+// SaveUpdateOffset stores the next Telegram update a bot should process
+func (s *MySQLStore) SaveUpdateOffset(ctx context.Context, botID int64, offset int) error {
+	state := &model.BotState{BotID: botID, UpdateOffset: offset}
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"update_offset", "updated_at"}),
+		}).
+		Create(state)
+	if result.Error != nil {
+		return fmt.Errorf("failed to save update offset: %w", result.Error)
+	}
+	return nil
+}
+
+// TryLock acquires a named MySQL advisory lock (GET_LOCK) without waiting.
+// The lock is bound to a dedicated connection, which is held until unlock is called.
+func (s *MySQLStore) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get underlying db: %w", err)
+	}
+
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get lock connection: %w", err)
+	}
+
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", name).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, fmt.Errorf("failed to acquire lock: %w", err)
+	}
+
+	if !acquired.Valid || acquired.Int64 != 1 {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		// Release with a fresh context so a cancelled caller still frees the lock
+		if _, err := conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", name); err != nil {
+			logctx.From(ctx).Warn().Err(err).Str("lock", name).Msg("Failed to release lock")
+		}
+		conn.Close()
+	}
+	return unlock, true, nil
+}
+
+// WithTx runs fn in a transaction; nested calls use savepoints
+func (s *MySQLStore) WithTx(ctx context.Context, fn func(Store) error) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&MySQLStore{db: tx, videos: s.videos})
+	})
+}
+
+// Ping checks database connectivity
+func (s *MySQLStore) Ping(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying db: %w", err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// DBStats returns the connection pool statistics of the primary database
+func (s *MySQLStore) DBStats() sql.DBStats {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+	return sqlDB.Stats()
+}
+
+// Close closes the database connection and the read replica pool, if any
+func (s *MySQLStore) Close() error {
+	if s.replica != nil {
+		if err := s.replica.Close(); err != nil {
+			log.Warn().Err(err).Msg("Failed to close read replica")
+		}
+	}
 	sqlDB, err := s.db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get underlying db: %w", err)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"testing"
 	"time"
 
@@ -18,7 +19,7 @@ import (
 )
 
 // testStore is a helper to create a test store with a real MySQL database
-func setupTestStore(t *testing.T) (*MySQLStore, func()) {
+func setupTestStore(t testing.TB) (*MySQLStore, func()) {
 	// Use environment variables or defaults for test database
 	host := os.Getenv("TEST_DB_HOST")
 	if host == "" {
@@ -73,6 +74,11 @@ func setupTestStore(t *testing.T) (*MySQLStore, func()) {
 	// Cleanup function
 	cleanup := func() {
 		// Clean up tables
+		store.db.Exec("DELETE FROM crawl_runs")
+		store.db.Exec("DELETE FROM pending_pushes")
+		store.db.Exec("DELETE FROM actress_aliases")
+		store.db.Exec("DELETE FROM command_logs")
+		store.db.Exec("DELETE FROM chat_settings")
 		store.db.Exec("DELETE FROM push_records")
 		store.db.Exec("DELETE FROM subscriptions")
 		store.db.Exec("DELETE FROM videos")
@@ -271,3 +277,194 @@ func TestProperty_UnpushedVideosOrdering(t *testing.T) {
 
 	properties.TestingRun(t)
 }
+
+func TestGetMatchingSubscriptions(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	subs := []*model.Subscription{
+		{ChatID: 1, Type: model.SubTypeAll, Enabled: true},
+		{ChatID: 2, Type: model.SubTypeActress, Keyword: "mikami", Enabled: true},
+		{ChatID: 3, Type: model.SubTypeTag, Keyword: "巨乳", Enabled: true},
+		{ChatID: 4, Type: model.SubTypeActress, Keyword: "Kawakita", Enabled: true},
+		{ChatID: 5, Type: model.SubTypeTag, Keyword: "Yua", Enabled: true},
+		{ChatID: 6, Type: model.SubTypeAll},
+	}
+	for _, sub := range subs {
+		if err := s.db.Create(sub).Error; err != nil {
+			t.Fatalf("failed to create subscription: %v", err)
+		}
+	}
+	// Enabled defaults to true on insert, so disable the last one explicitly
+	s.db.Model(subs[5]).Update("enabled", false)
+
+	video := &model.Video{Code: "SSIS-001", Actresses: "Yua Mikami", Tags: "単体, 巨乳"}
+	matched, err := s.GetMatchingSubscriptions(ctx, video)
+	if err != nil {
+		t.Fatalf("GetMatchingSubscriptions() error = %v", err)
+	}
+
+	var chats []int64
+	for _, sub := range matched {
+		chats = append(chats, sub.ChatID)
+	}
+	sort.Slice(chats, func(i, j int) bool { return chats[i] < chats[j] })
+	if want := []int64{1, 2, 3}; fmt.Sprint(chats) != fmt.Sprint(want) {
+		t.Errorf("GetMatchingSubscriptions() matched chats %v, want %v", chats, want)
+	}
+}
+
+// BenchmarkGetMatchingSubscriptions runs the SQL match query for a batch of unpushed
+// videos, as the push loop does, against 10k subscriptions spread across ALL, ACTRESS
+// and TAG types
+func BenchmarkGetMatchingSubscriptions(b *testing.B) {
+	s, cleanup := setupTestStore(b)
+	defer cleanup()
+	ctx := context.Background()
+
+	subs := make([]*model.Subscription, 10000)
+	for i := range subs {
+		sub := &model.Subscription{ChatID: int64(i + 1), Enabled: true}
+		switch i % 3 {
+		case 0:
+			sub.Type = model.SubTypeAll
+		case 1:
+			sub.Type = model.SubTypeActress
+			sub.Keyword = fmt.Sprintf("Actress %d", i%200)
+		default:
+			sub.Type = model.SubTypeTag
+			sub.Keyword = fmt.Sprintf("tag%d", i%50)
+		}
+		subs[i] = sub
+	}
+	if err := s.db.CreateInBatches(subs, 1000).Error; err != nil {
+		b.Fatalf("failed to create subscriptions: %v", err)
+	}
+
+	videos := make([]*model.Video, 20)
+	for i := range videos {
+		videos[i] = &model.Video{
+			ID:        uint(i + 1),
+			Code:      fmt.Sprintf("ABC-%03d", i),
+			Actresses: fmt.Sprintf("Actress %d, Actress %d", i, i+100),
+			Tags:      fmt.Sprintf("tag%d, tag%d", i, i+25),
+		}
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, video := range videos {
+			if _, err := s.GetMatchingSubscriptions(ctx, video); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func TestFailPendingPush_AbandonsAtMaxAttempts(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	video := &model.Video{Code: "SSIS-002"}
+	if err := s.SaveVideo(ctx, video); err != nil {
+		t.Fatalf("SaveVideo() error = %v", err)
+	}
+	push := &model.PendingPush{VideoID: video.ID, ChatID: 1}
+	if err := s.EnqueueVideoPushes(ctx, video.ID, []*model.PendingPush{push}); err != nil {
+		t.Fatalf("EnqueueVideoPushes() error = %v", err)
+	}
+
+	const maxAttempts = 2
+	abandoned, err := s.FailPendingPush(ctx, push.ID, maxAttempts)
+	if err != nil || abandoned {
+		t.Fatalf("first FailPendingPush() = %v, %v, want false, nil", abandoned, err)
+	}
+	if count, _ := s.CountPendingPushes(ctx, maxAttempts); count != 1 {
+		t.Fatalf("CountPendingPushes() after one failure = %d, want 1", count)
+	}
+
+	abandoned, err = s.FailPendingPush(ctx, push.ID, maxAttempts)
+	if err != nil || !abandoned {
+		t.Fatalf("last FailPendingPush() = %v, %v, want true, nil", abandoned, err)
+	}
+
+	var rows int64
+	s.db.Model(&model.PendingPush{}).Count(&rows)
+	if rows != 0 {
+		t.Errorf("outbox holds %d rows after giving up, want 0", rows)
+	}
+	if count, _ := s.CountPendingPushes(ctx, maxAttempts); count != 0 {
+		t.Errorf("CountPendingPushes() after giving up = %d, want 0", count)
+	}
+}
+
+func TestGetRelatedVideos_ActressBeforeCommonTag(t *testing.T) {
+	s, cleanup := setupTestStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	older := &model.Video{Code: "SSIS-010", Actresses: "三上悠亜", Tags: "単体"}
+	if err := s.SaveVideo(ctx, older); err != nil {
+		t.Fatalf("SaveVideo() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		tagOnly := &model.Video{Code: fmt.Sprintf("ABC-%03d", i), Actresses: "Other", Tags: "巨乳"}
+		if err := s.SaveVideo(ctx, tagOnly); err != nil {
+			t.Fatalf("SaveVideo() error = %v", err)
+		}
+	}
+
+	video := &model.Video{Code: "SSIS-001", Actresses: "三上悠亜", Tags: "巨乳"}
+	related, err := s.GetRelatedVideos(ctx, video, 2)
+	if err != nil {
+		t.Fatalf("GetRelatedVideos() error = %v", err)
+	}
+	if len(related) != 2 || related[0].Code != older.Code {
+		t.Errorf("GetRelatedVideos() = %v, want the actress match first", related)
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	if got, want := escapeLike(`100%_a\b`), `100\%\_a\\b`; got != want {
+		t.Errorf("escapeLike() = %q, want %q", got, want)
+	}
+}
+
+func TestSplitCatalogNames(t *testing.T) {
+	got := splitCatalogNames([]string{"三上悠亜, 河北彩花", "河北彩花", " ", "Aoi, 三上悠亜"})
+	want := []string{"Aoi", "三上悠亜", "河北彩花"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("splitCatalogNames() = %v, want %v", got, want)
+	}
+}
+
+func TestCountCatalogNames(t *testing.T) {
+	got := countCatalogNames([]string{"単体, 巨乳", "巨乳, 巨乳", "美少女, 単体", "巨乳", " "}, 2)
+	want := []*TagCount{{Tag: "巨乳", Count: 3}, {Tag: "単体", Count: 2}}
+	if len(got) != len(want) {
+		t.Fatalf("countCatalogNames() returned %d tags, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if *got[i] != *want[i] {
+			t.Errorf("countCatalogNames()[%d] = %+v, want %+v", i, *got[i], *want[i])
+		}
+	}
+}
+
+func TestGenerateAliases(t *testing.T) {
+	aliases := generateAliases([]string{"三上悠亜", "つばさ", "Yua Mikami"})
+
+	got := make(map[string]string)
+	for _, a := range aliases {
+		got[a.Name+"/"+a.Kind] = a.Normalized
+	}
+	want := map[string]string{
+		"三上悠亜/pinyin": "sanshangyouya",
+		"つばさ/romaji":  "tsubasa",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("generateAliases() = %v, want %v", got, want)
+	}
+}
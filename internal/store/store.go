@@ -2,6 +2,8 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/user/missav-bot-go/internal/model"
 )
@@ -14,15 +16,38 @@ type Store interface {
 	GetVideoByCode(ctx context.Context, code string) (*model.Video, error)
 	GetUnpushedVideos(ctx context.Context) ([]*model.Video, error)
 	MarkAsPushed(ctx context.Context, videoID uint) error
+	MarkAsPushedBulk(ctx context.Context, videoIDs []uint) error
+	HideVideos(ctx context.Context, code string) (int64, error)
+	DeleteVideos(ctx context.Context, code string, bannedBy int64) (int64, error)
 	SearchVideos(ctx context.Context, keyword string, limit int) ([]*model.Video, error)
+	FindVideos(ctx context.Context, filter *VideoFilter) ([]*model.Video, error)
+	CountVideosMatching(ctx context.Context, filter *VideoFilter) (int64, error)
 	GetLatestVideos(ctx context.Context, limit, offset int) ([]*model.Video, error)
 	CountVideos(ctx context.Context) (int64, error)
+	RefreshVideoCount(ctx context.Context) (int64, error)
 	ExistsByCode(ctx context.Context, code string) (bool, error)
+	GetActressNames(ctx context.Context) ([]string, error)
+	GetTagNames(ctx context.Context) ([]string, error)
+	GetPopularTags(ctx context.Context, limit int) ([]*TagCount, error)
+	UpdateVideoDetails(ctx context.Context, video *model.Video) error
+	GetIncompleteVideos(ctx context.Context, since time.Time, limit int) ([]*model.Video, error)
+	CountVideosByCompleteness(ctx context.Context) (map[int]int64, error)
+	GetVideosSince(ctx context.Context, since time.Time) ([]*model.Video, error)
+	GetRelatedVideos(ctx context.Context, video *model.Video, limit int) ([]*model.Video, error)
+
+	// Duplicate candidate operations
+	SaveDuplicateCandidates(ctx context.Context, candidates []*model.DuplicateCandidate) (int, error)
+	GetDuplicateCandidates(ctx context.Context, status model.DuplicateStatus, limit int) ([]*model.DuplicateCandidate, error)
+	ResolveDuplicateCandidate(ctx context.Context, id uint, status model.DuplicateStatus) (*model.DuplicateCandidate, error)
+
+	// Actress alias operations
+	AddActressAlias(ctx context.Context, name string, alias string) error
+	GetActressAliases(ctx context.Context, name string) ([]*model.ActressAlias, error)
 
 	// Subscription operations
 	CreateSubscription(ctx context.Context, sub *model.Subscription) error
-	DeleteSubscription(ctx context.Context, chatID int64, subType string, keyword string) error
-	DeleteAllSubscriptions(ctx context.Context, chatID int64) error
+	DeleteSubscription(ctx context.Context, chatID int64, userID int64, subType string, keyword string) error
+	DeleteAllSubscriptions(ctx context.Context, chatID int64) (int64, error)
 	GetSubscriptions(ctx context.Context, chatID int64) ([]*model.Subscription, error)
 	GetAllSubscriptions(ctx context.Context) ([]*model.Subscription, error)
 	GetMatchingSubscriptions(ctx context.Context, video *model.Video) ([]*model.Subscription, error)
@@ -30,8 +55,127 @@ type Store interface {
 	// PushRecord operations
 	RecordPush(ctx context.Context, record *model.PushRecord) error
 	HasPushed(ctx context.Context, videoID uint, chatID int64) (bool, error)
+	HasPushedCode(ctx context.Context, code string, chatID int64) (bool, error)
+	FilterUnpushedChats(ctx context.Context, videoID uint, code string, chatIDs []int64) ([]int64, error)
+	GetPushHistory(ctx context.Context, chatID int64, limit int) ([]*PushHistoryEntry, error)
+	CountPushesSince(ctx context.Context, chatID int64, since time.Time) (int64, error)
+	GetPushHistorySince(ctx context.Context, chatID int64, since time.Time, limit int) ([]*PushHistoryEntry, error)
+	CountCappedPushesSince(ctx context.Context, chatID int64, since time.Time) (int64, error)
+	GetEditablePushes(ctx context.Context, videoID uint) ([]*model.PushRecord, error)
+	GetPushedMessages(ctx context.Context, code string) ([]*model.PushRecord, error)
+	ClearPushMessage(ctx context.Context, recordID uint) error
+	ReportCappedPushes(ctx context.Context, before time.Time) (map[int64]int64, error)
+
+	// PendingPush (outbox) operations
+	EnqueueVideoPushes(ctx context.Context, videoID uint, pushes []*model.PendingPush) error
+	GetPendingPushes(ctx context.Context, maxAttempts int, limit int) ([]*model.PendingPush, error)
+	CompletePendingPush(ctx context.Context, id uint) error
+	FailPendingPush(ctx context.Context, id uint, maxAttempts int) (bool, error)
+	CountPendingPushes(ctx context.Context, maxAttempts int) (int64, error)
+
+	// CrawlRun operations
+	RecordCrawlRun(ctx context.Context, run *model.CrawlRun) error
+	GetRecentCrawlRuns(ctx context.Context, limit int) ([]*model.CrawlRun, error)
+
+	// CommandLog operations
+	RecordCommand(ctx context.Context, entry *model.CommandLog) error
+	GetCommandStats(ctx context.Context, chatID int64, since time.Time) ([]*CommandStat, error)
+
+	// ChatSettings operations
+	GetChatSettings(ctx context.Context, chatID int64) (*model.ChatSettings, error)
+	SaveChatSettings(ctx context.Context, settings *model.ChatSettings) error
+	GetRecapChats(ctx context.Context) ([]*model.ChatSettings, error)
+
+	// ChatMute operations
+	MuteCode(ctx context.Context, chatID int64, code string) error
+	UnmuteCode(ctx context.Context, chatID int64, code string) (bool, error)
+	IsCodeMuted(ctx context.Context, code string, chatID int64) (bool, error)
+	GetMutedCodes(ctx context.Context, chatID int64) ([]*model.ChatMute, error)
+
+	// CodeWatch operations
+	AddCodeWatch(ctx context.Context, watch *model.CodeWatch) (bool, error)
+	RemoveCodeWatch(ctx context.Context, chatID int64, code string) (bool, error)
+	GetCodeWatches(ctx context.Context, chatID int64) ([]*model.CodeWatch, error)
+	GetAllCodeWatches(ctx context.Context) ([]*model.CodeWatch, error)
+
+	// Blacklist operations
+	AddBlacklistEntry(ctx context.Context, entry *model.BlacklistEntry) error
+	DeleteBlacklistEntry(ctx context.Context, chatID int64, entryType model.BlacklistType, keyword string) (bool, error)
+	GetBlacklist(ctx context.Context, chatID int64) ([]*model.BlacklistEntry, error)
+
+	// User operations
+	TouchUser(ctx context.Context, user *model.User) error
+	GetUser(ctx context.Context, userID int64) (*model.User, error)
+
+	// BotState operations
+	GetUpdateOffset(ctx context.Context, botID int64) (int, error)
+	SaveUpdateOffset(ctx context.Context, botID int64, offset int) error
+
+	// Statistics operations
+	CountUnpushedVideos(ctx context.Context) (int64, error)
+	GetPushStatsByDay(ctx context.Context, since time.Time) ([]*PushDayStat, error)
+	GetPushStatsByChat(ctx context.Context, since time.Time, limit int) ([]*PushChatStat, error)
+	CountSubscriptionsByType(ctx context.Context) (map[model.SubscriptionType]int64, error)
+	CountSubscriptionsByChatType(ctx context.Context) ([]*SubscriptionCount, error)
+	CountSubscribedChats(ctx context.Context) (int64, error)
+
+	// Transactions
+	// WithTx runs fn against a store bound to a single transaction, committing when fn
+	// returns nil and rolling back otherwise. The store passed to fn must not be kept or closed.
+	WithTx(ctx context.Context, fn func(Store) error) error
 
 	// Health check
 	Ping(ctx context.Context) error
+	DBStats() sql.DBStats
 	Close() error
 }
+
+// CommandStat aggregates command invocations over a time window
+type CommandStat struct {
+	Command      string  `json:"command"`
+	Count        int64   `json:"count"`
+	Failures     int64   `json:"failures"`
+	AvgLatencyMs float64 `json:"avgLatencyMs"`
+}
+
+// PushDayStat counts the push outcomes of one day
+type PushDayStat struct {
+	Day     time.Time `json:"day"`
+	Success int64     `json:"success"`
+	Failed  int64     `json:"failed"`
+}
+
+// PushChatStat counts the push outcomes of one chat over a time window
+type PushChatStat struct {
+	ChatID  int64 `json:"chatId"`
+	Success int64 `json:"success"`
+	Failed  int64 `json:"failed"`
+}
+
+// SubscriptionCount counts the subscriptions sharing a type, chat type and enabled state
+type SubscriptionCount struct {
+	Type     model.SubscriptionType
+	ChatType string
+	Enabled  bool
+	Count    int64
+}
+
+// TagCount is a tag and the number of stored videos carrying it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// PushHistoryEntry is a video successfully pushed to a chat
+type PushHistoryEntry struct {
+	Video    *model.Video
+	PushedAt time.Time
+}
+
+// Locker is implemented by stores that can provide a lock shared across
+// multiple bot instances
+type Locker interface {
+	// TryLock attempts to acquire the named lock without waiting.
+	// On success it returns a function that releases the lock.
+	TryLock(ctx context.Context, name string) (unlock func(), acquired bool, err error)
+}
@@ -0,0 +1,35 @@
+package store
+
+import "sync"
+
+// videoCounter caches the number of stored videos so CountVideos does not run COUNT(*)
+// Saves adjust the cached value; RefreshVideoCount reconciles it with the table.
+type videoCounter struct {
+	mu    sync.Mutex
+	count int64
+	known bool
+}
+
+// get returns the cached count, if one has been loaded
+func (c *videoCounter) get() (int64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count, c.known
+}
+
+// set replaces the cached count with one counted from the table
+func (c *videoCounter) set(count int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.count = count
+	c.known = true
+}
+
+// add adjusts a loaded count by delta; before the first load there is nothing to adjust
+func (c *videoCounter) add(delta int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.known {
+		c.count += delta
+	}
+}
@@ -0,0 +1,24 @@
+package store
+
+import "testing"
+
+func TestVideoCounter(t *testing.T) {
+	var c videoCounter
+
+	// Saves before the first count are not tracked
+	c.add(3)
+	if _, ok := c.get(); ok {
+		t.Fatal("get() before set reported a count")
+	}
+
+	c.set(10)
+	c.add(2)
+	if count, ok := c.get(); !ok || count != 12 {
+		t.Errorf("get() = %d, %v; want 12, true", count, ok)
+	}
+
+	c.set(11)
+	if count, _ := c.get(); count != 11 {
+		t.Errorf("get() after reconcile = %d, want 11", count)
+	}
+}
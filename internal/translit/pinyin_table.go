@@ -0,0 +1,73 @@
+package translit
+
+// pinyinTable maps Han characters common in Japanese performer names to toneless pinyin.
+// Japanese-specific forms (e.g. 亜, 沢, 桜) use the reading of their Chinese counterpart.
+// Names containing characters outside the table get no pinyin form.
+var pinyinTable = map[rune]string{
+	'一': "yi", '七': "qi", '万': "wan", '三': "san", '上': "shang", '下': "xia", '世': "shi", '丘': "qiu",
+	'中': "zhong", '丸': "wan", '乃': "nai", '久': "jiu", '之': "zhi", '乙': "yi", '九': "jiu", '也': "ye",
+	'亀': "gui", '二': "er", '五': "wu", '井': "jing", '亜': "ya", '亞': "ya", '京': "jing", '亮': "liang",
+	'人': "ren", '仁': "ren", '今': "jin", '介': "jie", '仙': "xian", '代': "dai", '令': "ling", '伊': "yi",
+	'伸': "shen", '佐': "zuo", '佑': "you", '佳': "jia", '使': "shi", '依': "yi", '俊': "jun", '保': "bao",
+	'信': "xin", '倉': "cang", '倫': "lun", '健': "jian", '優': "you", '兄': "xiong", '光': "guang", '兎': "tu",
+	'八': "ba", '六': "liu", '内': "nei", '冬': "dong", '冷': "leng", '凛': "lin", '凜': "lin", '凰': "huang",
+	'刀': "dao", '利': "li", '前': "qian", '剣': "jian", '加': "jia", '助': "zhu", '勇': "yong", '北': "bei",
+	'匠': "jiang", '十': "shi", '千': "qian", '南': "nan", '博': "bo", '原': "yuan", '友': "you", '口': "kou",
+	'古': "gu", '可': "ke", '史': "shi", '右': "you", '司': "si", '合': "he", '吉': "ji", '名': "ming",
+	'向': "xiang", '君': "jun", '和': "he", '咲': "xiao", '唯': "wei", '喜': "xi", '嘉': "jia", '四': "si",
+	'国': "guo", '國': "guo", '園': "yuan", '地': "di", '坂': "ban", '城': "cheng", '堀': "ku", '堂': "tang",
+	'場': "chang", '増': "zeng", '士': "shi", '夏': "xia", '夕': "xi", '外': "wai", '多': "duo", '夜': "ye",
+	'夢': "meng", '大': "da", '天': "tian", '太': "tai", '夫': "fu", '央': "yang", '奈': "nai", '奏': "zou",
+	'女': "nu", '妃': "fei", '妹': "mei", '姉': "zi", '姫': "ji", '娘': "niang", '嬢': "niang", '子': "zi",
+	'孝': "xiao", '季': "ji", '宇': "yu", '安': "an", '宏': "hong", '宝': "bao", '実': "shi", '宮': "gong",
+	'宵': "xiao", '家': "jia", '富': "fu", '實': "shi", '寶': "bao", '寺': "si", '寿': "shou", '小': "xiao",
+	'尾': "wei", '屋': "wu", '山': "shan", '岡': "gang", '岩': "yan", '岬': "jia", '岸': "an", '峰': "feng",
+	'島': "dao", '崎': "qi", '嵐': "lan", '嶋': "dao", '嶺': "ling", '川': "chuan", '州': "zhou", '工': "gong",
+	'左': "zuo", '巧': "qiao", '市': "shi", '帆': "fan", '希': "xi", '帝': "di", '平': "ping", '幸': "xing",
+	'広': "guang", '康': "kang", '廣': "guang", '弓': "gong", '弘': "hong", '弟': "di", '弥': "mi", '弦': "xian",
+	'彩': "cai", '彰': "zhang", '律': "lu", '後': "hou", '徳': "de", '心': "xin", '志': "zhi", '忠': "zhong",
+	'念': "nian", '怜': "lian", '思': "si", '恋': "lian", '恭': "gong", '恵': "hui", '悠': "you", '情': "qing",
+	'惠': "hui", '想': "xiang", '意': "yi", '愛': "ai", '感': "gan", '慎': "shen", '成': "cheng", '掛': "gua",
+	'摩': "mo", '政': "zheng", '文': "wen", '斉': "qi", '斎': "zhai", '新': "xin", '日': "ri", '早': "zao",
+	'旭': "xu", '昇': "sheng", '昌': "chang", '明': "ming", '星': "xing", '春': "chun", '晴': "qing", '晶': "jing",
+	'智': "zhi", '暖': "nuan", '月': "yue", '有': "you", '望': "wang", '朝': "chao", '木': "mu", '未': "wei",
+	'末': "mo", '本': "ben", '杉': "shan", '李': "li", '杏': "xing", '村': "cun", '来': "lai", '東': "dong",
+	'松': "song", '林': "lin", '果': "guo", '枝': "zhi", '枢': "shu", '架': "jia", '柏': "bai", '柚': "you",
+	'柳': "liu", '柴': "chai", '柿': "shi", '栄': "rong", '栗': "li", '根': "gen", '桂': "gui", '桃': "tao",
+	'桐': "tong", '桑': "sang", '桜': "ying", '梅': "mei", '梓': "zi", '梨': "li", '森': "sen", '椎': "zhui",
+	'椿': "chun", '楓': "feng", '楠': "nan", '楽': "le", '榛': "zhen", '榮': "rong", '槻': "gui", '横': "heng",
+	'樹': "shu", '橋': "qiao", '檀': "tan", '櫻': "ying", '次': "ci", '歌': "ge", '正': "zheng", '武': "wu",
+	'歩': "bu", '母': "mu", '水': "shui", '永': "yong", '汐': "xi", '江': "jiang", '池': "chi", '沖': "chong",
+	'沙': "sha", '沢': "ze", '河': "he", '沼': "zhao", '泉': "quan", '泊': "bo", '波': "bo", '洋': "yang",
+	'津': "jin", '流': "liu", '浅': "qian", '浜': "bin", '浦': "pu", '浩': "hao", '浪': "lang", '海': "hai",
+	'涼': "liang", '深': "shen", '淳': "chun", '清': "qing", '渋': "se", '渚': "zhu", '渡': "du", '温': "wen",
+	'港': "gang", '湊': "cou", '湖': "hu", '湯': "tang", '満': "man", '滝': "long", '潔': "jie", '潮': "chao",
+	'澁': "se", '澄': "cheng", '澤': "ze", '澪': "ling", '濱': "bin", '瀧': "long", '瀬': "lai", '火': "huo",
+	'灯': "deng", '炎': "yan", '熊': "xiong", '燈': "deng", '父': "fu", '片': "pian", '犬': "quan", '猫': "mao",
+	'玉': "yu", '王': "wang", '玲': "ling", '珀': "po", '珊': "shan", '珠': "zhu", '理': "li", '琉': "liu",
+	'琥': "hu", '琳': "lin", '琴': "qin", '瑚': "hu", '瑛': "ying", '瑞': "rui", '瑠': "liu", '璃': "li",
+	'環': "huan", '生': "sheng", '田': "tian", '由': "you", '男': "nan", '町': "ding", '畑': "tian", '留': "liu",
+	'白': "bai", '百': "bai", '皇': "huang", '直': "zhi", '相': "xiang", '眞': "zhen", '真': "zhen", '瞬': "shun",
+	'矢': "shi", '知': "zhi", '石': "shi", '碧': "bi", '礼': "li", '祐': "you", '神': "shen", '祥': "xiang",
+	'福': "fu", '秀': "xiu", '秋': "qiu", '稲': "dao", '穂': "sui", '空': "kong", '竜': "long", '章': "zhang",
+	'竹': "zhu", '笑': "xiao", '笛': "di", '篠': "xiao", '米': "mi", '糸': "si", '紀': "ji", '紅': "hong",
+	'純': "chun", '紗': "sha", '紘': "hong", '紫': "zi", '紬': "chou", '絆': "ban", '結': "jie", '絢': "xuan",
+	'絵': "hui", '絹': "juan", '綺': "qi", '綾': "ling", '緑': "lu", '緒': "xu", '縁': "yuan", '織': "zhi",
+	'繭': "jian", '美': "mei", '義': "yi", '羽': "yu", '翔': "xiang", '翠': "cui", '翡': "fei", '耀': "yao",
+	'耶': "ye", '聖': "sheng", '能': "neng", '舘': "guan", '舞': "wu", '航': "hang", '良': "liang", '色': "se",
+	'芙': "fu", '芦': "lu", '花': "hua", '芹': "qin", '芽': "ya", '苑': "yuan", '苗': "miao", '若': "ruo",
+	'英': "ying", '苺': "mei", '茉': "mo", '茜': "qian", '茶': "cha", '草': "cao", '荒': "huang", '莉': "li",
+	'菅': "jian", '菊': "ju", '菖': "chang", '菜': "cai", '菫': "jin", '華': "hua", '萌': "meng", '萩': "qiu",
+	'葉': "ye", '葦': "wei", '葵': "kui", '蒲': "pu", '蒼': "cang", '蓉': "rong", '蓮': "lian", '薫': "xun",
+	'藍': "lan", '藤': "teng", '蘭': "lan", '虎': "hu", '虹': "hong", '蝶': "die", '街': "jie", '衣': "yi",
+	'裕': "yu", '西': "xi", '見': "jian", '詩': "shi", '誠': "cheng", '谷': "gu", '豆': "dou", '貴': "gui",
+	'赤': "chi", '路': "lu", '輔': "fu", '輝': "hui", '辺': "bian", '辻': "shi", '近': "jin", '透': "tou",
+	'逢': "feng", '道': "dao", '遙': "yao", '遠': "yuan", '遥': "yao", '邉': "bian", '邊': "bian", '那': "na",
+	'郎': "lang", '部': "bu", '郷': "xiang", '都': "du", '酒': "jiu", '里': "li", '野': "ye", '金': "jin",
+	'鈴': "ling", '銀': "yin", '鎌': "lian", '鏡': "jing", '鐘': "zhong", '長': "chang", '門': "men", '間': "jian",
+	'関': "guan", '關': "guan", '阿': "a", '陸': "lu", '陽': "yang", '雄': "xiong", '雅': "ya", '雨': "yu",
+	'雪': "xue", '雲': "yun", '霞': "xia", '青': "qing", '静': "jing", '靜': "jing", '音': "yin", '響': "xiang",
+	'順': "shun", '須': "xu", '風': "feng", '颯': "sa", '館': "guan", '香': "xiang", '馬': "ma", '高': "gao",
+	'鬼': "gui", '魔': "mo", '鳥': "niao", '鳳': "feng", '鶴': "he", '鷹': "ying", '鹿': "lu", '麗': "li",
+	'麟': "lin", '麦': "mai", '麻': "ma", '黒': "hei", '鼓': "gu", '齋': "zhai", '龍': "long",
+}
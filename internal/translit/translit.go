@@ -0,0 +1,140 @@
+// Package translit generates Latin-script forms of actress names so users can
+// search by romaji or pinyin instead of the original Japanese spelling
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Alias kinds
+const (
+	KindRomaji = "romaji"
+	KindPinyin = "pinyin"
+	KindManual = "manual"
+)
+
+// Form is a generated Latin-script spelling of a name
+type Form struct {
+	Kind  string
+	Value string
+}
+
+// Forms returns the transliterations that can be generated for a name
+// Kana-only names yield Hepburn romaji and Han-only names yield toneless pinyin.
+// Kanji readings are ambiguous in Japanese, so romaji for kanji names must be added as manual aliases.
+func Forms(name string) []Form {
+	var forms []Form
+	if romaji, ok := Romaji(name); ok {
+		forms = append(forms, Form{Kind: KindRomaji, Value: romaji})
+	}
+	if pinyin, ok := Pinyin(name); ok {
+		forms = append(forms, Form{Kind: KindPinyin, Value: pinyin})
+	}
+	return forms
+}
+
+// Normalize folds a name or query into the form used for alias comparison:
+// lowercase, full-width ASCII folded to half-width, with spaces and punctuation removed
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		// Fold full-width ASCII variants (U+FF01..U+FF5E)
+		if r >= 0xFF01 && r <= 0xFF5E {
+			r -= 0xFEE0
+		}
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || r == '・' || r == '･' {
+			continue
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// Pinyin returns the toneless pinyin of a name written only in Han characters
+// Returns false if the name contains other scripts or characters missing from the table.
+func Pinyin(name string) (string, bool) {
+	var syllables []string
+	for _, r := range name {
+		if unicode.IsSpace(r) {
+			continue
+		}
+		syllable, ok := pinyinTable[r]
+		if !ok {
+			return "", false
+		}
+		syllables = append(syllables, syllable)
+	}
+	if len(syllables) == 0 {
+		return "", false
+	}
+	return strings.Join(syllables, " "), true
+}
+
+// Romaji returns the Hepburn romanization of a name written only in kana
+// Returns false if the name contains kanji or other scripts.
+func Romaji(name string) (string, bool) {
+	runes := []rune(name)
+	var b strings.Builder
+	sawKana := false
+	geminate := false
+
+	for i := 0; i < len(runes); i++ {
+		r := toHiragana(runes[i])
+
+		switch {
+		case unicode.IsSpace(r) || r == '・':
+			b.WriteByte(' ')
+			continue
+		case r == 'っ':
+			geminate = true
+			sawKana = true
+			continue
+		case r == 'ー':
+			// Long vowels are left unmarked in simplified Hepburn
+			sawKana = true
+			continue
+		}
+
+		// Prefer two-kana combinations such as きゃ
+		syllable := ""
+		if i+1 < len(runes) {
+			if s, ok := kanaDigraphs[string([]rune{r, toHiragana(runes[i+1])})]; ok {
+				syllable = s
+				i++
+			}
+		}
+		if syllable == "" {
+			s, ok := kanaTable[r]
+			if !ok {
+				return "", false
+			}
+			syllable = s
+		}
+
+		if geminate {
+			if strings.HasPrefix(syllable, "ch") {
+				b.WriteByte('t')
+			} else if syllable[0] != 'a' && syllable[0] != 'i' && syllable[0] != 'u' &&
+				syllable[0] != 'e' && syllable[0] != 'o' && syllable[0] != 'n' {
+				b.WriteByte(syllable[0])
+			}
+			geminate = false
+		}
+		b.WriteString(syllable)
+		sawKana = true
+	}
+
+	if !sawKana {
+		return "", false
+	}
+	return strings.TrimSpace(b.String()), true
+}
+
+// toHiragana maps katakana to the corresponding hiragana
+func toHiragana(r rune) rune {
+	if r >= 'ァ' && r <= 'ヶ' {
+		return r - ('ァ' - 'ぁ')
+	}
+	return r
+}
@@ -0,0 +1,59 @@
+package translit
+
+import "testing"
+
+func TestRomaji(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"あいか", "aika", true},
+		{"キララ", "kirara", true},
+		{"しょうこ", "shouko", true},
+		{"さっちゃん", "satchan", true},
+		{"みっく", "mikku", true},
+		{"カレン", "karen", true},
+		{"ルーシー", "rushi", true},
+		{"三上", "", false},
+		{"Yua", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := Romaji(tt.name)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Romaji(%q) = %q, %v; want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPinyin(t *testing.T) {
+	got, ok := Pinyin("三上悠亜")
+	if !ok || got != "san shang you ya" {
+		t.Errorf("Pinyin(三上悠亜) = %q, %v", got, ok)
+	}
+	if _, ok := Pinyin("深田えいみ"); ok {
+		t.Error("Pinyin() should reject names containing kana")
+	}
+}
+
+func TestNormalize_MatchesTypedForms(t *testing.T) {
+	pinyin, _ := Pinyin("三上悠亜")
+	if Normalize("sanshang youya") != Normalize(pinyin) {
+		t.Errorf("Normalize(%q) != Normalize(%q)", "sanshang youya", pinyin)
+	}
+	if Normalize("Ｙｕａ・Mikami") != Normalize("yua mikami") {
+		t.Errorf("Normalize() should fold full-width letters, case and separators")
+	}
+}
+
+func TestForms(t *testing.T) {
+	forms := Forms("河北彩花")
+	if len(forms) != 1 || forms[0].Kind != KindPinyin || forms[0].Value != "he bei cai hua" {
+		t.Errorf("Forms(河北彩花) = %+v", forms)
+	}
+	forms = Forms("つばさ")
+	if len(forms) != 1 || forms[0].Kind != KindRomaji || forms[0].Value != "tsubasa" {
+		t.Errorf("Forms(つばさ) = %+v", forms)
+	}
+}